@@ -15,7 +15,7 @@
 /*
 Package onos-topo is the main entry point to the ONOS topology subsystem.
 
-Arguments
+# Arguments
 
 -caPath <the location of a CA certificate>
 
@@ -23,26 +23,184 @@ Arguments
 
 -certPath <the location of a client certificate>
 
+-store <the type of the device store, atomix or local; defaults to $ONOS_TOPO_STORE or atomix>
+
+-readOnly <whether to run the server as a read-only replica>
+
+-gateway <whether to serve the HTTP/JSON gateway; HTTP requests are subject to the same rate limits, deadlines, request logging and metrics as gRPC requests>
+
+-allowForceUpdates <whether to allow device updates that ignore the device version, e.g. for migrations>
+
+-allowSecretAccess <whether clients that present a verified client certificate may request device secrets, which are otherwise omitted from devices returned by the service>
+
+-dedupeWrites <whether to skip device updates that don't change the device>
+
+-idPattern <the regular expression to which added device IDs must conform; defaults to an RFC 1123 DNS label>
+
+-historySize <the number of recent events recorded for each device; 0 disables the device history log>
+
+-historyAge <the maximum age of recorded device events; 0 retains events indefinitely>
+
+-rateLimit <the maximum combined rate of device mutations per second across all mutating methods; 0 disables rate limiting>
+
+-rateBurst <the maximum burst of device mutations across all mutating methods>
+
+-requestLogSampleRate <the fraction of requests that are logged with their device, latency and status; 0 disables request logging>
+
+-requestLogErrors <whether failed requests are logged regardless of the sample rate>
+
+-maxDevices <the maximum number of devices; 0 disables the limit>
+
+-natsAddress <the address of a NATS server to which device events are published>
+
+-natsSubject <the NATS subject to which device events are published>
+
+-natsTLS <whether to connect to the NATS server with TLS>
+
+-natsUser <the user with which to authenticate to the NATS server>
+
+-natsPassword <the password with which to authenticate to the NATS server>
+
+-natsToken <the token with which to authenticate to the NATS server>
+
+-eventFormat <the format of published device events, json or proto>
+
+-allowedNetworks <a comma-separated list of CIDRs to which device addresses must belong>
+
+-requiredLabels <a comma-separated list of labels that devices must have>
+
+-strictValidation <whether to require coherent TLS configurations and semantic software versions>
+
+-compressionThreshold <the size in bytes above which stored devices are compressed; 0 disables compression>
+
+-maxDeviceSize <the maximum size in bytes of a stored device after compression; set to the entry size limit of the store, and 0 disables the limit>
+
+-conflictRetries <the number of times a field mask update is retried when the device is concurrently modified>
+
+-listWorkers <the number of workers that concurrently decode devices listed from the Atomix store>
+
+-listBufferSize <the number of listed Atomix map entries buffered ahead of the decoding workers>
+
+-localStorePath <the file to which the local store persists devices; devices are kept in memory if not set>
+
+-atomixGroups <a comma-separated list of Atomix groups across which devices are sharded; devices are stored in the group configured by the environment if not set>
+
+-defaultDeadline <the deadline applied to requests sent without a deadline; 0 leaves such requests unbounded>
+
+-maxRecvMsgSize <the maximum size in bytes of a request message; 0 uses the gRPC default of 4 MiB>
+
+-maxSendMsgSize <the maximum size in bytes of a response message; allow about 2 KiB per device in the largest list responses, and 0 uses the gRPC default>
+
+-deviceStatsSize <the number of most accessed devices for which read and write counts are collected; 0 disables device statistics>
+
+-maxDeadline <the maximum deadline of requests; longer client deadlines are capped, and 0 disables the cap>
+
+-evictionThreshold <the number of events a device subscriber may fall behind before it's disconnected; 0 drops the subscriber's oldest events instead>
+
+-labelIndex <whether to maintain an in-memory index of device labels to avoid scanning all devices for label selector queries>
+
+-uniqueSerialNumbers <whether to reject devices that are added or updated with the serial number of another device>
+
+-uniqueAddresses <whether to reject devices that are added or updated with the address of another device unless the request allows it>
+
+-subscriberMetrics <whether to publish the device subscriber count and send lag as topo_device_subscriber_metrics at /debug/vars>
+
+-traceLog <whether to trace RPCs and store operations and log each span; spans join the caller's trace when a W3C traceparent is sent in the request metadata>
 
 See ../../docs/run.md for how to run the application.
 */
 package main
 
 import (
+	"crypto/tls"
+	"expvar"
 	"flag"
 	"github.com/onosproject/onos-topo/pkg/manager"
 	"github.com/onosproject/onos-topo/pkg/northbound"
 	"github.com/onosproject/onos-topo/pkg/northbound/admin"
 	"github.com/onosproject/onos-topo/pkg/northbound/device"
 	"github.com/onosproject/onos-topo/pkg/northbound/diags"
+	"github.com/onosproject/onos-topo/pkg/trace"
 	log "k8s.io/klog"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 )
 
+// storeTypeEnv is the environment variable from which the default device store type is read
+const storeTypeEnv = "ONOS_TOPO_STORE"
+
+// mutationMethods are the device service methods that write to the store, whose combined rate is limited
+// by -rateLimit
+var mutationMethods = []string{
+	"/topo.device.DeviceService/Add",
+	"/topo.device.DeviceService/Update",
+	"/topo.device.DeviceService/UpdateMany",
+	"/topo.device.DeviceService/Remove",
+	"/topo.device.DeviceService/Rename",
+	"/topo.device.DeviceService/SwapAddresses",
+	"/topo.device.DeviceService/RotateCredentials",
+	"/topo.device.DeviceService/CompareAndSwapField",
+	"/topo.device.DeviceService/ClaimDevice",
+	"/topo.device.DeviceService/ReleaseDevice",
+	"/topo.device.DeviceService/SetAnnotations",
+	"/topo.device.DeviceService/AddGroup",
+	"/topo.device.DeviceService/UpdateGroup",
+	"/topo.device.DeviceService/RemoveGroup",
+	"/topo.device.DeviceService/EnsureDevice",
+	"/topo.device.DeviceService/CloneDevice",
+	"/topo.device.DeviceService/ReportState",
+}
+
 // The main entry point
 func main() {
 	caPath := flag.String("caPath", "", "path to CA certificate")
 	keyPath := flag.String("keyPath", "", "path to client private key")
 	certPath := flag.String("certPath", "", "path to client certificate")
+	storeType := flag.String("store", getStoreType(), "type of the device store (atomix or local)")
+	readOnly := flag.Bool("readOnly", false, "run the server as a read-only replica")
+	gateway := flag.Bool("gateway", false, "serve the HTTP/JSON gateway")
+	allowForceUpdates := flag.Bool("allowForceUpdates", false, "allow forced device updates that ignore the device version")
+	allowSecretAccess := flag.Bool("allowSecretAccess", false, "allow clients that present a verified client certificate to request device secrets")
+	dedupeWrites := flag.Bool("dedupeWrites", false, "skip device updates that don't change the device")
+	idPattern := flag.String("idPattern", device.DefaultIDPattern.String(), "regular expression to which added device IDs must conform")
+	historySize := flag.Int("historySize", 0, "number of recent events recorded for each device, or 0 to disable the history log")
+	historyAge := flag.Duration("historyAge", 24*time.Hour, "maximum age of recorded device events, or 0 to retain events indefinitely")
+	rateLimit := flag.Float64("rateLimit", 0, "maximum combined rate of device mutations per second across all mutating methods, or 0 for no limit")
+	rateBurst := flag.Int("rateBurst", 100, "maximum burst of device mutations across all mutating methods")
+	requestLogSampleRate := flag.Float64("requestLogSampleRate", 0, "fraction of requests that are logged, or 0 to disable request logging")
+	requestLogErrors := flag.Bool("requestLogErrors", false, "log failed requests regardless of the sample rate")
+	maxDevices := flag.Int("maxDevices", 0, "maximum number of devices, or 0 for no limit")
+	natsAddress := flag.String("natsAddress", "", "address of a NATS server to which device events are published")
+	natsSubject := flag.String("natsSubject", "topo.device.events", "NATS subject to which device events are published")
+	natsTLS := flag.Bool("natsTLS", false, "connect to the NATS server with TLS")
+	natsUser := flag.String("natsUser", "", "user with which to authenticate to the NATS server")
+	natsPassword := flag.String("natsPassword", "", "password with which to authenticate to the NATS server")
+	natsToken := flag.String("natsToken", "", "token with which to authenticate to the NATS server")
+	eventFormat := flag.String("eventFormat", string(device.SinkFormatJSON), "format of published device events (json or proto)")
+	allowedNetworks := flag.String("allowedNetworks", "", "comma-separated list of CIDRs to which device addresses must belong")
+	requiredLabels := flag.String("requiredLabels", "", "comma-separated list of labels that devices must have")
+	strictValidation := flag.Bool("strictValidation", false, "require coherent TLS configurations and semantic software versions")
+	conflictRetries := flag.Int("conflictRetries", 3, "number of times a field mask update is retried when the device is concurrently modified")
+	compressionThreshold := flag.Int("compressionThreshold", 0, "size in bytes above which stored devices are compressed, or 0 to disable compression")
+	maxDeviceSize := flag.Int("maxDeviceSize", 0, "maximum size in bytes of a stored device after compression, or 0 for no limit")
+	listWorkers := flag.Int("listWorkers", 1, "number of workers that concurrently decode devices listed from the Atomix store")
+	listBufferSize := flag.Int("listBufferSize", 0, "number of listed Atomix map entries buffered ahead of the decoding workers")
+	localStorePath := flag.String("localStorePath", "", "file to which the local store persists devices, or empty to keep devices in memory")
+	atomixGroups := flag.String("atomixGroups", "", "comma-separated list of Atomix groups across which devices are sharded")
+	defaultDeadline := flag.Duration("defaultDeadline", 30*time.Second, "deadline applied to requests sent without a deadline, or 0 for no deadline")
+	maxRecvMsgSize := flag.Int("maxRecvMsgSize", 0, "maximum size in bytes of a request message, or 0 for the gRPC default")
+	maxSendMsgSize := flag.Int("maxSendMsgSize", 0, "maximum size in bytes of a response message, or 0 for the gRPC default")
+	deviceStatsSize := flag.Int("deviceStatsSize", 0, "number of most accessed devices for which read and write counts are collected, or 0 to disable device statistics")
+	maxDeadline := flag.Duration("maxDeadline", 5*time.Minute, "maximum deadline of requests, or 0 for no maximum")
+	labelIndex := flag.Bool("labelIndex", false, "maintain an in-memory index of device labels for label selector queries")
+	uniqueSerialNumbers := flag.Bool("uniqueSerialNumbers", false, "reject devices with the serial number of another device")
+	uniqueAddresses := flag.Bool("uniqueAddresses", false, "reject devices with the address of another device unless the request allows duplicates")
+	subscriberMetrics := flag.Bool("subscriberMetrics", false, "publish the device subscriber count and send lag metrics")
+	traceLog := flag.Bool("traceLog", false, "trace RPCs and store operations and log each span")
+	evictionThreshold := flag.Int("evictionThreshold", 0, "number of events a device subscriber may fall behind before it's disconnected, or 0 to drop its oldest events instead")
 
 	//lines 93-109 are implemented according to
 	// https://github.com/kubernetes/klog/blob/master/examples/coexist_glog/coexist_glog.go
@@ -73,25 +231,181 @@ func main() {
 		log.Fatal("Unable to load onos-topo ", err)
 	} else {
 		mgr.Run()
-		err = startServer(*caPath, *keyPath, *certPath)
+		var limiter *northbound.RateLimiter
+		if *rateLimit > 0 {
+			limiter = northbound.NewRateLimiter(northbound.WithSharedRateLimit(mutationMethods, *rateLimit, *rateBurst))
+		}
+		var requestLogger *northbound.RequestLogger
+		if *requestLogSampleRate > 0 || *requestLogErrors {
+			requestLogger = northbound.NewRequestLogger(
+				northbound.WithLogSampler(northbound.NewRateSampler(*requestLogSampleRate)),
+				northbound.WithLogErrors(*requestLogErrors),
+				northbound.WithLogDeviceID(device.RequestDeviceID))
+		}
+		deadlines := northbound.NewDeadlinePolicy(
+			northbound.WithDefaultDeadline(*defaultDeadline),
+			northbound.WithMaxDeadline(*maxDeadline))
+		pattern, err := regexp.Compile(*idPattern)
+		if err != nil {
+			log.Fatal("Invalid device ID pattern ", err)
+		}
+		var atomixGroupNames []string
+		if *atomixGroups != "" {
+			for _, name := range strings.Split(*atomixGroups, ",") {
+				atomixGroupNames = append(atomixGroupNames, strings.TrimSpace(name))
+			}
+		}
+		store, err := device.NewStore(device.StoreType(*storeType), device.NewKlogLogger(), device.StoreConfig{
+			CompressionThreshold: *compressionThreshold,
+			ListWorkers:          *listWorkers,
+			ListBufferSize:       *listBufferSize,
+			LocalPath:            *localStorePath,
+			AtomixGroups:         atomixGroupNames,
+			MaxValueSize:         *maxDeviceSize,
+		})
+		if err != nil {
+			log.Fatal("Unable to create device store ", err)
+		}
+		deviceOpts := []device.ServiceOption{
+			device.WithStore(store),
+			device.WithReadOnly(*readOnly),
+			device.WithForceUpdates(*allowForceUpdates),
+			device.WithSecretAccess(*allowSecretAccess),
+			device.WithWriteDeduplication(*dedupeWrites),
+			device.WithHistory(*historySize, *historyAge),
+			device.WithIDPattern(pattern),
+			device.WithMaxDevices(*maxDevices),
+			device.WithConflictRetries(*conflictRetries),
+			device.WithDeviceStats(*deviceStatsSize),
+			device.WithSubscriberEviction(*evictionThreshold),
+			device.WithLabelIndex(*labelIndex),
+			device.WithUniqueSerialNumbers(*uniqueSerialNumbers),
+			device.WithUniqueAddresses(*uniqueAddresses),
+		}
+		if *subscriberMetrics {
+			deviceOpts = append(deviceOpts, device.WithSubscriberMetrics(expvar.NewMap("topo_device_subscriber_metrics")))
+		}
+		var tracer trace.Tracer
+		if *traceLog {
+			tracer = trace.NewRecordingTracer(logSpan)
+			deviceOpts = append(deviceOpts, device.WithTracer(tracer))
+		}
+		if *natsAddress != "" {
+			var natsOpts []device.NATSSinkOption
+			if *natsTLS {
+				natsOpts = append(natsOpts, device.WithNATSTLS(&tls.Config{}))
+			}
+			if *natsUser != "" {
+				natsOpts = append(natsOpts, device.WithNATSUserInfo(*natsUser, *natsPassword))
+			}
+			if *natsToken != "" {
+				natsOpts = append(natsOpts, device.WithNATSToken(*natsToken))
+			}
+			sink, err := device.NewNATSSink(*natsAddress, *natsSubject, device.SinkFormat(*eventFormat), natsOpts...)
+			if err != nil {
+				log.Fatal("Unable to connect to NATS ", err)
+			}
+			deviceOpts = append(deviceOpts, device.WithEventSink(sink))
+		}
+		if *allowedNetworks != "" {
+			var networks []*net.IPNet
+			for _, cidr := range strings.Split(*allowedNetworks, ",") {
+				_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+				if err != nil {
+					log.Fatal("Invalid allowed network ", err)
+				}
+				networks = append(networks, network)
+			}
+			deviceOpts = append(deviceOpts, device.WithValidators(device.AllowedNetworksValidator(networks...)))
+		}
+		if *requiredLabels != "" {
+			deviceOpts = append(deviceOpts, device.WithValidators(device.RequiredLabelsValidator(strings.Split(*requiredLabels, ",")...)))
+		}
+		if *strictValidation {
+			deviceOpts = append(deviceOpts, device.WithValidators(device.TLSValidator(), device.SemanticVersionValidator()))
+		}
+		serverOpts := []northbound.ServerOption{
+			northbound.WithMaxRecvMsgSize(*maxRecvMsgSize),
+			northbound.WithMaxSendMsgSize(*maxSendMsgSize),
+		}
+		err = startServer(*caPath, *keyPath, *certPath, *gateway, tracer, limiter, requestLogger, deadlines, serverOpts, deviceOpts...)
 		if err != nil {
 			log.Fatal("Unable to start onos-topo ", err)
 		}
 	}
 }
 
-// Creates gRPC server and registers various services; then serves.
-func startServer(caPath string, keyPath string, certPath string) error {
-	s := northbound.NewServer(northbound.NewServerConfig(caPath, keyPath, certPath))
-	s.AddService(admin.Service{})
-	s.AddService(diags.Service{})
+// getStoreType returns the default device store type from the environment
+func getStoreType() string {
+	if storeType := os.Getenv(storeTypeEnv); storeType != "" {
+		return storeType
+	}
+	return string(device.StoreTypeAtomix)
+}
+
+// logSpan logs a completed span
+func logSpan(span trace.SpanData) {
+	parent := ""
+	if span.HasParent {
+		parent = span.Parent.String()
+	}
+	if span.Err != nil {
+		log.Infof("Span %s %s parent=%s duration=%s attributes=%v error=%v", span.Name, span.Context, parent, span.Duration, span.Attributes, span.Err)
+	} else {
+		log.Infof("Span %s %s parent=%s duration=%s attributes=%v", span.Name, span.Context, parent, span.Duration, span.Attributes)
+	}
+}
 
-	deviceService, err := device.NewService()
+// Creates gRPC server and registers various services; then serves.
+func startServer(caPath string, keyPath string, certPath string, gateway bool, tracer trace.Tracer, limiter *northbound.RateLimiter, requestLogger *northbound.RequestLogger, deadlines *northbound.DeadlinePolicy, serverOpts []northbound.ServerOption, deviceOpts ...device.ServiceOption) error {
+	s := northbound.NewServer(northbound.NewServerConfig(caPath, keyPath, certPath), serverOpts...)
+	if tracer != nil {
+		s.AddInterceptor(northbound.TracingUnaryInterceptor(tracer))
+		s.AddStreamInterceptor(northbound.TracingStreamInterceptor(tracer))
+	}
+	if requestLogger != nil {
+		s.AddInterceptor(requestLogger.UnaryInterceptor())
+		s.AddStreamInterceptor(requestLogger.StreamInterceptor())
+	}
+	s.AddInterceptor(northbound.PayloadSizeUnaryInterceptor())
+	s.AddStreamInterceptor(northbound.PayloadSizeStreamInterceptor())
+	if limiter != nil {
+		s.AddInterceptor(limiter.UnaryInterceptor())
+	}
+	s.AddInterceptor(deadlines.UnaryInterceptor())
+	deviceService, err := device.NewService(deviceOpts...)
 	if err != nil {
 		return err
 	}
+
+	var adminOpts []admin.ServiceOption
+	if compactor, ok := deviceService.(admin.Compactor); ok {
+		adminOpts = append(adminOpts, admin.WithCompactor(compactor))
+	}
+	if clearer, ok := deviceService.(admin.Clearer); ok {
+		adminOpts = append(adminOpts, admin.WithClearer(clearer))
+	}
+	if provider, ok := deviceService.(admin.StatsProvider); ok {
+		adminOpts = append(adminOpts, admin.WithStatsProvider(provider))
+	}
+	if snapshotter, ok := deviceService.(admin.Snapshotter); ok {
+		adminOpts = append(adminOpts, admin.WithSnapshotter(snapshotter))
+	}
+	s.AddService(admin.NewService(adminOpts...))
+	s.AddService(diags.Service{})
 	s.AddService(deviceService)
 
+	if gateway {
+		go func() {
+			err := s.ServeHTTP(func(started string) {
+				log.Info("Started HTTP gateway on ", started)
+			})
+			if err != nil {
+				log.Error("Unable to start HTTP gateway ", err)
+			}
+		}()
+	}
+
 	return s.Serve(func(started string) {
 		log.Info("Started NBI on ", started)
 	})
@@ -0,0 +1,80 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-topo/pkg/northbound/device"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func getGetCapabilitiesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Args:  cobra.NoArgs,
+		Short: "Get the features supported by the topo service",
+		Run:   runGetCapabilitiesCommand,
+	}
+	return cmd
+}
+
+func runGetCapabilitiesCommand(cmd *cobra.Command, args []string) {
+	capabilities := getCapabilities()
+	features := capabilities.Features
+
+	writer := new(tabwriter.Writer)
+	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	fmt.Fprintln(writer, fmt.Sprintf("LABELS\t%t", features.Labels))
+	fmt.Fprintln(writer, fmt.Sprintf("LINKS\t%t", features.Links))
+	fmt.Fprintln(writer, fmt.Sprintf("SOFT DELETE\t%t", features.SoftDelete))
+	fmt.Fprintln(writer, fmt.Sprintf("OWNERSHIP\t%t", features.Ownership))
+	fmt.Fprintln(writer, fmt.Sprintf("ANNOTATIONS\t%t", features.Annotations))
+	fmt.Fprintln(writer, fmt.Sprintf("FIELD MASKS\t%t", features.FieldMasks))
+	fmt.Fprintln(writer, fmt.Sprintf("HEARTBEATS\t%t", features.Heartbeats))
+	fmt.Fprintln(writer, fmt.Sprintf("HISTORY\t%t", features.History))
+	fmt.Fprintln(writer, fmt.Sprintf("WRITABLE\t%t", features.Writable))
+	fmt.Fprintln(writer, fmt.Sprintf("FORCE UPDATES\t%t", features.ForceUpdates))
+	fmt.Fprintln(writer, fmt.Sprintf("SECRET ACCESS\t%t", features.SecretAccess))
+	fmt.Fprintln(writer, fmt.Sprintf("GROUPS\t%t", features.Groups))
+	fmt.Fprintln(writer, fmt.Sprintf("DEVICE FIELDS\t%s", strings.Join(capabilities.DeviceFields, ",")))
+	writer.Flush()
+}
+
+// getCapabilities gets the capabilities of the topo service
+// Servers that predate GetCapabilities report no optional features.
+func getCapabilities() *device.GetCapabilitiesResponse {
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	response, err := client.GetCapabilities(ctx, &device.GetCapabilitiesRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return &device.GetCapabilitiesResponse{
+			Features: &device.Features{},
+		}
+	} else if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	return response
+}
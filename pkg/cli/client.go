@@ -15,10 +15,14 @@
 package cli
 
 import (
+	"context"
 	"crypto/tls"
 	"github.com/onosproject/onos-topo/pkg/certs"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 const (
@@ -27,9 +31,12 @@ const (
 
 // getConnection returns a gRPC client connection to the topo service
 func getConnection() *grpc.ClientConn {
-	address := getConfigOrDefault("address", defaultAddress).(string)
-	certPath := getConfigString("tls.certPath")
-	keyPath := getConfigString("tls.keyPath")
+	address := getFlagOrConfig(addressFlag, "address")
+	if address == "" {
+		address = defaultAddress
+	}
+	certPath := getFlagOrConfig(certPathFlag, "tls.certPath")
+	keyPath := getFlagOrConfig(keyPathFlag, "tls.keyPath")
 	var opts []grpc.DialOption
 	if certPath != "" && keyPath != "" {
 		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
@@ -62,3 +69,23 @@ func getConnection() *grpc.ClientConn {
 	}
 	return conn
 }
+
+// interruptibleContext returns a context that's canceled when the process is interrupted with SIGINT or SIGTERM
+// The returned function cancels the context and stops relaying signals to it, after which interrupts terminate
+// the process as usual.
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
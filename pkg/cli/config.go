@@ -20,8 +20,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"os"
+	"strings"
 )
 
+// envPrefix is the prefix for environment variables that provide configuration defaults
+// Configuration keys are mapped to environment variables by upper-casing them and replacing
+// dots with underscores, e.g. tls.certPath is read from ONOS_TOPO_TLS_CERTPATH.
+const envPrefix = "onos_topo"
+
 // getConfigCommand returns a topo configuration command
 func getConfigCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -84,6 +90,14 @@ func getConfigString(key string) string {
 	return viper.GetString(key)
 }
 
+// getFlagOrConfig returns the given flag value if set, otherwise the configuration value for the given key
+func getFlagOrConfig(flag string, key string) string {
+	if flag != "" {
+		return flag
+	}
+	return getConfigString(key)
+}
+
 // getConfigOrDefault gets a configuration value or returns the default if the configuration is not set
 func getConfigOrDefault(key string, def interface{}) interface{} {
 	value := getConfig(key)
@@ -131,6 +145,10 @@ func init() {
 	viper.AddConfigPath("/etc/onos")
 	viper.AddConfigPath(".")
 
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
 	// Read in the configuration and ignore the error if the configuration file is not found.
 	if err = viper.ReadInConfig(); err != nil {
 		if _, ok := err.(*viper.ConfigFileNotFoundError); ok {
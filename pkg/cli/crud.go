@@ -18,37 +18,60 @@ import "github.com/spf13/cobra"
 
 func getGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get {device} [args]",
+		Use:   "get {device,group,capabilities} [args]",
 		Short: "Get topology resources",
 	}
 	cmd.AddCommand(getGetDeviceCommand())
+	cmd.AddCommand(getGetGroupCommand())
+	cmd.AddCommand(getGetCapabilitiesCommand())
 	return cmd
 }
 
 func getAddCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "add {device} [args]",
+		Use:   "add {device,group} [args]",
 		Short: "Add a topology resource",
 	}
 	cmd.AddCommand(getAddDeviceCommand())
+	cmd.AddCommand(getAddGroupCommand())
 	return cmd
 }
 
 func getUpdateCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "update {device} [args]",
+		Use:   "update {device,group} [args]",
 		Short: "Update a topology resource",
 	}
 	cmd.AddCommand(getUpdateDeviceCommand())
+	cmd.AddCommand(getUpdateGroupCommand())
 	return cmd
 }
 
 func getRemoveCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "remove {device} [args]",
+		Use:   "remove {device,group} [args]",
 		Short: "Remove a topology resource",
 	}
 	cmd.AddCommand(getRemoveDeviceCommand())
+	cmd.AddCommand(getRemoveGroupCommand())
+	return cmd
+}
+
+func getRenameCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename {device} [args]",
+		Short: "Rename a topology resource",
+	}
+	cmd.AddCommand(getRenameDeviceCommand())
+	return cmd
+}
+
+func getCloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone {device} [args]",
+		Short: "Add a copy of a topology resource",
+	}
+	cmd.AddCommand(getCloneDeviceCommand())
 	return cmd
 }
 
@@ -60,3 +83,12 @@ func getWatchCommand() *cobra.Command {
 	cmd.AddCommand(getWatchDeviceCommand())
 	return cmd
 }
+
+func getDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe {device} [args]",
+		Short: "Describe a topology resource",
+	}
+	cmd.AddCommand(getDescribeDeviceCommand())
+	return cmd
+}
@@ -15,13 +15,24 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/onosproject/onos-topo/pkg/northbound/device"
 	"github.com/spf13/cobra"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
 	"io"
+	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
 	"time"
 )
@@ -36,12 +47,36 @@ func getGetDeviceCommand() *cobra.Command {
 	}
 	cmd.Flags().BoolP("verbose", "v", false, "whether to print the device with verbose output")
 	cmd.Flags().Bool("no-headers", false, "disables output headers")
+	cmd.Flags().BoolP("watch", "w", false, "after listing the devices, watch for changes")
+	cmd.Flags().Bool("no-color", false, "disables colored output when watching")
+	cmd.Flags().StringSlice("state", []string{}, "list only devices in the given connection states (CONNECTED, DISCONNECTED, or CONNECTION_UNKNOWN)")
+	cmd.Flags().Bool("show-secrets", false, "include device passwords in verbose output; requires the service to allow secret access")
+	cmd.Flags().Bool("exclude-quiesced", false, "exclude devices that are quiesced for maintenance")
+	cmd.Flags().StringP("selector", "l", "", "list only devices matching the label selector, e.g. pod=a,role=leaf")
+	cmd.Flags().String("vendor", "", "list only devices from the given vendor")
+	cmd.Flags().String("model", "", "list only devices of the given hardware model")
+	cmd.Flags().String("modified-since", "", "list only devices modified since the given RFC 3339 time or within the given duration, e.g. 1h; can't be combined with other filters")
 	return cmd
 }
 
 func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	showSecrets, _ := cmd.Flags().GetBool("show-secrets")
 	noHeaders, _ := cmd.Flags().GetBool("no-headers")
+	stateNames, _ := cmd.Flags().GetStringSlice("state")
+	states := make([]device.ConnectionState, 0, len(stateNames))
+	for _, name := range stateNames {
+		state, ok := device.ConnectionState_value[strings.ToUpper(name)]
+		if !ok {
+			ExitWithErrorMessage("Invalid connection state %s", name)
+		}
+		states = append(states, device.ConnectionState(state))
+	}
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		watchDevices(args, nil, verbose, noHeaders, useColor(noColor), false, false, false)
+		return
+	}
 
 	conn := getConnection()
 	defer conn.Close()
@@ -51,9 +86,54 @@ func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 	if len(args) == 0 {
-		stream, err := client.List(ctx, &device.ListRequest{})
-		if err != nil {
-			ExitWithError(ExitBadConnection, err)
+		// The non-verbose listing doesn't show credentials, so only the basic view of each device is requested
+		view := device.ListRequest_BASIC
+		if verbose {
+			view = device.ListRequest_FULL
+		}
+		excludeQuiesced, _ := cmd.Flags().GetBool("exclude-quiesced")
+		selector, _ := cmd.Flags().GetString("selector")
+		vendor, _ := cmd.Flags().GetString("vendor")
+		model, _ := cmd.Flags().GetString("model")
+		modifiedSince, _ := cmd.Flags().GetString("modified-since")
+
+		var stream interface {
+			Recv() (*device.ListResponse, error)
+		}
+		var err error
+		if modifiedSince != "" {
+			if len(states) > 0 || excludeQuiesced || selector != "" || vendor != "" || model != "" {
+				ExitWithErrorMessage("--modified-since can't be combined with other filters")
+			}
+			since, err := parseSince(modifiedSince)
+			if err != nil {
+				ExitWithErrorMessage("Invalid --modified-since %s", modifiedSince)
+			}
+			timestamp, err := ptypes.TimestampProto(since)
+			if err != nil {
+				ExitWithErrorMessage("Invalid --modified-since %s", modifiedSince)
+			}
+			stream, err = client.ListModifiedSince(ctx, &device.ListModifiedSinceRequest{
+				Since:          timestamp,
+				View:           view,
+				IncludeSecrets: showSecrets,
+			})
+			if err != nil {
+				ExitWithError(ExitBadConnection, err)
+			}
+		} else {
+			stream, err = client.List(ctx, &device.ListRequest{
+				States:          states,
+				View:            view,
+				IncludeSecrets:  showSecrets,
+				ExcludeQuiesced: excludeQuiesced,
+				Selector:        selector,
+				Vendor:          vendor,
+				Model:           model,
+			})
+			if err != nil {
+				ExitWithError(ExitBadConnection, err)
+			}
 		}
 
 		writer := new(tabwriter.Writer)
@@ -61,7 +141,7 @@ func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 
 		if !noHeaders {
 			if verbose {
-				fmt.Fprintln(writer, "ID\tADDRESS\tVERSION\tUSER\tPASSWORD")
+				fmt.Fprintln(writer, "ID\tADDRESS\tVERSION\tVENDOR\tMODEL\tSERIAL NUMBER\tUSER\tPASSWORD\tLOCATION")
 			} else {
 				fmt.Fprintln(writer, "ID\tADDRESS\tVERSION")
 			}
@@ -77,7 +157,7 @@ func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 
 			dvc := response.Device
 			if verbose {
-				fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", dvc.Id, dvc.Address, dvc.SoftwareVersion, dvc.Credentials.User, dvc.Credentials.Password))
+				fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s", dvc.Id, dvc.Address, dvc.SoftwareVersion, dvc.Vendor, dvc.Model, dvc.SerialNumber, dvc.Credentials.EffectiveUser(), dvc.Credentials.EffectivePassword(), formatLocation(dvc.Location)))
 			} else {
 				fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s", dvc.Id, dvc.Address, dvc.SoftwareVersion))
 			}
@@ -85,7 +165,8 @@ func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 		writer.Flush()
 	} else {
 		response, err := client.Get(ctx, &device.GetRequest{
-			DeviceId: args[0],
+			DeviceId:       args[0],
+			IncludeSecrets: showSecrets,
 		})
 		if err != nil {
 			ExitWithError(ExitBadConnection, err)
@@ -98,15 +179,261 @@ func runGetDeviceCommand(cmd *cobra.Command, args []string) {
 		fmt.Fprintln(writer, fmt.Sprintf("ID\t%s", dvc.Id))
 		fmt.Fprintln(writer, fmt.Sprintf("ADDRESS\t%s", dvc.Address))
 		fmt.Fprintln(writer, fmt.Sprintf("VERSION\t%s", dvc.SoftwareVersion))
+		if dvc.Location != nil {
+			fmt.Fprintln(writer, fmt.Sprintf("LOCATION\t%s", formatLocation(dvc.Location)))
+		}
+		if dvc.Vendor != "" {
+			fmt.Fprintln(writer, fmt.Sprintf("VENDOR\t%s", dvc.Vendor))
+		}
+		if dvc.Model != "" {
+			fmt.Fprintln(writer, fmt.Sprintf("MODEL\t%s", dvc.Model))
+		}
+		if dvc.SerialNumber != "" {
+			fmt.Fprintln(writer, fmt.Sprintf("SERIAL NUMBER\t%s", dvc.SerialNumber))
+		}
 
 		if verbose {
-			fmt.Fprintln(writer, fmt.Sprintf("USER\t%s", dvc.Credentials.User))
-			fmt.Fprintln(writer, fmt.Sprintf("PASSWORD\t%s", dvc.Credentials.Password))
+			fmt.Fprintln(writer, fmt.Sprintf("USER\t%s", dvc.Credentials.EffectiveUser()))
+			fmt.Fprintln(writer, fmt.Sprintf("PASSWORD\t%s", dvc.Credentials.EffectivePassword()))
 		}
 		writer.Flush()
 	}
 }
 
+func getDescribeDeviceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "device <id>",
+		Aliases: []string{"devices"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "Describe a device and its related resources",
+		Run:     runDescribeDeviceCommand,
+	}
+	cmd.Flags().BoolP("verbose", "v", false, "whether to print the device with verbose output")
+	cmd.Flags().StringP("output", "o", "", "the output format (json); defaults to the configured output")
+	return cmd
+}
+
+func runDescribeDeviceCommand(cmd *cobra.Command, args []string) {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	output, _ := cmd.Flags().GetString("output")
+	if !cmd.Flags().Changed("output") {
+		output = getConfigString("output")
+	}
+	if output != "" && output != "json" {
+		ExitWithErrorMessage("Invalid output format %s", output)
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	response, err := client.Get(ctx, &device.GetRequest{
+		DeviceId: args[0],
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	dvc := response.Device
+
+	var parent *device.Device
+	if dvc.ParentId != "" {
+		parentResponse, err := client.Get(ctx, &device.GetRequest{
+			DeviceId: dvc.ParentId,
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			ExitWithError(ExitBadConnection, err)
+		} else if err == nil {
+			parent = parentResponse.Device
+		}
+	}
+
+	childrenResponse, err := client.ListChildren(ctx, &device.ListChildrenRequest{
+		ParentId: dvc.Id,
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	children := childrenResponse.Devices
+
+	if output == "json" {
+		printDeviceDescriptionJSON(dvc, parent, children)
+	} else {
+		printDeviceDescription(dvc, parent, children, verbose)
+	}
+}
+
+// printDeviceDescription prints a sectioned summary of the given device and its related devices
+func printDeviceDescription(dvc *device.Device, parent *device.Device, children []*device.Device, verbose bool) {
+	writer := new(tabwriter.Writer)
+	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+
+	fmt.Fprintln(writer, fmt.Sprintf("ID:\t%s", dvc.Id))
+	fmt.Fprintln(writer, fmt.Sprintf("ADDRESS:\t%s", dvc.Address))
+	fmt.Fprintln(writer, fmt.Sprintf("TARGET:\t%s", dvc.Target))
+	fmt.Fprintln(writer, fmt.Sprintf("VERSION:\t%s", dvc.SoftwareVersion))
+	if dvc.Vendor != "" {
+		fmt.Fprintln(writer, fmt.Sprintf("VENDOR:\t%s", dvc.Vendor))
+	}
+	if dvc.Model != "" {
+		fmt.Fprintln(writer, fmt.Sprintf("MODEL:\t%s", dvc.Model))
+	}
+	if dvc.SerialNumber != "" {
+		fmt.Fprintln(writer, fmt.Sprintf("SERIAL NUMBER:\t%s", dvc.SerialNumber))
+	}
+	if dvc.Metadata != nil {
+		fmt.Fprintln(writer, fmt.Sprintf("REVISION:\t%d", dvc.Metadata.Version))
+	}
+	if dvc.Updated != nil {
+		if timestamp, err := ptypes.Timestamp(dvc.Updated); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("UPDATED:\t%s", timestamp.Format(time.RFC3339)))
+		}
+	}
+	if dvc.Timeout != nil {
+		if timeout, err := ptypes.Duration(dvc.Timeout); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("TIMEOUT:\t%s", timeout))
+		}
+	}
+
+	if dvc.LifecycleStatus != nil {
+		fmt.Fprintln(writer, "LIFECYCLE:")
+		fmt.Fprintln(writer, fmt.Sprintf("  PHASE:\t%s", dvc.LifecycleStatus.Phase))
+		fmt.Fprintln(writer, fmt.Sprintf("  REASON:\t%s", dvc.LifecycleStatus.Reason))
+		if timestamp, err := ptypes.Timestamp(dvc.LifecycleStatus.Timestamp); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("  SINCE:\t%s", timestamp.Format(time.RFC3339)))
+		}
+	}
+
+	if dvc.ConnectionStatus != nil {
+		fmt.Fprintln(writer, "CONNECTION:")
+		fmt.Fprintln(writer, fmt.Sprintf("  STATE:\t%s", dvc.ConnectionStatus.State))
+		fmt.Fprintln(writer, fmt.Sprintf("  REASON:\t%s", dvc.ConnectionStatus.Reason))
+		if timestamp, err := ptypes.Timestamp(dvc.ConnectionStatus.Timestamp); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("  SINCE:\t%s", timestamp.Format(time.RFC3339)))
+		}
+		if dvc.ConnectionStatus.Reporter != "" {
+			fmt.Fprintln(writer, fmt.Sprintf("  REPORTER:\t%s", dvc.ConnectionStatus.Reporter))
+		}
+	}
+
+	if dvc.IsQuiesced() {
+		fmt.Fprintln(writer, "MAINTENANCE:")
+		fmt.Fprintln(writer, fmt.Sprintf("  REASON:\t%s", dvc.Maintenance.Reason))
+		if timestamp, err := ptypes.Timestamp(dvc.Maintenance.Since); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("  SINCE:\t%s", timestamp.Format(time.RFC3339)))
+		}
+	}
+
+	if dvc.Owner != nil {
+		fmt.Fprintln(writer, "OWNER:")
+		fmt.Fprintln(writer, fmt.Sprintf("  ID:\t%s", dvc.Owner.Id))
+		if timestamp, err := ptypes.Timestamp(dvc.Owner.Expiry); err == nil {
+			fmt.Fprintln(writer, fmt.Sprintf("  EXPIRES:\t%s", timestamp.Format(time.RFC3339)))
+		}
+	}
+
+	if len(dvc.Labels) > 0 {
+		fmt.Fprintln(writer, "LABELS:")
+		keys := make([]string, 0, len(dvc.Labels))
+		for key := range dvc.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintln(writer, fmt.Sprintf("  %s:\t%s", key, dvc.Labels[key]))
+		}
+	}
+
+	if dvc.Location != nil {
+		fmt.Fprintln(writer, "LOCATION:")
+		fmt.Fprintln(writer, fmt.Sprintf("  LATITUDE:\t%g", dvc.Location.Lat))
+		fmt.Fprintln(writer, fmt.Sprintf("  LONGITUDE:\t%g", dvc.Location.Lng))
+		fmt.Fprintln(writer, fmt.Sprintf("  ALTITUDE:\t%gm", dvc.Location.Altitude))
+	}
+
+	if dvc.Credentials != nil {
+		fmt.Fprintln(writer, "CREDENTIALS:")
+		fmt.Fprintln(writer, fmt.Sprintf("  USER:\t%s", dvc.Credentials.EffectiveUser()))
+		if verbose {
+			fmt.Fprintln(writer, fmt.Sprintf("  PASSWORD:\t%s", dvc.Credentials.EffectivePassword()))
+		}
+	}
+
+	if dvc.Tls != nil {
+		fmt.Fprintln(writer, "TLS:")
+		fmt.Fprintln(writer, fmt.Sprintf("  CA CERT:\t%s", dvc.Tls.CaCert))
+		fmt.Fprintln(writer, fmt.Sprintf("  CERT:\t%s", dvc.Tls.Cert))
+		fmt.Fprintln(writer, fmt.Sprintf("  KEY:\t%s", dvc.Tls.Key))
+		fmt.Fprintln(writer, fmt.Sprintf("  PLAIN:\t%t", dvc.Tls.Plain))
+		fmt.Fprintln(writer, fmt.Sprintf("  INSECURE:\t%t", dvc.Tls.Insecure))
+	}
+
+	fmt.Fprintln(writer, "PROTOCOLS:")
+	if len(dvc.Protocols) == 0 {
+		fmt.Fprintln(writer, "  <none>")
+	}
+	for _, protocol := range dvc.Protocols {
+		fmt.Fprintln(writer, fmt.Sprintf("  %s\t%d bytes of configuration", protocol.Type, len(protocol.Config)))
+	}
+
+	fmt.Fprintln(writer, "PARENT:")
+	if parent != nil {
+		fmt.Fprintln(writer, fmt.Sprintf("  %s\t%s", parent.Id, parent.Address))
+	} else if dvc.ParentId != "" {
+		fmt.Fprintln(writer, fmt.Sprintf("  %s\t<not found>", dvc.ParentId))
+	} else {
+		fmt.Fprintln(writer, "  <none>")
+	}
+
+	fmt.Fprintln(writer, "CHILDREN:")
+	if len(children) == 0 {
+		fmt.Fprintln(writer, "  <none>")
+	}
+	for _, child := range children {
+		fmt.Fprintln(writer, fmt.Sprintf("  %s\t%s", child.Id, child.Address))
+	}
+	writer.Flush()
+}
+
+// deviceDescription is the JSON representation of a described device
+type deviceDescription struct {
+	Device   json.RawMessage   `json:"device"`
+	Parent   json.RawMessage   `json:"parent,omitempty"`
+	Children []json.RawMessage `json:"children"`
+}
+
+// printDeviceDescriptionJSON prints the given device and its related devices as JSON
+func printDeviceDescriptionJSON(dvc *device.Device, parent *device.Device, children []*device.Device) {
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	marshal := func(d *device.Device) json.RawMessage {
+		value, err := marshaler.MarshalToString(d)
+		if err != nil {
+			ExitWithError(ExitError, err)
+		}
+		return json.RawMessage(value)
+	}
+
+	description := &deviceDescription{
+		Device:   marshal(dvc),
+		Children: make([]json.RawMessage, 0, len(children)),
+	}
+	if parent != nil {
+		description.Parent = marshal(parent)
+	}
+	for _, child := range children {
+		description.Children = append(description.Children, marshal(child))
+	}
+
+	bytes, err := json.MarshalIndent(description, "", "  ")
+	if err != nil {
+		ExitWithError(ExitError, err)
+	}
+	fmt.Fprintln(os.Stdout, string(bytes))
+}
+
 func getAddDeviceCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "device <id> [args]",
@@ -116,26 +443,43 @@ func getAddDeviceCommand() *cobra.Command {
 		Run:     runAddDeviceCommand,
 	}
 	cmd.Flags().StringP("address", "a", "", "the address of the device")
+	cmd.Flags().StringSlice("addresses", []string{}, "the failover addresses of the device")
 	cmd.Flags().StringP("user", "u", "", "the device username")
 	cmd.Flags().StringP("password", "p", "", "the device password")
+	cmd.Flags().Bool("password-stdin", false, "read the device password from stdin")
+	cmd.Flags().String("credentials-file", "", "the path to a YAML or JSON file containing the device user and password")
 	cmd.Flags().StringP("version", "v", "", "the device software version")
 	cmd.Flags().String("key", "", "the TLS key")
 	cmd.Flags().String("cert", "", "the TLS certificate")
 	cmd.Flags().String("ca-cert", "", "the TLS CA certificate")
 	cmd.Flags().DurationP("timeout", "t", 30*time.Second, "the device connection timeout")
+	cmd.Flags().StringToString("labels", map[string]string{}, "the device labels as key=value pairs")
+	cmd.Flags().String("serial-number", "", "the device hardware serial number")
+	cmd.Flags().String("model", "", "the device hardware model")
+	cmd.Flags().String("vendor", "", "the device hardware vendor")
+	cmd.Flags().Bool("if-not-exists", false, "succeed without changes if a device with the same ID already exists")
+	cmd.Flags().Bool("allow-duplicate-address", false, "add the device even if another device has the same address")
 	return cmd
 }
 
 func runAddDeviceCommand(cmd *cobra.Command, args []string) {
 	id := args[0]
 	address, _ := cmd.Flags().GetString("address")
-	user, _ := cmd.Flags().GetString("user")
-	password, _ := cmd.Flags().GetString("password")
+	addresses, _ := cmd.Flags().GetStringSlice("addresses")
 	version, _ := cmd.Flags().GetString("version")
 	key, _ := cmd.Flags().GetString("key")
 	cert, _ := cmd.Flags().GetString("cert")
 	caCert, _ := cmd.Flags().GetString("ca-cert")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	labels, _ := cmd.Flags().GetStringToString("labels")
+	serialNumber, _ := cmd.Flags().GetString("serial-number")
+	model, _ := cmd.Flags().GetString("model")
+	vendor, _ := cmd.Flags().GetString("vendor")
+
+	credentials := &device.Credentials{}
+	if err := loadCredentials(cmd, credentials); err != nil {
+		ExitWithError(ExitBadArgs, err)
+	}
 
 	conn := getConnection()
 	defer conn.Close()
@@ -145,24 +489,42 @@ func runAddDeviceCommand(cmd *cobra.Command, args []string) {
 	dvc := &device.Device{
 		Id:              id,
 		Address:         address,
+		Addresses:       addresses,
 		SoftwareVersion: version,
 		Timeout:         ptypes.DurationProto(timeout),
-		Credentials: &device.Credentials{
-			User:     user,
-			Password: password,
-		},
+		Credentials:     credentials,
 		Tls: &device.TlsConfig{
 			Cert:   cert,
 			Key:    key,
 			CaCert: caCert,
 		},
+		Labels:       labels,
+		SerialNumber: serialNumber,
+		Model:        model,
+		Vendor:       vendor,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
+	if ifNotExists, _ := cmd.Flags().GetBool("if-not-exists"); ifNotExists {
+		response, err := client.EnsureDevice(ctx, &device.EnsureDeviceRequest{
+			Device: dvc,
+		})
+		if err != nil {
+			ExitWithError(ExitBadConnection, err)
+		} else if !response.Created {
+			ExitWithOutput("Device %s already exists", id)
+		} else {
+			ExitWithOutput("Added device %s", id)
+		}
+		return
+	}
+
+	allowDuplicateAddress, _ := cmd.Flags().GetBool("allow-duplicate-address")
 	_, err := client.Add(ctx, &device.AddRequest{
-		Device: dvc,
+		Device:                dvc,
+		AllowDuplicateAddress: allowDuplicateAddress,
 	})
 	if err != nil {
 		ExitWithError(ExitBadConnection, err)
@@ -175,79 +537,146 @@ func getUpdateDeviceCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "device <id> [args]",
 		Aliases: []string{"devices"},
-		Args:    cobra.ExactArgs(1),
-		Short:   "Update a device",
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "Update a device, or all devices matching a label selector",
 		Run:     runUpdateDeviceCommand,
 	}
+	cmd.Flags().StringP("selector", "l", "", "update all devices matching the label selector instead of a single device")
 	cmd.Flags().StringP("address", "a", "", "the address of the device")
+	cmd.Flags().StringSlice("addresses", []string{}, "the failover addresses of the device")
 	cmd.Flags().StringP("user", "u", "", "the device username")
 	cmd.Flags().StringP("password", "p", "", "the device password")
+	cmd.Flags().Bool("password-stdin", false, "read the device password from stdin")
+	cmd.Flags().String("credentials-file", "", "the path to a YAML or JSON file containing the device user and password")
 	cmd.Flags().StringP("version", "v", "", "the device software version")
 	cmd.Flags().String("key", "", "the TLS key")
 	cmd.Flags().String("cert", "", "the TLS certificate")
 	cmd.Flags().String("ca-cert", "", "the TLS CA certificate")
 	cmd.Flags().DurationP("timeout", "t", 30*time.Second, "the device connection timeout")
+	cmd.Flags().StringToString("labels", map[string]string{}, "the device labels as key=value pairs")
+	cmd.Flags().String("serial-number", "", "the device hardware serial number")
+	cmd.Flags().String("model", "", "the device hardware model")
+	cmd.Flags().String("vendor", "", "the device hardware vendor")
+	cmd.Flags().Bool("quiesce", false, "quiesce the device for maintenance, or unquiesce it with --quiesce=false")
+	cmd.Flags().String("maintenance-reason", "", "the reason for quiescing the device")
+	cmd.Flags().Bool("allow-duplicate-address", false, "update the device even if another device has the same address")
 	return cmd
 }
 
 func runUpdateDeviceCommand(cmd *cobra.Command, args []string) {
-	id := args[0]
+	selector, _ := cmd.Flags().GetString("selector")
+	var id string
+	if len(args) > 0 {
+		id = args[0]
+	}
+	if !cmd.Flags().Changed("selector") && id == "" {
+		ExitWithErrorMessage("A device ID or --selector is required")
+	} else if cmd.Flags().Changed("selector") && id != "" {
+		ExitWithErrorMessage("A device ID cannot be combined with --selector")
+	}
+
+	credentials := &device.Credentials{}
+	if err := loadCredentials(cmd, credentials); err != nil {
+		ExitWithError(ExitBadArgs, err)
+	}
 
 	conn := getConnection()
 	defer conn.Close()
 
 	client := device.NewDeviceServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-
-	response, err := client.Get(ctx, &device.GetRequest{
-		DeviceId: id,
-	})
-	if err != nil {
-		ExitWithError(ExitBadConnection, err)
+	// Only the fields set by the flags are updated, so fields that aren't returned by the service, e.g. the
+	// device password, are not overwritten
+	dvc := &device.Device{
+		Id:          id,
+		Credentials: credentials,
+		Tls:         &device.TlsConfig{},
 	}
-
-	cancel()
-	dvc := response.Device
-
+	var paths []string
 	if cmd.Flags().Changed("address") {
-		address, _ := cmd.Flags().GetString("address")
-		dvc.Address = address
+		dvc.Address, _ = cmd.Flags().GetString("address")
+		paths = append(paths, "address")
 	}
-	if cmd.Flags().Changed("user") {
-		user, _ := cmd.Flags().GetString("user")
-		dvc.Credentials.User = user
+	if cmd.Flags().Changed("addresses") {
+		dvc.Addresses, _ = cmd.Flags().GetStringSlice("addresses")
+		paths = append(paths, "addresses")
+	}
+	if credentials.User != "" {
+		paths = append(paths, "credentials.user")
 	}
-	if cmd.Flags().Changed("password") {
-		password, _ := cmd.Flags().GetString("password")
-		dvc.Credentials.Password = password
+	if credentials.Password != "" {
+		paths = append(paths, "credentials.password")
 	}
 	if cmd.Flags().Changed("version") {
-		version, _ := cmd.Flags().GetString("version")
-		dvc.SoftwareVersion = version
+		dvc.SoftwareVersion, _ = cmd.Flags().GetString("version")
+		paths = append(paths, "software_version")
+	}
+	if cmd.Flags().Changed("labels") {
+		dvc.Labels, _ = cmd.Flags().GetStringToString("labels")
+		paths = append(paths, "labels")
+	}
+	if cmd.Flags().Changed("serial-number") {
+		dvc.SerialNumber, _ = cmd.Flags().GetString("serial-number")
+		paths = append(paths, "serial_number")
+	}
+	if cmd.Flags().Changed("model") {
+		dvc.Model, _ = cmd.Flags().GetString("model")
+		paths = append(paths, "model")
+	}
+	if cmd.Flags().Changed("vendor") {
+		dvc.Vendor, _ = cmd.Flags().GetString("vendor")
+		paths = append(paths, "vendor")
 	}
 	if cmd.Flags().Changed("key") {
-		key, _ := cmd.Flags().GetString("key")
-		dvc.Tls.Key = key
+		dvc.Tls.Key, _ = cmd.Flags().GetString("key")
+		paths = append(paths, "tls.key")
 	}
 	if cmd.Flags().Changed("cert") {
-		cert, _ := cmd.Flags().GetString("cert")
-		dvc.Tls.Cert = cert
+		dvc.Tls.Cert, _ = cmd.Flags().GetString("cert")
+		paths = append(paths, "tls.cert")
 	}
 	if cmd.Flags().Changed("ca-cert") {
-		caCert, _ := cmd.Flags().GetString("ca-cert")
-		dvc.Tls.CaCert = caCert
+		dvc.Tls.CaCert, _ = cmd.Flags().GetString("ca-cert")
+		paths = append(paths, "tls.caCert")
 	}
 	if cmd.Flags().Changed("timeout") {
 		timeout, _ := cmd.Flags().GetDuration("timeout")
 		dvc.Timeout = ptypes.DurationProto(timeout)
+		paths = append(paths, "timeout")
+	}
+	if cmd.Flags().Changed("quiesce") {
+		// The maintenance status is replaced as a whole, so unquiescing a device clears its reason
+		if quiesce, _ := cmd.Flags().GetBool("quiesce"); quiesce {
+			reason, _ := cmd.Flags().GetString("maintenance-reason")
+			dvc.Maintenance = &device.Maintenance{
+				Quiesced: true,
+				Reason:   reason,
+				Since:    ptypes.TimestampNow(),
+			}
+		}
+		paths = append(paths, "maintenance")
+	} else if cmd.Flags().Changed("maintenance-reason") {
+		ExitWithErrorMessage("--maintenance-reason requires --quiesce")
+	}
+	if len(paths) == 0 {
+		ExitWithErrorMessage("No device fields to update")
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	_, err = client.Update(ctx, &device.UpdateRequest{
+	if cmd.Flags().Changed("selector") {
+		updateSelectedDevices(ctx, client, selector, dvc, paths)
+		return
+	}
+
+	allowDuplicateAddress, _ := cmd.Flags().GetBool("allow-duplicate-address")
+	_, err := client.Update(ctx, &device.UpdateRequest{
 		Device: dvc,
+		UpdateMask: &field_mask.FieldMask{
+			Paths: paths,
+		},
+		AllowDuplicateAddress: allowDuplicateAddress,
 	})
 	if err != nil {
 		ExitWithError(ExitBadConnection, err)
@@ -256,17 +685,109 @@ func runUpdateDeviceCommand(cmd *cobra.Command, args []string) {
 	}
 }
 
+// updateSelectedDevices updates the given fields of all devices matching the given selector from the given
+// template, printing the result of each device and a summary
+func updateSelectedDevices(ctx context.Context, client device.DeviceServiceClient, selector string, template *device.Device, paths []string) {
+	response, err := client.UpdateMany(ctx, &device.UpdateManyRequest{
+		Selector:       selector,
+		DeviceTemplate: template,
+		UpdateMask: &field_mask.FieldMask{
+			Paths: paths,
+		},
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+
+	failed := 0
+	for _, result := range response.Results {
+		if codes.Code(result.Code) != codes.OK {
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed to update device %s: %s\n", result.DeviceId, result.Message)
+		} else {
+			Output("Updated device %s\n", result.DeviceId)
+		}
+	}
+	Output("%d updated, %d failed\n", len(response.Results)-failed, failed)
+	if failed > 0 {
+		os.Exit(ExitError)
+	}
+}
+
+// credentialsFile is the format of a device credentials file
+type credentialsFile struct {
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+}
+
+// loadCredentials reads the device credentials from the credentials file, stdin, and flags into the given credentials
+// Credentials given by flags or stdin take precedence over credentials read from the credentials file.
+func loadCredentials(cmd *cobra.Command, credentials *device.Credentials) error {
+	passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+	if passwordStdin && cmd.Flags().Changed("password") {
+		return errors.New("--password and --password-stdin are mutually exclusive")
+	}
+
+	if path, _ := cmd.Flags().GetString("credentials-file"); path != "" {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		// JSON is a subset of YAML, so both formats can be read with the YAML parser
+		file := &credentialsFile{}
+		if err := yaml.Unmarshal(bytes, file); err != nil {
+			return err
+		}
+		credentials.User = file.User
+		credentials.Password = file.Password
+	}
+
+	if cmd.Flags().Changed("user") {
+		credentials.User, _ = cmd.Flags().GetString("user")
+	}
+
+	if passwordStdin {
+		bytes, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		credentials.Password = strings.TrimRight(string(bytes), "\r\n")
+	} else if cmd.Flags().Changed("password") {
+		credentials.Password, _ = cmd.Flags().GetString("password")
+	}
+	return nil
+}
+
 func getRemoveDeviceCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "device <id> [args]",
 		Aliases: []string{"devices"},
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		Short:   "Remove a device",
-		Run:     runRemoveDeviceCommand,
+		Long: `Remove a device, or with --all, remove all devices matching a selector.
+
+The selector is a comma-separated list of label requirements of the form key=value, key!=value,
+key (the label is set), or !key (the label is not set). At least one of --selector or --target
+is required with --all to avoid removing every device by accident.`,
+		Run: runRemoveDeviceCommand,
 	}
+	cmd.Flags().Bool("all", false, "remove all devices matching the selector")
+	cmd.Flags().StringP("selector", "l", "", "the label selector of the devices to remove with --all")
+	cmd.Flags().String("target", "", "the target of the devices to remove with --all")
+	cmd.Flags().BoolP("yes", "y", false, "remove the matching devices without confirmation")
+	return cmd
 }
 
 func runRemoveDeviceCommand(cmd *cobra.Command, args []string) {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		if len(args) > 0 {
+			ExitWithErrorMessage("A device ID cannot be given with --all")
+		}
+		removeSelectedDevices(cmd)
+		return
+	} else if len(args) == 0 {
+		ExitWithErrorMessage("A device ID or --all is required")
+	}
 	id := args[0]
 
 	conn := getConnection()
@@ -289,73 +810,404 @@ func runRemoveDeviceCommand(cmd *cobra.Command, args []string) {
 	}
 }
 
+// removeSelectedDevices removes the devices matching the selector and target flags of the given command
+// The result of each removal is printed, and the command exits with an error if any device could not be
+// removed.
+func removeSelectedDevices(cmd *cobra.Command) {
+	selectorFlag, _ := cmd.Flags().GetString("selector")
+	target, _ := cmd.Flags().GetString("target")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	selector, err := device.ParseSelector(selectorFlag)
+	if err != nil {
+		ExitWithError(ExitBadArgs, err)
+	} else if selector.Empty() && target == "" {
+		ExitWithErrorMessage("A --selector or --target is required with --all")
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	devices, err := listSelectedDevices(client, selector, target)
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	} else if len(devices) == 0 {
+		ExitWithOutput("No matching devices")
+	}
+
+	if !yes {
+		for _, dvc := range devices {
+			Output("%s\n", dvc.Id)
+		}
+		if !confirm(fmt.Sprintf("Remove %d devices?", len(devices))) {
+			ExitWithOutput("Aborted")
+		}
+	}
+
+	failed := 0
+	for _, dvc := range devices {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		_, err := client.Remove(ctx, &device.RemoveRequest{
+			Device: dvc,
+		})
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed to remove device %s: %s\n", dvc.Id, err)
+		} else {
+			Output("Removed device %s\n", dvc.Id)
+		}
+	}
+
+	Output("%d removed, %d failed\n", len(devices)-failed, failed)
+	if failed > 0 {
+		os.Exit(ExitError)
+	}
+}
+
+// listSelectedDevices lists the devices matching the given selector and target
+// If the target is empty, devices with any target match.
+func listSelectedDevices(client device.DeviceServiceClient, selector device.Selector, target string) ([]*device.Device, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	stream, err := client.List(ctx, &device.ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []*device.Device
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if selector.Matches(response.Device) && (target == "" || response.Device.Target == target) {
+			devices = append(devices, response.Device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Id < devices[j].Id
+	})
+	return devices, nil
+}
+
+// confirm prompts the user to confirm the given question, returning whether the user answered yes
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stdout, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func getRenameDeviceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "device <id> <new-id>",
+		Aliases: []string{"devices"},
+		Args:    cobra.ExactArgs(2),
+		Short:   "Rename a device",
+		Run:     runRenameDeviceCommand,
+	}
+}
+
+func runRenameDeviceCommand(cmd *cobra.Command, args []string) {
+	id := args[0]
+	newID := args[1]
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := client.Rename(ctx, &device.RenameRequest{
+		DeviceId: id,
+		NewId:    newID,
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	} else {
+		ExitWithOutput("Renamed device %s to %s", id, newID)
+	}
+}
+
+func getCloneDeviceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "device <id> <new-id> [args]",
+		Aliases: []string{"devices"},
+		Args:    cobra.ExactArgs(2),
+		Short:   "Add a copy of a device with a new ID",
+		Run:     runCloneDeviceCommand,
+	}
+	cmd.Flags().StringP("address", "a", "", "the address of the copy")
+	cmd.Flags().StringP("version", "v", "", "the software version of the copy")
+	cmd.Flags().StringToString("labels", map[string]string{}, "the labels of the copy as key=value pairs")
+	cmd.Flags().String("serial-number", "", "the hardware serial number of the copy")
+	return cmd
+}
+
+func runCloneDeviceCommand(cmd *cobra.Command, args []string) {
+	id := args[0]
+	newID := args[1]
+
+	// Only the fields set by the flags override the fields of the source device
+	overrides := &device.Device{}
+	var paths []string
+	if cmd.Flags().Changed("address") {
+		overrides.Address, _ = cmd.Flags().GetString("address")
+		paths = append(paths, "address")
+	}
+	if cmd.Flags().Changed("version") {
+		overrides.SoftwareVersion, _ = cmd.Flags().GetString("version")
+		paths = append(paths, "software_version")
+	}
+	if cmd.Flags().Changed("labels") {
+		overrides.Labels, _ = cmd.Flags().GetStringToString("labels")
+		paths = append(paths, "labels")
+	}
+	if cmd.Flags().Changed("serial-number") {
+		overrides.SerialNumber, _ = cmd.Flags().GetString("serial-number")
+		paths = append(paths, "serial_number")
+	}
+	request := &device.CloneDeviceRequest{
+		SourceId: id,
+		NewId:    newID,
+	}
+	if len(paths) > 0 {
+		request.Overrides = overrides
+		request.UpdateMask = &field_mask.FieldMask{
+			Paths: paths,
+		}
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if _, err := client.CloneDevice(ctx, request); err != nil {
+		ExitWithError(ExitBadConnection, err)
+	} else {
+		ExitWithOutput("Cloned device %s to %s", id, newID)
+	}
+}
+
 func getWatchDeviceCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "device <id> [args]",
+		Use:     "device [<id>...] [args]",
 		Aliases: []string{"devices"},
-		Args:    cobra.MaximumNArgs(1),
+		Args:    cobra.ArbitraryArgs,
 		Short:   "Watch for device changes",
 		Run:     runWatchDeviceCommand,
 	}
 	cmd.Flags().BoolP("verbose", "v", false, "whether to print the device with verbose output")
 	cmd.Flags().Bool("no-headers", false, "disables output headers")
+	cmd.Flags().Bool("no-color", false, "disables colored output")
+	cmd.Flags().StringSlice("types", []string{}, "the event types to show (none, added, updated, removed)")
+	cmd.Flags().Bool("exit-on-sync", false, "exit once the current devices have been printed")
+	cmd.Flags().Bool("changes-only", false, "print only changes made after the watch starts, not the current devices")
+	cmd.Flags().StringP("output", "o", "", "the output format (jsonl for one JSON object per event)")
 	return cmd
 }
 
 func runWatchDeviceCommand(cmd *cobra.Command, args []string) {
-	var id string
-	if len(args) > 0 {
-		id = args[0]
-	}
-
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	noHeaders, _ := cmd.Flags().GetBool("no-headers")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	typeNames, _ := cmd.Flags().GetStringSlice("types")
+	exitOnSync, _ := cmd.Flags().GetBool("exit-on-sync")
+	changesOnly, _ := cmd.Flags().GetBool("changes-only")
+	output, _ := cmd.Flags().GetString("output")
+	if output != "" && output != "jsonl" {
+		ExitWithErrorMessage("Invalid output format %s", output)
+	} else if exitOnSync && changesOnly {
+		ExitWithErrorMessage("--exit-on-sync can't be combined with --changes-only")
+	}
 
+	types := make(map[device.ListResponse_Type]bool)
+	for _, name := range typeNames {
+		t, ok := device.ListResponse_Type_value[strings.ToUpper(name)]
+		if !ok {
+			ExitWithErrorMessage("Invalid event type %s", name)
+		}
+		types[device.ListResponse_Type(t)] = true
+	}
+	watchDevices(args, types, verbose, noHeaders, useColor(noColor), exitOnSync, changesOnly, output == "jsonl")
+}
+
+// watchDevices lists the current devices and then prints device events until the stream is closed or the
+// command is interrupted
+// If IDs are given, only the devices with those IDs are watched, and if types are given, only events of
+// those types are printed. If exitOnSync is true, the command exits once the current devices have been
+// printed. If skipReplay is true, the current devices are not printed and only subsequent changes are
+// printed. If jsonl is true, each event is printed as a single line of JSON without headers or colors.
+func watchDevices(ids []string, types map[device.ListResponse_Type]bool, verbose bool, noHeaders bool, color bool, exitOnSync bool, skipReplay bool, jsonl bool) {
 	conn := getConnection()
 	defer conn.Close()
 
 	client := device.NewDeviceServiceClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	// Interrupting the watch cancels the stream, and the expected cancellation error is not reported, so the
+	// connection is closed and the command exits successfully
+	ctx, cancel := interruptibleContext()
 	defer cancel()
 
 	stream, err := client.List(ctx, &device.ListRequest{
-		Subscribe: true,
+		Subscribe:  true,
+		ReplayDone: exitOnSync,
+		DeviceIds:  ids,
+		SkipReplay: skipReplay,
 	})
-	if err != nil {
+	if ctx.Err() != nil {
+		return
+	} else if err != nil {
 		ExitWithError(ExitBadConnection, err)
 	}
 
 	writer := new(tabwriter.Writer)
-	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	writer.Init(os.Stdout, 0, 0, watchColumnPadding, ' ', tabwriter.FilterHTML)
 
-	if !noHeaders {
+	// The time and event columns are padded to fixed widths and written ahead of the tabwriter, since the
+	// tabwriter would count color escape sequences toward the widths of its cells
+	timeWidth := len(time.Now().Format(time.RFC3339))
+	typeWidth := 0
+	for _, name := range device.ListResponse_Type_name {
+		if len(name) > typeWidth {
+			typeWidth = len(name)
+		}
+	}
+
+	if !noHeaders && !jsonl {
+		fmt.Fprint(os.Stdout, "TIME"+cellPadding("TIME", timeWidth)+"EVENT"+cellPadding("EVENT", typeWidth))
 		if verbose {
-			fmt.Fprintln(writer, "EVENT\tID\tADDRESS\tVERSION\tUSER\tPASSWORD")
+			fmt.Fprintln(writer, "ID\tADDRESS\tVERSION\tUSER\tPASSWORD")
 		} else {
-			fmt.Fprintln(writer, "EVENT\tID\tADDRESS\tVERSION")
+			fmt.Fprintln(writer, "ID\tADDRESS\tVERSION")
 		}
 		writer.Flush()
 	}
 
 	for {
 		response, err := stream.Recv()
-		if err == io.EOF {
-			ExitWithSuccess()
+		if err == io.EOF || ctx.Err() != nil {
+			return
 		} else if err != nil {
 			ExitWithError(ExitError, err)
 		}
 
+		if response.Type == device.ListResponse_REPLAY_DONE {
+			return
+		} else if response.Type == device.ListResponse_HEARTBEAT {
+			continue
+		}
+
 		device := response.Device
-		if id != "" && device.Id != id {
+		if len(types) > 0 && !types[response.Type] {
+			continue
+		}
+		if jsonl {
+			printEventJSONLine(response)
 			continue
 		}
 
+		timestamp := time.Now().Format(time.RFC3339)
+		eventType := response.Type.String()
+		padding := cellPadding(timestamp, timeWidth)
+		typePadding := cellPadding(eventType, typeWidth)
+		if color {
+			timestamp = colorize(timestamp, colorGray)
+			eventType = colorizeEventType(response.Type)
+		}
+		fmt.Fprint(os.Stdout, timestamp+padding+eventType+typePadding)
+
 		if verbose {
-			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", response.Type, device.Id, device.Address, device.SoftwareVersion, device.Credentials.User, device.Credentials.Password))
+			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", device.Id, device.Address, device.SoftwareVersion, device.Credentials.EffectiveUser(), device.Credentials.EffectivePassword()))
 		} else {
-			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s", response.Type, device.Id, device.Address, device.SoftwareVersion))
+			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s", device.Id, device.Address, device.SoftwareVersion))
 		}
 		writer.Flush()
 	}
 }
+
+// watchColumnPadding is the number of spaces between the columns of watched device events
+const watchColumnPadding = 3
+
+// cellPadding returns the spaces that pad the given cell text to the given column width
+// At least the column padding is returned if the text is as wide as or wider than the column.
+func cellPadding(text string, width int) string {
+	if len(text) >= width {
+		return strings.Repeat(" ", watchColumnPadding)
+	}
+	return strings.Repeat(" ", width-len(text)+watchColumnPadding)
+}
+
+// eventLine is the JSON representation of a watched device event
+type eventLine struct {
+	Type      string          `json:"type"`
+	Timestamp string          `json:"timestamp"`
+	Seq       uint64          `json:"seq"`
+	Device    json.RawMessage `json:"device"`
+}
+
+// printEventJSONLine prints the given event as a single line of JSON
+// Stdout is not buffered, so each line is visible to downstream consumers as soon as it's printed.
+func printEventJSONLine(response *device.ListResponse) {
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	value, err := marshaler.MarshalToString(response.Device)
+	if err != nil {
+		ExitWithError(ExitError, err)
+	}
+	bytes, err := json.Marshal(&eventLine{
+		Type:      response.Type.String(),
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Seq:       response.Seq,
+		Device:    json.RawMessage(value),
+	})
+	if err != nil {
+		ExitWithError(ExitError, err)
+	}
+	fmt.Fprintln(os.Stdout, string(bytes))
+}
+
+// formatLocation formats the given location as latitude,longitude,altitude or returns an empty string if
+// the location is not set
+// parseSince parses a point in time given either as an RFC 3339 time or as a duration before the current time
+func parseSince(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func formatLocation(location *device.GeoLocation) string {
+	if location == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g,%g,%g", location.Lat, location.Lng, location.Altitude)
+}
+
+// colorizeEventType returns the name of the given event type wrapped in the color for the type
+func colorizeEventType(t device.ListResponse_Type) string {
+	switch t {
+	case device.ListResponse_ADDED:
+		return colorize(t.String(), colorGreen)
+	case device.ListResponse_UPDATED:
+		return colorize(t.String(), colorYellow)
+	case device.ListResponse_REMOVED:
+		return colorize(t.String(), colorRed)
+	default:
+		return t.String()
+	}
+}
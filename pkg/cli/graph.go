@@ -0,0 +1,148 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-topo/pkg/northbound/device"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+func getGraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Args:  cobra.NoArgs,
+		Short: "Render the topology as a graph",
+		Long: `Render the topology as a Graphviz DOT or Mermaid graph on stdout.
+
+Each device is a node labeled with its ID and address. Devices are connected by an edge from their parent
+device, so the graph shows the device hierarchy. For example, to render the topology as an image:
+
+  topo graph --format dot | dot -Tsvg > topology.svg`,
+		Run: runGraphCommand,
+	}
+	cmd.Flags().StringP("format", "f", "dot", "the format of the graph (dot or mermaid)")
+	return cmd
+}
+
+func runGraphCommand(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	var render func(io.Writer, *topologyGraph)
+	switch format {
+	case "dot":
+		render = renderDOT
+	case "mermaid":
+		render = renderMermaid
+	default:
+		ExitWithErrorMessage("Invalid graph format %s", format)
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	stream, err := client.List(ctx, &device.ListRequest{
+		View: device.ListRequest_BASIC,
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+
+	graph := newTopologyGraph()
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			ExitWithError(ExitError, err)
+		}
+		graph.addDevice(response.Device)
+	}
+	render(os.Stdout, graph)
+}
+
+// newTopologyGraph returns a new empty topologyGraph
+func newTopologyGraph() *topologyGraph {
+	return &topologyGraph{
+		labels: make(map[string]string),
+	}
+}
+
+// topologyGraph is a graph of devices with edges from parent devices to their children
+type topologyGraph struct {
+	nodes  []string
+	labels map[string]string
+	edges  [][2]string
+}
+
+// addDevice adds a node for the given device and an edge from its parent
+// A parent that is not in the graph is added as a node labeled only with its ID, so references to missing
+// parents remain visible.
+func (g *topologyGraph) addDevice(dvc *device.Device) {
+	label := dvc.Id
+	if dvc.Address != "" {
+		label += "\n" + dvc.Address
+	}
+	g.addNode(dvc.Id, label)
+	if dvc.ParentId != "" {
+		g.addNode(dvc.ParentId, dvc.ParentId)
+		g.edges = append(g.edges, [2]string{dvc.ParentId, dvc.Id})
+	}
+}
+
+// addNode adds a node with the given label, replacing the label of a node added as a parent
+func (g *topologyGraph) addNode(id string, label string) {
+	if _, ok := g.labels[id]; !ok {
+		g.nodes = append(g.nodes, id)
+	} else if label == id {
+		return
+	}
+	g.labels[id] = label
+}
+
+// renderDOT writes the given graph in the Graphviz DOT language
+func renderDOT(w io.Writer, g *topologyGraph) {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	fmt.Fprintln(w, "digraph topology {")
+	for _, id := range g.nodes {
+		fmt.Fprintf(w, "  \"%s\" [label=\"%s\"];\n", quote.Replace(id), quote.Replace(g.labels[id]))
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(w, "  \"%s\" -> \"%s\";\n", quote.Replace(edge[0]), quote.Replace(edge[1]))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// renderMermaid writes the given graph as a Mermaid flowchart
+// Mermaid node IDs are restricted, so nodes are identified by their index and labeled with the device ID.
+func renderMermaid(w io.Writer, g *topologyGraph) {
+	quote := strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+	index := make(map[string]int)
+	fmt.Fprintln(w, "graph TD")
+	for i, id := range g.nodes {
+		index[id] = i
+		fmt.Fprintf(w, "  n%d[\"%s\"]\n", i, quote.Replace(g.labels[id]))
+	}
+	for _, edge := range g.edges {
+		fmt.Fprintf(w, "  n%d --> n%d\n", index[edge[0]], index[edge[1]])
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-topo/pkg/northbound/device"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func getGetGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group [<id>]",
+		Aliases: []string{"groups"},
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "Get a device group, or list the device groups",
+		Run:     runGetGroupCommand,
+	}
+	cmd.Flags().Bool("members", false, "list the devices that are members of the group")
+	cmd.Flags().Bool("no-headers", false, "disables output headers")
+	return cmd
+}
+
+func runGetGroupCommand(cmd *cobra.Command, args []string) {
+	members, _ := cmd.Flags().GetBool("members")
+	noHeaders, _ := cmd.Flags().GetBool("no-headers")
+	if members && len(args) == 0 {
+		ExitWithErrorMessage("A group ID is required with --members")
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	writer := new(tabwriter.Writer)
+	writer.Init(os.Stdout, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	defer writer.Flush()
+
+	if members {
+		response, err := client.ListGroupMembers(ctx, &device.ListGroupMembersRequest{
+			GroupId: args[0],
+			View:    device.ListRequest_BASIC,
+		})
+		if err != nil {
+			ExitWithError(ExitBadConnection, err)
+		}
+		if !noHeaders {
+			fmt.Fprintln(writer, "ID\tADDRESS\tVERSION")
+		}
+		for _, dvc := range response.Devices {
+			fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s", dvc.Id, dvc.Address, dvc.SoftwareVersion))
+		}
+		for _, id := range response.MissingIds {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf("Member %s does not exist", id))
+		}
+		return
+	}
+
+	var groups []*device.DeviceGroup
+	if len(args) > 0 {
+		response, err := client.GetGroup(ctx, &device.GetGroupRequest{
+			GroupId: args[0],
+		})
+		if err != nil {
+			ExitWithError(ExitBadConnection, err)
+		}
+		groups = []*device.DeviceGroup{response.Group}
+	} else {
+		response, err := client.ListGroups(ctx, &device.ListGroupsRequest{})
+		if err != nil {
+			ExitWithError(ExitBadConnection, err)
+		}
+		groups = response.Groups
+	}
+
+	if !noHeaders {
+		fmt.Fprintln(writer, "ID\tNAME\tSELECTOR\tMEMBERS")
+	}
+	for _, group := range groups {
+		fmt.Fprintln(writer, fmt.Sprintf("%s\t%s\t%s\t%s", group.Id, group.Name, group.Selector, strings.Join(group.MemberIds, ",")))
+	}
+}
+
+func getAddGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group <id> [args]",
+		Aliases: []string{"groups"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "Add a device group",
+		Long: `Add a device group.
+
+The members of the group are the devices given with --member and the devices whose labels match the
+--selector. The selector is a comma-separated list of label requirements of the form key=value,
+key!=value, key (the label is set), or !key (the label is not set).`,
+		Run: runAddGroupCommand,
+	}
+	addGroupFlags(cmd)
+	return cmd
+}
+
+func runAddGroupCommand(cmd *cobra.Command, args []string) {
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	response, err := client.AddGroup(ctx, &device.AddGroupRequest{
+		Group: groupFromFlags(cmd, args[0]),
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	ExitWithOutput("Added group %s", response.Group.Id)
+}
+
+func getUpdateGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group <id> [args]",
+		Aliases: []string{"groups"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "Replace the name, selector and members of a device group",
+		Run:     runUpdateGroupCommand,
+	}
+	addGroupFlags(cmd)
+	cmd.Flags().Uint64("version", 0, "the expected version of the group")
+	return cmd
+}
+
+func runUpdateGroupCommand(cmd *cobra.Command, args []string) {
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	group := groupFromFlags(cmd, args[0])
+	group.Version, _ = cmd.Flags().GetUint64("version")
+	response, err := client.UpdateGroup(ctx, &device.UpdateGroupRequest{
+		Group: group,
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	ExitWithOutput("Updated group %s", response.Group.Id)
+}
+
+func getRemoveGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "group <id>",
+		Aliases: []string{"groups"},
+		Args:    cobra.ExactArgs(1),
+		Short:   "Remove a device group",
+		Run:     runRemoveGroupCommand,
+	}
+	cmd.Flags().Uint64("version", 0, "the expected version of the group")
+	return cmd
+}
+
+func runRemoveGroupCommand(cmd *cobra.Command, args []string) {
+	version, _ := cmd.Flags().GetUint64("version")
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := client.RemoveGroup(ctx, &device.RemoveGroupRequest{
+		GroupId: args[0],
+		Version: version,
+	})
+	if err != nil {
+		ExitWithError(ExitBadConnection, err)
+	}
+	ExitWithOutput("Removed group %s", args[0])
+}
+
+// addGroupFlags adds the flags that set the fields of a device group to the given command
+func addGroupFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "the display name of the group")
+	cmd.Flags().StringP("selector", "l", "", "the label selector of the group's devices")
+	cmd.Flags().StringSlice("member", []string{}, "the IDs of the explicit members of the group")
+}
+
+// groupFromFlags returns a device group with the given ID and the fields set by the command's flags
+func groupFromFlags(cmd *cobra.Command, id string) *device.DeviceGroup {
+	name, _ := cmd.Flags().GetString("name")
+	selector, _ := cmd.Flags().GetString("selector")
+	members, _ := cmd.Flags().GetStringSlice("member")
+	if _, err := device.ParseSelector(selector); err != nil {
+		ExitWithErrorMessage("Invalid selector: %s", err)
+	}
+	return &device.DeviceGroup{
+		Id:        id,
+		Name:      name,
+		Selector:  selector,
+		MemberIds: members,
+	}
+}
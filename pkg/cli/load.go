@@ -0,0 +1,471 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/onosproject/onos-topo/pkg/northbound/device"
+	"github.com/spf13/cobra"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// csvColumns is the default order of the columns in a CSV device inventory
+var csvColumns = []string{"id", "address", "target", "version", "user"}
+
+// csvFieldPaths maps CSV columns to the device field paths they set
+var csvFieldPaths = map[string]string{
+	"address": "address",
+	"target":  "target",
+	"version": "software_version",
+	"user":    "credentials.user",
+}
+
+// loadedDevice is a device parsed from an inventory along with the line on which it was defined
+type loadedDevice struct {
+	line   int
+	device *device.Device
+	paths  []string
+}
+
+// loadResult is the result of loading the devices in an inventory
+type loadResult struct {
+	added   int
+	updated int
+	errs    []loadError
+}
+
+// loadError is an error loading the device defined on a line of an inventory
+type loadError struct {
+	line int
+	err  error
+}
+
+func (e loadError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err)
+}
+
+func getLoadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load <file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Add the devices in an inventory file",
+		Long: `Add the devices in an inventory file, or from stdin if the file is "-".
+
+CSV inventories contain one device per line with the columns id, address, target, version, and user.
+If the first line is a header naming the columns, the columns may be given in any order and any
+columns may be omitted except id.
+
+YAML inventories contain a list of devices with the keys id, address, addresses, target, version,
+user, labels, and tls, e.g.:
+
+  devices:
+  - id: switch-1
+    address: 10.0.0.1:5150
+    version: 1.0.0
+    tls:
+      plain: true
+
+The result of each device is printed as it's loaded unless --quiet is set, in which case only the
+progress and a final summary are printed. Failures are listed after all devices have been loaded, and
+the command exits with a non-zero status if any device failed.`,
+		Run: runLoadCommand,
+	}
+	cmd.Flags().StringP("format", "f", "csv", "the format of the inventory file (csv or yaml)")
+	cmd.Flags().DurationP("timeout", "t", 30*time.Second, "the device connection timeout")
+	cmd.Flags().Bool("update", false, "update the inventory fields of devices that already exist")
+	cmd.Flags().BoolP("quiet", "q", false, "print only progress and a summary rather than the result of each device")
+	return cmd
+}
+
+func runLoadCommand(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	update, _ := cmd.Flags().GetBool("update")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	devices, errs, err := parseInventory(args[0], format)
+	if err != nil {
+		ExitWithError(ExitBadArgs, err)
+	}
+	for _, loaded := range devices {
+		loaded.device.Timeout = ptypes.DurationProto(timeout)
+	}
+
+	progress := printDeviceResult
+	if quiet {
+		progress = newProgressPrinter(len(devices))
+	}
+	result := loadDevices(devices, update, progress)
+	errs = append(errs, result.errs...)
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].line < errs[j].line
+	})
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	summary := fmt.Sprintf("%d added, %d updated, %d failed", result.added, result.updated, len(errs))
+	if len(errs) > 0 {
+		ExitWithErrorMessage(summary)
+	}
+	ExitWithOutput(summary)
+}
+
+// parseInventory parses the devices in the inventory file with the given path and format
+// The inventory is read from stdin if the path is "-". An error is returned if the file can't be read or
+// isn't a valid inventory, while devices that are invalid are returned as errors with their line numbers.
+func parseInventory(path string, format string) ([]*loadedDevice, []loadError, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	switch format {
+	case "csv":
+		devices, errs := parseCSVDevices(reader)
+		return devices, errs, nil
+	case "yaml":
+		return parseYAMLDevices(reader)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %s", format)
+	}
+}
+
+// parseCSVDevices parses the devices in the given CSV inventory
+// Rows that can't be parsed or that define invalid devices are returned as errors with their line numbers.
+func parseCSVDevices(reader io.Reader) ([]*loadedDevice, []loadError) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var devices []*loadedDevice
+	var errs []loadError
+	columns := csvColumns
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				errs = append(errs, loadError{line: parseErr.Line, err: parseErr.Err})
+				continue
+			}
+			errs = append(errs, loadError{line: line, err: err})
+			break
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "id") {
+			columns = make([]string, len(record))
+			for i, name := range record {
+				columns[i] = strings.ToLower(strings.TrimSpace(name))
+			}
+			continue
+		}
+
+		dvc, err := parseCSVDevice(columns, record)
+		if err != nil {
+			errs = append(errs, loadError{line: line, err: err})
+			continue
+		}
+		var paths []string
+		for _, column := range columns[:len(record)] {
+			if path, ok := csvFieldPaths[column]; ok {
+				paths = append(paths, path)
+			}
+		}
+		devices = append(devices, &loadedDevice{line: line, device: dvc, paths: paths})
+	}
+	return devices, errs
+}
+
+// parseCSVDevice returns the device defined by the given CSV record
+func parseCSVDevice(columns []string, record []string) (*device.Device, error) {
+	if len(record) > len(columns) {
+		return nil, fmt.Errorf("expected at most %d columns, found %d", len(columns), len(record))
+	}
+
+	dvc := &device.Device{}
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		switch columns[i] {
+		case "id":
+			dvc.Id = value
+		case "address":
+			dvc.Address = value
+		case "target":
+			dvc.Target = value
+		case "version":
+			dvc.SoftwareVersion = value
+		case "user":
+			if value != "" {
+				dvc.Credentials = &device.Credentials{
+					User: value,
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown column %s", columns[i])
+		}
+	}
+	if err := dvc.Validate(); err != nil {
+		return nil, err
+	}
+	return dvc, nil
+}
+
+// yamlInventory is a YAML device inventory
+type yamlInventory struct {
+	Devices []yamlDevice `yaml:"devices"`
+}
+
+// yamlDevice is a device defined in a YAML inventory
+type yamlDevice struct {
+	ID        string            `yaml:"id"`
+	Address   string            `yaml:"address"`
+	Addresses []string          `yaml:"addresses"`
+	Target    string            `yaml:"target"`
+	Version   string            `yaml:"version"`
+	User      string            `yaml:"user"`
+	Labels    map[string]string `yaml:"labels"`
+	TLS       *yamlTLSConfig    `yaml:"tls"`
+}
+
+// yamlTLSConfig is the TLS configuration of a device defined in a YAML inventory
+type yamlTLSConfig struct {
+	CaCert   string `yaml:"caCert"`
+	Cert     string `yaml:"cert"`
+	Key      string `yaml:"key"`
+	Plain    bool   `yaml:"plain"`
+	Insecure bool   `yaml:"insecure"`
+}
+
+// parseYAMLDevices parses the devices in the given YAML inventory
+// Unknown keys are rejected so that misspelled fields aren't silently ignored. Devices that are invalid
+// are returned as errors with the line numbers on which they're defined.
+func parseYAMLDevices(reader io.Reader) ([]*loadedDevice, []loadError, error) {
+	bytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	inventory := &yamlInventory{}
+	if err := yaml.UnmarshalStrict(bytes, inventory); err != nil {
+		return nil, nil, err
+	}
+
+	lines := yamlDeviceLines(bytes, len(inventory.Devices))
+	var devices []*loadedDevice
+	var errs []loadError
+	for i, defined := range inventory.Devices {
+		dvc, paths := defined.device()
+		if err := dvc.Validate(); err != nil {
+			errs = append(errs, loadError{line: lines[i], err: err})
+			continue
+		}
+		devices = append(devices, &loadedDevice{line: lines[i], device: dvc, paths: paths})
+	}
+	return devices, errs, nil
+}
+
+// device returns the device defined in a YAML inventory and the field paths set by the definition
+func (d yamlDevice) device() (*device.Device, []string) {
+	dvc := &device.Device{
+		Id:              d.ID,
+		Address:         d.Address,
+		Addresses:       d.Addresses,
+		Target:          d.Target,
+		SoftwareVersion: d.Version,
+		Labels:          d.Labels,
+	}
+	var paths []string
+	if d.Address != "" {
+		paths = append(paths, "address")
+	}
+	if d.Addresses != nil {
+		paths = append(paths, "addresses")
+	}
+	if d.Target != "" {
+		paths = append(paths, "target")
+	}
+	if d.Version != "" {
+		paths = append(paths, "software_version")
+	}
+	if d.User != "" {
+		dvc.Credentials = &device.Credentials{
+			User: d.User,
+		}
+		paths = append(paths, "credentials.user")
+	}
+	if d.Labels != nil {
+		paths = append(paths, "labels")
+	}
+	if d.TLS != nil {
+		dvc.Tls = &device.TlsConfig{
+			CaCert:   d.TLS.CaCert,
+			Cert:     d.TLS.Cert,
+			Key:      d.TLS.Key,
+			Plain:    d.TLS.Plain,
+			Insecure: d.TLS.Insecure,
+		}
+		paths = append(paths, "tls")
+	}
+	return dvc, paths
+}
+
+// yamlDeviceLines returns the line on which each of the given number of devices is defined in the given
+// YAML inventory
+// The YAML decoder doesn't report the positions of values, so the lines are found by scanning for the
+// items of the devices list. If the items can't be found, e.g. because the list is written in flow style,
+// every device is reported on the line of the devices key.
+func yamlDeviceLines(bytes []byte, count int) []int {
+	var keyLine int
+	var items []int
+	indent := -1
+	for i, text := range strings.Split(string(bytes), "\n") {
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		depth := len(text) - len(strings.TrimLeft(text, " "))
+		if keyLine == 0 {
+			if depth == 0 && strings.HasPrefix(trimmed, "devices:") {
+				keyLine = i + 1
+			}
+			continue
+		}
+		isItem := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+		if indent == -1 {
+			if !isItem {
+				break
+			}
+			indent = depth
+		}
+		if depth < indent || (depth == indent && !isItem) {
+			break
+		} else if depth == indent {
+			items = append(items, i+1)
+		}
+	}
+
+	lines := make([]int, count)
+	for i := range lines {
+		if len(items) == count {
+			lines[i] = items[i]
+		} else {
+			lines[i] = keyLine
+		}
+	}
+	return lines
+}
+
+// loadDevices adds the given devices, reporting the result of each device to the given progress function
+// If update is true, the inventory fields of devices that already exist are updated.
+func loadDevices(devices []*loadedDevice, update bool, progress func(*loadedDevice, string, error)) loadResult {
+	result := loadResult{}
+	if len(devices) == 0 {
+		return result
+	}
+
+	conn := getConnection()
+	defer conn.Close()
+
+	client := device.NewDeviceServiceClient(conn)
+
+	for _, loaded := range devices {
+		action, err := loadDevice(client, loaded, update)
+		if err != nil {
+			result.errs = append(result.errs, loadError{line: loaded.line, err: err})
+		} else if action == "Updated" {
+			result.updated++
+		} else {
+			result.added++
+		}
+		progress(loaded, action, err)
+	}
+	return result
+}
+
+// loadDevice adds the given device, returning the action taken
+func loadDevice(client device.DeviceServiceClient, loaded *loadedDevice, update bool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := client.Add(ctx, &device.AddRequest{
+		Device: loaded.device,
+	})
+	if err == nil {
+		return "Added", nil
+	} else if !update || status.Code(err) != codes.AlreadyExists {
+		return "", err
+	}
+
+	// Update only the fields set by the inventory
+	_, err = client.Update(ctx, &device.UpdateRequest{
+		Device: loaded.device,
+		UpdateMask: &field_mask.FieldMask{
+			Paths: loaded.paths,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return "Updated", nil
+}
+
+// printDeviceResult prints the result of loading a device
+func printDeviceResult(loaded *loadedDevice, action string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load device %s on line %d\n", loaded.device.Id, loaded.line)
+	} else {
+		Output("%s device %s\n", action, loaded.device.Id)
+	}
+}
+
+// newProgressPrinter returns a progress function that prints the number of devices loaded
+// If stderr is a terminal, the progress is updated in place. Otherwise, the progress is printed
+// at every tenth of the devices to avoid filling logs.
+func newProgressPrinter(total int) func(*loadedDevice, string, error) {
+	terminal := isTerminal(os.Stderr)
+	step := total / 10
+	if step == 0 {
+		step = 1
+	}
+	count := 0
+	return func(*loadedDevice, string, error) {
+		count++
+		if terminal {
+			fmt.Fprintf(os.Stderr, "\rLoading devices: %d/%d", count, total)
+			if count == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		} else if count%step == 0 || count == total {
+			fmt.Fprintf(os.Stderr, "Loading devices: %d/%d\n", count, total)
+		}
+	}
+}
@@ -16,17 +16,34 @@ package cli
 
 import "github.com/spf13/cobra"
 
+// Connection flags override the address and TLS paths read from the configuration file and environment
+var (
+	addressFlag  string
+	certPathFlag string
+	keyPathFlag  string
+)
+
 // GetCommand returns the root command for the topo service
 func GetCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use: "topo {get,add,update,remove,watch} [args]",
+		Use: "topo {get,describe,add,update,remove,rename,watch,load,validate,graph} [args]",
 	}
 
+	cmd.PersistentFlags().StringVar(&addressFlag, "address", "", "the onos-topo service address")
+	cmd.PersistentFlags().StringVar(&certPathFlag, "tls-cert-path", "", "the path to the client TLS certificate")
+	cmd.PersistentFlags().StringVar(&keyPathFlag, "tls-key-path", "", "the path to the client TLS key")
+
 	cmd.AddCommand(getConfigCommand())
 	cmd.AddCommand(getGetCommand())
+	cmd.AddCommand(getDescribeCommand())
 	cmd.AddCommand(getAddCommand())
 	cmd.AddCommand(getUpdateCommand())
 	cmd.AddCommand(getRemoveCommand())
+	cmd.AddCommand(getRenameCommand())
+	cmd.AddCommand(getCloneCommand())
 	cmd.AddCommand(getWatchCommand())
+	cmd.AddCommand(getLoadCommand())
+	cmd.AddCommand(getValidateCommand())
+	cmd.AddCommand(getGraphCommand())
 	return cmd
 }
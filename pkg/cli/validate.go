@@ -0,0 +1,113 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-topo/pkg/northbound/device"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func getValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Validate the devices in an inventory file without contacting the service",
+		Long: `Validate the devices in an inventory file, or from stdin if the file is "-", without contacting the service.
+
+The file is in the format accepted by the load command. Devices are checked with the validations applied by
+the service, including the ID pattern, and with the service's strict validations: TLS configurations must
+be coherent and software versions must be semantic versions. IDs must also be unique within the file.
+
+Each invalid device is reported with the line on which it's defined, and the command exits with a non-zero
+status if any device is invalid.`,
+		Run: runValidateCommand,
+	}
+	cmd.Flags().StringP("format", "f", "", "the format of the inventory file (csv or yaml); defaults to the file extension")
+	cmd.Flags().String("id-pattern", device.DefaultIDPattern.String(), "the regular expression to which device IDs must conform")
+	return cmd
+}
+
+func runValidateCommand(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = inventoryFormat(args[0])
+	}
+	idPattern, _ := cmd.Flags().GetString("id-pattern")
+	pattern, err := regexp.Compile(idPattern)
+	if err != nil {
+		ExitWithError(ExitBadArgs, err)
+	}
+
+	devices, errs, err := parseInventory(args[0], format)
+	if err != nil {
+		ExitWithError(ExitError, err)
+	}
+	errs = append(errs, validateDevices(devices, pattern)...)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].line < errs[j].line
+	})
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	if len(errs) > 0 {
+		ExitWithErrorMessage("%d errors", len(errs))
+	}
+	ExitWithOutput("%d devices are valid", len(devices))
+}
+
+// inventoryFormat returns the format of the inventory file with the given path from its extension
+func inventoryFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "csv"
+	}
+}
+
+// validateDevices validates the given parsed devices with the given ID pattern and the service's strict
+// validators, returning an error for each violation
+func validateDevices(devices []*loadedDevice, pattern *regexp.Regexp) []loadError {
+	validators := []device.ValidatorFunc{
+		device.TLSValidator(),
+		device.SemanticVersionValidator(),
+	}
+
+	var errs []loadError
+	lines := make(map[string]int)
+	for _, loaded := range devices {
+		if err := device.ValidateID(loaded.device.Id, pattern); err != nil {
+			errs = append(errs, loadError{line: loaded.line, err: err})
+		}
+		if line, ok := lines[loaded.device.Id]; ok {
+			errs = append(errs, loadError{line: loaded.line, err: fmt.Errorf("duplicate device %s; first defined on line %d", loaded.device.Id, line)})
+		} else {
+			lines[loaded.device.Id] = loaded.line
+		}
+		for _, validator := range validators {
+			for _, violation := range validator(loaded.device) {
+				errs = append(errs, loadError{line: loaded.line, err: fmt.Errorf("invalid Device.%s: %s", violation.Field, violation.Description)})
+			}
+		}
+	}
+	return errs
+}
@@ -16,21 +16,314 @@
 package admin
 
 import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/onosproject/onos-topo/pkg/northbound"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
 )
 
+// snapshotChunkSize is the maximum number of bytes in each chunk of a streamed snapshot
+const snapshotChunkSize = 64 * 1024
+
+// Compactor is implemented by services whose stores accumulate stale entries
+type Compactor interface {
+	// Compact removes entries that expired more than the given duration ago, returning the number of
+	// entries removed keyed by the kind of entry
+	Compact(ctx context.Context, olderThan time.Duration) (map[string]uint64, error)
+}
+
+// Clearer is implemented by services whose stores can be reset
+type Clearer interface {
+	// Clear removes all entries from the service's stores
+	Clear(ctx context.Context) error
+}
+
+// StatsProvider is implemented by services that collect device access statistics
+type StatsProvider interface {
+	// DeviceStats returns the statistics of the given number of most accessed devices in descending order of
+	// accesses, or of all tracked devices if limit is 0, along with the time at which collection started
+	DeviceStats(ctx context.Context, limit int) ([]*DeviceStats, time.Time, error)
+}
+
+// Snapshotter is implemented by services whose stores can be snapshotted and restored
+type Snapshotter interface {
+	// Snapshot writes a snapshot of the entries of the service's stores to the given writer
+	Snapshot(ctx context.Context, writer io.Writer) error
+
+	// Restore stores the entries of a snapshot read from the given reader in the service's stores
+	// If replace is true, all entries are removed from the stores before the snapshot is restored.
+	Restore(ctx context.Context, reader io.Reader, replace bool) error
+}
+
+// Authorizer authorizes administrative requests, returning an error if the request is not authorized
+type Authorizer func(ctx context.Context) error
+
+// NewService returns a new admin Service
+func NewService(opts ...ServiceOption) Service {
+	service := Service{
+		authorizer: AuthorizeVerifiedClient,
+	}
+	for _, opt := range opts {
+		opt(&service)
+	}
+	return service
+}
+
+// ServiceOption is an option for configuring the admin Service
+type ServiceOption func(*Service)
+
+// WithCompactor adds a Compactor to be invoked by Compact requests
+func WithCompactor(compactor Compactor) ServiceOption {
+	return func(service *Service) {
+		service.compactors = append(service.compactors, compactor)
+	}
+}
+
+// WithClearer adds a Clearer to be invoked by Clear requests
+func WithClearer(clearer Clearer) ServiceOption {
+	return func(service *Service) {
+		service.clearers = append(service.clearers, clearer)
+	}
+}
+
+// WithStatsProvider sets the StatsProvider queried by GetDeviceStats requests
+func WithStatsProvider(provider StatsProvider) ServiceOption {
+	return func(service *Service) {
+		service.statsProvider = provider
+	}
+}
+
+// WithSnapshotter sets the Snapshotter invoked by Snapshot and Restore requests
+func WithSnapshotter(snapshotter Snapshotter) ServiceOption {
+	return func(service *Service) {
+		service.snapshotter = snapshotter
+	}
+}
+
+// WithAuthorizer sets the Authorizer for administrative requests
+// By default, only clients that present a client certificate verified by the server are authorized.
+func WithAuthorizer(authorizer Authorizer) ServiceOption {
+	return func(service *Service) {
+		service.authorizer = authorizer
+	}
+}
+
 // Service is a Service implementation for administration.
 type Service struct {
 	northbound.Service
+	compactors    []Compactor
+	clearers      []Clearer
+	statsProvider StatsProvider
+	snapshotter   Snapshotter
+	authorizer    Authorizer
 }
 
 // Register registers the Service with the gRPC server.
 func (s Service) Register(r *grpc.Server) {
-	server := Server{}
+	server := Server{
+		compactors:    s.compactors,
+		clearers:      s.clearers,
+		statsProvider: s.statsProvider,
+		snapshotter:   s.snapshotter,
+		authorizer:    s.authorizer,
+	}
 	RegisterTopoAdminServiceServer(r, server)
 }
 
 // Server implements the gRPC service for administrative facilities.
 type Server struct {
+	compactors    []Compactor
+	clearers      []Clearer
+	statsProvider StatsProvider
+	snapshotter   Snapshotter
+	authorizer    Authorizer
+}
+
+// authorize returns an error if the administrative request in the given context is not authorized
+func (s Server) authorize(ctx context.Context) error {
+	if s.authorizer == nil {
+		return status.Error(codes.PermissionDenied, "administrative requests are not authorized")
+	}
+	return s.authorizer(ctx)
+}
+
+// Compact removes stale entries from the stores of all registered compactors
+func (s Server) Compact(ctx context.Context, request *CompactRequest) (*CompactResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	var olderThan time.Duration
+	if request.OlderThan != nil {
+		duration, err := ptypes.Duration(request.OlderThan)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		olderThan = duration
+	}
+
+	removed := make(map[string]uint64)
+	for _, compactor := range s.compactors {
+		counts, err := compactor.Compact(ctx, olderThan)
+		if err != nil {
+			return nil, err
+		}
+		for kind, count := range counts {
+			removed[kind] += count
+		}
+	}
+	return &CompactResponse{
+		Removed: removed,
+	}, nil
+}
+
+// Clear removes all entries from the stores of all registered clearers
+func (s Server) Clear(ctx context.Context, request *ClearRequest) (*ClearResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	for _, clearer := range s.clearers {
+		if err := clearer.Clear(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &ClearResponse{}, nil
+}
+
+// GetDeviceStats gets the access statistics of the most accessed devices from the registered stats provider
+func (s Server) GetDeviceStats(ctx context.Context, request *GetDeviceStatsRequest) (*GetDeviceStatsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	} else if s.statsProvider == nil {
+		return nil, status.Error(codes.FailedPrecondition, "device statistics are not enabled")
+	}
+	devices, since, err := s.statsProvider.DeviceStats(ctx, int(request.Limit))
+	if err != nil {
+		return nil, err
+	}
+	sinceProto, err := ptypes.TimestampProto(since)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &GetDeviceStatsResponse{
+		Devices: devices,
+		Since:   sinceProto,
+	}, nil
+}
+
+// Snapshot streams a snapshot of the registered snapshotter's stores in chunks
+func (s Server) Snapshot(request *SnapshotRequest, stream TopoAdminService_SnapshotServer) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	} else if s.snapshotter == nil {
+		return status.Error(codes.FailedPrecondition, "snapshots are not supported")
+	}
+	writer := &chunkWriter{
+		stream: stream,
+		buf:    make([]byte, 0, snapshotChunkSize),
+	}
+	if err := s.snapshotter.Snapshot(stream.Context(), writer); err != nil {
+		return err
+	}
+	return writer.flush()
+}
+
+// chunkWriter is a Writer that sends the bytes written to it to a snapshot stream in chunks
+type chunkWriter struct {
+	stream TopoAdminService_SnapshotServer
+	buf    []byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := cap(w.buf) - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// flush sends the buffered bytes to the stream
+func (w *chunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	if err := w.stream.Send(&SnapshotChunk{Data: w.buf}); err != nil {
+		return err
+	}
+	w.buf = make([]byte, 0, snapshotChunkSize)
+	return nil
+}
+
+// Restore restores a snapshot received in chunks to the registered snapshotter's stores
+func (s Server) Restore(stream TopoAdminService_RestoreServer) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	} else if s.snapshotter == nil {
+		return status.Error(codes.FailedPrecondition, "snapshots are not supported")
+	}
+	request, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "no snapshot received")
+	} else if err != nil {
+		return err
+	}
+	reader := &chunkReader{
+		stream: stream,
+		buf:    request.Data,
+	}
+	if err := s.snapshotter.Restore(stream.Context(), reader, request.Mode == RestoreMode_REPLACE); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&RestoreResponse{})
+}
+
+// chunkReader is a Reader that reads the chunks of a snapshot received from a restore stream
+type chunkReader struct {
+	stream TopoAdminService_RestoreServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		request, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = request.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// AuthorizeVerifiedClient authorizes clients that present a client certificate verified by the server
+// It's the default Authorizer for administrative requests and may be used to authorize other sensitive
+// requests.
+func AuthorizeVerifiedClient(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no peer information")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return status.Error(codes.PermissionDenied, "a verified client certificate is required")
+	}
+	return nil
 }
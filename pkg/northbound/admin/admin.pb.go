@@ -9,7 +9,11 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	math "math"
 )
 
@@ -24,16 +28,560 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// RestoreMode is the mode in which a snapshot is restored
+type RestoreMode int32
+
+const (
+	// MERGE stores the entries of the snapshot, replacing stored entries with the same IDs and keeping others
+	RestoreMode_MERGE RestoreMode = 0
+	// REPLACE removes all stored entries before storing the entries of the snapshot
+	RestoreMode_REPLACE RestoreMode = 1
+)
+
+var RestoreMode_name = map[int32]string{
+	0: "MERGE",
+	1: "REPLACE",
+}
+
+var RestoreMode_value = map[string]int32{
+	"MERGE":   0,
+	"REPLACE": 1,
+}
+
+func (x RestoreMode) String() string {
+	return proto.EnumName(RestoreMode_name, int32(x))
+}
+
+func (RestoreMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{0}
+}
+
+// CompactRequest requests the removal of stale entries from the topology stores
+type CompactRequest struct {
+	// older_than is the minimum age of the stale entries to remove
+	// Entries are never removed before they have expired, regardless of the requested age.
+	OlderThan            *duration.Duration `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CompactRequest) Reset()         { *m = CompactRequest{} }
+func (m *CompactRequest) String() string { return proto.CompactTextString(m) }
+func (*CompactRequest) ProtoMessage()    {}
+func (*CompactRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{0}
+}
+
+func (m *CompactRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompactRequest.Unmarshal(m, b)
+}
+func (m *CompactRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompactRequest.Marshal(b, m, deterministic)
+}
+func (m *CompactRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactRequest.Merge(m, src)
+}
+func (m *CompactRequest) XXX_Size() int {
+	return xxx_messageInfo_CompactRequest.Size(m)
+}
+func (m *CompactRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactRequest proto.InternalMessageInfo
+
+func (m *CompactRequest) GetOlderThan() *duration.Duration {
+	if m != nil {
+		return m.OlderThan
+	}
+	return nil
+}
+
+// CompactResponse carries the number of stale entries removed from the topology stores
+type CompactResponse struct {
+	// removed is the number of entries removed, keyed by the kind of entry
+	Removed              map[string]uint64 `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CompactResponse) Reset()         { *m = CompactResponse{} }
+func (m *CompactResponse) String() string { return proto.CompactTextString(m) }
+func (*CompactResponse) ProtoMessage()    {}
+func (*CompactResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{1}
+}
+
+func (m *CompactResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompactResponse.Unmarshal(m, b)
+}
+func (m *CompactResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompactResponse.Marshal(b, m, deterministic)
+}
+func (m *CompactResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactResponse.Merge(m, src)
+}
+func (m *CompactResponse) XXX_Size() int {
+	return xxx_messageInfo_CompactResponse.Size(m)
+}
+func (m *CompactResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactResponse proto.InternalMessageInfo
+
+func (m *CompactResponse) GetRemoved() map[string]uint64 {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+// ClearRequest requests the removal of all entries from the topology stores
+type ClearRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearRequest) Reset()         { *m = ClearRequest{} }
+func (m *ClearRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearRequest) ProtoMessage()    {}
+func (*ClearRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{2}
+}
+
+func (m *ClearRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearRequest.Unmarshal(m, b)
+}
+func (m *ClearRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearRequest.Marshal(b, m, deterministic)
+}
+func (m *ClearRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearRequest.Merge(m, src)
+}
+func (m *ClearRequest) XXX_Size() int {
+	return xxx_messageInfo_ClearRequest.Size(m)
+}
+func (m *ClearRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearRequest proto.InternalMessageInfo
+
+// ClearResponse is sent in response to a ClearRequest
+type ClearResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClearResponse) Reset()         { *m = ClearResponse{} }
+func (m *ClearResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearResponse) ProtoMessage()    {}
+func (*ClearResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{3}
+}
+
+func (m *ClearResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearResponse.Unmarshal(m, b)
+}
+func (m *ClearResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearResponse.Marshal(b, m, deterministic)
+}
+func (m *ClearResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearResponse.Merge(m, src)
+}
+func (m *ClearResponse) XXX_Size() int {
+	return xxx_messageInfo_ClearResponse.Size(m)
+}
+func (m *ClearResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearResponse proto.InternalMessageInfo
+
+// GetDeviceStatsRequest requests the access statistics of the most accessed devices
+type GetDeviceStatsRequest struct {
+	// limit is the maximum number of devices for which statistics are returned
+	// If limit is 0, the statistics of all tracked devices are returned.
+	Limit                uint32   `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDeviceStatsRequest) Reset()         { *m = GetDeviceStatsRequest{} }
+func (m *GetDeviceStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDeviceStatsRequest) ProtoMessage()    {}
+func (*GetDeviceStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{4}
+}
+
+func (m *GetDeviceStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDeviceStatsRequest.Unmarshal(m, b)
+}
+func (m *GetDeviceStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDeviceStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetDeviceStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDeviceStatsRequest.Merge(m, src)
+}
+func (m *GetDeviceStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDeviceStatsRequest.Size(m)
+}
+func (m *GetDeviceStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDeviceStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDeviceStatsRequest proto.InternalMessageInfo
+
+func (m *GetDeviceStatsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// GetDeviceStatsResponse carries the access statistics of the most accessed devices
+type GetDeviceStatsResponse struct {
+	// devices is the statistics of each device in descending order of estimated accesses
+	Devices []*DeviceStats `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// since is the time at which statistics collection started
+	Since                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetDeviceStatsResponse) Reset()         { *m = GetDeviceStatsResponse{} }
+func (m *GetDeviceStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDeviceStatsResponse) ProtoMessage()    {}
+func (*GetDeviceStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{5}
+}
+
+func (m *GetDeviceStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDeviceStatsResponse.Unmarshal(m, b)
+}
+func (m *GetDeviceStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDeviceStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetDeviceStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDeviceStatsResponse.Merge(m, src)
+}
+func (m *GetDeviceStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDeviceStatsResponse.Size(m)
+}
+func (m *GetDeviceStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDeviceStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDeviceStatsResponse proto.InternalMessageInfo
+
+func (m *GetDeviceStatsResponse) GetDevices() []*DeviceStats {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *GetDeviceStatsResponse) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+// DeviceStats is the number of store reads and writes of a device
+type DeviceStats struct {
+	// device_id is the ID of the device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// reads is the number of times the device was read from the store
+	Reads uint64 `protobuf:"varint,2,opt,name=reads,proto3" json:"reads,omitempty"`
+	// writes is the number of times the device was written to the store
+	Writes uint64 `protobuf:"varint,3,opt,name=writes,proto3" json:"writes,omitempty"`
+	// overcount is the number of accesses the device may have had before it was last tracked
+	// Only a bounded number of devices is tracked. When an untracked device is accessed, the least accessed
+	// tracked device is replaced, and the new device inherits its estimated count as its overcount. The reads
+	// and writes count only accesses since the device was last tracked, so the device's true number of
+	// accesses is at least reads + writes and at most reads + writes + overcount.
+	Overcount            uint64   `protobuf:"varint,4,opt,name=overcount,proto3" json:"overcount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeviceStats) Reset()         { *m = DeviceStats{} }
+func (m *DeviceStats) String() string { return proto.CompactTextString(m) }
+func (*DeviceStats) ProtoMessage()    {}
+func (*DeviceStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{6}
+}
+
+func (m *DeviceStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceStats.Unmarshal(m, b)
+}
+func (m *DeviceStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceStats.Marshal(b, m, deterministic)
+}
+func (m *DeviceStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceStats.Merge(m, src)
+}
+func (m *DeviceStats) XXX_Size() int {
+	return xxx_messageInfo_DeviceStats.Size(m)
+}
+func (m *DeviceStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceStats proto.InternalMessageInfo
+
+func (m *DeviceStats) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *DeviceStats) GetReads() uint64 {
+	if m != nil {
+		return m.Reads
+	}
+	return 0
+}
+
+func (m *DeviceStats) GetWrites() uint64 {
+	if m != nil {
+		return m.Writes
+	}
+	return 0
+}
+
+func (m *DeviceStats) GetOvercount() uint64 {
+	if m != nil {
+		return m.Overcount
+	}
+	return 0
+}
+
+// SnapshotRequest requests a snapshot of the topology stores
+type SnapshotRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{7}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+// SnapshotChunk is a chunk of a snapshot of the topology stores
+// The chunks of a snapshot are concatenated to form the snapshot blob.
+type SnapshotChunk struct {
+	// data is the next chunk of the snapshot
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotChunk) Reset()         { *m = SnapshotChunk{} }
+func (m *SnapshotChunk) String() string { return proto.CompactTextString(m) }
+func (*SnapshotChunk) ProtoMessage()    {}
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{8}
+}
+
+func (m *SnapshotChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotChunk.Unmarshal(m, b)
+}
+func (m *SnapshotChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotChunk.Marshal(b, m, deterministic)
+}
+func (m *SnapshotChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotChunk.Merge(m, src)
+}
+func (m *SnapshotChunk) XXX_Size() int {
+	return xxx_messageInfo_SnapshotChunk.Size(m)
+}
+func (m *SnapshotChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotChunk proto.InternalMessageInfo
+
+func (m *SnapshotChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// RestoreRequest is a chunk of a snapshot to restore to the topology stores
+type RestoreRequest struct {
+	// mode is the mode in which the snapshot is restored
+	// Only the mode of the first request of the stream is used.
+	Mode RestoreMode `protobuf:"varint,1,opt,name=mode,proto3,enum=topo.admin.RestoreMode" json:"mode,omitempty"`
+	// data is the next chunk of the snapshot
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{9}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetMode() RestoreMode {
+	if m != nil {
+		return m.Mode
+	}
+	return RestoreMode_MERGE
+}
+
+func (m *RestoreRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// RestoreResponse is sent once a snapshot has been restored
+type RestoreResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9081d84c442224d8, []int{10}
+}
+
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("topo.admin.RestoreMode", RestoreMode_name, RestoreMode_value)
+	proto.RegisterType((*CompactRequest)(nil), "topo.admin.CompactRequest")
+	proto.RegisterType((*CompactResponse)(nil), "topo.admin.CompactResponse")
+	proto.RegisterMapType((map[string]uint64)(nil), "topo.admin.CompactResponse.RemovedEntry")
+	proto.RegisterType((*ClearRequest)(nil), "topo.admin.ClearRequest")
+	proto.RegisterType((*ClearResponse)(nil), "topo.admin.ClearResponse")
+	proto.RegisterType((*GetDeviceStatsRequest)(nil), "topo.admin.GetDeviceStatsRequest")
+	proto.RegisterType((*GetDeviceStatsResponse)(nil), "topo.admin.GetDeviceStatsResponse")
+	proto.RegisterType((*DeviceStats)(nil), "topo.admin.DeviceStats")
+	proto.RegisterType((*SnapshotRequest)(nil), "topo.admin.SnapshotRequest")
+	proto.RegisterType((*SnapshotChunk)(nil), "topo.admin.SnapshotChunk")
+	proto.RegisterType((*RestoreRequest)(nil), "topo.admin.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "topo.admin.RestoreResponse")
+}
+
 func init() { proto.RegisterFile("pkg/northbound/admin/admin.proto", fileDescriptor_9081d84c442224d8) }
 
 var fileDescriptor_9081d84c442224d8 = []byte{
-	// 85 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x28, 0xc8, 0x4e, 0xd7,
-	0xcf, 0xcb, 0x2f, 0x2a, 0xc9, 0x48, 0xca, 0x2f, 0xcd, 0x4b, 0xd1, 0x4f, 0x4c, 0xc9, 0xcd, 0xcc,
-	0x83, 0x90, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0x5c, 0x25, 0xf9, 0x05, 0xf9, 0x7a, 0x60,
-	0x11, 0x23, 0x21, 0x2e, 0x81, 0x90, 0xfc, 0x82, 0x7c, 0x47, 0x10, 0x27, 0x38, 0xb5, 0xa8, 0x2c,
-	0x33, 0x39, 0x35, 0x89, 0x0d, 0xac, 0xcc, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0xf8, 0x69, 0xb6,
-	0x5b, 0x4a, 0x00, 0x00, 0x00,
+	// 599 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x41, 0x6f, 0xda, 0x4c,
+	0x10, 0x8d, 0x03, 0x84, 0x30, 0x4e, 0x80, 0xac, 0xf2, 0xe5, 0x73, 0x9c, 0xaa, 0xa5, 0x5b, 0x55,
+	0x42, 0xad, 0x6a, 0x52, 0x7a, 0x89, 0x72, 0xa8, 0x94, 0x02, 0x89, 0x5a, 0x35, 0x52, 0xbb, 0x70,
+	0xe9, 0x29, 0x5a, 0xd8, 0x2d, 0x58, 0x60, 0xaf, 0xbb, 0x5e, 0x53, 0xe5, 0xd0, 0x53, 0x7f, 0x41,
+	0xff, 0x71, 0xc5, 0xae, 0x4d, 0x6c, 0x8a, 0x72, 0xb1, 0x3c, 0x33, 0x6f, 0xdf, 0xcc, 0xbc, 0x79,
+	0xd0, 0x8a, 0xe6, 0xd3, 0x4e, 0x28, 0xa4, 0x9a, 0x8d, 0x45, 0x12, 0xb2, 0x0e, 0x65, 0x81, 0x1f,
+	0x9a, 0xaf, 0x17, 0x49, 0xa1, 0x04, 0x02, 0x25, 0x22, 0xe1, 0xe9, 0x8c, 0xfb, 0x74, 0x2a, 0xc4,
+	0x74, 0xc1, 0x3b, 0xba, 0x32, 0x4e, 0xbe, 0x77, 0x58, 0x22, 0xa9, 0xf2, 0x45, 0x8a, 0x75, 0x9f,
+	0x6d, 0xd6, 0x95, 0x1f, 0xf0, 0x58, 0xd1, 0x20, 0x32, 0x00, 0xfc, 0x09, 0xea, 0x3d, 0x11, 0x44,
+	0x74, 0xa2, 0x08, 0xff, 0x91, 0xf0, 0x58, 0xa1, 0x0b, 0x00, 0xb1, 0x60, 0x5c, 0xde, 0xa9, 0x19,
+	0x0d, 0x1d, 0xab, 0x65, 0xb5, 0xed, 0xee, 0xa9, 0x67, 0x78, 0xbc, 0x8c, 0xc7, 0xeb, 0xa7, 0x7d,
+	0x48, 0x4d, 0x83, 0x47, 0x33, 0x1a, 0xe2, 0x3f, 0x16, 0x34, 0xd6, 0x64, 0x71, 0x24, 0xc2, 0x98,
+	0xa3, 0x0f, 0x50, 0x95, 0x3c, 0x10, 0x4b, 0xce, 0x1c, 0xab, 0x55, 0x6a, 0xdb, 0xdd, 0xb6, 0xf7,
+	0x30, 0xbe, 0xb7, 0x81, 0xf6, 0x88, 0x81, 0x0e, 0x42, 0x25, 0xef, 0x49, 0xf6, 0xd0, 0xbd, 0x84,
+	0x83, 0x7c, 0x01, 0x35, 0xa1, 0x34, 0xe7, 0xf7, 0x7a, 0xb4, 0x1a, 0x59, 0xfd, 0xa2, 0x63, 0xa8,
+	0x2c, 0xe9, 0x22, 0xe1, 0xce, 0x6e, 0xcb, 0x6a, 0x97, 0x89, 0x09, 0x2e, 0x77, 0x2f, 0x2c, 0x5c,
+	0x87, 0x83, 0xde, 0x82, 0x53, 0x99, 0x6e, 0x87, 0x1b, 0x70, 0x98, 0xc6, 0xa6, 0x25, 0x7e, 0x03,
+	0xff, 0xdd, 0x70, 0xd5, 0xe7, 0x4b, 0x7f, 0xc2, 0x87, 0x8a, 0xaa, 0x38, 0xd3, 0xe1, 0x18, 0x2a,
+	0x0b, 0x3f, 0xf0, 0x95, 0xee, 0x73, 0x48, 0x4c, 0x80, 0x7f, 0xc1, 0xc9, 0x26, 0x3c, 0xdd, 0xf4,
+	0x2d, 0x54, 0x99, 0x4e, 0xc7, 0xe9, 0xa6, 0xff, 0xe7, 0x37, 0xcd, 0xbf, 0xc8, 0x70, 0xe8, 0x1c,
+	0x2a, 0xb1, 0x1f, 0x4e, 0xcc, 0xd8, 0x76, 0xd7, 0xfd, 0x47, 0xe5, 0x51, 0x76, 0x2d, 0x62, 0x80,
+	0x78, 0x09, 0x76, 0x8e, 0x09, 0x9d, 0x41, 0xcd, 0x70, 0xdd, 0xf9, 0x2c, 0xd5, 0x63, 0xdf, 0x24,
+	0x3e, 0xb2, 0xd5, 0x02, 0x92, 0x53, 0x16, 0x67, 0xa2, 0xe8, 0x00, 0x9d, 0xc0, 0xde, 0x4f, 0xe9,
+	0x2b, 0x1e, 0x3b, 0x25, 0x9d, 0x4e, 0x23, 0xf4, 0x04, 0x6a, 0x62, 0xc9, 0xe5, 0x44, 0x24, 0xa1,
+	0x72, 0xca, 0xba, 0xf4, 0x90, 0xc0, 0x47, 0xd0, 0x18, 0x86, 0x34, 0x8a, 0x67, 0x22, 0xf3, 0x09,
+	0x7e, 0x01, 0x87, 0x59, 0xaa, 0x37, 0x4b, 0xc2, 0x39, 0x42, 0x50, 0x66, 0x54, 0x51, 0x3d, 0xc7,
+	0x01, 0xd1, 0xff, 0xf8, 0x2b, 0xd4, 0x09, 0x8f, 0x95, 0x90, 0x3c, 0x93, 0xf5, 0x35, 0x94, 0x03,
+	0xc1, 0xb8, 0x46, 0xd5, 0x8b, 0x1a, 0xa5, 0xc8, 0x5b, 0xc1, 0x38, 0xd1, 0xa0, 0x35, 0xe5, 0x6e,
+	0x8e, 0xf2, 0x08, 0x1a, 0x6b, 0x4a, 0x23, 0xfd, 0xab, 0x97, 0x60, 0xe7, 0xde, 0xa2, 0x1a, 0x54,
+	0x6e, 0x07, 0xe4, 0x66, 0xd0, 0xdc, 0x41, 0x36, 0x54, 0xc9, 0xe0, 0xcb, 0xe7, 0xab, 0xde, 0xa0,
+	0x69, 0x75, 0x7f, 0x97, 0xa0, 0x39, 0x12, 0x91, 0xb8, 0x5a, 0x75, 0x1b, 0x72, 0xb9, 0xd2, 0x09,
+	0xf5, 0xa1, 0x9a, 0xba, 0x10, 0xb9, 0x5b, 0xad, 0xa9, 0xc7, 0x76, 0xcf, 0x1e, 0xb1, 0x2d, 0xde,
+	0x41, 0xef, 0xa1, 0xa2, 0x6d, 0x85, 0x9c, 0x02, 0x2e, 0xe7, 0x3c, 0xf7, 0x74, 0x4b, 0x65, 0xfd,
+	0xfe, 0x1b, 0xd4, 0x8b, 0xb6, 0x42, 0xcf, 0xf3, 0xf0, 0xad, 0x0e, 0x75, 0xf1, 0x63, 0x90, 0x35,
+	0xf5, 0x35, 0xec, 0x67, 0x77, 0x42, 0x85, 0x2d, 0x36, 0x0e, 0xea, 0x9e, 0x6e, 0x2b, 0xea, 0xd3,
+	0xe2, 0x9d, 0x73, 0x0b, 0x5d, 0x43, 0x35, 0x15, 0xb9, 0x28, 0x54, 0xf1, 0xbe, 0xee, 0xd9, 0xd6,
+	0x5a, 0x36, 0x4d, 0xdb, 0x1a, 0xef, 0x69, 0x77, 0xbf, 0xfb, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x8a,
+	0x87, 0x09, 0x85, 0xe9, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -48,6 +596,26 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type TopoAdminServiceClient interface {
+	// Compact removes stale entries from the topology stores
+	// Compact is an administrative operation and requires an authorized client.
+	Compact(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResponse, error)
+	// Clear removes all devices and their annotations from the topology stores
+	// A REMOVED event is sent to subscribers for each removed device. Clear is an administrative
+	// operation and requires an authorized client.
+	Clear(ctx context.Context, in *ClearRequest, opts ...grpc.CallOption) (*ClearResponse, error)
+	// GetDeviceStats gets the access statistics of the most accessed devices since the server started
+	// Device statistics must be enabled on the server. GetDeviceStats is an administrative operation and
+	// requires an authorized client.
+	GetDeviceStats(ctx context.Context, in *GetDeviceStatsRequest, opts ...grpc.CallOption) (*GetDeviceStatsResponse, error)
+	// Snapshot streams a snapshot of the topology stores as a blob that can be restored with Restore
+	// Entries are read one by one, so writes made while the snapshot is taken may or may not be included.
+	// Snapshot is an administrative operation and requires an authorized client.
+	Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (TopoAdminService_SnapshotClient, error)
+	// Restore restores a snapshot taken with Snapshot to the topology stores
+	// Restored entries are written individually, so subscribers receive an event for each restored entry
+	// and derived indexes are rebuilt from the events. Restore is an administrative operation and requires an
+	// authorized client.
+	Restore(ctx context.Context, opts ...grpc.CallOption) (TopoAdminService_RestoreClient, error)
 }
 
 type topoAdminServiceClient struct {
@@ -58,22 +626,276 @@ func NewTopoAdminServiceClient(cc *grpc.ClientConn) TopoAdminServiceClient {
 	return &topoAdminServiceClient{cc}
 }
 
+func (c *topoAdminServiceClient) Compact(ctx context.Context, in *CompactRequest, opts ...grpc.CallOption) (*CompactResponse, error) {
+	out := new(CompactResponse)
+	err := c.cc.Invoke(ctx, "/topo.admin.TopoAdminService/Compact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *topoAdminServiceClient) Clear(ctx context.Context, in *ClearRequest, opts ...grpc.CallOption) (*ClearResponse, error) {
+	out := new(ClearResponse)
+	err := c.cc.Invoke(ctx, "/topo.admin.TopoAdminService/Clear", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *topoAdminServiceClient) GetDeviceStats(ctx context.Context, in *GetDeviceStatsRequest, opts ...grpc.CallOption) (*GetDeviceStatsResponse, error) {
+	out := new(GetDeviceStatsResponse)
+	err := c.cc.Invoke(ctx, "/topo.admin.TopoAdminService/GetDeviceStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *topoAdminServiceClient) Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (TopoAdminService_SnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TopoAdminService_serviceDesc.Streams[0], "/topo.admin.TopoAdminService/Snapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &topoAdminServiceSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TopoAdminService_SnapshotClient interface {
+	Recv() (*SnapshotChunk, error)
+	grpc.ClientStream
+}
+
+type topoAdminServiceSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *topoAdminServiceSnapshotClient) Recv() (*SnapshotChunk, error) {
+	m := new(SnapshotChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *topoAdminServiceClient) Restore(ctx context.Context, opts ...grpc.CallOption) (TopoAdminService_RestoreClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TopoAdminService_serviceDesc.Streams[1], "/topo.admin.TopoAdminService/Restore", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &topoAdminServiceRestoreClient{stream}
+	return x, nil
+}
+
+type TopoAdminService_RestoreClient interface {
+	Send(*RestoreRequest) error
+	CloseAndRecv() (*RestoreResponse, error)
+	grpc.ClientStream
+}
+
+type topoAdminServiceRestoreClient struct {
+	grpc.ClientStream
+}
+
+func (x *topoAdminServiceRestoreClient) Send(m *RestoreRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *topoAdminServiceRestoreClient) CloseAndRecv() (*RestoreResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TopoAdminServiceServer is the server API for TopoAdminService service.
 type TopoAdminServiceServer interface {
+	// Compact removes stale entries from the topology stores
+	// Compact is an administrative operation and requires an authorized client.
+	Compact(context.Context, *CompactRequest) (*CompactResponse, error)
+	// Clear removes all devices and their annotations from the topology stores
+	// A REMOVED event is sent to subscribers for each removed device. Clear is an administrative
+	// operation and requires an authorized client.
+	Clear(context.Context, *ClearRequest) (*ClearResponse, error)
+	// GetDeviceStats gets the access statistics of the most accessed devices since the server started
+	// Device statistics must be enabled on the server. GetDeviceStats is an administrative operation and
+	// requires an authorized client.
+	GetDeviceStats(context.Context, *GetDeviceStatsRequest) (*GetDeviceStatsResponse, error)
+	// Snapshot streams a snapshot of the topology stores as a blob that can be restored with Restore
+	// Entries are read one by one, so writes made while the snapshot is taken may or may not be included.
+	// Snapshot is an administrative operation and requires an authorized client.
+	Snapshot(*SnapshotRequest, TopoAdminService_SnapshotServer) error
+	// Restore restores a snapshot taken with Snapshot to the topology stores
+	// Restored entries are written individually, so subscribers receive an event for each restored entry
+	// and derived indexes are rebuilt from the events. Restore is an administrative operation and requires an
+	// authorized client.
+	Restore(TopoAdminService_RestoreServer) error
 }
 
 // UnimplementedTopoAdminServiceServer can be embedded to have forward compatible implementations.
 type UnimplementedTopoAdminServiceServer struct {
 }
 
+func (*UnimplementedTopoAdminServiceServer) Compact(ctx context.Context, req *CompactRequest) (*CompactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Compact not implemented")
+}
+func (*UnimplementedTopoAdminServiceServer) Clear(ctx context.Context, req *ClearRequest) (*ClearResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clear not implemented")
+}
+func (*UnimplementedTopoAdminServiceServer) GetDeviceStats(ctx context.Context, req *GetDeviceStatsRequest) (*GetDeviceStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceStats not implemented")
+}
+func (*UnimplementedTopoAdminServiceServer) Snapshot(req *SnapshotRequest, srv TopoAdminService_SnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedTopoAdminServiceServer) Restore(srv TopoAdminService_RestoreServer) error {
+	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+
 func RegisterTopoAdminServiceServer(s *grpc.Server, srv TopoAdminServiceServer) {
 	s.RegisterService(&_TopoAdminService_serviceDesc, srv)
 }
 
+func _TopoAdminService_Compact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TopoAdminServiceServer).Compact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.admin.TopoAdminService/Compact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TopoAdminServiceServer).Compact(ctx, req.(*CompactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TopoAdminService_Clear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TopoAdminServiceServer).Clear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.admin.TopoAdminService/Clear",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TopoAdminServiceServer).Clear(ctx, req.(*ClearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TopoAdminService_GetDeviceStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TopoAdminServiceServer).GetDeviceStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.admin.TopoAdminService/GetDeviceStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TopoAdminServiceServer).GetDeviceStats(ctx, req.(*GetDeviceStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TopoAdminService_Snapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TopoAdminServiceServer).Snapshot(m, &topoAdminServiceSnapshotServer{stream})
+}
+
+type TopoAdminService_SnapshotServer interface {
+	Send(*SnapshotChunk) error
+	grpc.ServerStream
+}
+
+type topoAdminServiceSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *topoAdminServiceSnapshotServer) Send(m *SnapshotChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TopoAdminService_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TopoAdminServiceServer).Restore(&topoAdminServiceRestoreServer{stream})
+}
+
+type TopoAdminService_RestoreServer interface {
+	SendAndClose(*RestoreResponse) error
+	Recv() (*RestoreRequest, error)
+	grpc.ServerStream
+}
+
+type topoAdminServiceRestoreServer struct {
+	grpc.ServerStream
+}
+
+func (x *topoAdminServiceRestoreServer) SendAndClose(m *RestoreResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *topoAdminServiceRestoreServer) Recv() (*RestoreRequest, error) {
+	m := new(RestoreRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _TopoAdminService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "topo.admin.TopoAdminService",
 	HandlerType: (*TopoAdminServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams:     []grpc.StreamDesc{},
-	Metadata:    "pkg/northbound/admin/admin.proto",
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Compact",
+			Handler:    _TopoAdminService_Compact_Handler,
+		},
+		{
+			MethodName: "Clear",
+			Handler:    _TopoAdminService_Clear_Handler,
+		},
+		{
+			MethodName: "GetDeviceStats",
+			Handler:    _TopoAdminService_GetDeviceStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Snapshot",
+			Handler:       _TopoAdminService_Snapshot_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _TopoAdminService_Restore_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/northbound/admin/admin.proto",
 }
@@ -19,6 +19,21 @@ import (
 	"google.golang.org/grpc"
 )
 
+// WithMaxCallRecvMsgSize returns a dial option that sets the maximum size in bytes of a message the client can
+// receive
+// By default, gRPC limits received messages to 4 MiB, so clients of a server configured with a larger
+// WithMaxSendMsgSize must raise their limit to receive the largest responses.
+func WithMaxCallRecvMsgSize(size int) grpc.DialOption {
+	return grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(size))
+}
+
+// WithMaxCallSendMsgSize returns a dial option that sets the maximum size in bytes of a message the client can
+// send
+// Requests larger than the server's WithMaxRecvMsgSize are rejected by the server regardless of this limit.
+func WithMaxCallSendMsgSize(size int) grpc.DialOption {
+	return grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(size))
+}
+
 // Connect establishes a client-side connection to the gRPC end-point.
 func Connect(address string, opts ...grpc.DialOption) *grpc.ClientConn {
 	conn, err := grpc.Dial(address, opts...)
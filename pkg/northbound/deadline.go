@@ -0,0 +1,87 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// NewDeadlinePolicy returns a new DeadlinePolicy configured with the given options
+func NewDeadlinePolicy(opts ...DeadlineOption) *DeadlinePolicy {
+	policy := &DeadlinePolicy{}
+	for _, opt := range opts {
+		opt(policy)
+	}
+	return policy
+}
+
+// DeadlineOption is an option for configuring a DeadlinePolicy
+type DeadlineOption func(*DeadlinePolicy)
+
+// WithDefaultDeadline sets the deadline applied to requests for which the client set no deadline
+// A timeout of 0 leaves requests without a deadline.
+func WithDefaultDeadline(timeout time.Duration) DeadlineOption {
+	return func(policy *DeadlinePolicy) {
+		policy.defaultTimeout = timeout
+	}
+}
+
+// WithMaxDeadline caps client deadlines that are further away than the given timeout
+// A timeout of 0 leaves client deadlines uncapped.
+func WithMaxDeadline(timeout time.Duration) DeadlineOption {
+	return func(policy *DeadlinePolicy) {
+		policy.maxTimeout = timeout
+	}
+}
+
+// DeadlinePolicy bounds the time unary requests to the server may take
+type DeadlinePolicy struct {
+	defaultTimeout time.Duration
+	maxTimeout     time.Duration
+}
+
+// UnaryInterceptor returns a unary interceptor that applies the deadline policy to the request context
+// Streaming RPCs, e.g. subscriptions, are expected to be long-lived and are not bounded by the policy.
+// If the handler fails because the deadline expired, the request fails with DeadlineExceeded.
+func (p *DeadlinePolicy) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := p.withDeadline(ctx)
+		defer cancel()
+		response, err := handler(ctx, req)
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			if _, ok := status.FromError(err); !ok {
+				return nil, status.Errorf(codes.DeadlineExceeded, "deadline exceeded for %s", info.FullMethod)
+			}
+		}
+		return response, err
+	}
+}
+
+// withDeadline returns a copy of the given context whose deadline conforms to the policy
+func (p *DeadlinePolicy) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		if p.defaultTimeout > 0 {
+			return context.WithTimeout(ctx, p.defaultTimeout)
+		}
+	} else if p.maxTimeout > 0 && time.Until(deadline) > p.maxTimeout {
+		return context.WithTimeout(ctx, p.maxTimeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,221 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"fmt"
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// The store benchmarks run against the local store. The helpers take the Store to measure, so the Atomix
+// store can be compared by calling them with a store connected to an Atomix cluster.
+
+func BenchmarkStoreLoad(b *testing.B) {
+	benchmarkStoreLoad(b, NewLocalStore())
+}
+
+func BenchmarkStoreStore(b *testing.B) {
+	benchmarkStoreStore(b, NewLocalStore())
+}
+
+func BenchmarkWatchFanout(b *testing.B) {
+	for _, watchers := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("watchers=%d", watchers), func(b *testing.B) {
+			benchmarkWatchFanout(b, NewLocalStore(), watchers)
+		})
+	}
+}
+
+// benchmarkStoreLoad measures the cost of loading a device from the given store
+func benchmarkStoreLoad(b *testing.B, store Store) {
+	device := &Device{
+		Id:      "benchmark-load",
+		Address: "localhost:5150",
+	}
+	if err := store.Store(context.Background(), device); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Load(context.Background(), device.Id); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkStoreStore measures the cost of storing a new device in the given store
+func benchmarkStoreStore(b *testing.B, store Store) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		device := &Device{
+			Id:      fmt.Sprintf("benchmark-store-%d", i),
+			Address: "localhost:5150",
+		}
+		if err := store.Store(context.Background(), device); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkWatchFanout measures the cost of delivering device events to the given number of watchers of the
+// given store. Each iteration stores one device and waits for the resulting event to be received by every
+// watcher.
+func benchmarkWatchFanout(b *testing.B, store Store, watchers int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < watchers; i++ {
+		ch := make(chan *Event)
+		if err := store.WatchFrom(ctx, 0, ch); err != nil {
+			b.Fatal(err)
+		}
+		go func() {
+			for event := range ch {
+				if event.Type != EventNone {
+					wg.Done()
+				}
+			}
+		}()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(watchers)
+		device := &Device{
+			Id:      fmt.Sprintf("benchmark-watch-%d", i),
+			Address: "localhost:5150",
+		}
+		if err := store.Store(context.Background(), device); err != nil {
+			b.Fatal(err)
+		}
+		wg.Wait()
+	}
+}
+
+func BenchmarkDeviceEncoding(b *testing.B) {
+	for _, configSize := range []int{1024, 16 * 1024, 256 * 1024} {
+		for _, compressionThreshold := range []int{0, 4096} {
+			b.Run(fmt.Sprintf("config=%d/threshold=%d", configSize, compressionThreshold), func(b *testing.B) {
+				benchmarkDeviceEncoding(b, configSize, compressionThreshold)
+			})
+		}
+	}
+}
+
+// benchmarkDeviceEncoding measures the cost of encoding and decoding a device with a protocol configuration
+// of the given size using the given compression threshold. The encoded size of the device is logged so
+// the CPU cost of compression can be compared with the space saved.
+func benchmarkDeviceEncoding(b *testing.B, configSize int, compressionThreshold int) {
+	var config []byte
+	for i := 0; len(config) < configSize; i++ {
+		config = append(config, fmt.Sprintf(`{"name":"eth%d","mtu":%d,"enabled":%t}`, i, 1500+rand.Intn(8000), i%3 != 0)...)
+	}
+	config = config[:configSize]
+	device := &Device{
+		Id:      "benchmark-encoding",
+		Address: "localhost:5150",
+		Protocols: []*Protocol{
+			{
+				Type:   Protocol_GNMI,
+				Config: config,
+			},
+		},
+	}
+
+	value, err := encodeDevice(device, compressionThreshold)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Logf("encoded %d bytes of configuration to %d bytes", configSize, len(value))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, err := encodeDevice(device, compressionThreshold)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := decodeDevice(device.Id, value, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListDecode(b *testing.B) {
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("devices=10000/workers=%d", workers), func(b *testing.B) {
+			benchmarkListDecode(b, 10000, workers)
+		})
+	}
+}
+
+// benchmarkListDecode measures the cost of decoding the given number of listed devices with the given number
+// of workers. Comparing a single worker with multiple workers shows the speedup of decoding large
+// topologies concurrently.
+func benchmarkListDecode(b *testing.B, devices int, workers int) {
+	entries := make([]*map_.KeyValue, devices)
+	for i := 0; i < devices; i++ {
+		device := &Device{
+			Id:      fmt.Sprintf("benchmark-list-%d", i),
+			Address: "localhost:5150",
+			Protocols: []*Protocol{
+				{
+					Type:   Protocol_GNMI,
+					Config: make([]byte, 1024),
+				},
+			},
+		}
+		value, err := encodeDevice(device, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		entries[i] = &map_.KeyValue{
+			Key:     device.Id,
+			Value:   value,
+			Version: int64(i + 1),
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mapCh := make(chan *map_.KeyValue, devices)
+		for _, kv := range entries {
+			mapCh <- kv
+		}
+		close(mapCh)
+
+		ch := make(chan *Device)
+		decodeEntries(mapCh, ch, workers, func(kv *map_.KeyValue, err error) {
+			b.Error(err)
+		})
+		count := 0
+		for range ch {
+			count++
+		}
+		if count != devices {
+			b.Fatalf("decoded %d of %d devices", count, devices)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"sync"
+	"time"
+)
+
+// newReadCache returns a new empty readCache
+func newReadCache() *readCache {
+	return &readCache{
+		devices: make(map[string]*cachedDevice),
+	}
+}
+
+// readCache caches the devices last read from the store
+// The cache is used to serve stale reads to clients that opt in to them when the store is unavailable.
+type readCache struct {
+	mu       sync.RWMutex
+	devices  map[string]*cachedDevice
+	list     []*Device
+	listedAt time.Time
+}
+
+// cachedDevice is a device and its annotations as last read from the store
+type cachedDevice struct {
+	device      *Device
+	annotations *Annotations
+	cachedAt    time.Time
+}
+
+// storeDevice caches the given device and annotations
+func (c *readCache) storeDevice(device *Device, annotations *Annotations) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.devices[device.Id] = &cachedDevice{
+		device:      device,
+		annotations: annotations,
+		cachedAt:    time.Now(),
+	}
+}
+
+// removeDevice removes the given device from the cache
+func (c *readCache) removeDevice(deviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.devices, deviceID)
+}
+
+// loadDevice returns the cached device with the given ID
+func (c *readCache) loadDevice(deviceID string) (*cachedDevice, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cached, ok := c.devices[deviceID]
+	return cached, ok
+}
+
+// storeList caches the given complete list of devices
+func (c *readCache) storeList(devices []*Device) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = devices
+	c.listedAt = time.Now()
+}
+
+// loadList returns the cached list of devices and the time at which it was cached
+func (c *readCache) loadList() ([]*Device, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.list, c.listedAt, c.list != nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"reflect"
+	"strings"
+)
+
+// GetCapabilities gets the optional features and device fields supported by the server
+func (s *Server) GetCapabilities(ctx context.Context, request *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return &GetCapabilitiesResponse{
+		Features: &Features{
+			Labels:       true,
+			Links:        false,
+			SoftDelete:   false,
+			Ownership:    true,
+			Annotations:  true,
+			FieldMasks:   true,
+			Heartbeats:   true,
+			History:      s.history != nil,
+			Writable:     !s.readOnly,
+			ForceUpdates: s.allowForceUpdates,
+			SecretAccess: s.allowSecretAccess,
+			Groups:       true,
+		},
+		DeviceFields: deviceFields(),
+	}, nil
+}
+
+// deviceFields returns the protobuf names of the top-level Device fields
+func deviceFields() []string {
+	var fields []string
+	for _, prop := range proto.GetProperties(reflect.TypeOf(Device{})).Prop {
+		if prop.OrigName != "" && !strings.HasPrefix(prop.Name, "XXX_") {
+			fields = append(fields, prop.OrigName)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,73 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CompareAndSwapField atomically sets a field of a device if the field has the expected value
+// The stored device is read and written with its version, so the field is only set if it still has the
+// expected value when the device is stored.
+func (s *Server) CompareAndSwapField(ctx context.Context, request *CompareAndSwapFieldRequest) (*CompareAndSwapFieldResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	mask := &field_mask.FieldMask{Paths: []string{request.Path}}
+	if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if request.Path == "" {
+		return nil, status.Error(codes.InvalidArgument, "no field specified")
+	} else if err := validateFieldMask(mask); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var device *Device
+	err := s.modifyDevice(ctx, request.DeviceId, "compare-and-swap", func(stored *Device) error {
+		if !fieldEqual(stored, request.Expected, request.Path) {
+			return status.Error(codes.FailedPrecondition, fmt.Sprintf("field %s does not have the expected value", request.Path))
+		}
+		previous := proto.Clone(stored).(*Device)
+		value := request.Value
+		if value == nil {
+			value = &Device{}
+		}
+		applyFieldMask(stored, value, mask)
+		if err := stored.ValidateTransition(previous); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		} else if err := stored.NormalizeCredentials(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		} else if err := stored.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		} else if err := s.runValidators(stored); err != nil {
+			return err
+		} else if err := s.validateParent(ctx, stored); err != nil {
+			return err
+		}
+		device = stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CompareAndSwapFieldResponse{
+		Metadata: device.Metadata,
+	}, nil
+}
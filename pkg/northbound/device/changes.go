@@ -0,0 +1,83 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"crypto/sha256"
+)
+
+// deviceDigest is a digest of a device used to determine which parts of the device changed
+// The credentials, lifecycle status and maintenance status are digested separately from the rest of the device
+// so that credential rotations, lifecycle changes and maintenance changes can be distinguished from other
+// updates. Only digests are retained to avoid holding credentials in memory.
+type deviceDigest struct {
+	device      [sha256.Size]byte
+	credentials [sha256.Size]byte
+	lifecycle   [sha256.Size]byte
+	maintenance [sha256.Size]byte
+}
+
+// newDeviceDigest returns the digest of the given device
+// The rest of the device is digested with Device.Hash, so the digest is independent of map ordering and of
+// the metadata and update time set by the store.
+func newDeviceDigest(device *Device) deviceDigest {
+	stripped := *device
+	stripped.Credentials = nil
+	stripped.LifecycleStatus = nil
+	stripped.Maintenance = nil
+	return deviceDigest{
+		device:      stripped.Hash(),
+		credentials: sha256.Sum256(marshalDeterministic(device.GetCredentials())),
+		lifecycle:   sha256.Sum256(marshalDeterministic(device.GetLifecycleStatus())),
+		maintenance: sha256.Sum256(marshalDeterministic(device.GetMaintenance())),
+	}
+}
+
+// changeDetector tracks the digests of the devices sent to a subscriber to determine the subtype of updates
+type changeDetector map[string]deviceDigest
+
+// subtype records the given event and returns its subtype
+func (d changeDetector) subtype(event *Event) ListResponse_Subtype {
+	if event.Device == nil {
+		return ListResponse_GENERAL
+	}
+	id := event.Device.Id
+	switch event.Type {
+	case EventRemoved:
+		delete(d, id)
+		return ListResponse_GENERAL
+	case EventAnnotated:
+		return ListResponse_GENERAL
+	}
+
+	digest := newDeviceDigest(event.Device)
+	previous, ok := d[id]
+	d[id] = digest
+	if event.Type != EventUpdated || !ok || previous.device != digest.device {
+		return ListResponse_GENERAL
+	}
+	credentials := previous.credentials != digest.credentials
+	lifecycle := previous.lifecycle != digest.lifecycle
+	maintenance := previous.maintenance != digest.maintenance
+	switch {
+	case credentials && !lifecycle && !maintenance:
+		return ListResponse_CREDENTIALS
+	case lifecycle && !credentials && !maintenance:
+		return ListResponse_LIFECYCLE
+	case maintenance && !credentials && !lifecycle:
+		return ListResponse_MAINTENANCE
+	}
+	return ListResponse_GENERAL
+}
@@ -0,0 +1,77 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"io/ioutil"
+)
+
+// compressedValueHeader is the first byte of compressed device values
+// A protobuf-encoded device never starts with a zero byte because zero is not a valid field tag, so values
+// stored before compression was enabled or below the compression threshold are decoded unchanged.
+const compressedValueHeader byte = 0
+
+// encodeDevice encodes the given device, compressing the encoded device if it's larger than the given
+// threshold, and prefixes it with the current schema version
+// Compression is disabled if the threshold is 0. Devices that don't get smaller when compressed are
+// stored uncompressed. Devices are only encoded to be written to a store, so the device's update time is
+// set to the current time before it's encoded.
+func encodeDevice(device *Device, threshold int) ([]byte, error) {
+	device.Updated = ptypes.TimestampNow()
+	value, err := compressDevice(device, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return addSchemaVersion(value), nil
+}
+
+// compressDevice encodes the given device, compressing the encoded device if it's larger than the given
+// threshold
+func compressDevice(device *Device, threshold int) ([]byte, error) {
+	value, err := proto.Marshal(device)
+	if err != nil || threshold <= 0 || len(value) <= threshold {
+		return value, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(compressedValueHeader)
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(value); err != nil {
+		return nil, err
+	} else if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if buf.Len() >= len(value) {
+		return value, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressValue returns the protobuf encoding of the given device value, decompressing it if necessary
+func decompressValue(value []byte) ([]byte, error) {
+	if len(value) == 0 || value[0] != compressedValueHeader {
+		return value, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(value[1:]))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,95 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// errUnchanged is returned by a device change to skip storing a device it did not change
+var errUnchanged = errors.New("device unchanged")
+
+// unknownReporter identifies a reporting agent for which the context carries no peer information
+const unknownReporter = "unknown"
+
+// ReportState sets the connection state of a device reported by a southbound agent
+func (s *Server) ReportState(ctx context.Context, request *ReportStateRequest) (*ReportStateResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if _, ok := ConnectionState_name[int32(request.State)]; !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid connection state")
+	}
+	reporter := reporterIdentity(ctx)
+
+	var device *Device
+	err := s.modifyDevice(ctx, request.DeviceId, "report-state", func(stored *Device) error {
+		device = stored
+		current := stored.ConnectionStatus
+		if current != nil && current.State == request.State && current.Reason == request.Reason {
+			return errUnchanged
+		}
+
+		// The timestamp marks when the device entered the state, so it's retained if only the reason changed
+		timestamp := ptypes.TimestampNow()
+		if current != nil && current.State == request.State && current.Timestamp != nil {
+			timestamp = current.Timestamp
+		}
+		stored.ConnectionStatus = &ConnectionStatus{
+			State:     request.State,
+			Reason:    request.Reason,
+			Timestamp: timestamp,
+			Reporter:  reporter,
+		}
+		return nil
+	})
+	if err != nil && err != errUnchanged {
+		return nil, err
+	}
+	if err == nil {
+		s.logger.Info("Device connection state reported", DeviceIDField(device.Id), OperationField("report-state"), VersionField(device.Metadata.Version), Field{Key: "state", Value: request.State.String()}, Field{Key: "reporter", Value: reporter})
+	}
+	return &ReportStateResponse{
+		Status:   device.ConnectionStatus,
+		Metadata: device.Metadata,
+	}, nil
+}
+
+// reporterIdentity returns the identity of the agent making the request in the given context
+// Agents that present a verified client certificate are identified by the certificate's common name.
+// Other agents are identified by their address.
+func reporterIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return unknownReporter
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+		if name := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; name != "" {
+			return name
+		}
+	}
+	if p.Addr != nil {
+		return p.Addr.String()
+	}
+	return unknownReporter
+}
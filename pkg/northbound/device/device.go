@@ -0,0 +1,267 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+// DefaultIDPattern is the default pattern for device IDs
+// IDs must be RFC 1123 DNS labels, so they can be used in URLs and as Kubernetes label values.
+var DefaultIDPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// GetProtocol returns the configuration for the given protocol type or nil if the protocol is not configured
+func (m *Device) GetProtocol(protocolType Protocol_Type) *Protocol {
+	for _, protocol := range m.GetProtocols() {
+		if protocol.Type == protocolType {
+			return protocol
+		}
+	}
+	return nil
+}
+
+// GetAllAddresses returns the primary address of the device followed by its failover addresses
+func (m *Device) GetAllAddresses() []string {
+	addresses := make([]string, 0, len(m.GetAddresses())+1)
+	if m.GetAddress() != "" {
+		addresses = append(addresses, m.GetAddress())
+	}
+	for _, address := range m.GetAddresses() {
+		if address != m.GetAddress() {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// Validate checks the device against the validation rules documented on the Device message
+// The rules are the same rules applied by clients, so a device that passes client-side validation
+// is accepted by the service. They're written by hand because the protos are compiled without
+// protoc-gen-validate, but Validate and DeviceValidationError follow the shape of its generated code so the
+// rules can be moved to proto annotations if the plugin is adopted.
+func (m *Device) Validate() error {
+	if m.GetId() == "" {
+		return DeviceValidationError{field: "id", reason: "value is required"}
+	}
+
+	if m.GetAddress() != "" {
+		if err := validateAddress(m.GetAddress()); err != nil {
+			return DeviceValidationError{field: "address", reason: err.Error()}
+		}
+	}
+	for i, address := range m.GetAddresses() {
+		if err := validateAddress(address); err != nil {
+			return DeviceValidationError{field: fmt.Sprintf("addresses[%d]", i), reason: err.Error()}
+		}
+	}
+
+	if m.GetTimeout() != nil {
+		timeout, err := ptypes.Duration(m.GetTimeout())
+		if err != nil {
+			return DeviceValidationError{field: "timeout", reason: err.Error()}
+		} else if timeout < 0 {
+			return DeviceValidationError{field: "timeout", reason: "value must be greater than or equal to 0s"}
+		}
+	}
+
+	if err := m.GetCredentials().validate(); err != nil {
+		return err
+	}
+
+	if location := m.GetLocation(); location != nil {
+		if math.IsNaN(location.Lat) || location.Lat < -90 || location.Lat > 90 {
+			return DeviceValidationError{field: "location.lat", reason: "value must be between -90 and 90"}
+		} else if math.IsNaN(location.Lng) || location.Lng < -180 || location.Lng > 180 {
+			return DeviceValidationError{field: "location.lng", reason: "value must be between -180 and 180"}
+		} else if math.IsNaN(location.Altitude) || math.IsInf(location.Altitude, 0) {
+			return DeviceValidationError{field: "location.altitude", reason: "value must be a finite number"}
+		}
+	}
+
+	protocols := make(map[Protocol_Type]bool)
+	for i, protocol := range m.GetProtocols() {
+		if protocols[protocol.Type] {
+			return DeviceValidationError{field: fmt.Sprintf("protocols[%d]", i), reason: fmt.Sprintf("duplicate protocol %s", protocol.Type)}
+		}
+		protocols[protocol.Type] = true
+	}
+	return nil
+}
+
+// ValidateID checks that the given device ID matches the given pattern
+func ValidateID(id string, pattern *regexp.Regexp) error {
+	if !pattern.MatchString(id) {
+		return DeviceValidationError{field: "id", reason: fmt.Sprintf("value must match the pattern %s", pattern)}
+	}
+	return nil
+}
+
+// NormalizeCredentials migrates the deprecated user and password credentials fields to basic_auth
+// User and password take precedence over the corresponding basic_auth fields, so clients that only set the
+// deprecated fields can update the user or password of a device. An error is returned if the deprecated
+// fields are combined with an SSH key or token.
+func (m *Device) NormalizeCredentials() error {
+	credentials := m.GetCredentials()
+	if credentials == nil || (credentials.User == "" && credentials.Password == "") {
+		return nil
+	}
+	if credentials.Credential != nil && credentials.GetBasicAuth() == nil {
+		return DeviceValidationError{field: "credentials", reason: "user and password cannot be combined with an SSH key or token"}
+	}
+
+	basicAuth := credentials.GetBasicAuth()
+	if basicAuth == nil {
+		basicAuth = &BasicAuth{}
+		credentials.Credential = &Credentials_BasicAuth{BasicAuth: basicAuth}
+	}
+	if credentials.User != "" {
+		basicAuth.User = credentials.User
+	}
+	if credentials.Password != "" {
+		basicAuth.Password = credentials.Password
+	}
+	credentials.User = ""
+	credentials.Password = ""
+	return nil
+}
+
+// EffectiveUser returns the user of the credential, or the deprecated user if no credential is set
+func (m *Credentials) EffectiveUser() string {
+	switch credential := m.GetCredential().(type) {
+	case *Credentials_BasicAuth:
+		return credential.BasicAuth.GetUser()
+	case *Credentials_SshKey:
+		return credential.SshKey.GetUser()
+	}
+	return m.GetUser()
+}
+
+// EffectivePassword returns the basic auth password, or the deprecated password if no credential is set
+func (m *Credentials) EffectivePassword() string {
+	if basicAuth := m.GetBasicAuth(); basicAuth != nil {
+		return basicAuth.GetPassword()
+	}
+	return m.GetPassword()
+}
+
+// validate checks that the credentials contain at most one credential and that the credential is complete
+func (m *Credentials) validate() error {
+	switch credential := m.GetCredential().(type) {
+	case *Credentials_BasicAuth:
+		if m.GetUser() != "" || m.GetPassword() != "" {
+			return DeviceValidationError{field: "credentials", reason: "user and password cannot be combined with basic_auth"}
+		}
+	case *Credentials_SshKey:
+		if m.GetUser() != "" || m.GetPassword() != "" {
+			return DeviceValidationError{field: "credentials", reason: "user and password cannot be combined with ssh_key"}
+		} else if credential.SshKey.GetPrivateKeyRef() == "" {
+			return DeviceValidationError{field: "credentials.ssh_key.private_key_ref", reason: "value is required"}
+		}
+	case *Credentials_Token:
+		if m.GetUser() != "" || m.GetPassword() != "" {
+			return DeviceValidationError{field: "credentials", reason: "user and password cannot be combined with token"}
+		} else if credential.Token.GetValueRef() == "" {
+			return DeviceValidationError{field: "credentials.token.value_ref", reason: "value is required"}
+		}
+	}
+	return nil
+}
+
+// lifecyclePhaseRanks is the order of lifecycle phases
+// A device may only transition to a phase of the same or a higher rank unless the transition is forced.
+var lifecyclePhaseRanks = map[LifecyclePhase]int{
+	LifecyclePhase_UNKNOWN:     0,
+	LifecyclePhase_CONFIGURING: 1,
+	LifecyclePhase_CONFIGURED:  2,
+	LifecyclePhase_FAILED:      2,
+}
+
+// Hash returns a SHA-256 hash of the content of the device
+// The store metadata and update time are set by the store on every write, so they're excluded and the hash
+// changes only when the content of the device changes. The device is marshaled deterministically, with map
+// entries sorted by key, so equal devices hash identically regardless of the order in which their labels
+// were set.
+func (m *Device) Hash() [sha256.Size]byte {
+	content := *m
+	content.Metadata = nil
+	content.Updated = nil
+	return sha256.Sum256(marshalDeterministic(&content))
+}
+
+// marshalDeterministic marshals the given message with map entries sorted by key
+// Messages that can't be marshaled have no content to hash, so they're marshaled as empty.
+func marshalDeterministic(message proto.Message) []byte {
+	buffer := proto.NewBuffer(nil)
+	buffer.SetDeterministic(true)
+	if err := buffer.Marshal(message); err != nil {
+		return nil
+	}
+	return buffer.Bytes()
+}
+
+// IsQuiesced returns whether the device is quiesced for maintenance
+func (m *Device) IsQuiesced() bool {
+	return m.GetMaintenance().GetQuiesced()
+}
+
+// ValidateTransition checks that the lifecycle phase of the device does not move backward from the
+// phase of the given previous version of the device
+func (m *Device) ValidateTransition(previous *Device) error {
+	from := previous.GetLifecycleStatus().GetPhase()
+	to := m.GetLifecycleStatus().GetPhase()
+	if lifecyclePhaseRanks[to] < lifecyclePhaseRanks[from] {
+		return DeviceValidationError{
+			field:  "lifecycle_status.phase",
+			reason: fmt.Sprintf("cannot transition from %s to %s", from, to),
+		}
+	}
+	return nil
+}
+
+// validateAddress returns an error if the given address is not a valid host:port address
+func validateAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("value must be a host:port address")
+	} else if host == "" {
+		return fmt.Errorf("value must include a host")
+	} else if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("value must include a valid port")
+	}
+	return nil
+}
+
+// DeviceValidationError is the validation error returned by Device.Validate if the device is invalid
+type DeviceValidationError struct {
+	field  string
+	reason string
+}
+
+// Field returns the name of the invalid field
+func (e DeviceValidationError) Field() string { return e.field }
+
+// Reason returns the reason the field is invalid
+func (e DeviceValidationError) Reason() string { return e.reason }
+
+func (e DeviceValidationError) Error() string {
+	return fmt.Sprintf("invalid Device.%s: %s", e.field, e.reason)
+}
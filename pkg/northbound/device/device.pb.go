@@ -10,6 +10,8 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	duration "github.com/golang/protobuf/ptypes/duration"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -27,6 +29,104 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// ConnectionState is the state of the connection to a device
+type ConnectionState int32
+
+const (
+	// CONNECTION_UNKNOWN indicates the connection state of the device is not known
+	ConnectionState_CONNECTION_UNKNOWN ConnectionState = 0
+	// CONNECTED indicates the device is reachable
+	ConnectionState_CONNECTED ConnectionState = 1
+	// DISCONNECTED indicates the device is not reachable
+	ConnectionState_DISCONNECTED ConnectionState = 2
+)
+
+var ConnectionState_name = map[int32]string{
+	0: "CONNECTION_UNKNOWN",
+	1: "CONNECTED",
+	2: "DISCONNECTED",
+}
+
+var ConnectionState_value = map[string]int32{
+	"CONNECTION_UNKNOWN": 0,
+	"CONNECTED":          1,
+	"DISCONNECTED":       2,
+}
+
+func (x ConnectionState) String() string {
+	return proto.EnumName(ConnectionState_name, int32(x))
+}
+
+func (ConnectionState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{0}
+}
+
+// LifecyclePhase is a phase of the device configuration lifecycle
+type LifecyclePhase int32
+
+const (
+	// UNKNOWN indicates the lifecycle phase of the device is not known
+	LifecyclePhase_UNKNOWN LifecyclePhase = 0
+	// CONFIGURING indicates the device is being configured
+	LifecyclePhase_CONFIGURING LifecyclePhase = 1
+	// CONFIGURED indicates the device has been configured
+	LifecyclePhase_CONFIGURED LifecyclePhase = 2
+	// FAILED indicates the device could not be configured
+	LifecyclePhase_FAILED LifecyclePhase = 3
+)
+
+var LifecyclePhase_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "CONFIGURING",
+	2: "CONFIGURED",
+	3: "FAILED",
+}
+
+var LifecyclePhase_value = map[string]int32{
+	"UNKNOWN":     0,
+	"CONFIGURING": 1,
+	"CONFIGURED":  2,
+	"FAILED":      3,
+}
+
+func (x LifecyclePhase) String() string {
+	return proto.EnumName(LifecyclePhase_name, int32(x))
+}
+
+func (LifecyclePhase) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{1}
+}
+
+// Device view
+type ListRequest_View int32
+
+const (
+	// FULL includes all device fields
+	ListRequest_FULL ListRequest_View = 0
+	// BASIC omits the device credentials, TLS configuration and protocols
+	// The basic view is intended for clients that only need to identify and locate devices, e.g. to
+	// display a list of devices, and avoids sending secrets and large protocol configurations to them.
+	ListRequest_BASIC ListRequest_View = 1
+)
+
+var ListRequest_View_name = map[int32]string{
+	0: "FULL",
+	1: "BASIC",
+}
+
+var ListRequest_View_value = map[string]int32{
+	"FULL":  0,
+	"BASIC": 1,
+}
+
+func (x ListRequest_View) String() string {
+	return proto.EnumName(ListRequest_View_name, int32(x))
+}
+
+func (ListRequest_View) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{8, 0}
+}
+
 // Device event type
 type ListResponse_Type int32
 
@@ -39,6 +139,14 @@ const (
 	ListResponse_UPDATED ListResponse_Type = 2
 	// REMOVED is an event which occurs when a device is removed from the topology
 	ListResponse_REMOVED ListResponse_Type = 3
+	// ANNOTATED is an event which occurs when a device's annotations are set
+	ListResponse_ANNOTATED ListResponse_Type = 4
+	// REPLAY_DONE marks the end of the devices streamed to a subscriber before events are streamed
+	// The response carries no device and is only sent if requested with ListRequest.replay_done.
+	ListResponse_REPLAY_DONE ListResponse_Type = 5
+	// HEARTBEAT is sent to a subscriber that requested heartbeats while no other responses are sent
+	// The response carries no device and the sequence number of the last event sent to the subscriber.
+	ListResponse_HEARTBEAT ListResponse_Type = 6
 )
 
 var ListResponse_Type_name = map[int32]string{
@@ -46,13 +154,19 @@ var ListResponse_Type_name = map[int32]string{
 	1: "ADDED",
 	2: "UPDATED",
 	3: "REMOVED",
+	4: "ANNOTATED",
+	5: "REPLAY_DONE",
+	6: "HEARTBEAT",
 }
 
 var ListResponse_Type_value = map[string]int32{
-	"NONE":    0,
-	"ADDED":   1,
-	"UPDATED": 2,
-	"REMOVED": 3,
+	"NONE":        0,
+	"ADDED":       1,
+	"UPDATED":     2,
+	"REMOVED":     3,
+	"ANNOTATED":   4,
+	"REPLAY_DONE": 5,
+	"HEARTBEAT":   6,
 }
 
 func (x ListResponse_Type) String() string {
@@ -60,16 +174,125 @@ func (x ListResponse_Type) String() string {
 }
 
 func (ListResponse_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{7, 0}
+	return fileDescriptor_b9d152c21573e6ba, []int{9, 0}
+}
+
+// Device event subtype
+type ListResponse_Subtype int32
+
+const (
+	// GENERAL indicates the event is not known to be limited to a specific part of the device
+	ListResponse_GENERAL ListResponse_Subtype = 0
+	// CREDENTIALS indicates only the device credentials changed, e.g. when credentials are rotated
+	ListResponse_CREDENTIALS ListResponse_Subtype = 1
+	// LIFECYCLE indicates only the device lifecycle status changed
+	ListResponse_LIFECYCLE ListResponse_Subtype = 2
+	// MAINTENANCE indicates only the device maintenance status changed, i.e. the device was quiesced or
+	// unquiesced
+	ListResponse_MAINTENANCE ListResponse_Subtype = 3
+)
+
+var ListResponse_Subtype_name = map[int32]string{
+	0: "GENERAL",
+	1: "CREDENTIALS",
+	2: "LIFECYCLE",
+	3: "MAINTENANCE",
+}
+
+var ListResponse_Subtype_value = map[string]int32{
+	"GENERAL":     0,
+	"CREDENTIALS": 1,
+	"LIFECYCLE":   2,
+	"MAINTENANCE": 3,
+}
+
+func (x ListResponse_Subtype) String() string {
+	return proto.EnumName(ListResponse_Subtype_name, int32(x))
+}
+
+func (ListResponse_Subtype) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{9, 1}
+}
+
+// Topology resource type
+type WatchAllResponse_ResourceType int32
+
+const (
+	// DEVICE indicates the event is a device event
+	WatchAllResponse_DEVICE WatchAllResponse_ResourceType = 0
+)
+
+var WatchAllResponse_ResourceType_name = map[int32]string{
+	0: "DEVICE",
+}
+
+var WatchAllResponse_ResourceType_value = map[string]int32{
+	"DEVICE": 0,
+}
+
+func (x WatchAllResponse_ResourceType) String() string {
+	return proto.EnumName(WatchAllResponse_ResourceType_name, int32(x))
+}
+
+func (WatchAllResponse_ResourceType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{26, 0}
+}
+
+// Southbound protocol type
+type Protocol_Type int32
+
+const (
+	// UNKNOWN is an unspecified protocol
+	Protocol_UNKNOWN Protocol_Type = 0
+	// GNMI is the gNMI protocol
+	Protocol_GNMI Protocol_Type = 1
+	// NETCONF is the NETCONF protocol
+	Protocol_NETCONF Protocol_Type = 2
+	// P4RUNTIME is the P4Runtime protocol
+	Protocol_P4RUNTIME Protocol_Type = 3
+	// GNOI is the gNOI protocol
+	Protocol_GNOI Protocol_Type = 4
+)
+
+var Protocol_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "GNMI",
+	2: "NETCONF",
+	3: "P4RUNTIME",
+	4: "GNOI",
+}
+
+var Protocol_Type_value = map[string]int32{
+	"UNKNOWN":   0,
+	"GNMI":      1,
+	"NETCONF":   2,
+	"P4RUNTIME": 3,
+	"GNOI":      4,
+}
+
+func (x Protocol_Type) String() string {
+	return proto.EnumName(Protocol_Type_name, int32(x))
+}
+
+func (Protocol_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{65, 0}
 }
 
 // AddRequest adds a device to the topology
 type AddRequest struct {
 	// device is the device to add
-	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// idempotency_key is an optional client-generated key identifying the request
+	// If a request with the same key was processed recently, the response to the original request is returned.
+	// A request with the same key that's still being processed is waited for. Failed requests aren't cached, so
+	// they can be retried with the same key.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// allow_duplicate_address allows the device to be added with the address of another device
+	// Duplicate addresses are only rejected if the server enforces unique addresses.
+	AllowDuplicateAddress bool     `protobuf:"varint,3,opt,name=allow_duplicate_address,json=allowDuplicateAddress,proto3" json:"allow_duplicate_address,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
 }
 
 func (m *AddRequest) Reset()         { *m = AddRequest{} }
@@ -104,6 +327,20 @@ func (m *AddRequest) GetDevice() *Device {
 	return nil
 }
 
+func (m *AddRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *AddRequest) GetAllowDuplicateAddress() bool {
+	if m != nil {
+		return m.AllowDuplicateAddress
+	}
+	return false
+}
+
 // AddResponse is sent in response to an AddDeviceRequest
 type AddResponse struct {
 	// metadata is the added device metadata
@@ -145,20 +382,128 @@ func (m *AddResponse) GetMetadata() *ObjectMetadata {
 	return nil
 }
 
-// UpdateRequest updates a device
-type UpdateRequest struct {
-	// device is the updated device
+// EnsureDeviceRequest adds a device if it doesn't already exist
+type EnsureDeviceRequest struct {
+	// device is the device to add if no device with the same ID exists
 	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *EnsureDeviceRequest) Reset()         { *m = EnsureDeviceRequest{} }
+func (m *EnsureDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*EnsureDeviceRequest) ProtoMessage()    {}
+func (*EnsureDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{2}
+}
+
+func (m *EnsureDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnsureDeviceRequest.Unmarshal(m, b)
+}
+func (m *EnsureDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnsureDeviceRequest.Marshal(b, m, deterministic)
+}
+func (m *EnsureDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnsureDeviceRequest.Merge(m, src)
+}
+func (m *EnsureDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_EnsureDeviceRequest.Size(m)
+}
+func (m *EnsureDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnsureDeviceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnsureDeviceRequest proto.InternalMessageInfo
+
+func (m *EnsureDeviceRequest) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+// EnsureDeviceResponse is sent in response to an EnsureDeviceRequest
+type EnsureDeviceResponse struct {
+	// device is the stored device, which is the requested device if it was created
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// created indicates whether the device was created by the request
+	Created              bool     `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnsureDeviceResponse) Reset()         { *m = EnsureDeviceResponse{} }
+func (m *EnsureDeviceResponse) String() string { return proto.CompactTextString(m) }
+func (*EnsureDeviceResponse) ProtoMessage()    {}
+func (*EnsureDeviceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{3}
+}
+
+func (m *EnsureDeviceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnsureDeviceResponse.Unmarshal(m, b)
+}
+func (m *EnsureDeviceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnsureDeviceResponse.Marshal(b, m, deterministic)
+}
+func (m *EnsureDeviceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnsureDeviceResponse.Merge(m, src)
+}
+func (m *EnsureDeviceResponse) XXX_Size() int {
+	return xxx_messageInfo_EnsureDeviceResponse.Size(m)
+}
+func (m *EnsureDeviceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnsureDeviceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnsureDeviceResponse proto.InternalMessageInfo
+
+func (m *EnsureDeviceResponse) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *EnsureDeviceResponse) GetCreated() bool {
+	if m != nil {
+		return m.Created
+	}
+	return false
+}
+
+// UpdateRequest updates a device
+type UpdateRequest struct {
+	// device is the updated device
+	// Devices are returned with their secrets redacted, so secrets that are empty in a full update are retained
+	// from the stored device if it has the same kind of credential. Use an update_mask including credentials or
+	// tls to remove secrets.
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// update_mask is an optional mask of the device fields to update
+	// If the mask is set, only the masked fields of the device are applied to the stored device.
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// idempotency_key is an optional client-generated key identifying the request
+	// If a request with the same key was processed recently, the response to the original request is returned.
+	// A request with the same key that's still being processed is waited for. Failed requests aren't cached, so
+	// they can be retried with the same key.
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// force indicates whether to store the device regardless of the stored device's version
+	// Forced updates are intended for data migrations and are rejected unless enabled on the server.
+	Force bool `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	// allow_duplicate_address allows the device to be updated with the address of another device
+	// Duplicate addresses are only rejected if the server enforces unique addresses.
+	AllowDuplicateAddress bool     `protobuf:"varint,5,opt,name=allow_duplicate_address,json=allowDuplicateAddress,proto3" json:"allow_duplicate_address,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
 func (m *UpdateRequest) Reset()         { *m = UpdateRequest{} }
 func (m *UpdateRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateRequest) ProtoMessage()    {}
 func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{2}
+	return fileDescriptor_b9d152c21573e6ba, []int{4}
 }
 
 func (m *UpdateRequest) XXX_Unmarshal(b []byte) error {
@@ -186,6 +531,34 @@ func (m *UpdateRequest) GetDevice() *Device {
 	return nil
 }
 
+func (m *UpdateRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *UpdateRequest) GetAllowDuplicateAddress() bool {
+	if m != nil {
+		return m.AllowDuplicateAddress
+	}
+	return false
+}
+
 // UpdateResponse is sent in response to an UpdateDeviceRequest
 type UpdateResponse struct {
 	// metadata is the updated device metadata
@@ -199,7 +572,7 @@ func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()    {}
 func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{3}
+	return fileDescriptor_b9d152c21573e6ba, []int{5}
 }
 
 func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
@@ -230,7 +603,17 @@ func (m *UpdateResponse) GetMetadata() *ObjectMetadata {
 // GetRequest gets a device by ID
 type GetRequest struct {
 	// device_id is the unique device ID with which to lookup the device
-	DeviceId             string   `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// stale_ok indicates whether the device last read by the service may be returned if the store is unavailable
+	StaleOk bool `protobuf:"varint,2,opt,name=stale_ok,json=staleOk,proto3" json:"stale_ok,omitempty"`
+	// known_version is the version of the device already known to the client
+	// If the device's version matches known_version, the response is not_modified and omits the device.
+	KnownVersion uint64 `protobuf:"varint,3,opt,name=known_version,json=knownVersion,proto3" json:"known_version,omitempty"`
+	// include_secrets indicates whether to include the device's secrets, i.e. passwords, SSH private key and
+	// token references, and the TLS key
+	// Secrets are omitted by default. Requests that include secrets are rejected with PermissionDenied unless
+	// the service allows secret access and authorizes the caller, by default by its verified client certificate.
+	IncludeSecrets       bool     `protobuf:"varint,4,opt,name=include_secrets,json=includeSecrets,proto3" json:"include_secrets,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -240,7 +623,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{4}
+	return fileDescriptor_b9d152c21573e6ba, []int{6}
 }
 
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
@@ -268,10 +651,40 @@ func (m *GetRequest) GetDeviceId() string {
 	return ""
 }
 
+func (m *GetRequest) GetStaleOk() bool {
+	if m != nil {
+		return m.StaleOk
+	}
+	return false
+}
+
+func (m *GetRequest) GetKnownVersion() uint64 {
+	if m != nil {
+		return m.KnownVersion
+	}
+	return 0
+}
+
+func (m *GetRequest) GetIncludeSecrets() bool {
+	if m != nil {
+		return m.IncludeSecrets
+	}
+	return false
+}
+
 // GetResponse carries a device
 type GetResponse struct {
 	// device is the device object
-	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// annotations is the device's annotations
+	Annotations *Annotations `protobuf:"bytes,2,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	// stale indicates whether the device was served from the service's cache because the store is unavailable
+	Stale bool `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`
+	// cached_at is the time at which a stale device was read from the store
+	CachedAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=cached_at,json=cachedAt,proto3" json:"cached_at,omitempty"`
+	// not_modified indicates whether the device's version matches the requested known_version
+	// If set, the device and annotations are omitted from the response.
+	NotModified          bool     `protobuf:"varint,5,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -281,7 +694,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{5}
+	return fileDescriptor_b9d152c21573e6ba, []int{7}
 }
 
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
@@ -309,6 +722,34 @@ func (m *GetResponse) GetDevice() *Device {
 	return nil
 }
 
+func (m *GetResponse) GetAnnotations() *Annotations {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *GetResponse) GetStale() bool {
+	if m != nil {
+		return m.Stale
+	}
+	return false
+}
+
+func (m *GetResponse) GetCachedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CachedAt
+	}
+	return nil
+}
+
+func (m *GetResponse) GetNotModified() bool {
+	if m != nil {
+		return m.NotModified
+	}
+	return false
+}
+
 // ListRequest requests a stream of devices and changes
 // By default, the request requests a stream of all devices that are present in the topology when
 // the request is received by the service. However, if `subscribe` is `true`, the stream will remain
@@ -317,7 +758,78 @@ func (m *GetResponse) GetDevice() *Device {
 type ListRequest struct {
 	// subscribe indicates whether to subscribe to events (e.g. ADD, UPDATE, and REMOVE) that occur
 	// after all devices have been streamed to the client
-	Subscribe            bool     `protobuf:"varint,1,opt,name=subscribe,proto3" json:"subscribe,omitempty"`
+	// Each version of a device is sent to the subscriber exactly once: events for changes that are already
+	// reflected in the streamed devices are not sent again.
+	Subscribe bool `protobuf:"varint,1,opt,name=subscribe,proto3" json:"subscribe,omitempty"`
+	// from_version is the device version from which to resume a subscription
+	// If set, only devices with a version greater than from_version are replayed before events are streamed.
+	// Devices removed while the client was disconnected are not replayed. Versions are only ordered within
+	// each shard of a sharded store, so from_version is rejected with FAILED_PRECONDITION if devices are sharded.
+	FromVersion uint64 `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	// annotations indicates whether to subscribe to ANNOTATED events for changes to device annotations
+	Annotations bool `protobuf:"varint,3,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	// exclude_credential_rotations indicates whether to exclude UPDATED events with the CREDENTIALS subtype
+	ExcludeCredentialRotations bool `protobuf:"varint,4,opt,name=exclude_credential_rotations,json=excludeCredentialRotations,proto3" json:"exclude_credential_rotations,omitempty"`
+	// prev_device indicates whether to include the previous value of the device in UPDATED and REMOVED events
+	PrevDevice bool `protobuf:"varint,5,opt,name=prev_device,json=prevDevice,proto3" json:"prev_device,omitempty"`
+	// stale_ok indicates whether the devices last listed by the service may be returned if the store is
+	// unavailable
+	// Stale devices are only served for requests that do not subscribe to events.
+	StaleOk bool `protobuf:"varint,6,opt,name=stale_ok,json=staleOk,proto3" json:"stale_ok,omitempty"`
+	// states filters devices by connection state
+	// If set, only devices in any of the given states are streamed. Events for devices that enter or leave
+	// the given states are streamed to subscribers, so an UPDATED event is sent when a device leaves the
+	// given states. Filters are combined with AND semantics.
+	States []ConnectionState `protobuf:"varint,7,rep,packed,name=states,proto3,enum=topo.device.ConnectionState" json:"states,omitempty"`
+	// replay_done indicates whether to send a REPLAY_DONE response once the current devices have been streamed
+	// to a subscriber
+	ReplayDone bool `protobuf:"varint,8,opt,name=replay_done,json=replayDone,proto3" json:"replay_done,omitempty"`
+	// view is the view of the devices to stream
+	// Defaults to the FULL view.
+	View ListRequest_View `protobuf:"varint,9,opt,name=view,proto3,enum=topo.device.ListRequest_View" json:"view,omitempty"`
+	// include_secrets indicates whether to include the devices' secrets, i.e. passwords, SSH private key and
+	// token references, and TLS keys
+	// Secrets are omitted by default. Requests that include secrets are rejected with PermissionDenied unless
+	// the service allows secret access and authorizes the caller, by default by its verified client certificate.
+	IncludeSecrets bool `protobuf:"varint,10,opt,name=include_secrets,json=includeSecrets,proto3" json:"include_secrets,omitempty"`
+	// heartbeat_interval is the interval at which a HEARTBEAT response is sent to a subscriber while no other
+	// responses are sent
+	// Heartbeats keep idle streams from being dropped by intermediaries and allow clients to detect a dead
+	// server. Heartbeats are not sent if the interval is not set, and intervals shorter than one second are
+	// rejected.
+	HeartbeatInterval *duration.Duration `protobuf:"bytes,11,opt,name=heartbeat_interval,json=heartbeatInterval,proto3" json:"heartbeat_interval,omitempty"`
+	// resume_token is the resume_token of the last device received on a stream whose snapshot was interrupted
+	// The devices of the snapshot are streamed in ID order, one device per response. Sends are flow controlled
+	// by gRPC, so the service streams the snapshot no faster than the client consumes it and the client never
+	// needs to buffer the snapshot. If the stream is dropped before the snapshot completes, the client can
+	// open a new stream with the same request and the resume_token of the last device it received, and the
+	// snapshot continues after that device. Devices before it are streamed again only if they've been
+	// updated since they were received. Devices removed while the client was disconnected are not streamed.
+	// Resume tokens are rejected with FAILED_PRECONDITION if devices are sharded.
+	ResumeToken string `protobuf:"bytes,12,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// exclude_quiesced indicates whether to exclude devices that are quiesced for maintenance
+	// An UPDATED event is streamed to subscribers when a device is quiesced, after which no events are
+	// streamed for the device until it's unquiesced.
+	ExcludeQuiesced bool `protobuf:"varint,13,opt,name=exclude_quiesced,json=excludeQuiesced,proto3" json:"exclude_quiesced,omitempty"`
+	// device_ids filters devices by ID
+	// If set, only the given devices are streamed, and subscribers receive events only for the given devices.
+	// Devices that don't exist are streamed to subscribers once they're added.
+	DeviceIds []string `protobuf:"bytes,14,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	// selector filters devices by label selector, e.g. "pod=a,role=leaf"
+	// If the server maintains a label index, devices are listed without scanning all devices when the
+	// selector requires a label to have a value or to be set.
+	Selector string `protobuf:"bytes,15,opt,name=selector,proto3" json:"selector,omitempty"`
+	// vendor filters devices by vendor
+	// If set, only devices with the given vendor are streamed.
+	Vendor string `protobuf:"bytes,16,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// model filters devices by hardware model
+	// If set, only devices with the given model are streamed.
+	Model string `protobuf:"bytes,17,opt,name=model,proto3" json:"model,omitempty"`
+	// skip_replay indicates whether to skip the replay of the current devices to a subscriber
+	// The current devices are replayed by default. If the replay is skipped, only events for changes made after
+	// the subscription is opened are streamed, and from_version and resume_token have no effect. A REPLAY_DONE
+	// response is still sent first if requested.
+	SkipReplay           bool     `protobuf:"varint,18,opt,name=skip_replay,json=skipReplay,proto3" json:"skip_replay,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -327,7 +839,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{6}
+	return fileDescriptor_b9d152c21573e6ba, []int{8}
 }
 
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
@@ -355,705 +867,5668 @@ func (m *ListRequest) GetSubscribe() bool {
 	return false
 }
 
-// ListResponse carries a single device event
-type ListResponse struct {
-	// type is the type of the event
-	Type ListResponse_Type `protobuf:"varint,1,opt,name=type,proto3,enum=topo.device.ListResponse_Type" json:"type,omitempty"`
-	// device is the device on which the event occurred
-	Device               *Device  `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ListRequest) GetFromVersion() uint64 {
+	if m != nil {
+		return m.FromVersion
+	}
+	return 0
 }
 
-func (m *ListResponse) Reset()         { *m = ListResponse{} }
-func (m *ListResponse) String() string { return proto.CompactTextString(m) }
-func (*ListResponse) ProtoMessage()    {}
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{7}
+func (m *ListRequest) GetAnnotations() bool {
+	if m != nil {
+		return m.Annotations
+	}
+	return false
 }
 
-func (m *ListResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
-}
-func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
-}
-func (m *ListResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListResponse.Merge(m, src)
-}
-func (m *ListResponse) XXX_Size() int {
-	return xxx_messageInfo_ListResponse.Size(m)
-}
-func (m *ListResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+func (m *ListRequest) GetExcludeCredentialRotations() bool {
+	if m != nil {
+		return m.ExcludeCredentialRotations
+	}
+	return false
 }
 
-var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+func (m *ListRequest) GetPrevDevice() bool {
+	if m != nil {
+		return m.PrevDevice
+	}
+	return false
+}
 
-func (m *ListResponse) GetType() ListResponse_Type {
+func (m *ListRequest) GetStaleOk() bool {
 	if m != nil {
-		return m.Type
+		return m.StaleOk
 	}
-	return ListResponse_NONE
+	return false
 }
 
-func (m *ListResponse) GetDevice() *Device {
+func (m *ListRequest) GetStates() []ConnectionState {
 	if m != nil {
-		return m.Device
+		return m.States
 	}
 	return nil
 }
 
-// RemoveRequest removes a device by ID
-type RemoveRequest struct {
-	// device is the device to remove
-	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ListRequest) GetReplayDone() bool {
+	if m != nil {
+		return m.ReplayDone
+	}
+	return false
 }
 
-func (m *RemoveRequest) Reset()         { *m = RemoveRequest{} }
-func (m *RemoveRequest) String() string { return proto.CompactTextString(m) }
-func (*RemoveRequest) ProtoMessage()    {}
-func (*RemoveRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{8}
+func (m *ListRequest) GetView() ListRequest_View {
+	if m != nil {
+		return m.View
+	}
+	return ListRequest_FULL
 }
 
-func (m *RemoveRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RemoveRequest.Unmarshal(m, b)
+func (m *ListRequest) GetIncludeSecrets() bool {
+	if m != nil {
+		return m.IncludeSecrets
+	}
+	return false
 }
-func (m *RemoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RemoveRequest.Marshal(b, m, deterministic)
+
+func (m *ListRequest) GetHeartbeatInterval() *duration.Duration {
+	if m != nil {
+		return m.HeartbeatInterval
+	}
+	return nil
 }
-func (m *RemoveRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RemoveRequest.Merge(m, src)
+
+func (m *ListRequest) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
 }
-func (m *RemoveRequest) XXX_Size() int {
-	return xxx_messageInfo_RemoveRequest.Size(m)
+
+func (m *ListRequest) GetExcludeQuiesced() bool {
+	if m != nil {
+		return m.ExcludeQuiesced
+	}
+	return false
 }
-func (m *RemoveRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_RemoveRequest.DiscardUnknown(m)
+
+func (m *ListRequest) GetDeviceIds() []string {
+	if m != nil {
+		return m.DeviceIds
+	}
+	return nil
 }
 
-var xxx_messageInfo_RemoveRequest proto.InternalMessageInfo
+func (m *ListRequest) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
 
-func (m *RemoveRequest) GetDevice() *Device {
+func (m *ListRequest) GetVendor() string {
 	if m != nil {
-		return m.Device
+		return m.Vendor
 	}
-	return nil
+	return ""
 }
 
-// RemoveResponse is sent in response to a RemoveDeviceRequest
-type RemoveResponse struct {
+func (m *ListRequest) GetModel() string {
+	if m != nil {
+		return m.Model
+	}
+	return ""
+}
+
+func (m *ListRequest) GetSkipReplay() bool {
+	if m != nil {
+		return m.SkipReplay
+	}
+	return false
+}
+
+// ListResponse carries a single device event
+type ListResponse struct {
+	// type is the type of the event
+	Type ListResponse_Type `protobuf:"varint,1,opt,name=type,proto3,enum=topo.device.ListResponse_Type" json:"type,omitempty"`
+	// device is the device on which the event occurred
+	Device *Device `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	// seq is the sequence number of the event
+	// Sequence numbers increase by one for each event that occurs in the store. Devices listed when the
+	// stream is opened carry the sequence number of the last event that occurred prior to the listing.
+	// Clients can compare the sequence numbers of consecutive events to detect missed events.
+	Seq uint64 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	// annotations is the device annotations on which an ANNOTATED event occurred
+	Annotations *Annotations `protobuf:"bytes,4,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	// subtype is the subtype of an UPDATED event
+	Subtype ListResponse_Subtype `protobuf:"varint,5,opt,name=subtype,proto3,enum=topo.device.ListResponse_Subtype" json:"subtype,omitempty"`
+	// prev_device is the value of the device before an UPDATED or REMOVED event
+	// The previous value is only set if requested with ListRequest.prev_device and known to the store.
+	PrevDevice *Device `protobuf:"bytes,6,opt,name=prev_device,json=prevDevice,proto3" json:"prev_device,omitempty"`
+	// stale indicates whether the device was served from the service's cache because the store is unavailable
+	Stale bool `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`
+	// cached_at is the time at which a stale device was listed from the store
+	CachedAt *timestamp.Timestamp `protobuf:"bytes,8,opt,name=cached_at,json=cachedAt,proto3" json:"cached_at,omitempty"`
+	// resume_token is an opaque token from which an interrupted snapshot can be resumed
+	// The token is set on the NONE responses of the snapshot and can be passed as ListRequest.resume_token.
+	ResumeToken string `protobuf:"bytes,9,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// version is the store version of the device after the event
+	// For REMOVED events, it's the version of the removed device.
+	Version uint64 `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	// prev_version is the store version of the device before an UPDATED or REMOVED event
+	// The previous version is 0 if the device was added or its previous value is not known to the store. The
+	// prev_version of each event of a device matches the version of the device's preceding event unless
+	// events were missed, so clients can verify that they've processed every change to a device.
+	PrevVersion          uint64   `protobuf:"varint,11,opt,name=prev_version,json=prevVersion,proto3" json:"prev_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RemoveResponse) Reset()         { *m = RemoveResponse{} }
-func (m *RemoveResponse) String() string { return proto.CompactTextString(m) }
-func (*RemoveResponse) ProtoMessage()    {}
-func (*RemoveResponse) Descriptor() ([]byte, []int) {
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
 	return fileDescriptor_b9d152c21573e6ba, []int{9}
 }
 
-func (m *RemoveResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RemoveResponse.Unmarshal(m, b)
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
 }
-func (m *RemoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RemoveResponse.Marshal(b, m, deterministic)
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
 }
-func (m *RemoveResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RemoveResponse.Merge(m, src)
+func (m *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(m, src)
 }
-func (m *RemoveResponse) XXX_Size() int {
-	return xxx_messageInfo_RemoveResponse.Size(m)
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
 }
-func (m *RemoveResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_RemoveResponse.DiscardUnknown(m)
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RemoveResponse proto.InternalMessageInfo
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
 
-// Device contains information about a device
-type Device struct {
-	// metadata is the store metadata used for concurrency control
-	Metadata *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	// id is a globally unique device identifier
-	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	// address is the host:port of the device
-	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
-	// target is the device target
-	Target string `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
-	// software_version is the device software version
-	SoftwareVersion string `protobuf:"bytes,5,opt,name=software_version,json=softwareVersion,proto3" json:"software_version,omitempty"`
-	// timeout indicates the device request timeout
-	Timeout *duration.Duration `protobuf:"bytes,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	// credentials contains the credentials for connecting to the device
-	Credentials *Credentials `protobuf:"bytes,7,opt,name=credentials,proto3" json:"credentials,omitempty"`
-	// tls is the device TLS configuration
-	Tls                  *TlsConfig `protobuf:"bytes,8,opt,name=tls,proto3" json:"tls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+func (m *ListResponse) GetType() ListResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ListResponse_NONE
 }
 
-func (m *Device) Reset()         { *m = Device{} }
-func (m *Device) String() string { return proto.CompactTextString(m) }
-func (*Device) ProtoMessage()    {}
-func (*Device) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{10}
+func (m *ListResponse) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
 }
 
-func (m *Device) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Device.Unmarshal(m, b)
-}
-func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Device.Marshal(b, m, deterministic)
-}
-func (m *Device) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Device.Merge(m, src)
-}
-func (m *Device) XXX_Size() int {
-	return xxx_messageInfo_Device.Size(m)
-}
-func (m *Device) XXX_DiscardUnknown() {
-	xxx_messageInfo_Device.DiscardUnknown(m)
+func (m *ListResponse) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
 }
 
-var xxx_messageInfo_Device proto.InternalMessageInfo
-
-func (m *Device) GetMetadata() *ObjectMetadata {
+func (m *ListResponse) GetAnnotations() *Annotations {
 	if m != nil {
-		return m.Metadata
+		return m.Annotations
 	}
 	return nil
 }
 
-func (m *Device) GetId() string {
+func (m *ListResponse) GetSubtype() ListResponse_Subtype {
 	if m != nil {
-		return m.Id
+		return m.Subtype
 	}
-	return ""
+	return ListResponse_GENERAL
 }
 
-func (m *Device) GetAddress() string {
+func (m *ListResponse) GetPrevDevice() *Device {
 	if m != nil {
-		return m.Address
+		return m.PrevDevice
 	}
-	return ""
+	return nil
 }
 
-func (m *Device) GetTarget() string {
+func (m *ListResponse) GetStale() bool {
 	if m != nil {
-		return m.Target
+		return m.Stale
 	}
-	return ""
+	return false
 }
 
-func (m *Device) GetSoftwareVersion() string {
+func (m *ListResponse) GetCachedAt() *timestamp.Timestamp {
 	if m != nil {
-		return m.SoftwareVersion
+		return m.CachedAt
 	}
-	return ""
+	return nil
 }
 
-func (m *Device) GetTimeout() *duration.Duration {
+func (m *ListResponse) GetResumeToken() string {
 	if m != nil {
-		return m.Timeout
+		return m.ResumeToken
 	}
-	return nil
+	return ""
 }
 
-func (m *Device) GetCredentials() *Credentials {
+func (m *ListResponse) GetVersion() uint64 {
 	if m != nil {
-		return m.Credentials
+		return m.Version
 	}
-	return nil
+	return 0
 }
 
-func (m *Device) GetTls() *TlsConfig {
+func (m *ListResponse) GetPrevVersion() uint64 {
 	if m != nil {
-		return m.Tls
+		return m.PrevVersion
 	}
-	return nil
+	return 0
 }
 
-// Credentials is the device credentials
-type Credentials struct {
-	// user is the user with which to connect to the device
-	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	// password is the password for connecting to the device
-	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+// UpdateManyRequest updates the same fields of all devices matching a label selector
+type UpdateManyRequest struct {
+	// selector is the label selector of the devices to update, e.g. "env=prod,!legacy"
+	// Requirements are separated by commas and may be key=value, key==value, key!=value, key, or !key.
+	// An empty selector matches all devices.
+	Selector string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// update_mask is the mask of the fields to update
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// device_template is the device from which the masked fields are copied to each matching device
+	// The template's id and metadata are ignored.
+	DeviceTemplate       *Device  `protobuf:"bytes,3,opt,name=device_template,json=deviceTemplate,proto3" json:"device_template,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Credentials) Reset()         { *m = Credentials{} }
-func (m *Credentials) String() string { return proto.CompactTextString(m) }
-func (*Credentials) ProtoMessage()    {}
-func (*Credentials) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{11}
+func (m *UpdateManyRequest) Reset()         { *m = UpdateManyRequest{} }
+func (m *UpdateManyRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateManyRequest) ProtoMessage()    {}
+func (*UpdateManyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{10}
 }
 
-func (m *Credentials) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Credentials.Unmarshal(m, b)
+func (m *UpdateManyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateManyRequest.Unmarshal(m, b)
 }
-func (m *Credentials) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Credentials.Marshal(b, m, deterministic)
+func (m *UpdateManyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateManyRequest.Marshal(b, m, deterministic)
 }
-func (m *Credentials) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Credentials.Merge(m, src)
+func (m *UpdateManyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateManyRequest.Merge(m, src)
 }
-func (m *Credentials) XXX_Size() int {
-	return xxx_messageInfo_Credentials.Size(m)
+func (m *UpdateManyRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateManyRequest.Size(m)
 }
-func (m *Credentials) XXX_DiscardUnknown() {
-	xxx_messageInfo_Credentials.DiscardUnknown(m)
+func (m *UpdateManyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateManyRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Credentials proto.InternalMessageInfo
+var xxx_messageInfo_UpdateManyRequest proto.InternalMessageInfo
 
-func (m *Credentials) GetUser() string {
+func (m *UpdateManyRequest) GetSelector() string {
 	if m != nil {
-		return m.User
+		return m.Selector
 	}
 	return ""
 }
 
-func (m *Credentials) GetPassword() string {
+func (m *UpdateManyRequest) GetUpdateMask() *field_mask.FieldMask {
 	if m != nil {
-		return m.Password
+		return m.UpdateMask
 	}
-	return ""
+	return nil
 }
 
-// Device TLS configuration
-type TlsConfig struct {
-	// caCert is the name of the device's CA certificate
-	CaCert string `protobuf:"bytes,3,opt,name=caCert,proto3" json:"caCert,omitempty"`
-	// cert is the name of the device's certificate
-	Cert string `protobuf:"bytes,4,opt,name=cert,proto3" json:"cert,omitempty"`
-	// key is the name of the device's TLS key
-	Key string `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
-	// plain indicates whether to connect to the device over plaintext
-	Plain bool `protobuf:"varint,6,opt,name=plain,proto3" json:"plain,omitempty"`
-	// insecure indicates whether to connect to the device with insecure communication
-	Insecure             bool     `protobuf:"varint,7,opt,name=insecure,proto3" json:"insecure,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *UpdateManyRequest) GetDeviceTemplate() *Device {
+	if m != nil {
+		return m.DeviceTemplate
+	}
+	return nil
 }
 
-func (m *TlsConfig) Reset()         { *m = TlsConfig{} }
-func (m *TlsConfig) String() string { return proto.CompactTextString(m) }
-func (*TlsConfig) ProtoMessage()    {}
-func (*TlsConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{12}
+// UpdateManyResponse carries the result of updating each device matching an UpdateManyRequest
+type UpdateManyResponse struct {
+	// results is the result of updating each matching device, in order of device ID
+	Results              []*UpdateManyResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
-func (m *TlsConfig) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TlsConfig.Unmarshal(m, b)
+func (m *UpdateManyResponse) Reset()         { *m = UpdateManyResponse{} }
+func (m *UpdateManyResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateManyResponse) ProtoMessage()    {}
+func (*UpdateManyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{11}
 }
-func (m *TlsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TlsConfig.Marshal(b, m, deterministic)
+
+func (m *UpdateManyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateManyResponse.Unmarshal(m, b)
 }
-func (m *TlsConfig) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TlsConfig.Merge(m, src)
+func (m *UpdateManyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateManyResponse.Marshal(b, m, deterministic)
 }
-func (m *TlsConfig) XXX_Size() int {
-	return xxx_messageInfo_TlsConfig.Size(m)
+func (m *UpdateManyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateManyResponse.Merge(m, src)
 }
-func (m *TlsConfig) XXX_DiscardUnknown() {
-	xxx_messageInfo_TlsConfig.DiscardUnknown(m)
+func (m *UpdateManyResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateManyResponse.Size(m)
 }
-
-var xxx_messageInfo_TlsConfig proto.InternalMessageInfo
-
-func (m *TlsConfig) GetCaCert() string {
-	if m != nil {
-		return m.CaCert
-	}
-	return ""
+func (m *UpdateManyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateManyResponse.DiscardUnknown(m)
 }
 
-func (m *TlsConfig) GetCert() string {
+var xxx_messageInfo_UpdateManyResponse proto.InternalMessageInfo
+
+func (m *UpdateManyResponse) GetResults() []*UpdateManyResult {
 	if m != nil {
-		return m.Cert
+		return m.Results
 	}
-	return ""
+	return nil
 }
 
-func (m *TlsConfig) GetKey() string {
+// UpdateManyResult is the result of updating a single device in an UpdateManyRequest
+type UpdateManyResult struct {
+	// device_id is the ID of the device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// metadata is the updated device metadata if the update succeeded
+	Metadata *ObjectMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// code is the gRPC status code of the update, which is 0 (OK) if the update succeeded
+	Code int32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	// message is the error message if the update failed
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateManyResult) Reset()         { *m = UpdateManyResult{} }
+func (m *UpdateManyResult) String() string { return proto.CompactTextString(m) }
+func (*UpdateManyResult) ProtoMessage()    {}
+func (*UpdateManyResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{12}
+}
+
+func (m *UpdateManyResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateManyResult.Unmarshal(m, b)
+}
+func (m *UpdateManyResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateManyResult.Marshal(b, m, deterministic)
+}
+func (m *UpdateManyResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateManyResult.Merge(m, src)
+}
+func (m *UpdateManyResult) XXX_Size() int {
+	return xxx_messageInfo_UpdateManyResult.Size(m)
+}
+func (m *UpdateManyResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateManyResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateManyResult proto.InternalMessageInfo
+
+func (m *UpdateManyResult) GetDeviceId() string {
 	if m != nil {
-		return m.Key
+		return m.DeviceId
 	}
 	return ""
 }
 
-func (m *TlsConfig) GetPlain() bool {
+func (m *UpdateManyResult) GetMetadata() *ObjectMetadata {
 	if m != nil {
-		return m.Plain
+		return m.Metadata
 	}
-	return false
+	return nil
 }
 
-func (m *TlsConfig) GetInsecure() bool {
+func (m *UpdateManyResult) GetCode() int32 {
 	if m != nil {
-		return m.Insecure
+		return m.Code
 	}
-	return false
+	return 0
 }
 
-// ObjectMetadata is the metadata required by the store for concurrency control
-type ObjectMetadata struct {
-	// id is the unique identifier for the object
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// version is the store version of the object
-	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+func (m *UpdateManyResult) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// ClaimDeviceRequest claims or renews a lease on a device
+type ClaimDeviceRequest struct {
+	// device_id is the ID of the device to claim
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// owner_id is the identifier of the controller claiming the device
+	OwnerId string `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// lease_duration is the duration of the lease
+	LeaseDuration        *duration.Duration `protobuf:"bytes,3,opt,name=lease_duration,json=leaseDuration,proto3" json:"lease_duration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ClaimDeviceRequest) Reset()         { *m = ClaimDeviceRequest{} }
+func (m *ClaimDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*ClaimDeviceRequest) ProtoMessage()    {}
+func (*ClaimDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{13}
+}
+
+func (m *ClaimDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClaimDeviceRequest.Unmarshal(m, b)
+}
+func (m *ClaimDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClaimDeviceRequest.Marshal(b, m, deterministic)
+}
+func (m *ClaimDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClaimDeviceRequest.Merge(m, src)
+}
+func (m *ClaimDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_ClaimDeviceRequest.Size(m)
+}
+func (m *ClaimDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClaimDeviceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClaimDeviceRequest proto.InternalMessageInfo
+
+func (m *ClaimDeviceRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *ClaimDeviceRequest) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *ClaimDeviceRequest) GetLeaseDuration() *duration.Duration {
+	if m != nil {
+		return m.LeaseDuration
+	}
+	return nil
+}
+
+// ClaimDeviceResponse is sent in response to a ClaimDeviceRequest
+type ClaimDeviceResponse struct {
+	// owner is the lease held by the claiming controller
+	Owner *Owner `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// metadata is the updated device metadata
+	Metadata             *ObjectMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ClaimDeviceResponse) Reset()         { *m = ClaimDeviceResponse{} }
+func (m *ClaimDeviceResponse) String() string { return proto.CompactTextString(m) }
+func (*ClaimDeviceResponse) ProtoMessage()    {}
+func (*ClaimDeviceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{14}
+}
+
+func (m *ClaimDeviceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClaimDeviceResponse.Unmarshal(m, b)
+}
+func (m *ClaimDeviceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClaimDeviceResponse.Marshal(b, m, deterministic)
+}
+func (m *ClaimDeviceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClaimDeviceResponse.Merge(m, src)
+}
+func (m *ClaimDeviceResponse) XXX_Size() int {
+	return xxx_messageInfo_ClaimDeviceResponse.Size(m)
+}
+func (m *ClaimDeviceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClaimDeviceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClaimDeviceResponse proto.InternalMessageInfo
+
+func (m *ClaimDeviceResponse) GetOwner() *Owner {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *ClaimDeviceResponse) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// ReleaseDeviceRequest releases a lease on a device
+type ReleaseDeviceRequest struct {
+	// device_id is the ID of the device to release
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// owner_id is the identifier of the controller releasing the device
+	OwnerId              string   `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ObjectMetadata) Reset()         { *m = ObjectMetadata{} }
-func (m *ObjectMetadata) String() string { return proto.CompactTextString(m) }
-func (*ObjectMetadata) ProtoMessage()    {}
-func (*ObjectMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b9d152c21573e6ba, []int{13}
+func (m *ReleaseDeviceRequest) Reset()         { *m = ReleaseDeviceRequest{} }
+func (m *ReleaseDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*ReleaseDeviceRequest) ProtoMessage()    {}
+func (*ReleaseDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{15}
 }
 
-func (m *ObjectMetadata) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ObjectMetadata.Unmarshal(m, b)
+func (m *ReleaseDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseDeviceRequest.Unmarshal(m, b)
 }
-func (m *ObjectMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ObjectMetadata.Marshal(b, m, deterministic)
+func (m *ReleaseDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseDeviceRequest.Marshal(b, m, deterministic)
 }
-func (m *ObjectMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ObjectMetadata.Merge(m, src)
+func (m *ReleaseDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseDeviceRequest.Merge(m, src)
 }
-func (m *ObjectMetadata) XXX_Size() int {
-	return xxx_messageInfo_ObjectMetadata.Size(m)
+func (m *ReleaseDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_ReleaseDeviceRequest.Size(m)
 }
-func (m *ObjectMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_ObjectMetadata.DiscardUnknown(m)
+func (m *ReleaseDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseDeviceRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ObjectMetadata proto.InternalMessageInfo
+var xxx_messageInfo_ReleaseDeviceRequest proto.InternalMessageInfo
 
-func (m *ObjectMetadata) GetId() string {
+func (m *ReleaseDeviceRequest) GetDeviceId() string {
 	if m != nil {
-		return m.Id
+		return m.DeviceId
 	}
 	return ""
 }
 
-func (m *ObjectMetadata) GetVersion() uint64 {
+func (m *ReleaseDeviceRequest) GetOwnerId() string {
 	if m != nil {
-		return m.Version
+		return m.OwnerId
 	}
-	return 0
+	return ""
 }
 
-func init() {
-	proto.RegisterEnum("topo.device.ListResponse_Type", ListResponse_Type_name, ListResponse_Type_value)
-	proto.RegisterType((*AddRequest)(nil), "topo.device.AddRequest")
-	proto.RegisterType((*AddResponse)(nil), "topo.device.AddResponse")
-	proto.RegisterType((*UpdateRequest)(nil), "topo.device.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "topo.device.UpdateResponse")
-	proto.RegisterType((*GetRequest)(nil), "topo.device.GetRequest")
-	proto.RegisterType((*GetResponse)(nil), "topo.device.GetResponse")
-	proto.RegisterType((*ListRequest)(nil), "topo.device.ListRequest")
-	proto.RegisterType((*ListResponse)(nil), "topo.device.ListResponse")
-	proto.RegisterType((*RemoveRequest)(nil), "topo.device.RemoveRequest")
-	proto.RegisterType((*RemoveResponse)(nil), "topo.device.RemoveResponse")
-	proto.RegisterType((*Device)(nil), "topo.device.Device")
-	proto.RegisterType((*Credentials)(nil), "topo.device.Credentials")
-	proto.RegisterType((*TlsConfig)(nil), "topo.device.TlsConfig")
-	proto.RegisterType((*ObjectMetadata)(nil), "topo.device.ObjectMetadata")
+// ReleaseDeviceResponse is sent in response to a ReleaseDeviceRequest
+type ReleaseDeviceResponse struct {
+	// metadata is the updated device metadata
+	Metadata             *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func init() { proto.RegisterFile("pkg/northbound/device/device.proto", fileDescriptor_b9d152c21573e6ba) }
+func (m *ReleaseDeviceResponse) Reset()         { *m = ReleaseDeviceResponse{} }
+func (m *ReleaseDeviceResponse) String() string { return proto.CompactTextString(m) }
+func (*ReleaseDeviceResponse) ProtoMessage()    {}
+func (*ReleaseDeviceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{16}
+}
 
-var fileDescriptor_b9d152c21573e6ba = []byte{
-	// 689 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x5d, 0x6f, 0xd3, 0x3c,
-	0x14, 0xee, 0xd7, 0xda, 0xf4, 0xe4, 0x5d, 0xdf, 0xca, 0xa0, 0x91, 0x75, 0x68, 0x42, 0xb9, 0xda,
-	0x34, 0x29, 0x45, 0x9d, 0x10, 0xa2, 0x02, 0xa1, 0xaa, 0x29, 0xd3, 0x24, 0xb6, 0x21, 0xb3, 0xed,
-	0x76, 0x4a, 0x63, 0xaf, 0x84, 0x75, 0x71, 0xb0, 0x9d, 0x4d, 0x13, 0xff, 0x87, 0xff, 0xc2, 0x2d,
-	0xbf, 0x08, 0xc5, 0x76, 0xda, 0xa4, 0x0c, 0x21, 0xc6, 0x55, 0x72, 0x8e, 0x9f, 0xe7, 0xf8, 0xf1,
-	0xf9, 0x02, 0x37, 0xb9, 0x9a, 0xf5, 0x63, 0xc6, 0xe5, 0xa7, 0x29, 0x4b, 0x63, 0xd2, 0x27, 0xf4,
-	0x26, 0x0a, 0xa9, 0xf9, 0x78, 0x09, 0x67, 0x92, 0x21, 0x5b, 0xb2, 0x84, 0x79, 0xda, 0xd5, 0xdb,
-	0x9e, 0x31, 0x36, 0x9b, 0xd3, 0xbe, 0x3a, 0x9a, 0xa6, 0x97, 0x7d, 0x92, 0xf2, 0x40, 0x46, 0x2c,
-	0xd6, 0x60, 0xf7, 0x15, 0xc0, 0x88, 0x10, 0x4c, 0xbf, 0xa4, 0x54, 0x48, 0xb4, 0x07, 0x4d, 0xcd,
-	0x73, 0xaa, 0xcf, 0xaa, 0x3b, 0xf6, 0xe0, 0x91, 0x57, 0x88, 0xe5, 0xf9, 0xea, 0x83, 0x0d, 0xc4,
-	0x7d, 0x07, 0xb6, 0xa2, 0x8a, 0x84, 0xc5, 0x82, 0xa2, 0x97, 0x60, 0x5d, 0x53, 0x19, 0x90, 0x40,
-	0x06, 0x86, 0xbd, 0x55, 0x62, 0x9f, 0x4c, 0x3f, 0xd3, 0x50, 0x1e, 0x19, 0x08, 0x5e, 0x80, 0xdd,
-	0xd7, 0xb0, 0x7e, 0x96, 0x90, 0x40, 0xd2, 0x07, 0xa9, 0x38, 0x84, 0x4e, 0xce, 0xfe, 0x57, 0x21,
-	0xbb, 0x00, 0x07, 0x54, 0xe6, 0x2a, 0xb6, 0xa0, 0xad, 0x09, 0x17, 0x11, 0x51, 0x71, 0xda, 0xd8,
-	0xd2, 0x8e, 0x43, 0xe2, 0x0e, 0xc1, 0x56, 0x50, 0x73, 0xe5, 0x5f, 0x29, 0xde, 0x03, 0xfb, 0x7d,
-	0x24, 0x16, 0xf7, 0x3c, 0x85, 0xb6, 0x48, 0xa7, 0x22, 0xe4, 0xd1, 0x54, 0xd3, 0x2d, 0xbc, 0x74,
-	0xb8, 0xdf, 0xaa, 0xf0, 0x9f, 0x46, 0x9b, 0xab, 0x06, 0xd0, 0x90, 0x77, 0x89, 0x46, 0x76, 0x06,
-	0xdb, 0xa5, 0x8b, 0x8a, 0x40, 0xef, 0xf4, 0x2e, 0xa1, 0x58, 0x61, 0x0b, 0xf2, 0x6a, 0x7f, 0x96,
-	0xf7, 0x02, 0x1a, 0x19, 0x15, 0x59, 0xd0, 0x38, 0x3e, 0x39, 0x9e, 0x74, 0x2b, 0xa8, 0x0d, 0x6b,
-	0x23, 0xdf, 0x9f, 0xf8, 0xdd, 0x2a, 0xb2, 0xa1, 0x75, 0xf6, 0xc1, 0x1f, 0x9d, 0x4e, 0xfc, 0x6e,
-	0x2d, 0x33, 0xf0, 0xe4, 0xe8, 0xe4, 0x7c, 0xe2, 0x77, 0xeb, 0x59, 0x15, 0x31, 0xbd, 0x66, 0x37,
-	0x0f, 0xab, 0x62, 0x17, 0x3a, 0x39, 0x5b, 0xcb, 0x77, 0xbf, 0xd7, 0xa0, 0xa9, 0x41, 0x0f, 0x2e,
-	0x28, 0xea, 0x40, 0x2d, 0x22, 0xea, 0xcd, 0x6d, 0x5c, 0x8b, 0x08, 0x72, 0xa0, 0x15, 0x10, 0xc2,
-	0xa9, 0x10, 0x4e, 0x5d, 0x39, 0x73, 0x13, 0x6d, 0x40, 0x53, 0x06, 0x7c, 0x46, 0xa5, 0xd3, 0x50,
-	0x07, 0xc6, 0x42, 0xbb, 0xd0, 0x15, 0xec, 0x52, 0xde, 0x06, 0x9c, 0x5e, 0xdc, 0x50, 0x2e, 0x22,
-	0x16, 0x3b, 0x6b, 0x0a, 0xf1, 0x7f, 0xee, 0x3f, 0xd7, 0x6e, 0xb4, 0x0f, 0x2d, 0x19, 0x5d, 0x53,
-	0x96, 0x4a, 0xa7, 0xa9, 0x44, 0x6e, 0x7a, 0x7a, 0xf6, 0xbc, 0x7c, 0xf6, 0x3c, 0xdf, 0xcc, 0x1e,
-	0xce, 0x91, 0x68, 0x08, 0x76, 0xc8, 0x29, 0xa1, 0xb1, 0x8c, 0x82, 0xb9, 0x70, 0x5a, 0x8a, 0xe8,
-	0x94, 0x5e, 0x37, 0x5e, 0x9e, 0xe3, 0x22, 0x18, 0xed, 0x40, 0x5d, 0xce, 0x85, 0x63, 0x29, 0xce,
-	0x46, 0x89, 0x73, 0x3a, 0x17, 0x63, 0x16, 0x5f, 0x46, 0x33, 0x9c, 0x41, 0xdc, 0x37, 0x60, 0x17,
-	0xa2, 0x20, 0x04, 0x8d, 0x54, 0x50, 0x6e, 0x9a, 0x5a, 0xfd, 0xa3, 0x1e, 0x58, 0x49, 0x20, 0xc4,
-	0x2d, 0xe3, 0x79, 0xc2, 0x16, 0xb6, 0xfb, 0x15, 0xda, 0x8b, 0x80, 0x59, 0xa6, 0xc2, 0x60, 0x4c,
-	0xb9, 0x34, 0x29, 0x34, 0x56, 0x16, 0x34, 0xcc, 0xbc, 0x3a, 0x7f, 0xea, 0x1f, 0x75, 0xa1, 0x7e,
-	0x45, 0xef, 0x4c, 0xc2, 0xb2, 0x5f, 0xf4, 0x18, 0xd6, 0x92, 0x79, 0x10, 0xc5, 0x2a, 0x45, 0x16,
-	0xd6, 0x46, 0x76, 0x79, 0x14, 0x0b, 0x1a, 0xa6, 0x9c, 0xaa, 0x14, 0x58, 0x78, 0x61, 0xbb, 0x43,
-	0xe8, 0x94, 0xeb, 0x6b, 0xaa, 0x5a, 0x2d, 0x56, 0x35, 0x2f, 0x4d, 0xa6, 0xbc, 0x81, 0x73, 0x73,
-	0xf0, 0xa3, 0x06, 0xeb, 0xba, 0x87, 0x3e, 0x52, 0xae, 0x5a, 0x69, 0x08, 0xf5, 0x11, 0x21, 0xe8,
-	0x49, 0x29, 0x5b, 0xcb, 0x05, 0xd8, 0x73, 0x7e, 0x3d, 0x30, 0xfd, 0x58, 0x41, 0x63, 0x68, 0xea,
-	0x4d, 0x83, 0x7a, 0x25, 0x54, 0x69, 0x79, 0xf5, 0xb6, 0xee, 0x3d, 0x5b, 0x04, 0x19, 0x42, 0xfd,
-	0x80, 0xca, 0x15, 0x01, 0xcb, 0xad, 0xb3, 0x22, 0xa0, 0xb0, 0x63, 0xdc, 0x0a, 0x7a, 0x0b, 0x8d,
-	0x6c, 0xc2, 0x91, 0x73, 0xcf, 0xd0, 0x6b, 0xf6, 0xe6, 0x6f, 0xd7, 0x81, 0x5b, 0x79, 0x5e, 0xcd,
-	0x5e, 0xa0, 0xa7, 0x6c, 0xe5, 0x05, 0xa5, 0xc1, 0x5d, 0x79, 0xc1, 0xca, 0x58, 0x56, 0xa6, 0x4d,
-	0xd5, 0xce, 0xfb, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x9d, 0xd7, 0xf7, 0x8f, 0x8b, 0x06, 0x00,
-	0x00,
+func (m *ReleaseDeviceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReleaseDeviceResponse.Unmarshal(m, b)
+}
+func (m *ReleaseDeviceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReleaseDeviceResponse.Marshal(b, m, deterministic)
+}
+func (m *ReleaseDeviceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReleaseDeviceResponse.Merge(m, src)
+}
+func (m *ReleaseDeviceResponse) XXX_Size() int {
+	return xxx_messageInfo_ReleaseDeviceResponse.Size(m)
+}
+func (m *ReleaseDeviceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReleaseDeviceResponse.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_ReleaseDeviceResponse proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ReleaseDeviceResponse) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
 
-// DeviceServiceClient is the client API for DeviceService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type DeviceServiceClient interface {
-	// Add adds a device to the topology
-	Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddResponse, error)
-	// Update updates a device
-	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
-	// Get gets a device by ID
-	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	// List gets a stream of device add/update/remove events
-	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (DeviceService_ListClient, error)
-	// Remove removes a device from the topology
-	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+// ReportStateRequest reports the connection state of a device observed by a southbound agent
+type ReportStateRequest struct {
+	// device_id is the ID of the device whose state is reported
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// state is the observed connection state of the device
+	State ConnectionState `protobuf:"varint,2,opt,name=state,proto3,enum=topo.device.ConnectionState" json:"state,omitempty"`
+	// reason is a human readable reason for the device being in the state
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type deviceServiceClient struct {
-	cc *grpc.ClientConn
+func (m *ReportStateRequest) Reset()         { *m = ReportStateRequest{} }
+func (m *ReportStateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportStateRequest) ProtoMessage()    {}
+func (*ReportStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{17}
 }
 
-func NewDeviceServiceClient(cc *grpc.ClientConn) DeviceServiceClient {
-	return &deviceServiceClient{cc}
+func (m *ReportStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportStateRequest.Unmarshal(m, b)
+}
+func (m *ReportStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportStateRequest.Marshal(b, m, deterministic)
+}
+func (m *ReportStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportStateRequest.Merge(m, src)
+}
+func (m *ReportStateRequest) XXX_Size() int {
+	return xxx_messageInfo_ReportStateRequest.Size(m)
+}
+func (m *ReportStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportStateRequest.DiscardUnknown(m)
 }
 
-func (c *deviceServiceClient) Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddResponse, error) {
-	out := new(AddResponse)
-	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Add", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ReportStateRequest proto.InternalMessageInfo
+
+func (m *ReportStateRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *ReportStateRequest) GetState() ConnectionState {
+	if m != nil {
+		return m.State
+	}
+	return ConnectionState_CONNECTION_UNKNOWN
+}
+
+func (m *ReportStateRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// ReportStateResponse is sent in response to a ReportStateRequest
+type ReportStateResponse struct {
+	// status is the connection status of the device after the report
+	Status *ConnectionStatus `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// metadata is the device metadata after the report
+	Metadata             *ObjectMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ReportStateResponse) Reset()         { *m = ReportStateResponse{} }
+func (m *ReportStateResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportStateResponse) ProtoMessage()    {}
+func (*ReportStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{18}
+}
+
+func (m *ReportStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportStateResponse.Unmarshal(m, b)
+}
+func (m *ReportStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportStateResponse.Marshal(b, m, deterministic)
+}
+func (m *ReportStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportStateResponse.Merge(m, src)
+}
+func (m *ReportStateResponse) XXX_Size() int {
+	return xxx_messageInfo_ReportStateResponse.Size(m)
+}
+func (m *ReportStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportStateResponse proto.InternalMessageInfo
+
+func (m *ReportStateResponse) GetStatus() *ConnectionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *ReportStateResponse) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// CompareAndSwapFieldRequest sets a field of a device only if the field has an expected value
+type CompareAndSwapFieldRequest struct {
+	// device_id is the ID of the device to update
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// path is the path of the field to compare and swap, e.g. lifecycle_status.phase
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// expected is a device whose value at the path is the expected current value of the field
+	// If the device is not set, the field is expected to be unset.
+	Expected *Device `protobuf:"bytes,3,opt,name=expected,proto3" json:"expected,omitempty"`
+	// value is a device whose value at the path is the new value of the field
+	// If the device is not set, the field is cleared.
+	Value                *Device  `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompareAndSwapFieldRequest) Reset()         { *m = CompareAndSwapFieldRequest{} }
+func (m *CompareAndSwapFieldRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapFieldRequest) ProtoMessage()    {}
+func (*CompareAndSwapFieldRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{19}
+}
+
+func (m *CompareAndSwapFieldRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapFieldRequest.Unmarshal(m, b)
+}
+func (m *CompareAndSwapFieldRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapFieldRequest.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapFieldRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapFieldRequest.Merge(m, src)
+}
+func (m *CompareAndSwapFieldRequest) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapFieldRequest.Size(m)
+}
+func (m *CompareAndSwapFieldRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapFieldRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapFieldRequest proto.InternalMessageInfo
+
+func (m *CompareAndSwapFieldRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *CompareAndSwapFieldRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CompareAndSwapFieldRequest) GetExpected() *Device {
+	if m != nil {
+		return m.Expected
+	}
+	return nil
+}
+
+func (m *CompareAndSwapFieldRequest) GetValue() *Device {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// CompareAndSwapFieldResponse is sent in response to a CompareAndSwapFieldRequest
+type CompareAndSwapFieldResponse struct {
+	// metadata is the updated device metadata
+	Metadata             *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CompareAndSwapFieldResponse) Reset()         { *m = CompareAndSwapFieldResponse{} }
+func (m *CompareAndSwapFieldResponse) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapFieldResponse) ProtoMessage()    {}
+func (*CompareAndSwapFieldResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{20}
+}
+
+func (m *CompareAndSwapFieldResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapFieldResponse.Unmarshal(m, b)
+}
+func (m *CompareAndSwapFieldResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapFieldResponse.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapFieldResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapFieldResponse.Merge(m, src)
+}
+func (m *CompareAndSwapFieldResponse) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapFieldResponse.Size(m)
+}
+func (m *CompareAndSwapFieldResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapFieldResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapFieldResponse proto.InternalMessageInfo
+
+func (m *CompareAndSwapFieldResponse) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// RotateCredentialsRequest replaces the credentials of a device
+type RotateCredentialsRequest struct {
+	// device_id is the ID of the device for which to rotate credentials
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// credentials is the new device credentials
+	Credentials *Credentials `protobuf:"bytes,2,opt,name=credentials,proto3" json:"credentials,omitempty"`
+	// version is the expected version of the device
+	// If set, the credentials are rotated only if the stored device has the same version.
+	Version              uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RotateCredentialsRequest) Reset()         { *m = RotateCredentialsRequest{} }
+func (m *RotateCredentialsRequest) String() string { return proto.CompactTextString(m) }
+func (*RotateCredentialsRequest) ProtoMessage()    {}
+func (*RotateCredentialsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{21}
+}
+
+func (m *RotateCredentialsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateCredentialsRequest.Unmarshal(m, b)
+}
+func (m *RotateCredentialsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateCredentialsRequest.Marshal(b, m, deterministic)
+}
+func (m *RotateCredentialsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateCredentialsRequest.Merge(m, src)
+}
+func (m *RotateCredentialsRequest) XXX_Size() int {
+	return xxx_messageInfo_RotateCredentialsRequest.Size(m)
+}
+func (m *RotateCredentialsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateCredentialsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateCredentialsRequest proto.InternalMessageInfo
+
+func (m *RotateCredentialsRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *RotateCredentialsRequest) GetCredentials() *Credentials {
+	if m != nil {
+		return m.Credentials
+	}
+	return nil
+}
+
+func (m *RotateCredentialsRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// RotateCredentialsResponse is sent in response to a RotateCredentialsRequest
+type RotateCredentialsResponse struct {
+	// metadata is the updated device metadata
+	Metadata             *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RotateCredentialsResponse) Reset()         { *m = RotateCredentialsResponse{} }
+func (m *RotateCredentialsResponse) String() string { return proto.CompactTextString(m) }
+func (*RotateCredentialsResponse) ProtoMessage()    {}
+func (*RotateCredentialsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{22}
+}
+
+func (m *RotateCredentialsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateCredentialsResponse.Unmarshal(m, b)
+}
+func (m *RotateCredentialsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateCredentialsResponse.Marshal(b, m, deterministic)
+}
+func (m *RotateCredentialsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateCredentialsResponse.Merge(m, src)
+}
+func (m *RotateCredentialsResponse) XXX_Size() int {
+	return xxx_messageInfo_RotateCredentialsResponse.Size(m)
+}
+func (m *RotateCredentialsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateCredentialsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateCredentialsResponse proto.InternalMessageInfo
+
+func (m *RotateCredentialsResponse) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// SubscribeRequest is a request sent by the client on a Subscribe stream
+// The first request on the stream must be a list request, which opens the subscription as a List request
+// with subscribe set would. Any later request must be a resync request.
+type SubscribeRequest struct {
+	// request is the list or resync request
+	//
+	// Types that are valid to be assigned to Request:
+	//	*SubscribeRequest_List
+	//	*SubscribeRequest_Resync
+	Request              isSubscribeRequest_Request `protobuf_oneof:"request"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{23}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+type isSubscribeRequest_Request interface {
+	isSubscribeRequest_Request()
+}
+
+type SubscribeRequest_List struct {
+	List *ListRequest `protobuf:"bytes,1,opt,name=list,proto3,oneof"`
+}
+
+type SubscribeRequest_Resync struct {
+	Resync *ResyncRequest `protobuf:"bytes,2,opt,name=resync,proto3,oneof"`
+}
+
+func (*SubscribeRequest_List) isSubscribeRequest_Request() {}
+
+func (*SubscribeRequest_Resync) isSubscribeRequest_Request() {}
+
+func (m *SubscribeRequest) GetRequest() isSubscribeRequest_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetList() *ListRequest {
+	if x, ok := m.GetRequest().(*SubscribeRequest_List); ok {
+		return x.List
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetResync() *ResyncRequest {
+	if x, ok := m.GetRequest().(*SubscribeRequest_Resync); ok {
+		return x.Resync
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*SubscribeRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*SubscribeRequest_List)(nil),
+		(*SubscribeRequest_Resync)(nil),
+	}
+}
+
+// ResyncRequest requests that the server resend the current devices on a Subscribe stream
+// The server responds with a NONE response for each device that currently matches the subscription's
+// filters, in ID order, followed by a REPLAY_DONE response, all carrying the sequence number of the last
+// event sent before the resync. Events that occur while the devices are resent are sent after the
+// REPLAY_DONE response, except for events already reflected in the resent devices, which are not sent.
+// The resent devices are the complete set of matching devices, so clients should discard any device they
+// hold that isn't resent. Resync requests received while a resync is pending are coalesced into it.
+type ResyncRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResyncRequest) Reset()         { *m = ResyncRequest{} }
+func (m *ResyncRequest) String() string { return proto.CompactTextString(m) }
+func (*ResyncRequest) ProtoMessage()    {}
+func (*ResyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{24}
+}
+
+func (m *ResyncRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResyncRequest.Unmarshal(m, b)
+}
+func (m *ResyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResyncRequest.Marshal(b, m, deterministic)
+}
+func (m *ResyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResyncRequest.Merge(m, src)
+}
+func (m *ResyncRequest) XXX_Size() int {
+	return xxx_messageInfo_ResyncRequest.Size(m)
+}
+func (m *ResyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResyncRequest proto.InternalMessageInfo
+
+// WatchAllRequest requests a stream of events for all topology resources
+type WatchAllRequest struct {
+	// from_version is the device version from which to resume a subscription
+	FromVersion          uint64   `protobuf:"varint,1,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchAllRequest) Reset()         { *m = WatchAllRequest{} }
+func (m *WatchAllRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchAllRequest) ProtoMessage()    {}
+func (*WatchAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{25}
+}
+
+func (m *WatchAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAllRequest.Unmarshal(m, b)
+}
+func (m *WatchAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAllRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAllRequest.Merge(m, src)
+}
+func (m *WatchAllRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchAllRequest.Size(m)
+}
+func (m *WatchAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAllRequest proto.InternalMessageInfo
+
+func (m *WatchAllRequest) GetFromVersion() uint64 {
+	if m != nil {
+		return m.FromVersion
+	}
+	return 0
+}
+
+// WatchAllResponse carries a single event for a topology resource
+type WatchAllResponse struct {
+	// type is the type of the resource on which the event occurred
+	Type WatchAllResponse_ResourceType `protobuf:"varint,1,opt,name=type,proto3,enum=topo.device.WatchAllResponse_ResourceType" json:"type,omitempty"`
+	// event is the resource event
+	//
+	// Types that are valid to be assigned to Event:
+	//	*WatchAllResponse_Device
+	Event                isWatchAllResponse_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *WatchAllResponse) Reset()         { *m = WatchAllResponse{} }
+func (m *WatchAllResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchAllResponse) ProtoMessage()    {}
+func (*WatchAllResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{26}
+}
+
+func (m *WatchAllResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAllResponse.Unmarshal(m, b)
+}
+func (m *WatchAllResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAllResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchAllResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAllResponse.Merge(m, src)
+}
+func (m *WatchAllResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchAllResponse.Size(m)
+}
+func (m *WatchAllResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAllResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAllResponse proto.InternalMessageInfo
+
+func (m *WatchAllResponse) GetType() WatchAllResponse_ResourceType {
+	if m != nil {
+		return m.Type
+	}
+	return WatchAllResponse_DEVICE
+}
+
+type isWatchAllResponse_Event interface {
+	isWatchAllResponse_Event()
+}
+
+type WatchAllResponse_Device struct {
+	Device *ListResponse `protobuf:"bytes,2,opt,name=device,proto3,oneof"`
+}
+
+func (*WatchAllResponse_Device) isWatchAllResponse_Event() {}
+
+func (m *WatchAllResponse) GetEvent() isWatchAllResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *WatchAllResponse) GetDevice() *ListResponse {
+	if x, ok := m.GetEvent().(*WatchAllResponse_Device); ok {
+		return x.Device
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*WatchAllResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*WatchAllResponse_Device)(nil),
+	}
+}
+
+// SetAnnotationsRequest sets the annotations of a device
+type SetAnnotationsRequest struct {
+	// annotations is the device annotations to set
+	// If the annotations version is set, the annotations are set only if the stored annotations have the
+	// same version.
+	Annotations          *Annotations `protobuf:"bytes,1,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SetAnnotationsRequest) Reset()         { *m = SetAnnotationsRequest{} }
+func (m *SetAnnotationsRequest) String() string { return proto.CompactTextString(m) }
+func (*SetAnnotationsRequest) ProtoMessage()    {}
+func (*SetAnnotationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{27}
+}
+
+func (m *SetAnnotationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetAnnotationsRequest.Unmarshal(m, b)
+}
+func (m *SetAnnotationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetAnnotationsRequest.Marshal(b, m, deterministic)
+}
+func (m *SetAnnotationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetAnnotationsRequest.Merge(m, src)
+}
+func (m *SetAnnotationsRequest) XXX_Size() int {
+	return xxx_messageInfo_SetAnnotationsRequest.Size(m)
+}
+func (m *SetAnnotationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetAnnotationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetAnnotationsRequest proto.InternalMessageInfo
+
+func (m *SetAnnotationsRequest) GetAnnotations() *Annotations {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+// SetAnnotationsResponse is sent in response to a SetAnnotationsRequest
+type SetAnnotationsResponse struct {
+	// version is the updated annotations version
+	Version              uint64   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetAnnotationsResponse) Reset()         { *m = SetAnnotationsResponse{} }
+func (m *SetAnnotationsResponse) String() string { return proto.CompactTextString(m) }
+func (*SetAnnotationsResponse) ProtoMessage()    {}
+func (*SetAnnotationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{28}
+}
+
+func (m *SetAnnotationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetAnnotationsResponse.Unmarshal(m, b)
+}
+func (m *SetAnnotationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetAnnotationsResponse.Marshal(b, m, deterministic)
+}
+func (m *SetAnnotationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetAnnotationsResponse.Merge(m, src)
+}
+func (m *SetAnnotationsResponse) XXX_Size() int {
+	return xxx_messageInfo_SetAnnotationsResponse.Size(m)
+}
+func (m *SetAnnotationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetAnnotationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetAnnotationsResponse proto.InternalMessageInfo
+
+func (m *SetAnnotationsResponse) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// ListPageRequest requests a page of devices
+// Devices are paged in order of their IDs. Paging is stable under concurrent changes: a device that exists
+// for the duration of the paging is returned exactly once, and a device added during the paging is
+// returned on a later page only if its ID sorts after the last device of the current page.
+type ListPageRequest struct {
+	// page_size is the maximum number of devices to return
+	// If the page size is 0, a default page size is used.
+	PageSize uint32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token from the previous page, or empty to request the first page
+	PageToken            string   `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPageRequest) Reset()         { *m = ListPageRequest{} }
+func (m *ListPageRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPageRequest) ProtoMessage()    {}
+func (*ListPageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{29}
+}
+
+func (m *ListPageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPageRequest.Unmarshal(m, b)
+}
+func (m *ListPageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPageRequest.Marshal(b, m, deterministic)
+}
+func (m *ListPageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPageRequest.Merge(m, src)
+}
+func (m *ListPageRequest) XXX_Size() int {
+	return xxx_messageInfo_ListPageRequest.Size(m)
+}
+func (m *ListPageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPageRequest proto.InternalMessageInfo
+
+func (m *ListPageRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListPageRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// ListPageResponse carries a page of devices
+type ListPageResponse struct {
+	// devices is the page of devices
+	Devices []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// next_page_token is the opaque token with which to request the next page
+	// The token is empty if there are no more devices.
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPageResponse) Reset()         { *m = ListPageResponse{} }
+func (m *ListPageResponse) String() string { return proto.CompactTextString(m) }
+func (*ListPageResponse) ProtoMessage()    {}
+func (*ListPageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{30}
+}
+
+func (m *ListPageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPageResponse.Unmarshal(m, b)
+}
+func (m *ListPageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPageResponse.Marshal(b, m, deterministic)
+}
+func (m *ListPageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPageResponse.Merge(m, src)
+}
+func (m *ListPageResponse) XXX_Size() int {
+	return xxx_messageInfo_ListPageResponse.Size(m)
+}
+func (m *ListPageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPageResponse proto.InternalMessageInfo
+
+func (m *ListPageResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *ListPageResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// ListChildrenRequest requests the direct children of a device
+type ListChildrenRequest struct {
+	// parent_id is the identifier of the device for which to list children
+	ParentId             string   `protobuf:"bytes,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChildrenRequest) Reset()         { *m = ListChildrenRequest{} }
+func (m *ListChildrenRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChildrenRequest) ProtoMessage()    {}
+func (*ListChildrenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{31}
+}
+
+func (m *ListChildrenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChildrenRequest.Unmarshal(m, b)
+}
+func (m *ListChildrenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChildrenRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChildrenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChildrenRequest.Merge(m, src)
+}
+func (m *ListChildrenRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChildrenRequest.Size(m)
+}
+func (m *ListChildrenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChildrenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChildrenRequest proto.InternalMessageInfo
+
+func (m *ListChildrenRequest) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+// ListChildrenResponse carries the direct children of a device
+type ListChildrenResponse struct {
+	// devices is the list of devices whose parent is the requested device
+	Devices              []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListChildrenResponse) Reset()         { *m = ListChildrenResponse{} }
+func (m *ListChildrenResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChildrenResponse) ProtoMessage()    {}
+func (*ListChildrenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{32}
+}
+
+func (m *ListChildrenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChildrenResponse.Unmarshal(m, b)
+}
+func (m *ListChildrenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChildrenResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChildrenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChildrenResponse.Merge(m, src)
+}
+func (m *ListChildrenResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChildrenResponse.Size(m)
+}
+func (m *ListChildrenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChildrenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChildrenResponse proto.InternalMessageInfo
+
+func (m *ListChildrenResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+// ListModifiedSinceRequest requests the devices modified since a point in time
+type ListModifiedSinceRequest struct {
+	// since is the time since which devices must have been modified to be listed
+	// Devices updated at or after the given time are listed, as are devices without an update time. If since
+	// is not set, all devices are listed.
+	Since *timestamp.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	// view is the set of device fields to include in the listed devices
+	View ListRequest_View `protobuf:"varint,2,opt,name=view,proto3,enum=topo.device.ListRequest_View" json:"view,omitempty"`
+	// include_secrets indicates whether to include the devices' secrets
+	// Requests that include secrets are rejected with PermissionDenied unless the server allows secret access
+	// and authorizes the caller.
+	IncludeSecrets       bool     `protobuf:"varint,3,opt,name=include_secrets,json=includeSecrets,proto3" json:"include_secrets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListModifiedSinceRequest) Reset()         { *m = ListModifiedSinceRequest{} }
+func (m *ListModifiedSinceRequest) String() string { return proto.CompactTextString(m) }
+func (*ListModifiedSinceRequest) ProtoMessage()    {}
+func (*ListModifiedSinceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{33}
+}
+
+func (m *ListModifiedSinceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListModifiedSinceRequest.Unmarshal(m, b)
+}
+func (m *ListModifiedSinceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListModifiedSinceRequest.Marshal(b, m, deterministic)
+}
+func (m *ListModifiedSinceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListModifiedSinceRequest.Merge(m, src)
+}
+func (m *ListModifiedSinceRequest) XXX_Size() int {
+	return xxx_messageInfo_ListModifiedSinceRequest.Size(m)
+}
+func (m *ListModifiedSinceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListModifiedSinceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListModifiedSinceRequest proto.InternalMessageInfo
+
+func (m *ListModifiedSinceRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *ListModifiedSinceRequest) GetView() ListRequest_View {
+	if m != nil {
+		return m.View
+	}
+	return ListRequest_FULL
+}
+
+func (m *ListModifiedSinceRequest) GetIncludeSecrets() bool {
+	if m != nil {
+		return m.IncludeSecrets
+	}
+	return false
+}
+
+// GetDeviceHistoryRequest requests the recent history of a device
+type GetDeviceHistoryRequest struct {
+	// device_id is the identifier of the device for which to get the history
+	DeviceId             string   `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDeviceHistoryRequest) Reset()         { *m = GetDeviceHistoryRequest{} }
+func (m *GetDeviceHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDeviceHistoryRequest) ProtoMessage()    {}
+func (*GetDeviceHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{34}
+}
+
+func (m *GetDeviceHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDeviceHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetDeviceHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDeviceHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetDeviceHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDeviceHistoryRequest.Merge(m, src)
+}
+func (m *GetDeviceHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDeviceHistoryRequest.Size(m)
+}
+func (m *GetDeviceHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDeviceHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDeviceHistoryRequest proto.InternalMessageInfo
+
+func (m *GetDeviceHistoryRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+// GetDeviceHistoryResponse carries the recent history of a device
+// The history of each device is also stored in this form in the device history log.
+type GetDeviceHistoryResponse struct {
+	// events is the list of recorded events for the device, oldest first
+	Events               []*DeviceHistoryEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *GetDeviceHistoryResponse) Reset()         { *m = GetDeviceHistoryResponse{} }
+func (m *GetDeviceHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDeviceHistoryResponse) ProtoMessage()    {}
+func (*GetDeviceHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{35}
+}
+
+func (m *GetDeviceHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDeviceHistoryResponse.Unmarshal(m, b)
+}
+func (m *GetDeviceHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDeviceHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GetDeviceHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDeviceHistoryResponse.Merge(m, src)
+}
+func (m *GetDeviceHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDeviceHistoryResponse.Size(m)
+}
+func (m *GetDeviceHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDeviceHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDeviceHistoryResponse proto.InternalMessageInfo
+
+func (m *GetDeviceHistoryResponse) GetEvents() []*DeviceHistoryEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+// DeviceHistoryEvent is a recorded device event
+type DeviceHistoryEvent struct {
+	// type is the type of the event
+	Type ListResponse_Type `protobuf:"varint,1,opt,name=type,proto3,enum=topo.device.ListResponse_Type" json:"type,omitempty"`
+	// device is the device on which the event occurred
+	Device *Device `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	// timestamp is the time at which the event was recorded
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DeviceHistoryEvent) Reset()         { *m = DeviceHistoryEvent{} }
+func (m *DeviceHistoryEvent) String() string { return proto.CompactTextString(m) }
+func (*DeviceHistoryEvent) ProtoMessage()    {}
+func (*DeviceHistoryEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{36}
+}
+
+func (m *DeviceHistoryEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceHistoryEvent.Unmarshal(m, b)
+}
+func (m *DeviceHistoryEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceHistoryEvent.Marshal(b, m, deterministic)
+}
+func (m *DeviceHistoryEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceHistoryEvent.Merge(m, src)
+}
+func (m *DeviceHistoryEvent) XXX_Size() int {
+	return xxx_messageInfo_DeviceHistoryEvent.Size(m)
+}
+func (m *DeviceHistoryEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceHistoryEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceHistoryEvent proto.InternalMessageInfo
+
+func (m *DeviceHistoryEvent) GetType() ListResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ListResponse_NONE
+}
+
+func (m *DeviceHistoryEvent) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *DeviceHistoryEvent) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// SearchDevicesRequest requests the devices matching a free-text query
+type SearchDevicesRequest struct {
+	// query is the text to search for
+	// A device matches if its id, address, failover addresses, target, or software_version contains the
+	// query, ignoring case. An empty query matches all devices.
+	Query                string   `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchDevicesRequest) Reset()         { *m = SearchDevicesRequest{} }
+func (m *SearchDevicesRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchDevicesRequest) ProtoMessage()    {}
+func (*SearchDevicesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{37}
+}
+
+func (m *SearchDevicesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchDevicesRequest.Unmarshal(m, b)
+}
+func (m *SearchDevicesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchDevicesRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchDevicesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchDevicesRequest.Merge(m, src)
+}
+func (m *SearchDevicesRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchDevicesRequest.Size(m)
+}
+func (m *SearchDevicesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchDevicesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchDevicesRequest proto.InternalMessageInfo
+
+func (m *SearchDevicesRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+// SearchDevicesResponse carries the devices matching a free-text query
+type SearchDevicesResponse struct {
+	// devices is the list of matching devices, ordered by device ID
+	Devices              []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SearchDevicesResponse) Reset()         { *m = SearchDevicesResponse{} }
+func (m *SearchDevicesResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchDevicesResponse) ProtoMessage()    {}
+func (*SearchDevicesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{38}
+}
+
+func (m *SearchDevicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchDevicesResponse.Unmarshal(m, b)
+}
+func (m *SearchDevicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchDevicesResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchDevicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchDevicesResponse.Merge(m, src)
+}
+func (m *SearchDevicesResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchDevicesResponse.Size(m)
+}
+func (m *SearchDevicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchDevicesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchDevicesResponse proto.InternalMessageInfo
+
+func (m *SearchDevicesResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+// AddGroupRequest adds a device group
+type AddGroupRequest struct {
+	// group is the group to add
+	Group                *DeviceGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AddGroupRequest) Reset()         { *m = AddGroupRequest{} }
+func (m *AddGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*AddGroupRequest) ProtoMessage()    {}
+func (*AddGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{39}
+}
+
+func (m *AddGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddGroupRequest.Unmarshal(m, b)
+}
+func (m *AddGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *AddGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddGroupRequest.Merge(m, src)
+}
+func (m *AddGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_AddGroupRequest.Size(m)
+}
+func (m *AddGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddGroupRequest proto.InternalMessageInfo
+
+func (m *AddGroupRequest) GetGroup() *DeviceGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// AddGroupResponse is sent in response to an AddGroupRequest
+type AddGroupResponse struct {
+	// group is the added group
+	Group                *DeviceGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AddGroupResponse) Reset()         { *m = AddGroupResponse{} }
+func (m *AddGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*AddGroupResponse) ProtoMessage()    {}
+func (*AddGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{40}
+}
+
+func (m *AddGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddGroupResponse.Unmarshal(m, b)
+}
+func (m *AddGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *AddGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddGroupResponse.Merge(m, src)
+}
+func (m *AddGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_AddGroupResponse.Size(m)
+}
+func (m *AddGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddGroupResponse proto.InternalMessageInfo
+
+func (m *AddGroupResponse) GetGroup() *DeviceGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// UpdateGroupRequest replaces the name, selector and members of a device group
+type UpdateGroupRequest struct {
+	// group is the updated group
+	// If the group version is set, the group is updated only if the stored group has the same version.
+	Group                *DeviceGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *UpdateGroupRequest) Reset()         { *m = UpdateGroupRequest{} }
+func (m *UpdateGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateGroupRequest) ProtoMessage()    {}
+func (*UpdateGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{41}
+}
+
+func (m *UpdateGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateGroupRequest.Unmarshal(m, b)
+}
+func (m *UpdateGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateGroupRequest.Merge(m, src)
+}
+func (m *UpdateGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateGroupRequest.Size(m)
+}
+func (m *UpdateGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateGroupRequest proto.InternalMessageInfo
+
+func (m *UpdateGroupRequest) GetGroup() *DeviceGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// UpdateGroupResponse is sent in response to an UpdateGroupRequest
+type UpdateGroupResponse struct {
+	// group is the updated group
+	Group                *DeviceGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *UpdateGroupResponse) Reset()         { *m = UpdateGroupResponse{} }
+func (m *UpdateGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateGroupResponse) ProtoMessage()    {}
+func (*UpdateGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{42}
+}
+
+func (m *UpdateGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateGroupResponse.Unmarshal(m, b)
+}
+func (m *UpdateGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateGroupResponse.Merge(m, src)
+}
+func (m *UpdateGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateGroupResponse.Size(m)
+}
+func (m *UpdateGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateGroupResponse proto.InternalMessageInfo
+
+func (m *UpdateGroupResponse) GetGroup() *DeviceGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// GetGroupRequest gets a device group by ID
+type GetGroupRequest struct {
+	// group_id is the identifier of the group
+	GroupId              string   `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetGroupRequest) Reset()         { *m = GetGroupRequest{} }
+func (m *GetGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*GetGroupRequest) ProtoMessage()    {}
+func (*GetGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{43}
+}
+
+func (m *GetGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGroupRequest.Unmarshal(m, b)
+}
+func (m *GetGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *GetGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGroupRequest.Merge(m, src)
+}
+func (m *GetGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_GetGroupRequest.Size(m)
+}
+func (m *GetGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGroupRequest proto.InternalMessageInfo
+
+func (m *GetGroupRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+// GetGroupResponse carries a device group
+type GetGroupResponse struct {
+	// group is the requested group
+	Group                *DeviceGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetGroupResponse) Reset()         { *m = GetGroupResponse{} }
+func (m *GetGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*GetGroupResponse) ProtoMessage()    {}
+func (*GetGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{44}
+}
+
+func (m *GetGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGroupResponse.Unmarshal(m, b)
+}
+func (m *GetGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *GetGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGroupResponse.Merge(m, src)
+}
+func (m *GetGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_GetGroupResponse.Size(m)
+}
+func (m *GetGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetGroupResponse proto.InternalMessageInfo
+
+func (m *GetGroupResponse) GetGroup() *DeviceGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// ListGroupsRequest lists the device groups
+type ListGroupsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupsRequest) Reset()         { *m = ListGroupsRequest{} }
+func (m *ListGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsRequest) ProtoMessage()    {}
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{45}
+}
+
+func (m *ListGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsRequest.Unmarshal(m, b)
+}
+func (m *ListGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsRequest.Merge(m, src)
+}
+func (m *ListGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsRequest.Size(m)
+}
+func (m *ListGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsRequest proto.InternalMessageInfo
+
+// ListGroupsResponse carries the device groups
+type ListGroupsResponse struct {
+	// groups is the list of groups, ordered by group ID
+	Groups               []*DeviceGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListGroupsResponse) Reset()         { *m = ListGroupsResponse{} }
+func (m *ListGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGroupsResponse) ProtoMessage()    {}
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{46}
+}
+
+func (m *ListGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupsResponse.Merge(m, src)
+}
+func (m *ListGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGroupsResponse.Size(m)
+}
+func (m *ListGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupsResponse proto.InternalMessageInfo
+
+func (m *ListGroupsResponse) GetGroups() []*DeviceGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// RemoveGroupRequest removes a device group
+// Removing a group does not affect its member devices.
+type RemoveGroupRequest struct {
+	// group_id is the identifier of the group
+	GroupId string `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// version is the expected version of the group
+	// If set, the group is removed only if the stored group has the same version.
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveGroupRequest) Reset()         { *m = RemoveGroupRequest{} }
+func (m *RemoveGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveGroupRequest) ProtoMessage()    {}
+func (*RemoveGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{47}
+}
+
+func (m *RemoveGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveGroupRequest.Unmarshal(m, b)
+}
+func (m *RemoveGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveGroupRequest.Merge(m, src)
+}
+func (m *RemoveGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveGroupRequest.Size(m)
+}
+func (m *RemoveGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveGroupRequest proto.InternalMessageInfo
+
+func (m *RemoveGroupRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *RemoveGroupRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// RemoveGroupResponse is sent in response to a RemoveGroupRequest
+type RemoveGroupResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveGroupResponse) Reset()         { *m = RemoveGroupResponse{} }
+func (m *RemoveGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveGroupResponse) ProtoMessage()    {}
+func (*RemoveGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{48}
+}
+
+func (m *RemoveGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveGroupResponse.Unmarshal(m, b)
+}
+func (m *RemoveGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoveGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveGroupResponse.Merge(m, src)
+}
+func (m *RemoveGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveGroupResponse.Size(m)
+}
+func (m *RemoveGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveGroupResponse proto.InternalMessageInfo
+
+// ListGroupMembersRequest lists the devices that are members of a device group
+type ListGroupMembersRequest struct {
+	// group_id is the identifier of the group
+	GroupId string `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// view is the view of the member devices
+	// Defaults to the FULL view.
+	View                 ListRequest_View `protobuf:"varint,2,opt,name=view,proto3,enum=topo.device.ListRequest_View" json:"view,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListGroupMembersRequest) Reset()         { *m = ListGroupMembersRequest{} }
+func (m *ListGroupMembersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListGroupMembersRequest) ProtoMessage()    {}
+func (*ListGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{49}
+}
+
+func (m *ListGroupMembersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupMembersRequest.Unmarshal(m, b)
+}
+func (m *ListGroupMembersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupMembersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListGroupMembersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupMembersRequest.Merge(m, src)
+}
+func (m *ListGroupMembersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListGroupMembersRequest.Size(m)
+}
+func (m *ListGroupMembersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupMembersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupMembersRequest proto.InternalMessageInfo
+
+func (m *ListGroupMembersRequest) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *ListGroupMembersRequest) GetView() ListRequest_View {
+	if m != nil {
+		return m.View
+	}
+	return ListRequest_FULL
+}
+
+// ListGroupMembersResponse carries the members of a device group
+type ListGroupMembersResponse struct {
+	// devices is the list of member devices, ordered by device ID
+	Devices []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// missing_ids is the list of explicit members of the group that do not exist
+	MissingIds           []string `protobuf:"bytes,2,rep,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListGroupMembersResponse) Reset()         { *m = ListGroupMembersResponse{} }
+func (m *ListGroupMembersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListGroupMembersResponse) ProtoMessage()    {}
+func (*ListGroupMembersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{50}
+}
+
+func (m *ListGroupMembersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListGroupMembersResponse.Unmarshal(m, b)
+}
+func (m *ListGroupMembersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListGroupMembersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListGroupMembersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListGroupMembersResponse.Merge(m, src)
+}
+func (m *ListGroupMembersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListGroupMembersResponse.Size(m)
+}
+func (m *ListGroupMembersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListGroupMembersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListGroupMembersResponse proto.InternalMessageInfo
+
+func (m *ListGroupMembersResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func (m *ListGroupMembersResponse) GetMissingIds() []string {
+	if m != nil {
+		return m.MissingIds
+	}
+	return nil
+}
+
+// RemoveRequest removes a device by ID
+type RemoveRequest struct {
+	// device is the device to remove
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// idempotency_key is an optional client-generated key identifying the request
+	// If a request with the same key was processed recently, the response to the original request is returned.
+	// A request with the same key that's still being processed is waited for. Failed requests aren't cached, so
+	// they can be retried with the same key.
+	IdempotencyKey       string   `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRequest) Reset()         { *m = RemoveRequest{} }
+func (m *RemoveRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRequest) ProtoMessage()    {}
+func (*RemoveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{51}
+}
+
+func (m *RemoveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRequest.Unmarshal(m, b)
+}
+func (m *RemoveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRequest.Merge(m, src)
+}
+func (m *RemoveRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveRequest.Size(m)
+}
+func (m *RemoveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRequest proto.InternalMessageInfo
+
+func (m *RemoveRequest) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *RemoveRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
+// RemoveResponse is sent in response to a RemoveDeviceRequest
+type RemoveResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveResponse) Reset()         { *m = RemoveResponse{} }
+func (m *RemoveResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveResponse) ProtoMessage()    {}
+func (*RemoveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{52}
+}
+
+func (m *RemoveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveResponse.Unmarshal(m, b)
+}
+func (m *RemoveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveResponse.Merge(m, src)
+}
+func (m *RemoveResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveResponse.Size(m)
+}
+func (m *RemoveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveResponse proto.InternalMessageInfo
+
+// SwapAddressesRequest swaps the addresses of two devices
+type SwapAddressesRequest struct {
+	// first_device_id is the ID of the first device
+	FirstDeviceId string `protobuf:"bytes,1,opt,name=first_device_id,json=firstDeviceId,proto3" json:"first_device_id,omitempty"`
+	// second_device_id is the ID of the second device
+	SecondDeviceId       string   `protobuf:"bytes,2,opt,name=second_device_id,json=secondDeviceId,proto3" json:"second_device_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SwapAddressesRequest) Reset()         { *m = SwapAddressesRequest{} }
+func (m *SwapAddressesRequest) String() string { return proto.CompactTextString(m) }
+func (*SwapAddressesRequest) ProtoMessage()    {}
+func (*SwapAddressesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{53}
+}
+
+func (m *SwapAddressesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SwapAddressesRequest.Unmarshal(m, b)
+}
+func (m *SwapAddressesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SwapAddressesRequest.Marshal(b, m, deterministic)
+}
+func (m *SwapAddressesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SwapAddressesRequest.Merge(m, src)
+}
+func (m *SwapAddressesRequest) XXX_Size() int {
+	return xxx_messageInfo_SwapAddressesRequest.Size(m)
+}
+func (m *SwapAddressesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SwapAddressesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SwapAddressesRequest proto.InternalMessageInfo
+
+func (m *SwapAddressesRequest) GetFirstDeviceId() string {
+	if m != nil {
+		return m.FirstDeviceId
+	}
+	return ""
+}
+
+func (m *SwapAddressesRequest) GetSecondDeviceId() string {
+	if m != nil {
+		return m.SecondDeviceId
+	}
+	return ""
+}
+
+// SwapAddressesResponse is sent in response to a SwapAddressesRequest
+type SwapAddressesResponse struct {
+	// first_device is the updated first device
+	FirstDevice *Device `protobuf:"bytes,1,opt,name=first_device,json=firstDevice,proto3" json:"first_device,omitempty"`
+	// second_device is the updated second device
+	SecondDevice         *Device  `protobuf:"bytes,2,opt,name=second_device,json=secondDevice,proto3" json:"second_device,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SwapAddressesResponse) Reset()         { *m = SwapAddressesResponse{} }
+func (m *SwapAddressesResponse) String() string { return proto.CompactTextString(m) }
+func (*SwapAddressesResponse) ProtoMessage()    {}
+func (*SwapAddressesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{54}
+}
+
+func (m *SwapAddressesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SwapAddressesResponse.Unmarshal(m, b)
+}
+func (m *SwapAddressesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SwapAddressesResponse.Marshal(b, m, deterministic)
+}
+func (m *SwapAddressesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SwapAddressesResponse.Merge(m, src)
+}
+func (m *SwapAddressesResponse) XXX_Size() int {
+	return xxx_messageInfo_SwapAddressesResponse.Size(m)
+}
+func (m *SwapAddressesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SwapAddressesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SwapAddressesResponse proto.InternalMessageInfo
+
+func (m *SwapAddressesResponse) GetFirstDevice() *Device {
+	if m != nil {
+		return m.FirstDevice
+	}
+	return nil
+}
+
+func (m *SwapAddressesResponse) GetSecondDevice() *Device {
+	if m != nil {
+		return m.SecondDevice
+	}
+	return nil
+}
+
+// RenameRequest changes the ID of a device
+type RenameRequest struct {
+	// device_id is the current ID of the device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// new_id is the new ID of the device
+	NewId string `protobuf:"bytes,2,opt,name=new_id,json=newId,proto3" json:"new_id,omitempty"`
+	// version is the expected version of the device
+	// If set, the device is renamed only if the stored device has the same version.
+	Version              uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameRequest) Reset()         { *m = RenameRequest{} }
+func (m *RenameRequest) String() string { return proto.CompactTextString(m) }
+func (*RenameRequest) ProtoMessage()    {}
+func (*RenameRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{55}
+}
+
+func (m *RenameRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameRequest.Unmarshal(m, b)
+}
+func (m *RenameRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameRequest.Marshal(b, m, deterministic)
+}
+func (m *RenameRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameRequest.Merge(m, src)
+}
+func (m *RenameRequest) XXX_Size() int {
+	return xxx_messageInfo_RenameRequest.Size(m)
+}
+func (m *RenameRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameRequest proto.InternalMessageInfo
+
+func (m *RenameRequest) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *RenameRequest) GetNewId() string {
+	if m != nil {
+		return m.NewId
+	}
+	return ""
+}
+
+func (m *RenameRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// RenameResponse is sent in response to a RenameRequest
+type RenameResponse struct {
+	// device is the renamed device
+	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameResponse) Reset()         { *m = RenameResponse{} }
+func (m *RenameResponse) String() string { return proto.CompactTextString(m) }
+func (*RenameResponse) ProtoMessage()    {}
+func (*RenameResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{56}
+}
+
+func (m *RenameResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameResponse.Unmarshal(m, b)
+}
+func (m *RenameResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameResponse.Marshal(b, m, deterministic)
+}
+func (m *RenameResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameResponse.Merge(m, src)
+}
+func (m *RenameResponse) XXX_Size() int {
+	return xxx_messageInfo_RenameResponse.Size(m)
+}
+func (m *RenameResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameResponse proto.InternalMessageInfo
+
+func (m *RenameResponse) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+// CloneDeviceRequest adds a copy of an existing device
+type CloneDeviceRequest struct {
+	// source_id is the ID of the device to copy
+	SourceId string `protobuf:"bytes,1,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	// new_id is the ID of the added device
+	NewId string `protobuf:"bytes,2,opt,name=new_id,json=newId,proto3" json:"new_id,omitempty"`
+	// overrides carries the values of the fields in the update mask to set on the copy
+	Overrides *Device `protobuf:"bytes,3,opt,name=overrides,proto3" json:"overrides,omitempty"`
+	// update_mask is the mask of the fields of overrides to set on the copy
+	// The mask is required if overrides is set.
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *CloneDeviceRequest) Reset()         { *m = CloneDeviceRequest{} }
+func (m *CloneDeviceRequest) String() string { return proto.CompactTextString(m) }
+func (*CloneDeviceRequest) ProtoMessage()    {}
+func (*CloneDeviceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{57}
+}
+
+func (m *CloneDeviceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloneDeviceRequest.Unmarshal(m, b)
+}
+func (m *CloneDeviceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloneDeviceRequest.Marshal(b, m, deterministic)
+}
+func (m *CloneDeviceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloneDeviceRequest.Merge(m, src)
+}
+func (m *CloneDeviceRequest) XXX_Size() int {
+	return xxx_messageInfo_CloneDeviceRequest.Size(m)
+}
+func (m *CloneDeviceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloneDeviceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloneDeviceRequest proto.InternalMessageInfo
+
+func (m *CloneDeviceRequest) GetSourceId() string {
+	if m != nil {
+		return m.SourceId
+	}
+	return ""
+}
+
+func (m *CloneDeviceRequest) GetNewId() string {
+	if m != nil {
+		return m.NewId
+	}
+	return ""
+}
+
+func (m *CloneDeviceRequest) GetOverrides() *Device {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+func (m *CloneDeviceRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+// CloneDeviceResponse is sent in response to a CloneDeviceRequest
+type CloneDeviceResponse struct {
+	// device is the added device
+	Device               *Device  `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloneDeviceResponse) Reset()         { *m = CloneDeviceResponse{} }
+func (m *CloneDeviceResponse) String() string { return proto.CompactTextString(m) }
+func (*CloneDeviceResponse) ProtoMessage()    {}
+func (*CloneDeviceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{58}
+}
+
+func (m *CloneDeviceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloneDeviceResponse.Unmarshal(m, b)
+}
+func (m *CloneDeviceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloneDeviceResponse.Marshal(b, m, deterministic)
+}
+func (m *CloneDeviceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloneDeviceResponse.Merge(m, src)
+}
+func (m *CloneDeviceResponse) XXX_Size() int {
+	return xxx_messageInfo_CloneDeviceResponse.Size(m)
+}
+func (m *CloneDeviceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloneDeviceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloneDeviceResponse proto.InternalMessageInfo
+
+func (m *CloneDeviceResponse) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+// Device contains information about a device
+// Devices are validated against the following rules before they're stored:
+//   - id is required
+//   - address, if set, and each of addresses must be a host:port address
+//   - timeout, if set, must be greater than or equal to 0s
+//   - protocols may contain at most one configuration for each protocol type
+//   - location, if set, must have a latitude from -90 to 90, a longitude from -180 to 180, and a finite altitude
+//
+// The IDs of added and renamed devices must additionally match the ID pattern configured for the service,
+// which by default requires IDs to be RFC 1123 DNS labels.
+// The rules are implemented by the Validate method of the generated Go type rather than by protoc-gen-validate
+// annotations, which the protoc-go image that compiles these protos doesn't support. Go clients, including
+// the onos CLI, validate devices with the same method.
+type Device struct {
+	// metadata is the store metadata used for concurrency control
+	Metadata *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// id is a globally unique device identifier
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// address is the host:port of the device
+	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	// target is the device target
+	Target string `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	// software_version is the device software version
+	SoftwareVersion string `protobuf:"bytes,5,opt,name=software_version,json=softwareVersion,proto3" json:"software_version,omitempty"`
+	// timeout indicates the device request timeout
+	Timeout *duration.Duration `protobuf:"bytes,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// credentials contains the credentials for connecting to the device
+	Credentials *Credentials `protobuf:"bytes,7,opt,name=credentials,proto3" json:"credentials,omitempty"`
+	// tls is the device TLS configuration
+	Tls *TlsConfig `protobuf:"bytes,8,opt,name=tls,proto3" json:"tls,omitempty"`
+	// protocols is the list of southbound protocol configurations for the device
+	// Each protocol may be configured at most once per device.
+	Protocols []*Protocol `protobuf:"bytes,9,rep,name=protocols,proto3" json:"protocols,omitempty"`
+	// parent_id is the identifier of the device's parent in the containment hierarchy
+	// A device with no parent is a root of the hierarchy.
+	ParentId string `protobuf:"bytes,10,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// addresses is the list of failover host:port addresses of the device
+	// The address field remains the primary address of the device, and clients should fail over to the
+	// addresses in the order in which they're listed.
+	Addresses []string `protobuf:"bytes,11,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// lifecycle_status is the status of the device in its configuration lifecycle
+	// Lifecycle phases may only advance from UNKNOWN to CONFIGURING to CONFIGURED or FAILED unless the
+	// update is forced.
+	LifecycleStatus *LifecycleStatus `protobuf:"bytes,12,opt,name=lifecycle_status,json=lifecycleStatus,proto3" json:"lifecycle_status,omitempty"`
+	// connection_status is the status of the connection to the device
+	ConnectionStatus *ConnectionStatus `protobuf:"bytes,13,opt,name=connection_status,json=connectionStatus,proto3" json:"connection_status,omitempty"`
+	// location is the geographic location of the device
+	Location *GeoLocation `protobuf:"bytes,14,opt,name=location,proto3" json:"location,omitempty"`
+	// labels is a set of key/value pairs used to organize and select devices
+	Labels map[string]string `protobuf:"bytes,15,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// owner is the controller that holds a lease on the device
+	// The owner is set with ClaimDevice and cleared with ReleaseDevice, and can't be set when the device is
+	// added or updated with a field mask.
+	Owner *Owner `protobuf:"bytes,16,opt,name=owner,proto3" json:"owner,omitempty"`
+	// maintenance is the maintenance status of the device
+	Maintenance *Maintenance `protobuf:"bytes,17,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	// serial_number is the serial number of the device hardware
+	// If the service enforces unique serial numbers, a device can't be added or updated with the serial
+	// number of another device.
+	SerialNumber string `protobuf:"bytes,18,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// model is the hardware model of the device
+	Model string `protobuf:"bytes,19,opt,name=model,proto3" json:"model,omitempty"`
+	// vendor is the vendor of the device hardware
+	Vendor string `protobuf:"bytes,20,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// updated is the time at which the device was last written to the store
+	// The update time is set by the store whenever the device is stored and can't be set by clients. Devices
+	// that have not been written since the service started recording update times have no update time.
+	Updated              *timestamp.Timestamp `protobuf:"bytes,21,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Device) Reset()         { *m = Device{} }
+func (m *Device) String() string { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()    {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{59}
+}
+
+func (m *Device) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Device.Unmarshal(m, b)
+}
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Device.Marshal(b, m, deterministic)
+}
+func (m *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(m, src)
+}
+func (m *Device) XXX_Size() int {
+	return xxx_messageInfo_Device.Size(m)
+}
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Device proto.InternalMessageInfo
+
+func (m *Device) GetMetadata() *ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Device) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Device) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Device) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *Device) GetSoftwareVersion() string {
+	if m != nil {
+		return m.SoftwareVersion
+	}
+	return ""
+}
+
+func (m *Device) GetTimeout() *duration.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *Device) GetCredentials() *Credentials {
+	if m != nil {
+		return m.Credentials
+	}
+	return nil
+}
+
+func (m *Device) GetTls() *TlsConfig {
+	if m != nil {
+		return m.Tls
+	}
+	return nil
+}
+
+func (m *Device) GetProtocols() []*Protocol {
+	if m != nil {
+		return m.Protocols
+	}
+	return nil
+}
+
+func (m *Device) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+func (m *Device) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *Device) GetLifecycleStatus() *LifecycleStatus {
+	if m != nil {
+		return m.LifecycleStatus
+	}
+	return nil
+}
+
+func (m *Device) GetConnectionStatus() *ConnectionStatus {
+	if m != nil {
+		return m.ConnectionStatus
+	}
+	return nil
+}
+
+func (m *Device) GetLocation() *GeoLocation {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (m *Device) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Device) GetOwner() *Owner {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *Device) GetMaintenance() *Maintenance {
+	if m != nil {
+		return m.Maintenance
+	}
+	return nil
+}
+
+func (m *Device) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *Device) GetModel() string {
+	if m != nil {
+		return m.Model
+	}
+	return ""
+}
+
+func (m *Device) GetVendor() string {
+	if m != nil {
+		return m.Vendor
+	}
+	return ""
+}
+
+func (m *Device) GetUpdated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+// Maintenance is the maintenance status of a device
+type Maintenance struct {
+	// quiesced indicates whether the device is under maintenance
+	// Controllers should ignore quiesced devices, e.g. not connect to them or raise alerts for them, until the
+	// device is unquiesced. Subscribers can exclude quiesced devices with ListRequest.exclude_quiesced.
+	Quiesced bool `protobuf:"varint,1,opt,name=quiesced,proto3" json:"quiesced,omitempty"`
+	// reason is a human readable reason for the maintenance
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// since is the time at which the device was quiesced
+	Since                *timestamp.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Maintenance) Reset()         { *m = Maintenance{} }
+func (m *Maintenance) String() string { return proto.CompactTextString(m) }
+func (*Maintenance) ProtoMessage()    {}
+func (*Maintenance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{60}
+}
+
+func (m *Maintenance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Maintenance.Unmarshal(m, b)
+}
+func (m *Maintenance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Maintenance.Marshal(b, m, deterministic)
+}
+func (m *Maintenance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Maintenance.Merge(m, src)
+}
+func (m *Maintenance) XXX_Size() int {
+	return xxx_messageInfo_Maintenance.Size(m)
+}
+func (m *Maintenance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Maintenance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Maintenance proto.InternalMessageInfo
+
+func (m *Maintenance) GetQuiesced() bool {
+	if m != nil {
+		return m.Quiesced
+	}
+	return false
+}
+
+func (m *Maintenance) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Maintenance) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+// Owner is a controller's lease on a device
+type Owner struct {
+	// id is the identifier of the controller that owns the device
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// expiry is the time at which the lease expires unless it's renewed
+	// Once the lease expires, the device can be claimed by another controller.
+	Expiry               *timestamp.Timestamp `protobuf:"bytes,2,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Owner) Reset()         { *m = Owner{} }
+func (m *Owner) String() string { return proto.CompactTextString(m) }
+func (*Owner) ProtoMessage()    {}
+func (*Owner) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{61}
+}
+
+func (m *Owner) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Owner.Unmarshal(m, b)
+}
+func (m *Owner) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Owner.Marshal(b, m, deterministic)
+}
+func (m *Owner) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Owner.Merge(m, src)
+}
+func (m *Owner) XXX_Size() int {
+	return xxx_messageInfo_Owner.Size(m)
+}
+func (m *Owner) XXX_DiscardUnknown() {
+	xxx_messageInfo_Owner.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Owner proto.InternalMessageInfo
+
+func (m *Owner) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Owner) GetExpiry() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expiry
+	}
+	return nil
+}
+
+// GeoLocation is a geographic location
+type GeoLocation struct {
+	// lat is the latitude in degrees, from -90 to 90
+	Lat float64 `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	// lng is the longitude in degrees, from -180 to 180
+	Lng float64 `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	// altitude is the altitude in meters above sea level
+	Altitude             float64  `protobuf:"fixed64,3,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoLocation) Reset()         { *m = GeoLocation{} }
+func (m *GeoLocation) String() string { return proto.CompactTextString(m) }
+func (*GeoLocation) ProtoMessage()    {}
+func (*GeoLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{62}
+}
+
+func (m *GeoLocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoLocation.Unmarshal(m, b)
+}
+func (m *GeoLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoLocation.Marshal(b, m, deterministic)
+}
+func (m *GeoLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoLocation.Merge(m, src)
+}
+func (m *GeoLocation) XXX_Size() int {
+	return xxx_messageInfo_GeoLocation.Size(m)
+}
+func (m *GeoLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoLocation proto.InternalMessageInfo
+
+func (m *GeoLocation) GetLat() float64 {
+	if m != nil {
+		return m.Lat
+	}
+	return 0
+}
+
+func (m *GeoLocation) GetLng() float64 {
+	if m != nil {
+		return m.Lng
+	}
+	return 0
+}
+
+func (m *GeoLocation) GetAltitude() float64 {
+	if m != nil {
+		return m.Altitude
+	}
+	return 0
+}
+
+// ConnectionStatus is the status of the connection to a device
+type ConnectionStatus struct {
+	// state is the connection state of the device
+	State ConnectionState `protobuf:"varint,1,opt,name=state,proto3,enum=topo.device.ConnectionState" json:"state,omitempty"`
+	// reason is a human readable reason for the device being in the state
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// timestamp is the time at which the device entered the state
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// reporter identifies the southbound agent that last reported the state with ReportState
+	Reporter             string   `protobuf:"bytes,4,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionStatus) Reset()         { *m = ConnectionStatus{} }
+func (m *ConnectionStatus) String() string { return proto.CompactTextString(m) }
+func (*ConnectionStatus) ProtoMessage()    {}
+func (*ConnectionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{63}
+}
+
+func (m *ConnectionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionStatus.Unmarshal(m, b)
+}
+func (m *ConnectionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionStatus.Marshal(b, m, deterministic)
+}
+func (m *ConnectionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionStatus.Merge(m, src)
+}
+func (m *ConnectionStatus) XXX_Size() int {
+	return xxx_messageInfo_ConnectionStatus.Size(m)
+}
+func (m *ConnectionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionStatus proto.InternalMessageInfo
+
+func (m *ConnectionStatus) GetState() ConnectionState {
+	if m != nil {
+		return m.State
+	}
+	return ConnectionState_CONNECTION_UNKNOWN
+}
+
+func (m *ConnectionStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ConnectionStatus) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *ConnectionStatus) GetReporter() string {
+	if m != nil {
+		return m.Reporter
+	}
+	return ""
+}
+
+// LifecycleStatus is the status of a device in its configuration lifecycle
+type LifecycleStatus struct {
+	// phase is the lifecycle phase of the device
+	Phase LifecyclePhase `protobuf:"varint,1,opt,name=phase,proto3,enum=topo.device.LifecyclePhase" json:"phase,omitempty"`
+	// reason is a human readable reason for the device being in the phase
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// timestamp is the time at which the device entered the phase
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *LifecycleStatus) Reset()         { *m = LifecycleStatus{} }
+func (m *LifecycleStatus) String() string { return proto.CompactTextString(m) }
+func (*LifecycleStatus) ProtoMessage()    {}
+func (*LifecycleStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{64}
+}
+
+func (m *LifecycleStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleStatus.Unmarshal(m, b)
+}
+func (m *LifecycleStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleStatus.Marshal(b, m, deterministic)
+}
+func (m *LifecycleStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleStatus.Merge(m, src)
+}
+func (m *LifecycleStatus) XXX_Size() int {
+	return xxx_messageInfo_LifecycleStatus.Size(m)
+}
+func (m *LifecycleStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleStatus proto.InternalMessageInfo
+
+func (m *LifecycleStatus) GetPhase() LifecyclePhase {
+	if m != nil {
+		return m.Phase
+	}
+	return LifecyclePhase_UNKNOWN
+}
+
+func (m *LifecycleStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *LifecycleStatus) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// Protocol is the configuration for a southbound protocol of a device
+type Protocol struct {
+	// type is the southbound protocol type
+	Type Protocol_Type `protobuf:"varint,1,opt,name=type,proto3,enum=topo.device.Protocol_Type" json:"type,omitempty"`
+	// config is the opaque protocol-specific configuration
+	// The format of the configuration is defined by the southbound service that manages the protocol.
+	Config               []byte   `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Protocol) Reset()         { *m = Protocol{} }
+func (m *Protocol) String() string { return proto.CompactTextString(m) }
+func (*Protocol) ProtoMessage()    {}
+func (*Protocol) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{65}
+}
+
+func (m *Protocol) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Protocol.Unmarshal(m, b)
+}
+func (m *Protocol) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Protocol.Marshal(b, m, deterministic)
+}
+func (m *Protocol) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Protocol.Merge(m, src)
+}
+func (m *Protocol) XXX_Size() int {
+	return xxx_messageInfo_Protocol.Size(m)
+}
+func (m *Protocol) XXX_DiscardUnknown() {
+	xxx_messageInfo_Protocol.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Protocol proto.InternalMessageInfo
+
+func (m *Protocol) GetType() Protocol_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Protocol_UNKNOWN
+}
+
+func (m *Protocol) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+// Credentials is the device credentials
+// Exactly one of basic_auth, ssh_key, or token may be set. For compatibility with older clients, user and
+// password may be set instead of basic_auth. The service migrates user and password to basic_auth when the
+// device is stored, so they're always empty in devices returned by the service.
+type Credentials struct {
+	// user is the user with which to connect to the device
+	// Deprecated: use basic_auth.
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// password is the password for connecting to the device
+	// Deprecated: use basic_auth.
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// credential is the credential with which to connect to the device
+	//
+	// Types that are valid to be assigned to Credential:
+	//	*Credentials_BasicAuth
+	//	*Credentials_SshKey
+	//	*Credentials_Token
+	Credential           isCredentials_Credential `protobuf_oneof:"credential"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *Credentials) Reset()         { *m = Credentials{} }
+func (m *Credentials) String() string { return proto.CompactTextString(m) }
+func (*Credentials) ProtoMessage()    {}
+func (*Credentials) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{66}
+}
+
+func (m *Credentials) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Credentials.Unmarshal(m, b)
+}
+func (m *Credentials) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Credentials.Marshal(b, m, deterministic)
+}
+func (m *Credentials) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Credentials.Merge(m, src)
+}
+func (m *Credentials) XXX_Size() int {
+	return xxx_messageInfo_Credentials.Size(m)
+}
+func (m *Credentials) XXX_DiscardUnknown() {
+	xxx_messageInfo_Credentials.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Credentials proto.InternalMessageInfo
+
+func (m *Credentials) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Credentials) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type isCredentials_Credential interface {
+	isCredentials_Credential()
+}
+
+type Credentials_BasicAuth struct {
+	BasicAuth *BasicAuth `protobuf:"bytes,3,opt,name=basic_auth,json=basicAuth,proto3,oneof"`
+}
+
+type Credentials_SshKey struct {
+	SshKey *SshKey `protobuf:"bytes,4,opt,name=ssh_key,json=sshKey,proto3,oneof"`
+}
+
+type Credentials_Token struct {
+	Token *Token `protobuf:"bytes,5,opt,name=token,proto3,oneof"`
+}
+
+func (*Credentials_BasicAuth) isCredentials_Credential() {}
+
+func (*Credentials_SshKey) isCredentials_Credential() {}
+
+func (*Credentials_Token) isCredentials_Credential() {}
+
+func (m *Credentials) GetCredential() isCredentials_Credential {
+	if m != nil {
+		return m.Credential
+	}
+	return nil
+}
+
+func (m *Credentials) GetBasicAuth() *BasicAuth {
+	if x, ok := m.GetCredential().(*Credentials_BasicAuth); ok {
+		return x.BasicAuth
+	}
+	return nil
+}
+
+func (m *Credentials) GetSshKey() *SshKey {
+	if x, ok := m.GetCredential().(*Credentials_SshKey); ok {
+		return x.SshKey
+	}
+	return nil
+}
+
+func (m *Credentials) GetToken() *Token {
+	if x, ok := m.GetCredential().(*Credentials_Token); ok {
+		return x.Token
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Credentials) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Credentials_BasicAuth)(nil),
+		(*Credentials_SshKey)(nil),
+		(*Credentials_Token)(nil),
+	}
+}
+
+// BasicAuth is a user and password credential
+type BasicAuth struct {
+	// user is the user with which to connect to the device
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// password is the password for connecting to the device
+	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BasicAuth) Reset()         { *m = BasicAuth{} }
+func (m *BasicAuth) String() string { return proto.CompactTextString(m) }
+func (*BasicAuth) ProtoMessage()    {}
+func (*BasicAuth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{67}
+}
+
+func (m *BasicAuth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BasicAuth.Unmarshal(m, b)
+}
+func (m *BasicAuth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BasicAuth.Marshal(b, m, deterministic)
+}
+func (m *BasicAuth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BasicAuth.Merge(m, src)
+}
+func (m *BasicAuth) XXX_Size() int {
+	return xxx_messageInfo_BasicAuth.Size(m)
+}
+func (m *BasicAuth) XXX_DiscardUnknown() {
+	xxx_messageInfo_BasicAuth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BasicAuth proto.InternalMessageInfo
+
+func (m *BasicAuth) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *BasicAuth) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+// SshKey is an SSH private key credential
+type SshKey struct {
+	// user is the user with which to connect to the device
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// private_key_ref is a reference to the private key, e.g. the name of a secret
+	PrivateKeyRef        string   `protobuf:"bytes,2,opt,name=private_key_ref,json=privateKeyRef,proto3" json:"private_key_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SshKey) Reset()         { *m = SshKey{} }
+func (m *SshKey) String() string { return proto.CompactTextString(m) }
+func (*SshKey) ProtoMessage()    {}
+func (*SshKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{68}
+}
+
+func (m *SshKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SshKey.Unmarshal(m, b)
+}
+func (m *SshKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SshKey.Marshal(b, m, deterministic)
+}
+func (m *SshKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SshKey.Merge(m, src)
+}
+func (m *SshKey) XXX_Size() int {
+	return xxx_messageInfo_SshKey.Size(m)
+}
+func (m *SshKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_SshKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SshKey proto.InternalMessageInfo
+
+func (m *SshKey) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *SshKey) GetPrivateKeyRef() string {
+	if m != nil {
+		return m.PrivateKeyRef
+	}
+	return ""
+}
+
+// Token is an API token credential
+type Token struct {
+	// value_ref is a reference to the token value, e.g. the name of a secret
+	ValueRef             string   `protobuf:"bytes,1,opt,name=value_ref,json=valueRef,proto3" json:"value_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Token) Reset()         { *m = Token{} }
+func (m *Token) String() string { return proto.CompactTextString(m) }
+func (*Token) ProtoMessage()    {}
+func (*Token) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{69}
+}
+
+func (m *Token) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Token.Unmarshal(m, b)
+}
+func (m *Token) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Token.Marshal(b, m, deterministic)
+}
+func (m *Token) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Token.Merge(m, src)
+}
+func (m *Token) XXX_Size() int {
+	return xxx_messageInfo_Token.Size(m)
+}
+func (m *Token) XXX_DiscardUnknown() {
+	xxx_messageInfo_Token.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Token proto.InternalMessageInfo
+
+func (m *Token) GetValueRef() string {
+	if m != nil {
+		return m.ValueRef
+	}
+	return ""
+}
+
+// Device TLS configuration
+type TlsConfig struct {
+	// caCert is the name of the device's CA certificate
+	CaCert string `protobuf:"bytes,3,opt,name=caCert,proto3" json:"caCert,omitempty"`
+	// cert is the name of the device's certificate
+	Cert string `protobuf:"bytes,4,opt,name=cert,proto3" json:"cert,omitempty"`
+	// key is the name of the device's TLS key
+	Key string `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	// plain indicates whether to connect to the device over plaintext
+	Plain bool `protobuf:"varint,6,opt,name=plain,proto3" json:"plain,omitempty"`
+	// insecure indicates whether to connect to the device with insecure communication
+	Insecure             bool     `protobuf:"varint,7,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TlsConfig) Reset()         { *m = TlsConfig{} }
+func (m *TlsConfig) String() string { return proto.CompactTextString(m) }
+func (*TlsConfig) ProtoMessage()    {}
+func (*TlsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{70}
+}
+
+func (m *TlsConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TlsConfig.Unmarshal(m, b)
+}
+func (m *TlsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TlsConfig.Marshal(b, m, deterministic)
+}
+func (m *TlsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TlsConfig.Merge(m, src)
+}
+func (m *TlsConfig) XXX_Size() int {
+	return xxx_messageInfo_TlsConfig.Size(m)
+}
+func (m *TlsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_TlsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TlsConfig proto.InternalMessageInfo
+
+func (m *TlsConfig) GetCaCert() string {
+	if m != nil {
+		return m.CaCert
+	}
+	return ""
+}
+
+func (m *TlsConfig) GetCert() string {
+	if m != nil {
+		return m.Cert
+	}
+	return ""
+}
+
+func (m *TlsConfig) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TlsConfig) GetPlain() bool {
+	if m != nil {
+		return m.Plain
+	}
+	return false
+}
+
+func (m *TlsConfig) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+// Annotations are transient operational notes attached to a device
+// Annotations are stored alongside the device but are versioned independently, so setting annotations
+// does not change the device version.
+type Annotations struct {
+	// device_id is the identifier of the annotated device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// values is the annotation key/value pairs
+	Values map[string]string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// version is the store version of the annotations
+	Version              uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Annotations) Reset()         { *m = Annotations{} }
+func (m *Annotations) String() string { return proto.CompactTextString(m) }
+func (*Annotations) ProtoMessage()    {}
+func (*Annotations) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{71}
+}
+
+func (m *Annotations) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Annotations.Unmarshal(m, b)
+}
+func (m *Annotations) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Annotations.Marshal(b, m, deterministic)
+}
+func (m *Annotations) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Annotations.Merge(m, src)
+}
+func (m *Annotations) XXX_Size() int {
+	return xxx_messageInfo_Annotations.Size(m)
+}
+func (m *Annotations) XXX_DiscardUnknown() {
+	xxx_messageInfo_Annotations.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Annotations proto.InternalMessageInfo
+
+func (m *Annotations) GetDeviceId() string {
+	if m != nil {
+		return m.DeviceId
+	}
+	return ""
+}
+
+func (m *Annotations) GetValues() map[string]string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *Annotations) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// DeviceGroup is a named set of devices
+// The members of a group are its explicit members and the devices whose labels match its selector. Members
+// are resolved against the current devices when the group's members are listed, so devices that are added
+// or relabeled join the group without updating it.
+type DeviceGroup struct {
+	// id is the unique identifier of the group
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the display name of the group, e.g. "pod-a leaf switches"
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// selector is the label selector matched against device labels, e.g. "pod=a,role=leaf"
+	// Devices are not selected by labels if the selector is empty.
+	Selector string `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+	// member_ids is the list of identifiers of the explicit members of the group
+	MemberIds []string `protobuf:"bytes,4,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	// version is the store version of the group
+	Version              uint64   `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeviceGroup) Reset()         { *m = DeviceGroup{} }
+func (m *DeviceGroup) String() string { return proto.CompactTextString(m) }
+func (*DeviceGroup) ProtoMessage()    {}
+func (*DeviceGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{72}
+}
+
+func (m *DeviceGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceGroup.Unmarshal(m, b)
+}
+func (m *DeviceGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceGroup.Marshal(b, m, deterministic)
+}
+func (m *DeviceGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceGroup.Merge(m, src)
+}
+func (m *DeviceGroup) XXX_Size() int {
+	return xxx_messageInfo_DeviceGroup.Size(m)
+}
+func (m *DeviceGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceGroup proto.InternalMessageInfo
+
+func (m *DeviceGroup) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeviceGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeviceGroup) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *DeviceGroup) GetMemberIds() []string {
+	if m != nil {
+		return m.MemberIds
+	}
+	return nil
+}
+
+func (m *DeviceGroup) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// SnapshotEntry is an entry of a snapshot of the device store
+// A snapshot is a sequence of entries, each preceded by its length in bytes encoded as a varint. The
+// annotations of a device follow the device.
+type SnapshotEntry struct {
+	// Types that are valid to be assigned to Entry:
+	//	*SnapshotEntry_Device
+	//	*SnapshotEntry_Annotations
+	//	*SnapshotEntry_Group
+	Entry                isSnapshotEntry_Entry `protobuf_oneof:"entry"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *SnapshotEntry) Reset()         { *m = SnapshotEntry{} }
+func (m *SnapshotEntry) String() string { return proto.CompactTextString(m) }
+func (*SnapshotEntry) ProtoMessage()    {}
+func (*SnapshotEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{73}
+}
+
+func (m *SnapshotEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotEntry.Unmarshal(m, b)
+}
+func (m *SnapshotEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotEntry.Marshal(b, m, deterministic)
+}
+func (m *SnapshotEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotEntry.Merge(m, src)
+}
+func (m *SnapshotEntry) XXX_Size() int {
+	return xxx_messageInfo_SnapshotEntry.Size(m)
+}
+func (m *SnapshotEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotEntry proto.InternalMessageInfo
+
+type isSnapshotEntry_Entry interface {
+	isSnapshotEntry_Entry()
+}
+
+type SnapshotEntry_Device struct {
+	Device *Device `protobuf:"bytes,1,opt,name=device,proto3,oneof"`
+}
+
+type SnapshotEntry_Annotations struct {
+	Annotations *Annotations `protobuf:"bytes,2,opt,name=annotations,proto3,oneof"`
+}
+
+type SnapshotEntry_Group struct {
+	Group *DeviceGroup `protobuf:"bytes,3,opt,name=group,proto3,oneof"`
+}
+
+func (*SnapshotEntry_Device) isSnapshotEntry_Entry() {}
+
+func (*SnapshotEntry_Annotations) isSnapshotEntry_Entry() {}
+
+func (*SnapshotEntry_Group) isSnapshotEntry_Entry() {}
+
+func (m *SnapshotEntry) GetEntry() isSnapshotEntry_Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *SnapshotEntry) GetDevice() *Device {
+	if x, ok := m.GetEntry().(*SnapshotEntry_Device); ok {
+		return x.Device
+	}
+	return nil
+}
+
+func (m *SnapshotEntry) GetAnnotations() *Annotations {
+	if x, ok := m.GetEntry().(*SnapshotEntry_Annotations); ok {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (m *SnapshotEntry) GetGroup() *DeviceGroup {
+	if x, ok := m.GetEntry().(*SnapshotEntry_Group); ok {
+		return x.Group
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*SnapshotEntry) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*SnapshotEntry_Device)(nil),
+		(*SnapshotEntry_Annotations)(nil),
+		(*SnapshotEntry_Group)(nil),
+	}
+}
+
+// ObjectMetadata is the metadata required by the store for concurrency control
+type ObjectMetadata struct {
+	// id is the unique identifier for the object
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// version is the store version of the object
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ObjectMetadata) Reset()         { *m = ObjectMetadata{} }
+func (m *ObjectMetadata) String() string { return proto.CompactTextString(m) }
+func (*ObjectMetadata) ProtoMessage()    {}
+func (*ObjectMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{74}
+}
+
+func (m *ObjectMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObjectMetadata.Unmarshal(m, b)
+}
+func (m *ObjectMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObjectMetadata.Marshal(b, m, deterministic)
+}
+func (m *ObjectMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectMetadata.Merge(m, src)
+}
+func (m *ObjectMetadata) XXX_Size() int {
+	return xxx_messageInfo_ObjectMetadata.Size(m)
+}
+func (m *ObjectMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectMetadata proto.InternalMessageInfo
+
+func (m *ObjectMetadata) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ObjectMetadata) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// GetCapabilitiesRequest requests the capabilities of the server
+type GetCapabilitiesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCapabilitiesRequest) Reset()         { *m = GetCapabilitiesRequest{} }
+func (m *GetCapabilitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCapabilitiesRequest) ProtoMessage()    {}
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{75}
+}
+
+func (m *GetCapabilitiesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapabilitiesRequest.Unmarshal(m, b)
+}
+func (m *GetCapabilitiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapabilitiesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCapabilitiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapabilitiesRequest.Merge(m, src)
+}
+func (m *GetCapabilitiesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCapabilitiesRequest.Size(m)
+}
+func (m *GetCapabilitiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapabilitiesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapabilitiesRequest proto.InternalMessageInfo
+
+// GetCapabilitiesResponse describes the capabilities of the server
+type GetCapabilitiesResponse struct {
+	// features is the set of optional features supported by the server
+	Features *Features `protobuf:"bytes,1,opt,name=features,proto3" json:"features,omitempty"`
+	// device_fields is the names of the top-level Device fields known to the server
+	// Fields set by clients that are not in this list are discarded by the server.
+	DeviceFields         []string `protobuf:"bytes,2,rep,name=device_fields,json=deviceFields,proto3" json:"device_fields,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCapabilitiesResponse) Reset()         { *m = GetCapabilitiesResponse{} }
+func (m *GetCapabilitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetCapabilitiesResponse) ProtoMessage()    {}
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{76}
+}
+
+func (m *GetCapabilitiesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCapabilitiesResponse.Unmarshal(m, b)
+}
+func (m *GetCapabilitiesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCapabilitiesResponse.Marshal(b, m, deterministic)
+}
+func (m *GetCapabilitiesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCapabilitiesResponse.Merge(m, src)
+}
+func (m *GetCapabilitiesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetCapabilitiesResponse.Size(m)
+}
+func (m *GetCapabilitiesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCapabilitiesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCapabilitiesResponse proto.InternalMessageInfo
+
+func (m *GetCapabilitiesResponse) GetFeatures() *Features {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *GetCapabilitiesResponse) GetDeviceFields() []string {
+	if m != nil {
+		return m.DeviceFields
+	}
+	return nil
+}
+
+// Features is a set of optional features and whether they're supported by the server
+// Features that depend on the server configuration are reported as supported only if they're enabled.
+type Features struct {
+	// labels indicates whether devices can be labeled and selected by label
+	Labels bool `protobuf:"varint,1,opt,name=labels,proto3" json:"labels,omitempty"`
+	// links indicates whether links between devices are supported
+	Links bool `protobuf:"varint,2,opt,name=links,proto3" json:"links,omitempty"`
+	// soft_delete indicates whether removed devices are retained for recovery
+	SoftDelete bool `protobuf:"varint,3,opt,name=soft_delete,json=softDelete,proto3" json:"soft_delete,omitempty"`
+	// ownership indicates whether devices can be claimed with ClaimDevice
+	Ownership bool `protobuf:"varint,4,opt,name=ownership,proto3" json:"ownership,omitempty"`
+	// annotations indicates whether device annotations are supported
+	Annotations bool `protobuf:"varint,5,opt,name=annotations,proto3" json:"annotations,omitempty"`
+	// field_masks indicates whether devices can be updated with a field mask
+	FieldMasks bool `protobuf:"varint,6,opt,name=field_masks,json=fieldMasks,proto3" json:"field_masks,omitempty"`
+	// heartbeats indicates whether subscribers can request heartbeats
+	Heartbeats bool `protobuf:"varint,7,opt,name=heartbeats,proto3" json:"heartbeats,omitempty"`
+	// history indicates whether device histories are recorded
+	History bool `protobuf:"varint,8,opt,name=history,proto3" json:"history,omitempty"`
+	// writable indicates whether the server accepts writes, i.e. it is not a read-only replica
+	Writable bool `protobuf:"varint,9,opt,name=writable,proto3" json:"writable,omitempty"`
+	// force_updates indicates whether the server accepts forced updates
+	ForceUpdates bool `protobuf:"varint,10,opt,name=force_updates,json=forceUpdates,proto3" json:"force_updates,omitempty"`
+	// secret_access indicates whether clients may request device secrets
+	SecretAccess bool `protobuf:"varint,11,opt,name=secret_access,json=secretAccess,proto3" json:"secret_access,omitempty"`
+	// groups indicates whether the server supports device groups
+	Groups               bool     `protobuf:"varint,12,opt,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Features) Reset()         { *m = Features{} }
+func (m *Features) String() string { return proto.CompactTextString(m) }
+func (*Features) ProtoMessage()    {}
+func (*Features) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b9d152c21573e6ba, []int{77}
+}
+
+func (m *Features) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Features.Unmarshal(m, b)
+}
+func (m *Features) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Features.Marshal(b, m, deterministic)
+}
+func (m *Features) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Features.Merge(m, src)
+}
+func (m *Features) XXX_Size() int {
+	return xxx_messageInfo_Features.Size(m)
+}
+func (m *Features) XXX_DiscardUnknown() {
+	xxx_messageInfo_Features.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Features proto.InternalMessageInfo
+
+func (m *Features) GetLabels() bool {
+	if m != nil {
+		return m.Labels
+	}
+	return false
+}
+
+func (m *Features) GetLinks() bool {
+	if m != nil {
+		return m.Links
+	}
+	return false
+}
+
+func (m *Features) GetSoftDelete() bool {
+	if m != nil {
+		return m.SoftDelete
+	}
+	return false
+}
+
+func (m *Features) GetOwnership() bool {
+	if m != nil {
+		return m.Ownership
+	}
+	return false
+}
+
+func (m *Features) GetAnnotations() bool {
+	if m != nil {
+		return m.Annotations
+	}
+	return false
+}
+
+func (m *Features) GetFieldMasks() bool {
+	if m != nil {
+		return m.FieldMasks
+	}
+	return false
+}
+
+func (m *Features) GetHeartbeats() bool {
+	if m != nil {
+		return m.Heartbeats
+	}
+	return false
+}
+
+func (m *Features) GetHistory() bool {
+	if m != nil {
+		return m.History
+	}
+	return false
+}
+
+func (m *Features) GetWritable() bool {
+	if m != nil {
+		return m.Writable
+	}
+	return false
+}
+
+func (m *Features) GetForceUpdates() bool {
+	if m != nil {
+		return m.ForceUpdates
+	}
+	return false
+}
+
+func (m *Features) GetSecretAccess() bool {
+	if m != nil {
+		return m.SecretAccess
+	}
+	return false
+}
+
+func (m *Features) GetGroups() bool {
+	if m != nil {
+		return m.Groups
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterEnum("topo.device.ConnectionState", ConnectionState_name, ConnectionState_value)
+	proto.RegisterEnum("topo.device.LifecyclePhase", LifecyclePhase_name, LifecyclePhase_value)
+	proto.RegisterEnum("topo.device.ListRequest_View", ListRequest_View_name, ListRequest_View_value)
+	proto.RegisterEnum("topo.device.ListResponse_Type", ListResponse_Type_name, ListResponse_Type_value)
+	proto.RegisterEnum("topo.device.ListResponse_Subtype", ListResponse_Subtype_name, ListResponse_Subtype_value)
+	proto.RegisterEnum("topo.device.WatchAllResponse_ResourceType", WatchAllResponse_ResourceType_name, WatchAllResponse_ResourceType_value)
+	proto.RegisterEnum("topo.device.Protocol_Type", Protocol_Type_name, Protocol_Type_value)
+	proto.RegisterType((*AddRequest)(nil), "topo.device.AddRequest")
+	proto.RegisterType((*AddResponse)(nil), "topo.device.AddResponse")
+	proto.RegisterType((*EnsureDeviceRequest)(nil), "topo.device.EnsureDeviceRequest")
+	proto.RegisterType((*EnsureDeviceResponse)(nil), "topo.device.EnsureDeviceResponse")
+	proto.RegisterType((*UpdateRequest)(nil), "topo.device.UpdateRequest")
+	proto.RegisterType((*UpdateResponse)(nil), "topo.device.UpdateResponse")
+	proto.RegisterType((*GetRequest)(nil), "topo.device.GetRequest")
+	proto.RegisterType((*GetResponse)(nil), "topo.device.GetResponse")
+	proto.RegisterType((*ListRequest)(nil), "topo.device.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "topo.device.ListResponse")
+	proto.RegisterType((*UpdateManyRequest)(nil), "topo.device.UpdateManyRequest")
+	proto.RegisterType((*UpdateManyResponse)(nil), "topo.device.UpdateManyResponse")
+	proto.RegisterType((*UpdateManyResult)(nil), "topo.device.UpdateManyResult")
+	proto.RegisterType((*ClaimDeviceRequest)(nil), "topo.device.ClaimDeviceRequest")
+	proto.RegisterType((*ClaimDeviceResponse)(nil), "topo.device.ClaimDeviceResponse")
+	proto.RegisterType((*ReleaseDeviceRequest)(nil), "topo.device.ReleaseDeviceRequest")
+	proto.RegisterType((*ReleaseDeviceResponse)(nil), "topo.device.ReleaseDeviceResponse")
+	proto.RegisterType((*ReportStateRequest)(nil), "topo.device.ReportStateRequest")
+	proto.RegisterType((*ReportStateResponse)(nil), "topo.device.ReportStateResponse")
+	proto.RegisterType((*CompareAndSwapFieldRequest)(nil), "topo.device.CompareAndSwapFieldRequest")
+	proto.RegisterType((*CompareAndSwapFieldResponse)(nil), "topo.device.CompareAndSwapFieldResponse")
+	proto.RegisterType((*RotateCredentialsRequest)(nil), "topo.device.RotateCredentialsRequest")
+	proto.RegisterType((*RotateCredentialsResponse)(nil), "topo.device.RotateCredentialsResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "topo.device.SubscribeRequest")
+	proto.RegisterType((*ResyncRequest)(nil), "topo.device.ResyncRequest")
+	proto.RegisterType((*WatchAllRequest)(nil), "topo.device.WatchAllRequest")
+	proto.RegisterType((*WatchAllResponse)(nil), "topo.device.WatchAllResponse")
+	proto.RegisterType((*SetAnnotationsRequest)(nil), "topo.device.SetAnnotationsRequest")
+	proto.RegisterType((*SetAnnotationsResponse)(nil), "topo.device.SetAnnotationsResponse")
+	proto.RegisterType((*ListPageRequest)(nil), "topo.device.ListPageRequest")
+	proto.RegisterType((*ListPageResponse)(nil), "topo.device.ListPageResponse")
+	proto.RegisterType((*ListChildrenRequest)(nil), "topo.device.ListChildrenRequest")
+	proto.RegisterType((*ListChildrenResponse)(nil), "topo.device.ListChildrenResponse")
+	proto.RegisterType((*ListModifiedSinceRequest)(nil), "topo.device.ListModifiedSinceRequest")
+	proto.RegisterType((*GetDeviceHistoryRequest)(nil), "topo.device.GetDeviceHistoryRequest")
+	proto.RegisterType((*GetDeviceHistoryResponse)(nil), "topo.device.GetDeviceHistoryResponse")
+	proto.RegisterType((*DeviceHistoryEvent)(nil), "topo.device.DeviceHistoryEvent")
+	proto.RegisterType((*SearchDevicesRequest)(nil), "topo.device.SearchDevicesRequest")
+	proto.RegisterType((*SearchDevicesResponse)(nil), "topo.device.SearchDevicesResponse")
+	proto.RegisterType((*AddGroupRequest)(nil), "topo.device.AddGroupRequest")
+	proto.RegisterType((*AddGroupResponse)(nil), "topo.device.AddGroupResponse")
+	proto.RegisterType((*UpdateGroupRequest)(nil), "topo.device.UpdateGroupRequest")
+	proto.RegisterType((*UpdateGroupResponse)(nil), "topo.device.UpdateGroupResponse")
+	proto.RegisterType((*GetGroupRequest)(nil), "topo.device.GetGroupRequest")
+	proto.RegisterType((*GetGroupResponse)(nil), "topo.device.GetGroupResponse")
+	proto.RegisterType((*ListGroupsRequest)(nil), "topo.device.ListGroupsRequest")
+	proto.RegisterType((*ListGroupsResponse)(nil), "topo.device.ListGroupsResponse")
+	proto.RegisterType((*RemoveGroupRequest)(nil), "topo.device.RemoveGroupRequest")
+	proto.RegisterType((*RemoveGroupResponse)(nil), "topo.device.RemoveGroupResponse")
+	proto.RegisterType((*ListGroupMembersRequest)(nil), "topo.device.ListGroupMembersRequest")
+	proto.RegisterType((*ListGroupMembersResponse)(nil), "topo.device.ListGroupMembersResponse")
+	proto.RegisterType((*RemoveRequest)(nil), "topo.device.RemoveRequest")
+	proto.RegisterType((*RemoveResponse)(nil), "topo.device.RemoveResponse")
+	proto.RegisterType((*SwapAddressesRequest)(nil), "topo.device.SwapAddressesRequest")
+	proto.RegisterType((*SwapAddressesResponse)(nil), "topo.device.SwapAddressesResponse")
+	proto.RegisterType((*RenameRequest)(nil), "topo.device.RenameRequest")
+	proto.RegisterType((*RenameResponse)(nil), "topo.device.RenameResponse")
+	proto.RegisterType((*CloneDeviceRequest)(nil), "topo.device.CloneDeviceRequest")
+	proto.RegisterType((*CloneDeviceResponse)(nil), "topo.device.CloneDeviceResponse")
+	proto.RegisterType((*Device)(nil), "topo.device.Device")
+	proto.RegisterMapType((map[string]string)(nil), "topo.device.Device.LabelsEntry")
+	proto.RegisterType((*Maintenance)(nil), "topo.device.Maintenance")
+	proto.RegisterType((*Owner)(nil), "topo.device.Owner")
+	proto.RegisterType((*GeoLocation)(nil), "topo.device.GeoLocation")
+	proto.RegisterType((*ConnectionStatus)(nil), "topo.device.ConnectionStatus")
+	proto.RegisterType((*LifecycleStatus)(nil), "topo.device.LifecycleStatus")
+	proto.RegisterType((*Protocol)(nil), "topo.device.Protocol")
+	proto.RegisterType((*Credentials)(nil), "topo.device.Credentials")
+	proto.RegisterType((*BasicAuth)(nil), "topo.device.BasicAuth")
+	proto.RegisterType((*SshKey)(nil), "topo.device.SshKey")
+	proto.RegisterType((*Token)(nil), "topo.device.Token")
+	proto.RegisterType((*TlsConfig)(nil), "topo.device.TlsConfig")
+	proto.RegisterType((*Annotations)(nil), "topo.device.Annotations")
+	proto.RegisterMapType((map[string]string)(nil), "topo.device.Annotations.ValuesEntry")
+	proto.RegisterType((*DeviceGroup)(nil), "topo.device.DeviceGroup")
+	proto.RegisterType((*SnapshotEntry)(nil), "topo.device.SnapshotEntry")
+	proto.RegisterType((*ObjectMetadata)(nil), "topo.device.ObjectMetadata")
+	proto.RegisterType((*GetCapabilitiesRequest)(nil), "topo.device.GetCapabilitiesRequest")
+	proto.RegisterType((*GetCapabilitiesResponse)(nil), "topo.device.GetCapabilitiesResponse")
+	proto.RegisterType((*Features)(nil), "topo.device.Features")
+}
+
+func init() { proto.RegisterFile("pkg/northbound/device/device.proto", fileDescriptor_b9d152c21573e6ba) }
+
+var fileDescriptor_b9d152c21573e6ba = []byte{
+	// 3905 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0xcd, 0x73, 0xdb, 0x48,
+	0x76, 0x17, 0xf8, 0xcd, 0x47, 0x89, 0xa4, 0x5b, 0x96, 0x07, 0x86, 0xed, 0xb1, 0x8c, 0xf9, 0x58,
+	0xed, 0x64, 0x57, 0xf6, 0x68, 0x9c, 0x99, 0x89, 0x67, 0x92, 0x2c, 0x4d, 0x52, 0x32, 0xc7, 0x12,
+	0xe5, 0x80, 0xb2, 0x37, 0x53, 0xa9, 0x0d, 0x0b, 0x02, 0x5a, 0x12, 0x46, 0x20, 0x40, 0xa3, 0x9b,
+	0x92, 0xb5, 0xa9, 0x9c, 0x52, 0x49, 0x55, 0x0e, 0xd9, 0x43, 0x0e, 0x49, 0x55, 0xfe, 0x82, 0x24,
+	0x95, 0xaa, 0x5c, 0x52, 0x95, 0x5c, 0x72, 0xcb, 0x1f, 0xb2, 0xb7, 0xe4, 0x98, 0xca, 0x5f, 0x90,
+	0xea, 0x0f, 0x80, 0x00, 0x08, 0x52, 0x1f, 0xde, 0x3d, 0x91, 0xfd, 0xfa, 0xd7, 0x8d, 0xd7, 0xef,
+	0x75, 0xbf, 0x7e, 0x1f, 0x0d, 0xfa, 0xf8, 0xf4, 0xf8, 0xb1, 0xe7, 0x07, 0xf4, 0xe4, 0xd0, 0x9f,
+	0x78, 0xf6, 0x63, 0x1b, 0x9f, 0x39, 0x16, 0x96, 0x3f, 0x9b, 0xe3, 0xc0, 0xa7, 0x3e, 0xaa, 0x51,
+	0x7f, 0xec, 0x6f, 0x0a, 0x92, 0xf6, 0xe1, 0xb1, 0xef, 0x1f, 0xbb, 0xf8, 0x31, 0xef, 0x3a, 0x9c,
+	0x1c, 0x3d, 0xb6, 0x27, 0x81, 0x49, 0x1d, 0xdf, 0x13, 0x60, 0x6d, 0x3d, 0xdd, 0x7f, 0xe4, 0x60,
+	0xd7, 0x1e, 0x8e, 0x4c, 0x72, 0x2a, 0x11, 0x0f, 0xd3, 0x08, 0xea, 0x8c, 0x30, 0xa1, 0xe6, 0x68,
+	0x2c, 0x00, 0xfa, 0x3f, 0x28, 0x00, 0x2d, 0xdb, 0x36, 0xf0, 0xdb, 0x09, 0x26, 0x14, 0xfd, 0x0e,
+	0x94, 0xc4, 0xb7, 0x55, 0x65, 0x5d, 0xd9, 0xa8, 0x6d, 0xad, 0x6e, 0xc6, 0xf8, 0xd9, 0xec, 0xf0,
+	0x1f, 0x43, 0x42, 0xd0, 0x8f, 0xa0, 0xe1, 0xd8, 0x78, 0x34, 0xf6, 0x29, 0xf6, 0xac, 0x8b, 0xe1,
+	0x29, 0xbe, 0x50, 0x73, 0xeb, 0xca, 0x46, 0xd5, 0xa8, 0xc7, 0xc8, 0x2f, 0xf1, 0x05, 0xfa, 0x12,
+	0x3e, 0x30, 0x5d, 0xd7, 0x3f, 0x1f, 0xda, 0x93, 0xb1, 0xeb, 0x58, 0x26, 0xc5, 0x43, 0xd3, 0xb6,
+	0x03, 0x4c, 0x88, 0x9a, 0x5f, 0x57, 0x36, 0x2a, 0xc6, 0x1a, 0xef, 0xee, 0x84, 0xbd, 0x2d, 0xd1,
+	0xa9, 0x6f, 0x43, 0x8d, 0xf3, 0x46, 0xc6, 0xbe, 0x47, 0x30, 0xfa, 0x0a, 0x2a, 0x23, 0x4c, 0x4d,
+	0xdb, 0xa4, 0xa6, 0x64, 0xef, 0x5e, 0x82, 0xbd, 0xfd, 0xc3, 0x1f, 0xb0, 0x45, 0xf7, 0x24, 0xc4,
+	0x88, 0xc0, 0xfa, 0x73, 0x58, 0xed, 0x7a, 0x64, 0x12, 0x60, 0xb9, 0x80, 0x1b, 0x2c, 0x56, 0xff,
+	0x05, 0xdc, 0x4e, 0xce, 0x21, 0x99, 0xba, 0x96, 0xc4, 0x54, 0x28, 0x5b, 0x01, 0x36, 0x29, 0xb6,
+	0xb9, 0xa4, 0x2a, 0x46, 0xd8, 0xd4, 0xff, 0x57, 0x81, 0x95, 0xd7, 0x63, 0xdb, 0xa4, 0x37, 0xe2,
+	0x0e, 0x7d, 0x03, 0xb5, 0x09, 0x1f, 0xcd, 0x95, 0xcf, 0x27, 0xaf, 0x6d, 0x69, 0x9b, 0x42, 0xfb,
+	0x9b, 0xa1, 0xf6, 0x37, 0xb7, 0xd9, 0xfe, 0xd8, 0x33, 0xc9, 0xa9, 0x01, 0x02, 0xce, 0xfe, 0x67,
+	0xe9, 0x31, 0x9f, 0xa9, 0xc7, 0xdb, 0x50, 0x3c, 0xf2, 0x03, 0x0b, 0xab, 0x05, 0xce, 0xbc, 0x68,
+	0x2c, 0xd2, 0x6e, 0x71, 0x91, 0x76, 0x7b, 0x50, 0x0f, 0x57, 0xfc, 0xbe, 0x0a, 0xfe, 0x5b, 0x05,
+	0x60, 0x07, 0xd3, 0x50, 0x74, 0xf7, 0xa0, 0x2a, 0x46, 0x0c, 0x1d, 0x9b, 0x4f, 0x54, 0x35, 0x2a,
+	0x82, 0xd0, 0xb3, 0xd1, 0x5d, 0xa8, 0x10, 0x6a, 0xba, 0x78, 0xe8, 0x9f, 0x86, 0x4a, 0xe0, 0xed,
+	0xfd, 0x53, 0xf4, 0x11, 0xac, 0x9c, 0x7a, 0xfe, 0xb9, 0x37, 0x3c, 0xc3, 0x01, 0x71, 0x7c, 0x8f,
+	0x8b, 0xa1, 0x60, 0x2c, 0x73, 0xe2, 0x1b, 0x41, 0xe3, 0xd2, 0xf2, 0x2c, 0x77, 0x62, 0xe3, 0x21,
+	0xc1, 0x56, 0x80, 0x29, 0x91, 0xe2, 0xa8, 0x4b, 0xf2, 0x40, 0x50, 0xf5, 0xff, 0x51, 0xa0, 0xc6,
+	0x99, 0xba, 0xc9, 0x4e, 0x79, 0x06, 0x35, 0xd3, 0xf3, 0x7c, 0xca, 0x8f, 0x3b, 0x91, 0x0a, 0x55,
+	0x13, 0x23, 0x5a, 0xd3, 0x7e, 0x23, 0x0e, 0x66, 0x6a, 0xe2, 0x2b, 0x92, 0x87, 0x4b, 0x34, 0xd0,
+	0x57, 0x50, 0xb5, 0x4c, 0xeb, 0x04, 0xdb, 0x43, 0x93, 0xaa, 0x85, 0x39, 0x1b, 0xe4, 0x20, 0x34,
+	0x0f, 0x46, 0x45, 0x80, 0x5b, 0x14, 0x3d, 0x82, 0x65, 0xcf, 0xa7, 0xc3, 0x91, 0x6f, 0x3b, 0x47,
+	0x0e, 0xb6, 0xa5, 0x52, 0x6b, 0x9e, 0x4f, 0xf7, 0x24, 0x49, 0xff, 0x75, 0x11, 0x6a, 0xbb, 0x0e,
+	0x89, 0x14, 0x70, 0x1f, 0xaa, 0x64, 0x72, 0x48, 0xac, 0xc0, 0x39, 0x14, 0xab, 0xad, 0x18, 0x53,
+	0x02, 0x9b, 0xf0, 0x28, 0xf0, 0x47, 0x91, 0x94, 0x73, 0x5c, 0xca, 0x35, 0x46, 0x0b, 0x85, 0xbc,
+	0x9e, 0x5c, 0xbe, 0x58, 0x48, 0x62, 0x91, 0x3f, 0x83, 0xfb, 0xf8, 0x9d, 0x50, 0x83, 0x15, 0x60,
+	0x1b, 0x7b, 0xd4, 0x31, 0xdd, 0x61, 0x10, 0x0d, 0x11, 0x3a, 0xd1, 0x24, 0xa6, 0x1d, 0x41, 0x8c,
+	0x68, 0x86, 0x87, 0x50, 0x1b, 0x07, 0xf8, 0x6c, 0x28, 0x95, 0x22, 0x96, 0x05, 0x8c, 0x24, 0x74,
+	0x91, 0xd8, 0x29, 0xa5, 0xe4, 0x4e, 0x79, 0x0a, 0x25, 0x42, 0x4d, 0x8a, 0x89, 0x5a, 0x5e, 0xcf,
+	0x6f, 0xd4, 0xb7, 0xee, 0x27, 0x34, 0xd3, 0xf6, 0x3d, 0x0f, 0x5b, 0xec, 0x2b, 0x03, 0x06, 0x32,
+	0x24, 0x96, 0x7d, 0x31, 0xc0, 0x63, 0xd7, 0xbc, 0x18, 0xda, 0xbe, 0x87, 0xd5, 0x8a, 0xf8, 0xa2,
+	0x20, 0x75, 0x7c, 0x0f, 0xa3, 0xcf, 0xa1, 0x70, 0xe6, 0xe0, 0x73, 0xb5, 0xba, 0xae, 0x6c, 0xd4,
+	0xb7, 0x1e, 0x24, 0x26, 0x8d, 0xc9, 0x77, 0xf3, 0x8d, 0x83, 0xcf, 0x0d, 0x0e, 0xcd, 0xda, 0x8e,
+	0x90, 0xb5, 0x1d, 0xd1, 0x0b, 0x40, 0x27, 0xd8, 0x0c, 0xe8, 0x21, 0x36, 0xe9, 0xd0, 0xf1, 0x28,
+	0x0e, 0xce, 0x4c, 0x57, 0xad, 0xf1, 0x8d, 0x70, 0x77, 0x66, 0x23, 0x74, 0xe4, 0x4d, 0x63, 0xdc,
+	0x8a, 0x06, 0xf5, 0xe4, 0x18, 0xa6, 0xbf, 0x00, 0x93, 0xc9, 0x08, 0x0f, 0xa9, 0x7f, 0x8a, 0x3d,
+	0x75, 0x99, 0x9f, 0xb0, 0x9a, 0xa0, 0x1d, 0x30, 0x12, 0xfa, 0x31, 0x34, 0x43, 0xed, 0xbc, 0x9d,
+	0x38, 0x98, 0x58, 0xd8, 0x56, 0x57, 0x38, 0x5b, 0x0d, 0x49, 0xff, 0x23, 0x49, 0x46, 0x0f, 0x00,
+	0xa2, 0xc3, 0x4a, 0xd4, 0xfa, 0x7a, 0x7e, 0xa3, 0x6a, 0x54, 0xc3, 0xd3, 0x4a, 0x90, 0x06, 0x15,
+	0x82, 0x5d, 0x6c, 0x51, 0x3f, 0x50, 0x1b, 0xe2, 0x28, 0x87, 0x6d, 0x74, 0x07, 0x4a, 0x67, 0xd8,
+	0xb3, 0xfd, 0x40, 0x6d, 0xf2, 0x1e, 0xd9, 0x62, 0x07, 0x60, 0xe4, 0xdb, 0xd8, 0x55, 0x6f, 0x71,
+	0xb2, 0x68, 0x30, 0xe9, 0x93, 0x53, 0x67, 0x3c, 0x14, 0xf2, 0x56, 0x91, 0x90, 0x3e, 0x23, 0x19,
+	0x9c, 0xa2, 0xdf, 0x83, 0x02, 0x13, 0x2c, 0xaa, 0x40, 0x61, 0xfb, 0xf5, 0xee, 0x6e, 0x73, 0x09,
+	0x55, 0xa1, 0xf8, 0xbc, 0x35, 0xe8, 0xb5, 0x9b, 0x8a, 0xfe, 0xab, 0x22, 0x2c, 0x0b, 0x15, 0xc8,
+	0xe3, 0xbc, 0x05, 0x05, 0x7a, 0x31, 0x16, 0xdb, 0xbb, 0xbe, 0xf5, 0x61, 0x86, 0xae, 0x04, 0x70,
+	0xf3, 0xe0, 0x62, 0x8c, 0x0d, 0x8e, 0x8d, 0x99, 0x80, 0xdc, 0xe5, 0x26, 0xa0, 0x09, 0x79, 0x82,
+	0xdf, 0x4a, 0x1b, 0xc4, 0xfe, 0xa6, 0x8d, 0x42, 0xe1, 0x3a, 0x46, 0xe1, 0x1b, 0x28, 0x93, 0xc9,
+	0x21, 0xe7, 0xb8, 0xc8, 0x39, 0x7e, 0x34, 0x9f, 0xe3, 0x81, 0x00, 0x1a, 0xe1, 0x08, 0xf4, 0x34,
+	0x79, 0x54, 0x4a, 0xf3, 0x99, 0x8f, 0x9f, 0x9f, 0xc8, 0x0e, 0x95, 0xe7, 0xda, 0xa1, 0xca, 0xf5,
+	0xec, 0x50, 0x62, 0xdb, 0x55, 0x67, 0xb7, 0x9d, 0x0a, 0xe5, 0xd0, 0xa8, 0x00, 0x17, 0x5b, 0xd8,
+	0x64, 0x83, 0xf9, 0x0a, 0xc2, 0xee, 0x9a, 0xb0, 0x39, 0x8c, 0x26, 0x6d, 0x8e, 0x6e, 0x43, 0x81,
+	0xa9, 0x8a, 0xa9, 0xbf, 0xbf, 0xdf, 0xef, 0x0a, 0xf5, 0xb7, 0x3a, 0x9d, 0x6e, 0xa7, 0xa9, 0xa0,
+	0x1a, 0x94, 0x5f, 0xbf, 0xea, 0xb4, 0x0e, 0xba, 0x9d, 0x66, 0x8e, 0x35, 0x8c, 0xee, 0xde, 0xfe,
+	0x9b, 0x6e, 0xa7, 0x99, 0x47, 0x2b, 0x50, 0x6d, 0xf5, 0xfb, 0xfb, 0x07, 0xbc, 0xaf, 0x80, 0x1a,
+	0x50, 0x33, 0xba, 0xaf, 0x76, 0x5b, 0xdf, 0x0f, 0x3b, 0x6c, 0x92, 0x22, 0xeb, 0x7f, 0xd1, 0x6d,
+	0x19, 0x07, 0xcf, 0xbb, 0xad, 0x83, 0x66, 0x49, 0xdf, 0x81, 0xb2, 0x14, 0x2f, 0x9b, 0x66, 0xa7,
+	0xdb, 0xef, 0x1a, 0x2d, 0xb6, 0xd5, 0x1a, 0x50, 0x6b, 0x1b, 0xdd, 0x4e, 0xb7, 0x7f, 0xd0, 0x6b,
+	0xed, 0x0e, 0x9a, 0x0a, 0x1b, 0xb7, 0xdb, 0xdb, 0xee, 0xb6, 0xbf, 0x6f, 0xef, 0x76, 0x9b, 0x39,
+	0xd6, 0xbf, 0xd7, 0xea, 0xf5, 0x0f, 0xba, 0xfd, 0x56, 0xbf, 0xdd, 0x6d, 0xe6, 0xf5, 0x7f, 0x56,
+	0xe0, 0xd6, 0x6b, 0x79, 0x89, 0x7b, 0x17, 0xa1, 0xe5, 0x8d, 0x1f, 0x17, 0x25, 0x75, 0x5c, 0xde,
+	0xcb, 0x49, 0xf8, 0x16, 0x1a, 0xf2, 0x98, 0x52, 0x3c, 0x1a, 0xbb, 0x26, 0x15, 0xd7, 0xcb, 0x9c,
+	0x6d, 0x50, 0x17, 0xcd, 0x03, 0x09, 0xd5, 0xf7, 0x00, 0xc5, 0x79, 0x8d, 0xee, 0xfb, 0x32, 0xd3,
+	0x9e, 0x4b, 0x89, 0xaa, 0xac, 0xe7, 0x37, 0x6a, 0x29, 0x8b, 0x97, 0x18, 0x31, 0x71, 0xa9, 0x11,
+	0xa2, 0xf5, 0xbf, 0x53, 0xa0, 0x99, 0xee, 0x5d, 0x7c, 0xeb, 0xc7, 0x5d, 0x8b, 0xdc, 0x35, 0x5c,
+	0x0b, 0x84, 0xa0, 0x60, 0xf9, 0xb6, 0x58, 0x6c, 0xd1, 0xe0, 0xff, 0xd9, 0x36, 0x1b, 0x61, 0x42,
+	0xcc, 0x63, 0xe1, 0x09, 0x55, 0x8d, 0xb0, 0xa9, 0xff, 0x4a, 0x01, 0xd4, 0x76, 0x4d, 0x67, 0x94,
+	0xf4, 0x34, 0x2f, 0x73, 0x48, 0xfc, 0x73, 0x0f, 0x07, 0xac, 0x4f, 0xf8, 0xcf, 0x65, 0xde, 0xee,
+	0xd9, 0xe8, 0x67, 0x50, 0x77, 0xb1, 0x49, 0xf0, 0x30, 0x74, 0xfc, 0xd5, 0xfc, 0x65, 0xf6, 0x7a,
+	0x85, 0x0f, 0x08, 0x9b, 0xfa, 0x3b, 0x58, 0x4d, 0xf0, 0x23, 0x25, 0xbf, 0x01, 0x45, 0xfe, 0x0d,
+	0xe9, 0x8a, 0xa0, 0xa4, 0x2c, 0x58, 0x8f, 0x21, 0x00, 0x37, 0x16, 0x9c, 0xde, 0x87, 0xdb, 0x06,
+	0x16, 0xcc, 0xfc, 0x26, 0x64, 0xa1, 0xbf, 0x82, 0xb5, 0xd4, 0x7c, 0xef, 0xeb, 0x35, 0xfe, 0x39,
+	0x20, 0x03, 0x8f, 0xfd, 0x80, 0x8a, 0x5b, 0xfa, 0x2a, 0xfc, 0x6d, 0x71, 0x93, 0x46, 0x85, 0xfd,
+	0xbe, 0xec, 0xda, 0x17, 0x50, 0x76, 0x4b, 0x05, 0xd8, 0x24, 0x52, 0x79, 0x55, 0x43, 0xb6, 0xf4,
+	0xbf, 0x54, 0x60, 0x35, 0xf1, 0x7d, 0xb9, 0x9e, 0xdf, 0x15, 0xbe, 0xc5, 0x84, 0xc8, 0xd5, 0x3c,
+	0x58, 0xf0, 0x91, 0x09, 0x31, 0x24, 0xf8, 0xe6, 0x8a, 0xfa, 0x27, 0x05, 0xb4, 0xb6, 0x3f, 0x1a,
+	0x9b, 0x01, 0x6e, 0x79, 0xf6, 0xe0, 0xdc, 0x1c, 0x73, 0x0b, 0x70, 0x25, 0x79, 0x20, 0x28, 0x8c,
+	0x4d, 0x7a, 0x22, 0x75, 0xc5, 0xff, 0xa3, 0xc7, 0x50, 0xc1, 0xef, 0xc6, 0xd8, 0x62, 0x51, 0xce,
+	0x02, 0x13, 0x11, 0x81, 0xd0, 0x8f, 0xa1, 0x78, 0x66, 0xba, 0x13, 0xac, 0x16, 0xe6, 0xa3, 0x05,
+	0x42, 0x7f, 0x03, 0xf7, 0x32, 0x59, 0x7d, 0xdf, 0xad, 0xf0, 0x37, 0x0a, 0xa8, 0xdc, 0x33, 0x8c,
+	0x79, 0x8a, 0xe4, 0x4a, 0x12, 0x78, 0x06, 0xb5, 0xa9, 0xff, 0x99, 0xed, 0xa8, 0xc7, 0xa7, 0x8c,
+	0x83, 0xe3, 0xd7, 0x55, 0x3e, 0x71, 0x5d, 0xe9, 0x07, 0x70, 0x37, 0x83, 0x9d, 0xf7, 0x5d, 0xe5,
+	0x5f, 0x28, 0xd0, 0x1c, 0x84, 0x6e, 0x78, 0xb8, 0xba, 0x4d, 0x28, 0xb8, 0x0e, 0xa1, 0xaa, 0x92,
+	0xc1, 0x79, 0xcc, 0xe7, 0x7c, 0xb1, 0x64, 0x70, 0x1c, 0x73, 0x7d, 0x03, 0x4c, 0x2e, 0x3c, 0x2b,
+	0xba, 0x40, 0xe2, 0x23, 0x0c, 0xde, 0x35, 0x1d, 0x23, 0xb1, 0xcf, 0xab, 0xcc, 0xd4, 0x73, 0xa2,
+	0xde, 0x80, 0x95, 0x04, 0x4a, 0x7f, 0x0a, 0x8d, 0x9f, 0x9b, 0xd4, 0x3a, 0x69, 0xb9, 0x6e, 0xc8,
+	0x54, 0x3a, 0x44, 0x50, 0x66, 0x42, 0x04, 0xfd, 0x5f, 0x14, 0x68, 0x4e, 0x87, 0x49, 0xd1, 0xfc,
+	0x41, 0xc2, 0x29, 0xfb, 0x2c, 0xc1, 0x5a, 0x1a, 0xcc, 0x78, 0xf5, 0x27, 0x81, 0x85, 0x63, 0x0e,
+	0xda, 0x17, 0x29, 0x07, 0xed, 0xee, 0x5c, 0x27, 0x89, 0xad, 0x4d, 0x90, 0x75, 0x0d, 0x96, 0xe3,
+	0x53, 0x21, 0x80, 0x52, 0xa7, 0xfb, 0xa6, 0xd7, 0xee, 0x36, 0x97, 0x9e, 0x97, 0xa1, 0x88, 0xcf,
+	0xb0, 0x47, 0xf5, 0x01, 0xac, 0x0d, 0x30, 0x8d, 0xbb, 0x67, 0x72, 0xa9, 0x29, 0xa7, 0x4e, 0xb9,
+	0x86, 0x53, 0xa7, 0x6f, 0xc1, 0x9d, 0xf4, 0xa4, 0x52, 0x10, 0xb1, 0xad, 0xa5, 0x24, 0xb7, 0xd6,
+	0x1e, 0x34, 0xd8, 0x3a, 0x5e, 0x99, 0xc7, 0x71, 0x93, 0x37, 0x36, 0x8f, 0xf1, 0x90, 0x38, 0xbf,
+	0x14, 0xa2, 0x5b, 0x31, 0x2a, 0x8c, 0x30, 0x70, 0x7e, 0x89, 0x99, 0x7f, 0xce, 0x3b, 0x85, 0xd3,
+	0x25, 0x0e, 0x3a, 0x87, 0x73, 0x97, 0x4b, 0x77, 0xa0, 0x39, 0x9d, 0x4e, 0x7e, 0xfc, 0xa7, 0x50,
+	0x16, 0x9c, 0x87, 0xf7, 0x7a, 0xe6, 0x91, 0x0e, 0x31, 0xe8, 0x53, 0x68, 0x78, 0xf8, 0x1d, 0x1d,
+	0xce, 0x7c, 0x66, 0x85, 0x91, 0x5f, 0x45, 0x9f, 0xda, 0x82, 0x55, 0xf6, 0xa9, 0xf6, 0x89, 0xe3,
+	0xda, 0x01, 0xf6, 0x12, 0xdc, 0x07, 0xd8, 0xa3, 0xb1, 0xe3, 0x29, 0x08, 0x3d, 0x5b, 0xef, 0xc2,
+	0xed, 0xe4, 0x98, 0x1b, 0xb1, 0xa8, 0xff, 0xa3, 0x02, 0x2a, 0x9b, 0x27, 0x8c, 0x78, 0x07, 0x8e,
+	0x37, 0xbd, 0xd1, 0x9e, 0x40, 0x91, 0xb0, 0xb6, 0xaa, 0xcc, 0xf1, 0xa8, 0xa6, 0xde, 0xac, 0x00,
+	0x46, 0x71, 0x5e, 0xee, 0xbd, 0xe2, 0xbc, 0x7c, 0x66, 0xda, 0xe1, 0x4b, 0xf8, 0x60, 0x07, 0x53,
+	0xb1, 0x80, 0x17, 0x0e, 0xa1, 0x7e, 0x70, 0x71, 0x15, 0x43, 0xa6, 0x0f, 0x40, 0x9d, 0x1d, 0x17,
+	0x59, 0x9c, 0x12, 0xdf, 0xc5, 0xa1, 0xb0, 0x1e, 0x66, 0x08, 0x4b, 0x8e, 0xe9, 0x32, 0x9c, 0x21,
+	0xe1, 0xfa, 0xbf, 0x2a, 0x80, 0x66, 0xbb, 0x7f, 0xfb, 0xb1, 0xd3, 0xd7, 0x50, 0x8d, 0x32, 0x9d,
+	0x6a, 0xfe, 0x52, 0xb5, 0x4c, 0xc1, 0xfa, 0x4f, 0xe0, 0xf6, 0x00, 0x9b, 0x81, 0x75, 0x22, 0x66,
+	0x8c, 0x8e, 0xe9, 0x6d, 0x28, 0xbe, 0x9d, 0xe0, 0xe0, 0x42, 0xca, 0x4d, 0x34, 0xf4, 0x6d, 0x58,
+	0x4b, 0xa1, 0x6f, 0xb6, 0xbf, 0x5a, 0xd0, 0x68, 0xd9, 0xf6, 0x4e, 0xe0, 0x4f, 0xc6, 0x53, 0xbb,
+	0x5c, 0x3c, 0x66, 0xed, 0x4c, 0x8b, 0x20, 0xc6, 0x0b, 0xbc, 0x80, 0xe9, 0xcf, 0xa1, 0x39, 0x9d,
+	0x42, 0x72, 0x71, 0xdd, 0x39, 0x3a, 0xa1, 0x9b, 0xfe, 0x5e, 0x9c, 0x74, 0x61, 0x35, 0x31, 0xcb,
+	0x0d, 0x99, 0xf9, 0x09, 0x34, 0x76, 0x30, 0x4d, 0x70, 0x72, 0x17, 0x2a, 0xbc, 0x6f, 0xba, 0x7f,
+	0xcb, 0xbc, 0xdd, 0xb3, 0xd9, 0xf2, 0xa7, 0xe8, 0x1b, 0x7e, 0x71, 0x15, 0x6e, 0xb1, 0xdd, 0xc7,
+	0x69, 0xa1, 0xe2, 0xf5, 0x6d, 0x40, 0x71, 0xa2, 0x9c, 0xfa, 0x09, 0x94, 0xf8, 0x98, 0x50, 0xbd,
+	0xf3, 0xe7, 0x96, 0x38, 0xbd, 0xc7, 0xbc, 0xcd, 0x91, 0x7f, 0x86, 0xaf, 0xb8, 0xa2, 0xb8, 0x09,
+	0xcf, 0x25, 0x4d, 0xf8, 0x1a, 0xac, 0x26, 0xa6, 0x12, 0x3c, 0xe9, 0xc7, 0xf0, 0x41, 0xc4, 0xe9,
+	0x1e, 0x1e, 0x1d, 0xe2, 0x80, 0x5c, 0xe1, 0x33, 0xd7, 0xb7, 0x45, 0xfa, 0x0f, 0xa0, 0xce, 0x7e,
+	0xe8, 0x66, 0xb6, 0xff, 0x21, 0xd4, 0x46, 0x0e, 0x21, 0x8e, 0x77, 0xcc, 0xd3, 0x3f, 0x39, 0x9e,
+	0xfe, 0x01, 0x49, 0xea, 0xd9, 0x44, 0xc7, 0xb0, 0x22, 0xd6, 0xfa, 0x5b, 0x2d, 0x51, 0xe8, 0x4d,
+	0xa8, 0x87, 0x9f, 0x91, 0xd2, 0x3c, 0x81, 0xdb, 0xcc, 0xc1, 0x94, 0xd9, 0xea, 0xa9, 0x21, 0xf8,
+	0x14, 0x1a, 0x47, 0x4e, 0x40, 0xe8, 0x30, 0x6d, 0x4a, 0x57, 0x38, 0xb9, 0x13, 0x3a, 0x86, 0x1b,
+	0xd0, 0x24, 0xd8, 0xf2, 0x3d, 0x3b, 0x06, 0x94, 0xdf, 0x16, 0xf4, 0x10, 0xa9, 0xff, 0xb5, 0x02,
+	0x6b, 0xa9, 0x4f, 0x49, 0x61, 0x7e, 0x09, 0xcb, 0xf1, 0x6f, 0x2d, 0x5a, 0x71, 0x2d, 0xf6, 0x75,
+	0xf4, 0x35, 0xac, 0x24, 0xbe, 0xbd, 0xc8, 0x64, 0x2e, 0xc7, 0xb9, 0xd1, 0x7f, 0xc1, 0xc4, 0xed,
+	0x99, 0xa3, 0xab, 0x85, 0x43, 0x6b, 0x50, 0xf2, 0xf0, 0xf9, 0x74, 0x65, 0x45, 0x0f, 0x9f, 0x27,
+	0x77, 0x6e, 0xca, 0xaf, 0xfd, 0x7d, 0xa8, 0x87, 0xd3, 0xdf, 0x20, 0x2b, 0xae, 0xff, 0x07, 0x0f,
+	0xaf, 0x7d, 0x6f, 0x36, 0xa4, 0x14, 0xee, 0x57, 0x8c, 0x47, 0x41, 0x98, 0xcf, 0xe3, 0xe7, 0x50,
+	0xf5, 0xcf, 0x70, 0x10, 0x38, 0x36, 0x26, 0x8b, 0xc2, 0x94, 0x29, 0x2a, 0x9d, 0x3f, 0x29, 0x5c,
+	0x27, 0x7f, 0xc2, 0x6a, 0x50, 0x09, 0xce, 0x6f, 0xb2, 0xfc, 0xff, 0x2e, 0x43, 0x49, 0x6a, 0xf8,
+	0xa6, 0x31, 0x00, 0xaa, 0x43, 0x2e, 0x12, 0x45, 0xce, 0xe1, 0xba, 0x8a, 0xd7, 0xe2, 0xaa, 0x46,
+	0xd8, 0x64, 0x71, 0x2b, 0x35, 0x83, 0x63, 0x4c, 0x65, 0x92, 0x43, 0xb6, 0x58, 0x6e, 0x97, 0xf8,
+	0x47, 0xf4, 0xdc, 0x0c, 0x70, 0xe4, 0x9f, 0x17, 0x39, 0xa2, 0x11, 0xd2, 0xc3, 0x34, 0xfe, 0x17,
+	0x50, 0x66, 0x37, 0xab, 0x3f, 0xa1, 0x6a, 0xe9, 0xb2, 0xc4, 0x45, 0x88, 0x4c, 0x47, 0x54, 0xe5,
+	0xeb, 0x44, 0x54, 0x1b, 0x90, 0xa7, 0x2e, 0x91, 0x69, 0xc5, 0x3b, 0x89, 0x31, 0x07, 0x2e, 0x69,
+	0xfb, 0xde, 0x91, 0x73, 0x6c, 0x30, 0x08, 0xfa, 0x02, 0xaa, 0x9c, 0x07, 0xcb, 0x77, 0x89, 0x5a,
+	0xe5, 0x96, 0x6a, 0x2d, 0x81, 0x7f, 0x25, 0x7b, 0x8d, 0x29, 0x2e, 0xe9, 0x6a, 0x42, 0xd2, 0xd5,
+	0x64, 0x45, 0x0f, 0x33, 0x3c, 0xc1, 0x6a, 0x4d, 0xe4, 0xb1, 0x23, 0x02, 0xda, 0x81, 0xa6, 0xeb,
+	0x1c, 0x61, 0xeb, 0xc2, 0x72, 0xf1, 0x50, 0xc6, 0xf7, 0xcb, 0x9c, 0xcd, 0xfb, 0x29, 0x93, 0x2b,
+	0x41, 0x32, 0xbc, 0x6f, 0xb8, 0x49, 0x02, 0xfa, 0x0e, 0x6e, 0x59, 0x51, 0x0e, 0x20, 0x9c, 0x69,
+	0xe5, 0x2a, 0x99, 0x82, 0xa6, 0x95, 0xa2, 0xa0, 0xa7, 0x50, 0x71, 0x7d, 0x4b, 0x64, 0x96, 0xea,
+	0x19, 0x72, 0xde, 0xc1, 0xfe, 0xae, 0xec, 0x37, 0x22, 0x24, 0xf3, 0x06, 0x5d, 0xf3, 0x10, 0xbb,
+	0x44, 0x6d, 0xcc, 0xf5, 0x06, 0x37, 0x77, 0x39, 0xa2, 0xeb, 0xd1, 0xe0, 0xc2, 0x90, 0xf0, 0x69,
+	0xd6, 0xa9, 0x79, 0x59, 0xd6, 0xe9, 0x19, 0xd4, 0x46, 0xa6, 0xe3, 0x51, 0xec, 0x99, 0xcc, 0xb1,
+	0xbe, 0x95, 0xc1, 0xdb, 0xde, 0xb4, 0xdf, 0x88, 0x83, 0x59, 0x15, 0x8f, 0xe0, 0x80, 0x55, 0x83,
+	0xbc, 0x09, 0xbb, 0x9b, 0x78, 0xa6, 0xbf, 0x6a, 0x2c, 0x0b, 0x62, 0x9f, 0xd3, 0xa6, 0x25, 0x82,
+	0xd5, 0x78, 0x89, 0x60, 0x5a, 0x50, 0xb8, 0x9d, 0x28, 0x28, 0x3c, 0x85, 0xb2, 0x38, 0xca, 0xb6,
+	0xba, 0x76, 0xa9, 0x33, 0x19, 0x42, 0xb5, 0xdf, 0x83, 0x5a, 0x4c, 0x0a, 0x2c, 0x9f, 0xcf, 0xee,
+	0x1f, 0x61, 0x9f, 0xd8, 0x5f, 0xc6, 0x84, 0x48, 0x7c, 0x48, 0xcb, 0xc4, 0x1b, 0xcf, 0x72, 0x5f,
+	0x2b, 0x3a, 0x81, 0x5a, 0x6c, 0x7d, 0x2c, 0xab, 0x1b, 0x95, 0x51, 0x44, 0x39, 0x2d, 0x6a, 0xc7,
+	0xd2, 0x4b, 0xb9, 0x78, 0x7a, 0x69, 0x1a, 0x95, 0xe4, 0xaf, 0x18, 0x95, 0xe8, 0x2f, 0xa1, 0xc8,
+	0x95, 0x20, 0x6d, 0x84, 0x12, 0xd9, 0x88, 0x2d, 0x28, 0xe1, 0x77, 0x63, 0x27, 0xb8, 0x50, 0x73,
+	0x97, 0xce, 0x25, 0x91, 0xfa, 0x1e, 0xd4, 0x62, 0xbb, 0x87, 0x2d, 0xde, 0x35, 0x45, 0x92, 0x41,
+	0x31, 0xd8, 0x5f, 0x4e, 0xf1, 0x8e, 0xd5, 0x9c, 0xa4, 0x78, 0xc7, 0x6c, 0x95, 0xa6, 0x4b, 0x1d,
+	0x3a, 0x91, 0xe9, 0x56, 0xc5, 0x88, 0xda, 0xfa, 0xbf, 0x29, 0xd0, 0x4c, 0x6f, 0xe8, 0x69, 0x36,
+	0x4e, 0xb9, 0x49, 0x36, 0x2e, 0x29, 0xae, 0x1b, 0x47, 0x0c, 0x8c, 0xed, 0x80, 0xa7, 0xf1, 0x70,
+	0x20, 0x2d, 0x65, 0xd4, 0x66, 0x89, 0xea, 0x46, 0xea, 0x44, 0xa3, 0xcf, 0xa1, 0x38, 0x3e, 0x31,
+	0x49, 0xc8, 0xf5, 0xbd, 0xec, 0xe3, 0xff, 0x8a, 0x41, 0x0c, 0x81, 0xfc, 0xcd, 0x33, 0xad, 0xff,
+	0xbd, 0x02, 0x95, 0xd0, 0xc2, 0xb1, 0x14, 0x50, 0x2c, 0x1c, 0xd3, 0x32, 0xcd, 0x60, 0x3c, 0x14,
+	0xbb, 0x03, 0x25, 0x8b, 0x9b, 0x52, 0xce, 0xce, 0xb2, 0x21, 0x5b, 0x7a, 0x5b, 0x56, 0x50, 0x58,
+	0xb1, 0xa4, 0xff, 0xb2, 0xbf, 0xff, 0xf3, 0x7e, 0x73, 0x89, 0x95, 0x53, 0x76, 0xfa, 0x7b, 0x3d,
+	0x51, 0x43, 0xe9, 0x77, 0x0f, 0xda, 0xfb, 0xfd, 0xed, 0x66, 0x8e, 0x95, 0x37, 0x5e, 0x3d, 0x35,
+	0x5e, 0xf7, 0x0f, 0x7a, 0x7b, 0xdd, 0x66, 0x5e, 0xa0, 0xf6, 0x7b, 0xcd, 0x82, 0xfe, 0x6b, 0x05,
+	0x6a, 0x31, 0xfb, 0xce, 0x52, 0x8c, 0x13, 0x82, 0xc3, 0x6a, 0x06, 0xff, 0xcf, 0x44, 0x3e, 0x36,
+	0x09, 0x39, 0xf7, 0x83, 0xf0, 0x2a, 0x8b, 0xda, 0xe8, 0x2b, 0x80, 0x43, 0x93, 0x38, 0xd6, 0xd0,
+	0x9c, 0xd0, 0x13, 0x35, 0x9f, 0x71, 0x13, 0x3c, 0x67, 0xdd, 0xad, 0x09, 0x3d, 0x79, 0xb1, 0x64,
+	0x54, 0x0f, 0xc3, 0x06, 0xda, 0x84, 0x32, 0x21, 0x27, 0xdc, 0x47, 0xcc, 0x4a, 0x44, 0x0e, 0xc8,
+	0xc9, 0x4b, 0x7c, 0xc1, 0xd2, 0x3e, 0x84, 0xff, 0x43, 0x9f, 0x41, 0x51, 0x24, 0x2b, 0x8a, 0x19,
+	0xd6, 0x8c, 0x67, 0x2c, 0x5e, 0x2c, 0x19, 0x02, 0xf2, 0x7c, 0x19, 0x60, 0x7a, 0x4d, 0xe9, 0xdf,
+	0x40, 0x35, 0xe2, 0xe1, 0xba, 0xeb, 0xd3, 0x3b, 0x50, 0x12, 0xac, 0x64, 0x8e, 0xfc, 0x14, 0x1a,
+	0xe3, 0xc0, 0x39, 0x63, 0x4e, 0xca, 0x29, 0xbe, 0x18, 0x06, 0xf8, 0x28, 0xcc, 0xa5, 0x48, 0xf2,
+	0x4b, 0x7c, 0x61, 0xe0, 0x23, 0xfd, 0x63, 0x28, 0x72, 0x16, 0xd9, 0x95, 0xc6, 0xcd, 0x0e, 0x87,
+	0x4a, 0xdf, 0x89, 0x13, 0x18, 0xea, 0xcf, 0xa0, 0x1a, 0x5d, 0x9b, 0x5c, 0xeb, 0x66, 0x1b, 0x07,
+	0x34, 0xcc, 0x63, 0x8b, 0x16, 0x63, 0xc3, 0x62, 0x54, 0xb1, 0xf7, 0xf9, 0xff, 0xd0, 0xd6, 0x15,
+	0x13, 0xb6, 0x6e, 0xec, 0x9a, 0x8e, 0x27, 0x2b, 0xe9, 0xa2, 0xc1, 0x16, 0xea, 0x78, 0x04, 0x5b,
+	0x93, 0x20, 0xac, 0x12, 0x46, 0x6d, 0xfd, 0x3f, 0x15, 0xa8, 0xc5, 0x52, 0x5b, 0x8b, 0x3d, 0xd1,
+	0x6f, 0xa1, 0xc4, 0xb9, 0x16, 0x21, 0x44, 0x6d, 0xeb, 0xe3, 0x79, 0x09, 0xb4, 0xcd, 0x37, 0x1c,
+	0x26, 0x2f, 0x26, 0x31, 0x66, 0xbe, 0xc3, 0xca, 0x6c, 0x78, 0x6c, 0xc0, 0xb5, 0x6c, 0xf8, 0x5f,
+	0x29, 0x50, 0x8b, 0x05, 0x82, 0x33, 0x56, 0x15, 0x41, 0x81, 0x79, 0xc2, 0x61, 0xee, 0x9c, 0xfd,
+	0x4f, 0x94, 0xef, 0xf2, 0xa9, 0xf2, 0xdd, 0x03, 0x80, 0x11, 0x0f, 0xb6, 0x78, 0xa4, 0x54, 0x10,
+	0x0e, 0x86, 0xa0, 0xf4, 0xec, 0xc4, 0x1a, 0x8a, 0x49, 0xa7, 0xfb, 0xdf, 0x15, 0x58, 0x19, 0x78,
+	0xe6, 0x98, 0x9c, 0xf8, 0x54, 0x2c, 0xe3, 0xa7, 0x57, 0xf0, 0x3a, 0xa7, 0x09, 0x4e, 0xf4, 0xed,
+	0xb5, 0x1e, 0xa3, 0xbc, 0x58, 0x4a, 0x56, 0x9e, 0x9f, 0x84, 0x51, 0x78, 0x7e, 0x71, 0x14, 0xce,
+	0x4e, 0x0b, 0x07, 0xf2, 0xa4, 0x29, 0xe3, 0x53, 0x7f, 0x06, 0xf5, 0xa4, 0x23, 0x3b, 0x23, 0xc4,
+	0xf9, 0x41, 0xb2, 0x0a, 0x77, 0x76, 0x30, 0x6d, 0x9b, 0x63, 0xf3, 0xd0, 0x71, 0x1d, 0xea, 0x44,
+	0x11, 0x9c, 0xfe, 0x16, 0x3e, 0x98, 0xe9, 0x91, 0xee, 0xf8, 0xe7, 0x50, 0x39, 0xc2, 0x26, 0x9d,
+	0x04, 0x38, 0xcc, 0xc4, 0x26, 0x9d, 0xc2, 0x6d, 0xd9, 0x69, 0x44, 0x30, 0xe6, 0x6e, 0xc8, 0x6d,
+	0xc9, 0x5f, 0xdf, 0x85, 0x31, 0xec, 0xb2, 0x20, 0xf2, 0x60, 0x80, 0xe8, 0xff, 0x97, 0x83, 0x4a,
+	0x38, 0x96, 0x1d, 0x24, 0xe9, 0x3f, 0x89, 0xbb, 0x5c, 0xb6, 0xd8, 0x56, 0x72, 0x1d, 0xef, 0x94,
+	0xc8, 0x67, 0x49, 0xa2, 0xc1, 0x9f, 0x2d, 0xf8, 0x47, 0x2c, 0x04, 0x74, 0x31, 0x0d, 0xdf, 0xf4,
+	0x00, 0x23, 0x75, 0x38, 0x85, 0xf9, 0x9d, 0xdc, 0x69, 0x22, 0x27, 0xce, 0x58, 0x3e, 0x7b, 0x99,
+	0x12, 0xd2, 0x2f, 0x69, 0x8a, 0xb3, 0x2f, 0x69, 0x1e, 0x42, 0x6d, 0xfa, 0x6e, 0x90, 0xc8, 0xf3,
+	0x09, 0x47, 0x61, 0x14, 0x43, 0xd0, 0x87, 0x00, 0xd1, 0x23, 0x10, 0x22, 0x8f, 0x69, 0x8c, 0xc2,
+	0x74, 0x70, 0x22, 0xb2, 0x7b, 0xf2, 0x49, 0x4b, 0xd8, 0x64, 0xdb, 0xf9, 0x3c, 0x70, 0xa8, 0x79,
+	0xe8, 0x62, 0x5e, 0xae, 0xaf, 0x18, 0x51, 0x9b, 0xc9, 0x8d, 0xbf, 0x1f, 0x1b, 0x0a, 0x77, 0x29,
+	0x7c, 0xb6, 0xb2, 0xcc, 0x89, 0x22, 0x7f, 0x44, 0x84, 0x2f, 0x67, 0x05, 0x98, 0x0e, 0x4d, 0xcb,
+	0x62, 0x31, 0x4a, 0x4d, 0x80, 0x04, 0xb1, 0xc5, 0x69, 0x4c, 0x9e, 0x32, 0x17, 0xb3, 0x2c, 0xe4,
+	0x29, 0x5a, 0x9f, 0x7d, 0x07, 0x8d, 0x94, 0x13, 0x80, 0xee, 0x00, 0x6a, 0xef, 0xf7, 0xfb, 0xdd,
+	0xf6, 0x41, 0x6f, 0xbf, 0x3f, 0x9c, 0x5e, 0x52, 0x2b, 0x50, 0x95, 0x74, 0x5e, 0xed, 0x6f, 0xc2,
+	0x72, 0xa7, 0x37, 0x98, 0x52, 0x72, 0x9f, 0x7d, 0x07, 0xf5, 0xe4, 0xd5, 0x9c, 0xbc, 0xe4, 0x58,
+	0xf5, 0x7e, 0xbf, 0xbf, 0xdd, 0xdb, 0x79, 0x6d, 0xf4, 0xfa, 0x3b, 0x4d, 0x05, 0xd5, 0x01, 0x42,
+	0x02, 0x1b, 0xcf, 0x6a, 0x02, 0xdb, 0xad, 0xde, 0x2e, 0x7b, 0x31, 0xb0, 0xf5, 0x5f, 0xab, 0xb0,
+	0x22, 0xf6, 0xfd, 0x00, 0x07, 0xf2, 0xb5, 0x57, 0xbe, 0x65, 0xdb, 0xe8, 0x83, 0xe4, 0x91, 0x8a,
+	0x9e, 0x65, 0x6a, 0xea, 0x6c, 0x87, 0xcc, 0x52, 0x2c, 0xa1, 0xd7, 0xb0, 0x1c, 0x7f, 0x98, 0x88,
+	0xd6, 0x13, 0xd8, 0x8c, 0x77, 0x8f, 0xda, 0xa3, 0x05, 0x88, 0x68, 0xda, 0x36, 0x94, 0x84, 0x12,
+	0x90, 0x96, 0x51, 0x94, 0x0f, 0xa7, 0xba, 0x97, 0xd9, 0x17, 0x4d, 0xb2, 0x0f, 0x30, 0x2d, 0xd3,
+	0xa3, 0x0f, 0xe7, 0x56, 0xf7, 0xc5, 0x64, 0x0f, 0xe7, 0xf6, 0x47, 0x13, 0x3e, 0x83, 0xfc, 0x0e,
+	0xa6, 0x29, 0x41, 0x4d, 0x5f, 0xfe, 0x69, 0xea, 0x6c, 0x47, 0x34, 0xf6, 0x0f, 0xa1, 0xc0, 0xb2,
+	0x56, 0x68, 0x6e, 0x89, 0x4b, 0x9b, 0x5f, 0xed, 0xd1, 0x97, 0x9e, 0x28, 0xe8, 0x25, 0x54, 0xa3,
+	0xea, 0x19, 0x4a, 0xc6, 0x5a, 0xe9, 0xaa, 0xda, 0xc2, 0xa9, 0x36, 0x94, 0x27, 0x0a, 0x93, 0xaf,
+	0x48, 0x38, 0xa1, 0x74, 0x01, 0x2d, 0x96, 0xec, 0xd2, 0xee, 0x65, 0xf6, 0x45, 0x4b, 0xb2, 0xe1,
+	0xd6, 0x4c, 0x99, 0x10, 0x7d, 0x92, 0x1c, 0x33, 0xa7, 0xaa, 0xa9, 0x7d, 0x7a, 0x19, 0x2c, 0xfa,
+	0xca, 0x0f, 0xb0, 0x9a, 0x51, 0x74, 0x45, 0x3f, 0x4a, 0xb9, 0xdb, 0xf3, 0x2a, 0xc8, 0xda, 0xc6,
+	0xe5, 0xc0, 0xe8, 0x5b, 0x06, 0xd4, 0x62, 0xef, 0x15, 0x50, 0x72, 0x4b, 0xcc, 0xbe, 0xac, 0xd0,
+	0xd6, 0xe7, 0x03, 0xa2, 0x39, 0xff, 0x18, 0x56, 0x12, 0x2f, 0x07, 0xd0, 0xa3, 0x94, 0x54, 0x67,
+	0x5f, 0x29, 0x68, 0xfa, 0x22, 0x48, 0x9c, 0xdb, 0x58, 0x05, 0x3f, 0xc5, 0xed, 0xec, 0xdb, 0x02,
+	0x6d, 0x7d, 0x3e, 0x20, 0x9a, 0xb3, 0x07, 0x95, 0xb0, 0xa0, 0x86, 0xee, 0xcf, 0xec, 0xa2, 0x58,
+	0xd9, 0x4e, 0x7b, 0x30, 0xa7, 0x37, 0xbe, 0xf0, 0x44, 0x5e, 0x31, 0xb5, 0xf0, 0xac, 0xf4, 0xa6,
+	0xa6, 0x2f, 0x82, 0xc4, 0xad, 0x83, 0xc8, 0xe3, 0xcd, 0xec, 0xde, 0x58, 0xee, 0x50, 0xbb, 0x97,
+	0xd9, 0x97, 0xd4, 0x75, 0x94, 0x12, 0x9b, 0xd1, 0x75, 0x3a, 0xcd, 0xa7, 0xad, 0xcf, 0x07, 0xc4,
+	0xad, 0x61, 0xbc, 0xde, 0x97, 0xb2, 0x86, 0x19, 0xe5, 0x43, 0xed, 0xd1, 0x02, 0x44, 0x34, 0xed,
+	0xf7, 0x70, 0x6b, 0xa6, 0xfc, 0x97, 0x3a, 0x68, 0xf3, 0xca, 0x83, 0x97, 0x59, 0x15, 0x93, 0x17,
+	0x2e, 0x12, 0x45, 0x32, 0xf4, 0x71, 0xda, 0x8c, 0x65, 0x95, 0xf3, 0xb4, 0x4f, 0x2e, 0x41, 0x25,
+	0xf6, 0x41, 0xbc, 0x4a, 0x95, 0xde, 0x07, 0x19, 0xf5, 0x2e, 0x4d, 0x5f, 0x04, 0x89, 0x66, 0x7e,
+	0x09, 0x95, 0xb0, 0xac, 0x9e, 0xda, 0xac, 0xa9, 0x8a, 0xbe, 0xf6, 0x60, 0x4e, 0x6f, 0x4c, 0x12,
+	0x7f, 0x02, 0xf5, 0x64, 0x35, 0x1b, 0xa5, 0x99, 0xc8, 0xa8, 0x9f, 0x6b, 0x1f, 0x2d, 0xc4, 0x44,
+	0x9c, 0xfe, 0x29, 0x34, 0x52, 0x4e, 0x1f, 0xfa, 0x28, 0x2d, 0xbf, 0x0c, 0x67, 0x51, 0xfb, 0x78,
+	0x31, 0x28, 0x7e, 0x6c, 0xc3, 0xf2, 0x5b, 0x4a, 0x12, 0xa9, 0xc2, 0x9e, 0xf6, 0x60, 0x4e, 0x6f,
+	0xfc, 0x5c, 0xc4, 0xea, 0x67, 0x28, 0xeb, 0x5a, 0x4c, 0x4c, 0xb8, 0x3e, 0x1f, 0x10, 0x67, 0x2f,
+	0x2c, 0x8f, 0xa5, 0xd8, 0x4b, 0xd5, 0xd8, 0xb4, 0x07, 0x73, 0x7a, 0xe3, 0x97, 0xfa, 0xb4, 0x20,
+	0x86, 0x66, 0x8b, 0xb7, 0x89, 0xf2, 0x99, 0xf6, 0x70, 0x6e, 0x7f, 0xd2, 0x8a, 0x46, 0xe5, 0xac,
+	0x19, 0x2b, 0x9a, 0xae, 0x99, 0x69, 0xeb, 0xf3, 0x01, 0xd1, 0x9c, 0xa6, 0x78, 0x96, 0x10, 0x2f,
+	0x51, 0xa5, 0x4e, 0xd5, 0x9c, 0x52, 0x99, 0xf6, 0xc9, 0x25, 0xa8, 0xf0, 0x13, 0x87, 0x25, 0x9e,
+	0x61, 0xf9, 0xe2, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0xa4, 0x8e, 0xc0, 0x4a, 0xd7, 0x33, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DeviceServiceClient is the client API for DeviceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DeviceServiceClient interface {
+	// Add adds a device to the topology
+	Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddResponse, error)
+	// EnsureDevice adds a device if no device with the same ID exists and returns the stored device
+	// The device is created and read atomically, so concurrent callers all receive the same stored device and
+	// exactly one of them is told it created the device. An existing device is returned as stored, even if it
+	// differs from the requested device.
+	EnsureDevice(ctx context.Context, in *EnsureDeviceRequest, opts ...grpc.CallOption) (*EnsureDeviceResponse, error)
+	// Update updates a device
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// UpdateMany updates the masked fields of all devices matching a label selector
+	// Each device is updated independently, so the update may succeed for some devices and fail for others.
+	// Devices concurrently modified are reloaded and updated again, as with field mask updates.
+	UpdateMany(ctx context.Context, in *UpdateManyRequest, opts ...grpc.CallOption) (*UpdateManyResponse, error)
+	// Get gets a device by ID
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	// List gets a stream of device add/update/remove events
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (DeviceService_ListClient, error)
+	// Subscribe gets a stream of device events on which the client can request a resync
+	// The stream behaves as a List subscription opened with the first request on the stream. Resync requests
+	// force the server to resend the current devices without tearing down the stream.
+	Subscribe(ctx context.Context, opts ...grpc.CallOption) (DeviceService_SubscribeClient, error)
+	// Remove removes a device from the topology
+	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	// RotateCredentials replaces the credentials of a device without changing any other device fields
+	// Subscribers receive an UPDATED event with the CREDENTIALS subtype, which they may choose to exclude.
+	RotateCredentials(ctx context.Context, in *RotateCredentialsRequest, opts ...grpc.CallOption) (*RotateCredentialsResponse, error)
+	// CompareAndSwapField atomically sets a field of a device if the field has the expected value
+	// The request fails with FailedPrecondition if the current value of the field doesn't match the expected
+	// value, which gives controllers a building block for state machines, e.g. moving a device from the
+	// CONFIGURING to the CONFIGURED lifecycle phase only if it's still being configured.
+	CompareAndSwapField(ctx context.Context, in *CompareAndSwapFieldRequest, opts ...grpc.CallOption) (*CompareAndSwapFieldResponse, error)
+	// ClaimDevice claims a lease on a device for a controller, or renews the controller's lease
+	// Claiming a device whose lease is held by another controller and has not expired fails with
+	// FailedPrecondition.
+	ClaimDevice(ctx context.Context, in *ClaimDeviceRequest, opts ...grpc.CallOption) (*ClaimDeviceResponse, error)
+	// ReleaseDevice releases a controller's lease on a device
+	// Releasing a device whose lease is held by another controller fails with FailedPrecondition. Releasing a
+	// device that has no owner succeeds.
+	ReleaseDevice(ctx context.Context, in *ReleaseDeviceRequest, opts ...grpc.CallOption) (*ReleaseDeviceResponse, error)
+	// ReportState reports the connection state of a device observed by a southbound agent
+	// Only the connection status of the device is changed, so agents needn't read and write the whole device.
+	// The reporting agent is identified by the common name of its verified client certificate, or otherwise
+	// by its address, and is recorded in the status. The device is only written, producing an update event,
+	// if the state or reason changed.
+	ReportState(ctx context.Context, in *ReportStateRequest, opts ...grpc.CallOption) (*ReportStateResponse, error)
+	// ListPage gets a page of devices
+	ListPage(ctx context.Context, in *ListPageRequest, opts ...grpc.CallOption) (*ListPageResponse, error)
+	// SwapAddresses swaps the address and failover addresses of two devices
+	// Swapping addresses produces an UPDATED event for each device. If the second device can't be updated,
+	// the update of the first device is rolled back.
+	SwapAddresses(ctx context.Context, in *SwapAddressesRequest, opts ...grpc.CallOption) (*SwapAddressesResponse, error)
+	// Rename changes the ID of a device
+	// Renaming a device produces a REMOVED event for the old ID and an ADDED event for the new ID, and the
+	// parent_id of each of the device's children is updated to the new ID.
+	Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error)
+	// CloneDevice adds a copy of an existing device with a new ID and the given field overrides
+	// The server-managed fields of the source device, i.e. its metadata, owner, lifecycle, connection and
+	// maintenance status, and its hardware serial number, are not copied. The copy is validated and added
+	// like any other device, so the request fails with AlreadyExists if a device with the new ID exists.
+	CloneDevice(ctx context.Context, in *CloneDeviceRequest, opts ...grpc.CallOption) (*CloneDeviceResponse, error)
+	// ListChildren lists the direct children of a device
+	ListChildren(ctx context.Context, in *ListChildrenRequest, opts ...grpc.CallOption) (*ListChildrenResponse, error)
+	// ListModifiedSince gets a stream of the devices modified since a point in time
+	// The devices are streamed as ListResponses with the NONE event type. Removed devices are not streamed.
+	ListModifiedSince(ctx context.Context, in *ListModifiedSinceRequest, opts ...grpc.CallOption) (DeviceService_ListModifiedSinceClient, error)
+	// GetDeviceHistory gets the recently recorded events for a device
+	// The history log is opt-in and bounded by the number and age of the events recorded for each device.
+	// The history of a removed device is retained until it expires.
+	GetDeviceHistory(ctx context.Context, in *GetDeviceHistoryRequest, opts ...grpc.CallOption) (*GetDeviceHistoryResponse, error)
+	// SearchDevices lists the devices matching a free-text query
+	SearchDevices(ctx context.Context, in *SearchDevicesRequest, opts ...grpc.CallOption) (*SearchDevicesResponse, error)
+	// WatchAll gets a stream of events for all topology resources
+	// Devices are replayed as device events before events are streamed, as with a List subscription.
+	WatchAll(ctx context.Context, in *WatchAllRequest, opts ...grpc.CallOption) (DeviceService_WatchAllClient, error)
+	// SetAnnotations sets the annotations of a device without changing the device version
+	SetAnnotations(ctx context.Context, in *SetAnnotationsRequest, opts ...grpc.CallOption) (*SetAnnotationsResponse, error)
+	// GetCapabilities gets the optional features and device fields supported by the server
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+	// AddGroup adds a device group
+	AddGroup(ctx context.Context, in *AddGroupRequest, opts ...grpc.CallOption) (*AddGroupResponse, error)
+	// UpdateGroup replaces a device group
+	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*UpdateGroupResponse, error)
+	// GetGroup gets a device group
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupResponse, error)
+	// ListGroups lists the device groups
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// RemoveGroup removes a device group
+	RemoveGroup(ctx context.Context, in *RemoveGroupRequest, opts ...grpc.CallOption) (*RemoveGroupResponse, error)
+	// ListGroupMembers lists the devices that are members of a device group
+	// The group's selector is resolved against the current devices.
+	ListGroupMembers(ctx context.Context, in *ListGroupMembersRequest, opts ...grpc.CallOption) (*ListGroupMembersResponse, error)
+}
+
+type deviceServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDeviceServiceClient(cc *grpc.ClientConn) DeviceServiceClient {
+	return &deviceServiceClient{cc}
+}
+
+func (c *deviceServiceClient) Add(ctx context.Context, in *AddRequest, opts ...grpc.CallOption) (*AddResponse, error) {
+	out := new(AddResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Add", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) EnsureDevice(ctx context.Context, in *EnsureDeviceRequest, opts ...grpc.CallOption) (*EnsureDeviceResponse, error) {
+	out := new(EnsureDeviceResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/EnsureDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) UpdateMany(ctx context.Context, in *UpdateManyRequest, opts ...grpc.CallOption) (*UpdateManyResponse, error) {
+	out := new(UpdateManyResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/UpdateMany", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (DeviceService_ListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DeviceService_serviceDesc.Streams[0], "/topo.device.DeviceService/List", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deviceServiceListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeviceService_ListClient interface {
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type deviceServiceListClient struct {
+	grpc.ClientStream
+}
+
+func (x *deviceServiceListClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deviceServiceClient) Subscribe(ctx context.Context, opts ...grpc.CallOption) (DeviceService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DeviceService_serviceDesc.Streams[1], "/topo.device.DeviceService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deviceServiceSubscribeClient{stream}
+	return x, nil
+}
+
+type DeviceService_SubscribeClient interface {
+	Send(*SubscribeRequest) error
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type deviceServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *deviceServiceSubscribeClient) Send(m *SubscribeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deviceServiceSubscribeClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deviceServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error) {
+	out := new(RemoveResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) RotateCredentials(ctx context.Context, in *RotateCredentialsRequest, opts ...grpc.CallOption) (*RotateCredentialsResponse, error) {
+	out := new(RotateCredentialsResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/RotateCredentials", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) CompareAndSwapField(ctx context.Context, in *CompareAndSwapFieldRequest, opts ...grpc.CallOption) (*CompareAndSwapFieldResponse, error) {
+	out := new(CompareAndSwapFieldResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/CompareAndSwapField", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ClaimDevice(ctx context.Context, in *ClaimDeviceRequest, opts ...grpc.CallOption) (*ClaimDeviceResponse, error) {
+	out := new(ClaimDeviceResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ClaimDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ReleaseDevice(ctx context.Context, in *ReleaseDeviceRequest, opts ...grpc.CallOption) (*ReleaseDeviceResponse, error) {
+	out := new(ReleaseDeviceResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ReleaseDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ReportState(ctx context.Context, in *ReportStateRequest, opts ...grpc.CallOption) (*ReportStateResponse, error) {
+	out := new(ReportStateResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ReportState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListPage(ctx context.Context, in *ListPageRequest, opts ...grpc.CallOption) (*ListPageResponse, error) {
+	out := new(ListPageResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ListPage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) SwapAddresses(ctx context.Context, in *SwapAddressesRequest, opts ...grpc.CallOption) (*SwapAddressesResponse, error) {
+	out := new(SwapAddressesResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/SwapAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error) {
+	out := new(RenameResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Rename", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) CloneDevice(ctx context.Context, in *CloneDeviceRequest, opts ...grpc.CallOption) (*CloneDeviceResponse, error) {
+	out := new(CloneDeviceResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/CloneDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListChildren(ctx context.Context, in *ListChildrenRequest, opts ...grpc.CallOption) (*ListChildrenResponse, error) {
+	out := new(ListChildrenResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ListChildren", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListModifiedSince(ctx context.Context, in *ListModifiedSinceRequest, opts ...grpc.CallOption) (DeviceService_ListModifiedSinceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DeviceService_serviceDesc.Streams[2], "/topo.device.DeviceService/ListModifiedSince", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deviceServiceListModifiedSinceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeviceService_ListModifiedSinceClient interface {
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type deviceServiceListModifiedSinceClient struct {
+	grpc.ClientStream
+}
+
+func (x *deviceServiceListModifiedSinceClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deviceServiceClient) GetDeviceHistory(ctx context.Context, in *GetDeviceHistoryRequest, opts ...grpc.CallOption) (*GetDeviceHistoryResponse, error) {
+	out := new(GetDeviceHistoryResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/GetDeviceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) SearchDevices(ctx context.Context, in *SearchDevicesRequest, opts ...grpc.CallOption) (*SearchDevicesResponse, error) {
+	out := new(SearchDevicesResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/SearchDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) WatchAll(ctx context.Context, in *WatchAllRequest, opts ...grpc.CallOption) (DeviceService_WatchAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DeviceService_serviceDesc.Streams[3], "/topo.device.DeviceService/WatchAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deviceServiceWatchAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DeviceService_WatchAllClient interface {
+	Recv() (*WatchAllResponse, error)
+	grpc.ClientStream
+}
+
+type deviceServiceWatchAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *deviceServiceWatchAllClient) Recv() (*WatchAllResponse, error) {
+	m := new(WatchAllResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deviceServiceClient) SetAnnotations(ctx context.Context, in *SetAnnotationsRequest, opts ...grpc.CallOption) (*SetAnnotationsResponse, error) {
+	out := new(SetAnnotationsResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/SetAnnotations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/GetCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) AddGroup(ctx context.Context, in *AddGroupRequest, opts ...grpc.CallOption) (*AddGroupResponse, error) {
+	out := new(AddGroupResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/AddGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*UpdateGroupResponse, error) {
+	out := new(UpdateGroupResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/UpdateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupResponse, error) {
+	out := new(GetGroupResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/GetGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) RemoveGroup(ctx context.Context, in *RemoveGroupRequest, opts ...grpc.CallOption) (*RemoveGroupResponse, error) {
+	out := new(RemoveGroupResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/RemoveGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deviceServiceClient) ListGroupMembers(ctx context.Context, in *ListGroupMembersRequest, opts ...grpc.CallOption) (*ListGroupMembersResponse, error) {
+	out := new(ListGroupMembersResponse)
+	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/ListGroupMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeviceServiceServer is the server API for DeviceService service.
+type DeviceServiceServer interface {
+	// Add adds a device to the topology
+	Add(context.Context, *AddRequest) (*AddResponse, error)
+	// EnsureDevice adds a device if no device with the same ID exists and returns the stored device
+	// The device is created and read atomically, so concurrent callers all receive the same stored device and
+	// exactly one of them is told it created the device. An existing device is returned as stored, even if it
+	// differs from the requested device.
+	EnsureDevice(context.Context, *EnsureDeviceRequest) (*EnsureDeviceResponse, error)
+	// Update updates a device
+	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	// UpdateMany updates the masked fields of all devices matching a label selector
+	// Each device is updated independently, so the update may succeed for some devices and fail for others.
+	// Devices concurrently modified are reloaded and updated again, as with field mask updates.
+	UpdateMany(context.Context, *UpdateManyRequest) (*UpdateManyResponse, error)
+	// Get gets a device by ID
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	// List gets a stream of device add/update/remove events
+	List(*ListRequest, DeviceService_ListServer) error
+	// Subscribe gets a stream of device events on which the client can request a resync
+	// The stream behaves as a List subscription opened with the first request on the stream. Resync requests
+	// force the server to resend the current devices without tearing down the stream.
+	Subscribe(DeviceService_SubscribeServer) error
+	// Remove removes a device from the topology
+	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+	// RotateCredentials replaces the credentials of a device without changing any other device fields
+	// Subscribers receive an UPDATED event with the CREDENTIALS subtype, which they may choose to exclude.
+	RotateCredentials(context.Context, *RotateCredentialsRequest) (*RotateCredentialsResponse, error)
+	// CompareAndSwapField atomically sets a field of a device if the field has the expected value
+	// The request fails with FailedPrecondition if the current value of the field doesn't match the expected
+	// value, which gives controllers a building block for state machines, e.g. moving a device from the
+	// CONFIGURING to the CONFIGURED lifecycle phase only if it's still being configured.
+	CompareAndSwapField(context.Context, *CompareAndSwapFieldRequest) (*CompareAndSwapFieldResponse, error)
+	// ClaimDevice claims a lease on a device for a controller, or renews the controller's lease
+	// Claiming a device whose lease is held by another controller and has not expired fails with
+	// FailedPrecondition.
+	ClaimDevice(context.Context, *ClaimDeviceRequest) (*ClaimDeviceResponse, error)
+	// ReleaseDevice releases a controller's lease on a device
+	// Releasing a device whose lease is held by another controller fails with FailedPrecondition. Releasing a
+	// device that has no owner succeeds.
+	ReleaseDevice(context.Context, *ReleaseDeviceRequest) (*ReleaseDeviceResponse, error)
+	// ReportState reports the connection state of a device observed by a southbound agent
+	// Only the connection status of the device is changed, so agents needn't read and write the whole device.
+	// The reporting agent is identified by the common name of its verified client certificate, or otherwise
+	// by its address, and is recorded in the status. The device is only written, producing an update event,
+	// if the state or reason changed.
+	ReportState(context.Context, *ReportStateRequest) (*ReportStateResponse, error)
+	// ListPage gets a page of devices
+	ListPage(context.Context, *ListPageRequest) (*ListPageResponse, error)
+	// SwapAddresses swaps the address and failover addresses of two devices
+	// Swapping addresses produces an UPDATED event for each device. If the second device can't be updated,
+	// the update of the first device is rolled back.
+	SwapAddresses(context.Context, *SwapAddressesRequest) (*SwapAddressesResponse, error)
+	// Rename changes the ID of a device
+	// Renaming a device produces a REMOVED event for the old ID and an ADDED event for the new ID, and the
+	// parent_id of each of the device's children is updated to the new ID.
+	Rename(context.Context, *RenameRequest) (*RenameResponse, error)
+	// CloneDevice adds a copy of an existing device with a new ID and the given field overrides
+	// The server-managed fields of the source device, i.e. its metadata, owner, lifecycle, connection and
+	// maintenance status, and its hardware serial number, are not copied. The copy is validated and added
+	// like any other device, so the request fails with AlreadyExists if a device with the new ID exists.
+	CloneDevice(context.Context, *CloneDeviceRequest) (*CloneDeviceResponse, error)
+	// ListChildren lists the direct children of a device
+	ListChildren(context.Context, *ListChildrenRequest) (*ListChildrenResponse, error)
+	// ListModifiedSince gets a stream of the devices modified since a point in time
+	// The devices are streamed as ListResponses with the NONE event type. Removed devices are not streamed.
+	ListModifiedSince(*ListModifiedSinceRequest, DeviceService_ListModifiedSinceServer) error
+	// GetDeviceHistory gets the recently recorded events for a device
+	// The history log is opt-in and bounded by the number and age of the events recorded for each device.
+	// The history of a removed device is retained until it expires.
+	GetDeviceHistory(context.Context, *GetDeviceHistoryRequest) (*GetDeviceHistoryResponse, error)
+	// SearchDevices lists the devices matching a free-text query
+	SearchDevices(context.Context, *SearchDevicesRequest) (*SearchDevicesResponse, error)
+	// WatchAll gets a stream of events for all topology resources
+	// Devices are replayed as device events before events are streamed, as with a List subscription.
+	WatchAll(*WatchAllRequest, DeviceService_WatchAllServer) error
+	// SetAnnotations sets the annotations of a device without changing the device version
+	SetAnnotations(context.Context, *SetAnnotationsRequest) (*SetAnnotationsResponse, error)
+	// GetCapabilities gets the optional features and device fields supported by the server
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	// AddGroup adds a device group
+	AddGroup(context.Context, *AddGroupRequest) (*AddGroupResponse, error)
+	// UpdateGroup replaces a device group
+	UpdateGroup(context.Context, *UpdateGroupRequest) (*UpdateGroupResponse, error)
+	// GetGroup gets a device group
+	GetGroup(context.Context, *GetGroupRequest) (*GetGroupResponse, error)
+	// ListGroups lists the device groups
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	// RemoveGroup removes a device group
+	RemoveGroup(context.Context, *RemoveGroupRequest) (*RemoveGroupResponse, error)
+	// ListGroupMembers lists the devices that are members of a device group
+	// The group's selector is resolved against the current devices.
+	ListGroupMembers(context.Context, *ListGroupMembersRequest) (*ListGroupMembersResponse, error)
+}
+
+// UnimplementedDeviceServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedDeviceServiceServer struct {
+}
+
+func (*UnimplementedDeviceServiceServer) Add(ctx context.Context, req *AddRequest) (*AddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
+}
+func (*UnimplementedDeviceServiceServer) EnsureDevice(ctx context.Context, req *EnsureDeviceRequest) (*EnsureDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnsureDevice not implemented")
+}
+func (*UnimplementedDeviceServiceServer) Update(ctx context.Context, req *UpdateRequest) (*UpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedDeviceServiceServer) UpdateMany(ctx context.Context, req *UpdateManyRequest) (*UpdateManyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMany not implemented")
+}
+func (*UnimplementedDeviceServiceServer) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedDeviceServiceServer) List(req *ListRequest, srv DeviceService_ListServer) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedDeviceServiceServer) Subscribe(srv DeviceService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedDeviceServiceServer) Remove(ctx context.Context, req *RemoveRequest) (*RemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (*UnimplementedDeviceServiceServer) RotateCredentials(ctx context.Context, req *RotateCredentialsRequest) (*RotateCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateCredentials not implemented")
+}
+func (*UnimplementedDeviceServiceServer) CompareAndSwapField(ctx context.Context, req *CompareAndSwapFieldRequest) (*CompareAndSwapFieldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwapField not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ClaimDevice(ctx context.Context, req *ClaimDeviceRequest) (*ClaimDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimDevice not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ReleaseDevice(ctx context.Context, req *ReleaseDeviceRequest) (*ReleaseDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseDevice not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ReportState(ctx context.Context, req *ReportStateRequest) (*ReportStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportState not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ListPage(ctx context.Context, req *ListPageRequest) (*ListPageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPage not implemented")
+}
+func (*UnimplementedDeviceServiceServer) SwapAddresses(ctx context.Context, req *SwapAddressesRequest) (*SwapAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwapAddresses not implemented")
+}
+func (*UnimplementedDeviceServiceServer) Rename(ctx context.Context, req *RenameRequest) (*RenameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rename not implemented")
+}
+func (*UnimplementedDeviceServiceServer) CloneDevice(ctx context.Context, req *CloneDeviceRequest) (*CloneDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneDevice not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ListChildren(ctx context.Context, req *ListChildrenRequest) (*ListChildrenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChildren not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ListModifiedSince(req *ListModifiedSinceRequest, srv DeviceService_ListModifiedSinceServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListModifiedSince not implemented")
+}
+func (*UnimplementedDeviceServiceServer) GetDeviceHistory(ctx context.Context, req *GetDeviceHistoryRequest) (*GetDeviceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceHistory not implemented")
+}
+func (*UnimplementedDeviceServiceServer) SearchDevices(ctx context.Context, req *SearchDevicesRequest) (*SearchDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchDevices not implemented")
+}
+func (*UnimplementedDeviceServiceServer) WatchAll(req *WatchAllRequest, srv DeviceService_WatchAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAll not implemented")
+}
+func (*UnimplementedDeviceServiceServer) SetAnnotations(ctx context.Context, req *SetAnnotationsRequest) (*SetAnnotationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAnnotations not implemented")
+}
+func (*UnimplementedDeviceServiceServer) GetCapabilities(ctx context.Context, req *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (*UnimplementedDeviceServiceServer) AddGroup(ctx context.Context, req *AddGroupRequest) (*AddGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroup not implemented")
+}
+func (*UnimplementedDeviceServiceServer) UpdateGroup(ctx context.Context, req *UpdateGroupRequest) (*UpdateGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroup not implemented")
+}
+func (*UnimplementedDeviceServiceServer) GetGroup(ctx context.Context, req *GetGroupRequest) (*GetGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ListGroups(ctx context.Context, req *ListGroupsRequest) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (*UnimplementedDeviceServiceServer) RemoveGroup(ctx context.Context, req *RemoveGroupRequest) (*RemoveGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveGroup not implemented")
+}
+func (*UnimplementedDeviceServiceServer) ListGroupMembers(ctx context.Context, req *ListGroupMembersRequest) (*ListGroupMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroupMembers not implemented")
+}
+
+func RegisterDeviceServiceServer(s *grpc.Server, srv DeviceServiceServer) {
+	s.RegisterService(&_DeviceService_serviceDesc, srv)
+}
+
+func _DeviceService_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/Add",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).Add(ctx, req.(*AddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_EnsureDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnsureDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).EnsureDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/EnsureDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).EnsureDevice(ctx, req.(*EnsureDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_UpdateMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).UpdateMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/UpdateMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).UpdateMany(ctx, req.(*UpdateManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeviceServiceServer).List(m, &deviceServiceListServer{stream})
+}
+
+type DeviceService_ListServer interface {
+	Send(*ListResponse) error
+	grpc.ServerStream
+}
+
+type deviceServiceListServer struct {
+	grpc.ServerStream
+}
+
+func (x *deviceServiceListServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DeviceService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeviceServiceServer).Subscribe(&deviceServiceSubscribeServer{stream})
+}
+
+type DeviceService_SubscribeServer interface {
+	Send(*ListResponse) error
+	Recv() (*SubscribeRequest, error)
+	grpc.ServerStream
+}
+
+type deviceServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *deviceServiceSubscribeServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deviceServiceSubscribeServer) Recv() (*SubscribeRequest, error) {
+	m := new(SubscribeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DeviceService_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).Remove(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_RotateCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).RotateCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/RotateCredentials",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).RotateCredentials(ctx, req.(*RotateCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_CompareAndSwapField_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSwapFieldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).CompareAndSwapField(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/CompareAndSwapField",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).CompareAndSwapField(ctx, req.(*CompareAndSwapFieldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_ClaimDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ClaimDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ClaimDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ClaimDevice(ctx, req.(*ClaimDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_ReleaseDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ReleaseDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ReleaseDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ReleaseDevice(ctx, req.(*ReleaseDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_ReportState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ReportState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ReportState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ReportState(ctx, req.(*ReportStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_ListPage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ListPage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ListPage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ListPage(ctx, req.(*ListPageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeviceService_SwapAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).SwapAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/SwapAddresses",
 	}
-	return out, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).SwapAddresses(ctx, req.(*SwapAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *deviceServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
-	out := new(UpdateResponse)
-	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Update", in, out, opts...)
-	if err != nil {
+func _DeviceService_Rename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).Rename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/Rename",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).Rename(ctx, req.(*RenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *deviceServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
-	out := new(GetResponse)
-	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Get", in, out, opts...)
-	if err != nil {
+func _DeviceService_CloneDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneDeviceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).CloneDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/CloneDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).CloneDevice(ctx, req.(*CloneDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *deviceServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (DeviceService_ListClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_DeviceService_serviceDesc.Streams[0], "/topo.device.DeviceService/List", opts...)
-	if err != nil {
+func _DeviceService_ListChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChildrenRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &deviceServiceListClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ListChildren(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ListChildren",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ListChildren(ctx, req.(*ListChildrenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type DeviceService_ListClient interface {
-	Recv() (*ListResponse, error)
-	grpc.ClientStream
+func _DeviceService_ListModifiedSince_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListModifiedSinceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeviceServiceServer).ListModifiedSince(m, &deviceServiceListModifiedSinceServer{stream})
 }
 
-type deviceServiceListClient struct {
-	grpc.ClientStream
+type DeviceService_ListModifiedSinceServer interface {
+	Send(*ListResponse) error
+	grpc.ServerStream
 }
 
-func (x *deviceServiceListClient) Recv() (*ListResponse, error) {
-	m := new(ListResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type deviceServiceListModifiedSinceServer struct {
+	grpc.ServerStream
+}
+
+func (x *deviceServiceListModifiedSinceServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DeviceService_GetDeviceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceHistoryRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).GetDeviceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/GetDeviceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).GetDeviceHistory(ctx, req.(*GetDeviceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *deviceServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error) {
-	out := new(RemoveResponse)
-	err := c.cc.Invoke(ctx, "/topo.device.DeviceService/Remove", in, out, opts...)
-	if err != nil {
+func _DeviceService_SearchDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchDevicesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).SearchDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/SearchDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).SearchDevices(ctx, req.(*SearchDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// DeviceServiceServer is the server API for DeviceService service.
-type DeviceServiceServer interface {
-	// Add adds a device to the topology
-	Add(context.Context, *AddRequest) (*AddResponse, error)
-	// Update updates a device
-	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
-	// Get gets a device by ID
-	Get(context.Context, *GetRequest) (*GetResponse, error)
-	// List gets a stream of device add/update/remove events
-	List(*ListRequest, DeviceService_ListServer) error
-	// Remove removes a device from the topology
-	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+func _DeviceService_WatchAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeviceServiceServer).WatchAll(m, &deviceServiceWatchAllServer{stream})
 }
 
-// UnimplementedDeviceServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedDeviceServiceServer struct {
+type DeviceService_WatchAllServer interface {
+	Send(*WatchAllResponse) error
+	grpc.ServerStream
 }
 
-func (*UnimplementedDeviceServiceServer) Add(ctx context.Context, req *AddRequest) (*AddResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
-}
-func (*UnimplementedDeviceServiceServer) Update(ctx context.Context, req *UpdateRequest) (*UpdateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
-}
-func (*UnimplementedDeviceServiceServer) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
-}
-func (*UnimplementedDeviceServiceServer) List(req *ListRequest, srv DeviceService_ListServer) error {
-	return status.Errorf(codes.Unimplemented, "method List not implemented")
-}
-func (*UnimplementedDeviceServiceServer) Remove(ctx context.Context, req *RemoveRequest) (*RemoveResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+type deviceServiceWatchAllServer struct {
+	grpc.ServerStream
 }
 
-func RegisterDeviceServiceServer(s *grpc.Server, srv DeviceServiceServer) {
-	s.RegisterService(&_DeviceService_serviceDesc, srv)
+func (x *deviceServiceWatchAllServer) Send(m *WatchAllResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _DeviceService_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddRequest)
+func _DeviceService_SetAnnotations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAnnotationsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DeviceServiceServer).Add(ctx, in)
+		return srv.(DeviceServiceServer).SetAnnotations(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/topo.device.DeviceService/Add",
+		FullMethod: "/topo.device.DeviceService/SetAnnotations",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DeviceServiceServer).Add(ctx, req.(*AddRequest))
+		return srv.(DeviceServiceServer).SetAnnotations(ctx, req.(*SetAnnotationsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DeviceService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateRequest)
+func _DeviceService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DeviceServiceServer).Update(ctx, in)
+		return srv.(DeviceServiceServer).GetCapabilities(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/topo.device.DeviceService/Update",
+		FullMethod: "/topo.device.DeviceService/GetCapabilities",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DeviceServiceServer).Update(ctx, req.(*UpdateRequest))
+		return srv.(DeviceServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DeviceService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRequest)
+func _DeviceService_AddGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddGroupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DeviceServiceServer).Get(ctx, in)
+		return srv.(DeviceServiceServer).AddGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/topo.device.DeviceService/Get",
+		FullMethod: "/topo.device.DeviceService/AddGroup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DeviceServiceServer).Get(ctx, req.(*GetRequest))
+		return srv.(DeviceServiceServer).AddGroup(ctx, req.(*AddGroupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DeviceService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _DeviceService_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(DeviceServiceServer).List(m, &deviceServiceListServer{stream})
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/UpdateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type DeviceService_ListServer interface {
-	Send(*ListResponse) error
-	grpc.ServerStream
+func _DeviceService_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type deviceServiceListServer struct {
-	grpc.ServerStream
+func _DeviceService_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *deviceServiceListServer) Send(m *ListResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _DeviceService_RemoveGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceServiceServer).RemoveGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/topo.device.DeviceService/RemoveGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceServiceServer).RemoveGroup(ctx, req.(*RemoveGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _DeviceService_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveRequest)
+func _DeviceService_ListGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupMembersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DeviceServiceServer).Remove(ctx, in)
+		return srv.(DeviceServiceServer).ListGroupMembers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/topo.device.DeviceService/Remove",
+		FullMethod: "/topo.device.DeviceService/ListGroupMembers",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DeviceServiceServer).Remove(ctx, req.(*RemoveRequest))
+		return srv.(DeviceServiceServer).ListGroupMembers(ctx, req.(*ListGroupMembersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1066,10 +6541,18 @@ var _DeviceService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Add",
 			Handler:    _DeviceService_Add_Handler,
 		},
+		{
+			MethodName: "EnsureDevice",
+			Handler:    _DeviceService_EnsureDevice_Handler,
+		},
 		{
 			MethodName: "Update",
 			Handler:    _DeviceService_Update_Handler,
 		},
+		{
+			MethodName: "UpdateMany",
+			Handler:    _DeviceService_UpdateMany_Handler,
+		},
 		{
 			MethodName: "Get",
 			Handler:    _DeviceService_Get_Handler,
@@ -1078,6 +6561,86 @@ var _DeviceService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Remove",
 			Handler:    _DeviceService_Remove_Handler,
 		},
+		{
+			MethodName: "RotateCredentials",
+			Handler:    _DeviceService_RotateCredentials_Handler,
+		},
+		{
+			MethodName: "CompareAndSwapField",
+			Handler:    _DeviceService_CompareAndSwapField_Handler,
+		},
+		{
+			MethodName: "ClaimDevice",
+			Handler:    _DeviceService_ClaimDevice_Handler,
+		},
+		{
+			MethodName: "ReleaseDevice",
+			Handler:    _DeviceService_ReleaseDevice_Handler,
+		},
+		{
+			MethodName: "ReportState",
+			Handler:    _DeviceService_ReportState_Handler,
+		},
+		{
+			MethodName: "ListPage",
+			Handler:    _DeviceService_ListPage_Handler,
+		},
+		{
+			MethodName: "SwapAddresses",
+			Handler:    _DeviceService_SwapAddresses_Handler,
+		},
+		{
+			MethodName: "Rename",
+			Handler:    _DeviceService_Rename_Handler,
+		},
+		{
+			MethodName: "CloneDevice",
+			Handler:    _DeviceService_CloneDevice_Handler,
+		},
+		{
+			MethodName: "ListChildren",
+			Handler:    _DeviceService_ListChildren_Handler,
+		},
+		{
+			MethodName: "GetDeviceHistory",
+			Handler:    _DeviceService_GetDeviceHistory_Handler,
+		},
+		{
+			MethodName: "SearchDevices",
+			Handler:    _DeviceService_SearchDevices_Handler,
+		},
+		{
+			MethodName: "SetAnnotations",
+			Handler:    _DeviceService_SetAnnotations_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _DeviceService_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "AddGroup",
+			Handler:    _DeviceService_AddGroup_Handler,
+		},
+		{
+			MethodName: "UpdateGroup",
+			Handler:    _DeviceService_UpdateGroup_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _DeviceService_GetGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _DeviceService_ListGroups_Handler,
+		},
+		{
+			MethodName: "RemoveGroup",
+			Handler:    _DeviceService_RemoveGroup_Handler,
+		},
+		{
+			MethodName: "ListGroupMembers",
+			Handler:    _DeviceService_ListGroupMembers_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1085,6 +6648,22 @@ var _DeviceService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _DeviceService_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _DeviceService_Subscribe_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListModifiedSince",
+			Handler:       _DeviceService_ListModifiedSince_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchAll",
+			Handler:       _DeviceService_WatchAll_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/northbound/device/device.proto",
 }
@@ -0,0 +1,113 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"testing"
+)
+
+func newHashedDevice() *Device {
+	return &Device{
+		Id:              "device-1",
+		Address:         "device-1:5150",
+		Target:          "device-1",
+		SoftwareVersion: "1.0.0",
+		Credentials: &Credentials{
+			User:     "admin",
+			Password: "secret",
+		},
+	}
+}
+
+func TestHashLabelOrder(t *testing.T) {
+	keys := make([]string, 32)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("label-%d", i)
+	}
+
+	// The labels are inserted in opposite orders
+	forward := newHashedDevice()
+	forward.Labels = make(map[string]string)
+	for _, key := range keys {
+		forward.Labels[key] = key
+	}
+	reverse := newHashedDevice()
+	reverse.Labels = make(map[string]string)
+	for i := len(keys) - 1; i >= 0; i-- {
+		reverse.Labels[keys[i]] = keys[i]
+	}
+
+	if forward.Hash() != reverse.Hash() {
+		t.Error("devices with the same labels have different hashes")
+	}
+}
+
+func TestHashIgnoresStoreFields(t *testing.T) {
+	device := newHashedDevice()
+	hash := device.Hash()
+
+	tests := []struct {
+		name   string
+		update func(*Device)
+		same   bool
+	}{
+		{
+			name: "metadata",
+			update: func(device *Device) {
+				device.Metadata = &ObjectMetadata{Id: device.Id, Version: 10}
+			},
+			same: true,
+		},
+		{
+			name: "updated",
+			update: func(device *Device) {
+				device.Updated = &timestamp.Timestamp{Seconds: 1571184000}
+			},
+			same: true,
+		},
+		{
+			name: "address",
+			update: func(device *Device) {
+				device.Address = "device-1:5151"
+			},
+			same: false,
+		},
+		{
+			name: "labels",
+			update: func(device *Device) {
+				device.Labels = map[string]string{"rack": "1"}
+			},
+			same: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := newHashedDevice()
+			test.update(updated)
+			if same := updated.Hash() == hash; same != test.same {
+				t.Errorf("expected hash equality %t, got %t", test.same, same)
+			}
+		})
+	}
+
+	// Hash must not modify the device
+	device.Metadata = &ObjectMetadata{Id: device.Id, Version: 10}
+	device.Hash()
+	if device.Metadata == nil || device.Metadata.Version != 10 {
+		t.Error("Hash modified the device metadata")
+	}
+}
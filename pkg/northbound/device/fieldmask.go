@@ -0,0 +1,149 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"reflect"
+	"strings"
+)
+
+// immutableFields is the set of top-level device fields that cannot be updated with a field mask
+var immutableFields = map[string]bool{
+	"metadata": true,
+	"id":       true,
+	"owner":    true,
+}
+
+// validateFieldMask returns an error if any path in the given mask is not a known, mutable device field
+func validateFieldMask(mask *field_mask.FieldMask) error {
+	for _, path := range mask.Paths {
+		names := strings.Split(path, ".")
+		if immutableFields[names[0]] {
+			return fmt.Errorf("field %s cannot be updated", path)
+		}
+		t := reflect.TypeOf(Device{})
+		for i, name := range names {
+			field, ok := lookupField(t, name)
+			if !ok {
+				return fmt.Errorf("unknown field %s", path)
+			}
+			if i < len(names)-1 {
+				if field.Type.Kind() != reflect.Ptr || field.Type.Elem().Kind() != reflect.Struct {
+					return fmt.Errorf("field %s is not a message", strings.Join(names[:i+1], "."))
+				}
+				t = field.Type.Elem()
+			}
+		}
+	}
+	return nil
+}
+
+// hasPath returns whether the given mask includes the given top-level field
+func hasPath(mask *field_mask.FieldMask, field string) bool {
+	for _, path := range mask.Paths {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFieldMask copies the fields in the given mask from the source device to the destination device
+// The mask must have been validated with validateFieldMask.
+func applyFieldMask(dst *Device, src *Device, mask *field_mask.FieldMask) {
+	for _, path := range mask.Paths {
+		applyFieldPath(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem(), strings.Split(path, "."))
+	}
+}
+
+// applyFieldPath copies the value at the given path from the source struct to the destination struct
+func applyFieldPath(dst reflect.Value, src reflect.Value, names []string) {
+	field, _ := lookupField(dst.Type(), names[0])
+	dstField := dst.FieldByIndex(field.Index)
+	var srcField reflect.Value
+	if src.IsValid() {
+		srcField = src.FieldByIndex(field.Index)
+	}
+
+	if len(names) == 1 {
+		if srcField.IsValid() {
+			if message, ok := srcField.Interface().(proto.Message); ok && !srcField.IsNil() {
+				dstField.Set(reflect.ValueOf(proto.Clone(message)))
+			} else {
+				dstField.Set(srcField)
+			}
+		} else {
+			dstField.Set(reflect.Zero(dstField.Type()))
+		}
+		return
+	}
+
+	if dstField.IsNil() {
+		dstField.Set(reflect.New(dstField.Type().Elem()))
+	}
+	var srcElem reflect.Value
+	if srcField.IsValid() && !srcField.IsNil() {
+		srcElem = srcField.Elem()
+	}
+	applyFieldPath(dstField.Elem(), srcElem, names[1:])
+}
+
+// fieldEqual returns whether the given devices have equal values at the given path
+// The path must have been validated with validateFieldMask. A nil device or unset parent message is treated
+// as an unset field.
+func fieldEqual(a *Device, b *Device, path string) bool {
+	names := strings.Split(path, ".")
+	va, vb := fieldValue(a, names), fieldValue(b, names)
+	if message, ok := va.Interface().(proto.Message); ok {
+		return proto.Equal(message, vb.Interface().(proto.Message))
+	}
+	// Unset and empty repeated and map fields are equivalent
+	if kind := va.Kind(); (kind == reflect.Slice || kind == reflect.Map) && va.Len() == 0 && vb.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(va.Interface(), vb.Interface())
+}
+
+// fieldValue returns the value of the field at the given path of the given device, or the zero value of the
+// field if the device or a parent message is nil
+func fieldValue(device *Device, names []string) reflect.Value {
+	t := reflect.TypeOf(Device{})
+	v := reflect.ValueOf(device)
+	for _, name := range names {
+		field, _ := lookupField(t, name)
+		if !v.IsNil() {
+			v = v.Elem().FieldByIndex(field.Index)
+		} else {
+			v = reflect.Zero(field.Type)
+		}
+		if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			t = field.Type.Elem()
+		}
+	}
+	return v
+}
+
+// lookupField looks up a struct field by its protobuf field name
+func lookupField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for _, prop := range proto.GetProperties(t).Prop {
+		if prop.OrigName == name {
+			return t.FieldByName(prop.Name)
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,476 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/onosproject/onos-topo/pkg/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	devicesPath      = "/v1/devices"
+	capabilitiesPath = "/v1/capabilities"
+)
+
+// RegisterHTTP registers the device HTTP/JSON gateway handlers with the given mux
+// The gateway is written by hand rather than generated by grpc-gateway: the plugin isn't available in the
+// protoc-go image that compiles the protos, its runtime isn't a dependency of the module, and its generated
+// handlers wrap streamed responses in result objects and don't serve ETags. Requests are dispatched through
+// the server's interceptors, so they're handled exactly as gRPC requests.
+//
+// Endpoints:
+//
+//	GET    /v1/devices                   List, streamed as newline-delimited JSON ListResponses
+//	GET    /v1/devices/{id}              Get, with the device hash as the ETag; If-None-Match may return 304
+//	GET    /v1/devices/{id}/children     ListChildren
+//	PUT    /v1/devices/{id}/annotations  SetAnnotations from the Annotations in the request body
+//	POST   /v1/devices                   Add the Device in the request body
+//	PUT    /v1/devices/{id}              Update from the UpdateRequest in the request body
+//	DELETE /v1/devices/{id}              Remove
+//	GET    /v1/capabilities              GetCapabilities
+//
+// Query parameters of GET /v1/devices, which set the ListRequest field of the same name:
+//
+//	subscribe=true          keep the stream open and stream changes
+//	from_version=N          resume a subscription from the given version
+//	prev_device=true        include the previous value of updated devices
+//	replay_done=true        mark the end of the replay
+//	skip_replay=true        stream only changes made after the subscription is opened
+//	view=BASIC              omit credentials, TLS and protocols
+//	heartbeat_interval=30s  send heartbeats at the given interval
+//	resume_token=T          resume an interrupted snapshot
+//	exclude_quiesced=true   exclude quiesced devices
+//	state=S                 stream only devices in the given connection state; repeatable
+//	device_id=ID            stream only the given device; repeatable
+//	selector=S              stream only devices matching the label selector, e.g. pod=a,role=leaf
+//	vendor=V                stream only devices of the given vendor
+//	model=M                 stream only devices of the given model
+//	modified_since=T        call ListModifiedSince with the RFC 3339 time; only view and include_secrets apply
+//
+// Query parameters of both GET /v1/devices and GET /v1/devices/{id}:
+//
+//	stale_ok=true         serve cached devices if the store is unavailable
+//	include_secrets=true  include secrets if the client is authorized, by default by a verified client certificate
+func (s Service) RegisterHTTP(mux *http.ServeMux, interceptor grpc.UnaryServerInterceptor, streamInterceptor grpc.StreamServerInterceptor) {
+	gateway := &gateway{
+		server:            s.newServer(),
+		interceptor:       interceptor,
+		streamInterceptor: streamInterceptor,
+	}
+	mux.HandleFunc(devicesPath, gateway.handleDevices)
+	mux.HandleFunc(devicesPath+"/", gateway.handleDevice)
+	mux.HandleFunc(capabilitiesPath, gateway.handleCapabilities)
+}
+
+// gateway translates HTTP/JSON requests into device service requests
+// Requests are dispatched through the service's generated method handlers with the server's interceptors, so
+// they're rate limited, given deadlines, logged and measured exactly as the equivalent gRPC requests.
+type gateway struct {
+	server            *Server
+	interceptor       grpc.UnaryServerInterceptor
+	streamInterceptor grpc.StreamServerInterceptor
+}
+
+// invoke calls the given unary method of the device service with the given request
+func (g *gateway) invoke(r *http.Request, method string, request proto.Message) (proto.Message, error) {
+	for _, desc := range _DeviceService_serviceDesc.Methods {
+		if desc.MethodName != method {
+			continue
+		}
+		response, err := desc.Handler(g.server, requestContext(r), func(in interface{}) error {
+			proto.Merge(in.(proto.Message), request)
+			return nil
+		}, g.interceptor)
+		if err != nil {
+			return nil, err
+		}
+		return response.(proto.Message), nil
+	}
+	return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// invokeStream calls the given server streaming method of the device service with the given request, sending
+// the responses to the given stream
+func (g *gateway) invokeStream(method string, request proto.Message, stream *httpListStream) error {
+	for _, desc := range _DeviceService_serviceDesc.Streams {
+		if desc.StreamName != method {
+			continue
+		}
+		stream.request = request
+		if g.streamInterceptor == nil {
+			return desc.Handler(g.server, stream)
+		}
+		info := &grpc.StreamServerInfo{
+			FullMethod:     "/" + _DeviceService_serviceDesc.ServiceName + "/" + method,
+			IsServerStream: true,
+		}
+		return g.streamInterceptor(g.server, stream, info, desc.Handler)
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+}
+
+// requestContext returns the context of the given HTTP request carrying the client's address and TLS state as
+// the gRPC peer and its trace context as incoming metadata
+// Interceptors and authorizers then see HTTP clients as they see gRPC clients, e.g. a client that presents a
+// verified client certificate to the gateway is authorized to access secrets.
+func requestContext(r *http.Request) context.Context {
+	p := &peer.Peer{
+		Addr: httpAddr(r.RemoteAddr),
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+	ctx := peer.NewContext(r.Context(), p)
+	if traceparent := r.Header.Get(trace.TraceparentHeader); traceparent != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(trace.TraceparentHeader, traceparent))
+	}
+	return ctx
+}
+
+// httpAddr is the address of an HTTP client
+type httpAddr string
+
+func (a httpAddr) Network() string {
+	return "tcp"
+}
+
+func (a httpAddr) String() string {
+	return string(a)
+}
+
+var marshaler = &jsonpb.Marshaler{OrigName: true}
+
+var unmarshaler = &jsonpb.Unmarshaler{}
+
+func (g *gateway) handleDevices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		g.list(w, r)
+	case http.MethodPost:
+		request := &AddRequest{
+			Device: &Device{},
+		}
+		if err := unmarshaler.Unmarshal(r.Body, request.Device); err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		response, err := g.invoke(r, "Add", request)
+		writeResponse(w, response, err)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *gateway) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	response, err := g.invoke(r, "GetCapabilities", &GetCapabilitiesRequest{})
+	writeResponse(w, response, err)
+}
+
+func (g *gateway) handleDevice(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, devicesPath+"/"), "/")
+	id := path[0]
+	if id == "" || len(path) > 2 || (len(path) == 2 && path[1] != "children" && path[1] != "annotations") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if len(path) == 2 && path[1] == "annotations" {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		request := &SetAnnotationsRequest{
+			Annotations: &Annotations{},
+		}
+		if err := unmarshaler.Unmarshal(r.Body, request.Annotations); err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		request.Annotations.DeviceId = id
+		response, err := g.invoke(r, "SetAnnotations", request)
+		writeResponse(w, response, err)
+		return
+	}
+
+	if len(path) == 2 {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		response, err := g.invoke(r, "ListChildren", &ListChildrenRequest{
+			ParentId: id,
+		})
+		writeResponse(w, response, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		response, err := g.invoke(r, "Get", &GetRequest{
+			DeviceId:       id,
+			StaleOk:        r.URL.Query().Get("stale_ok") == "true",
+			IncludeSecrets: r.URL.Query().Get("include_secrets") == "true",
+		})
+		if err == nil {
+			etag := formatETag(response.(*GetResponse).Device.Hash())
+			w.Header().Set("ETag", etag)
+			if matchETag(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		writeResponse(w, response, err)
+	case http.MethodPut:
+		request := &UpdateRequest{}
+		if err := unmarshaler.Unmarshal(r.Body, request); err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		if request.Device == nil {
+			request.Device = &Device{}
+		}
+		request.Device.Id = id
+		response, err := g.invoke(r, "Update", request)
+		writeResponse(w, response, err)
+	case http.MethodDelete:
+		response, err := g.invoke(r, "Remove", &RemoveRequest{
+			Device: &Device{
+				Id: id,
+			},
+		})
+		writeResponse(w, response, err)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list streams devices to the client as newline-delimited JSON using chunked transfer encoding
+func (g *gateway) list(w http.ResponseWriter, r *http.Request) {
+	request := &ListRequest{
+		Subscribe:       r.URL.Query().Get("subscribe") == "true",
+		PrevDevice:      r.URL.Query().Get("prev_device") == "true",
+		StaleOk:         r.URL.Query().Get("stale_ok") == "true",
+		ReplayDone:      r.URL.Query().Get("replay_done") == "true",
+		IncludeSecrets:  r.URL.Query().Get("include_secrets") == "true",
+		ResumeToken:     r.URL.Query().Get("resume_token"),
+		ExcludeQuiesced: r.URL.Query().Get("exclude_quiesced") == "true",
+		SkipReplay:      r.URL.Query().Get("skip_replay") == "true",
+		DeviceIds:       r.URL.Query()["device_id"],
+		Selector:        r.URL.Query().Get("selector"),
+		Vendor:          r.URL.Query().Get("vendor"),
+		Model:           r.URL.Query().Get("model"),
+	}
+	for _, state := range r.URL.Query()["state"] {
+		value, ok := ConnectionState_value[state]
+		if !ok {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid state"))
+			return
+		}
+		request.States = append(request.States, ConnectionState(value))
+	}
+	if view := r.URL.Query().Get("view"); view != "" {
+		value, ok := ListRequest_View_value[view]
+		if !ok {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid view"))
+			return
+		}
+		request.View = ListRequest_View(value)
+	}
+	if fromVersion := r.URL.Query().Get("from_version"); fromVersion != "" {
+		version, err := strconv.ParseUint(fromVersion, 10, 64)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid from_version"))
+			return
+		}
+		request.FromVersion = version
+	}
+	if heartbeatInterval := r.URL.Query().Get("heartbeat_interval"); heartbeatInterval != "" {
+		interval, err := time.ParseDuration(heartbeatInterval)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "invalid heartbeat_interval"))
+			return
+		}
+		request.HeartbeatInterval = ptypes.DurationProto(interval)
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	stream := &httpListStream{
+		ctx:    requestContext(r),
+		writer: w,
+	}
+	if modifiedSince := r.URL.Query().Get("modified_since"); modifiedSince != "" {
+		g.listModifiedSince(w, modifiedSince, request, stream)
+		return
+	}
+	if err := g.invokeStream("List", request, stream); err != nil && !stream.sent {
+		writeError(w, err)
+	}
+}
+
+// listModifiedSince streams the devices modified since the given RFC 3339 time to the client
+// Only the view and include_secrets parameters of the list request apply to devices listed by update time.
+func (g *gateway) listModifiedSince(w http.ResponseWriter, modifiedSince string, request *ListRequest, stream *httpListStream) {
+	since, err := time.Parse(time.RFC3339Nano, modifiedSince)
+	if err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, "invalid modified_since"))
+		return
+	}
+	timestamp, err := ptypes.TimestampProto(since)
+	if err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, "invalid modified_since"))
+		return
+	}
+	err = g.invokeStream("ListModifiedSince", &ListModifiedSinceRequest{
+		Since:          timestamp,
+		View:           request.View,
+		IncludeSecrets: request.IncludeSecrets,
+	}, stream)
+	if err != nil && !stream.sent {
+		writeError(w, err)
+	}
+}
+
+// httpListStream is a server stream that receives a single request and writes responses to an HTTP response
+type httpListStream struct {
+	ctx     context.Context
+	writer  http.ResponseWriter
+	request proto.Message
+	sent    bool
+}
+
+func (s *httpListStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *httpListStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *httpListStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *httpListStream) SetTrailer(metadata.MD) {}
+
+func (s *httpListStream) RecvMsg(m interface{}) error {
+	if s.request == nil {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.request)
+	s.request = nil
+	return nil
+}
+
+func (s *httpListStream) SendMsg(m interface{}) error {
+	if err := marshaler.Marshal(s.writer, m.(proto.Message)); err != nil {
+		return err
+	}
+	if _, err := s.writer.Write([]byte("\n")); err != nil {
+		return err
+	}
+	if flusher, ok := s.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	s.sent = true
+	return nil
+}
+
+// writeResponse writes the given response or error to the HTTP response
+func writeResponse(w http.ResponseWriter, response proto.Message, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = marshaler.Marshal(w, response)
+}
+
+// formatETag returns the ETag for the given device hash
+// The hash changes only when the content of the device changes, so writes that don't change the device
+// don't invalidate clients' cached copies.
+func formatETag(hash [sha256.Size]byte) string {
+	return strconv.Quote(hex.EncodeToString(hash[:]))
+}
+
+// matchETag returns whether the given If-None-Match header matches the given ETag
+// Weak comparison is used, as required for If-None-Match, so weak ETags match on their opaque value.
+func matchETag(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError writes the given error to the HTTP response with the status matching the error's gRPC code
+func writeError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFromCode(st.Code()))
+	_ = marshaler.Marshal(w, st.Proto())
+}
+
+// httpStatusFromCode returns the HTTP status for the given gRPC code
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return http.StatusRequestTimeout
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
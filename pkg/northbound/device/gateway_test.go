@@ -0,0 +1,131 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/onosproject/onos-topo/pkg/northbound"
+	"google.golang.org/grpc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMatchETag(t *testing.T) {
+	etag := formatETag(newHashedDevice().Hash())
+	tests := []struct {
+		name   string
+		header string
+		match  bool
+	}{
+		{name: "empty", header: "", match: false},
+		{name: "exact", header: etag, match: true},
+		{name: "weak", header: "W/" + etag, match: true},
+		{name: "list", header: `"other", ` + etag, match: true},
+		{name: "any", header: "*", match: true},
+		{name: "other", header: `"other"`, match: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if match := matchETag(test.header, etag); match != test.match {
+				t.Errorf("expected match %t, got %t", test.match, match)
+			}
+		})
+	}
+}
+
+// newTestGateway returns an HTTP handler serving the gateway of a service backed by a local store through the
+// given interceptors
+func newTestGateway(t *testing.T, interceptor grpc.UnaryServerInterceptor, streamInterceptor grpc.StreamServerInterceptor) http.Handler {
+	t.Helper()
+	service, err := NewService(WithStore(NewLocalStore()), WithLogger(NewNopLogger()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	service.(*Service).RegisterHTTP(mux, interceptor, streamInterceptor)
+	return mux
+}
+
+func TestGatewayInterceptors(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	record := func(method string) {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, method)
+	}
+	gateway := newTestGateway(t, func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		record(info.FullMethod)
+		return handler(ctx, req)
+	}, func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		record(info.FullMethod)
+		return handler(srv, ss)
+	})
+
+	device := `{"id": "device-1", "address": "device-1:5150", "target": "device-1", "software_version": "1.0.0"}`
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		rpc    string
+	}{
+		{method: http.MethodPost, path: "/v1/devices", body: device, status: http.StatusOK, rpc: "Add"},
+		{method: http.MethodGet, path: "/v1/devices/device-1", status: http.StatusOK, rpc: "Get"},
+		{method: http.MethodGet, path: "/v1/devices", status: http.StatusOK, rpc: "List"},
+		{method: http.MethodGet, path: "/v1/devices?modified_since=2019-01-01T00:00:00Z", status: http.StatusOK, rpc: "ListModifiedSince"},
+		{method: http.MethodGet, path: "/v1/devices/device-1/children", status: http.StatusOK, rpc: "ListChildren"},
+		{method: http.MethodPut, path: "/v1/devices/device-1/annotations", body: `{"values": {"key": "value"}}`, status: http.StatusOK, rpc: "SetAnnotations"},
+		{method: http.MethodPut, path: "/v1/devices/device-1", body: `{"device": {"target": "target"}, "update_mask": {"paths": ["target"]}}`, status: http.StatusOK, rpc: "Update"},
+		{method: http.MethodDelete, path: "/v1/devices/device-1", status: http.StatusOK, rpc: "Remove"},
+		{method: http.MethodGet, path: "/v1/capabilities", status: http.StatusOK, rpc: "GetCapabilities"},
+	}
+	for _, test := range tests {
+		t.Run(test.rpc, func(t *testing.T) {
+			mu.Lock()
+			methods = nil
+			mu.Unlock()
+			response := httptest.NewRecorder()
+			gateway.ServeHTTP(response, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			if response.Code != test.status {
+				t.Fatalf("expected status %d, got %d: %s", test.status, response.Code, response.Body)
+			}
+			expected := "/topo.device.DeviceService/" + test.rpc
+			if len(methods) != 1 || methods[0] != expected {
+				t.Errorf("expected the request to be intercepted as %s, got %v", expected, methods)
+			}
+		})
+	}
+}
+
+func TestGatewayRateLimit(t *testing.T) {
+	limiter := northbound.NewRateLimiter(northbound.WithMethodRateLimit("/topo.device.DeviceService/Add", 0.001, 1))
+	gateway := newTestGateway(t, limiter.UnaryInterceptor(), nil)
+	add := func(id string) int {
+		body := `{"id": "` + id + `", "address": "` + id + `:5150", "target": "` + id + `", "software_version": "1.0.0"}`
+		response := httptest.NewRecorder()
+		gateway.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/v1/devices", strings.NewReader(body)))
+		return response.Code
+	}
+	if code := add("device-1"); code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	if code := add("device-2"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+}
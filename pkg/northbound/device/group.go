@@ -0,0 +1,192 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+)
+
+// AddGroup adds a device group
+func (s *Server) AddGroup(ctx context.Context, request *AddGroupRequest) (*AddGroupResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	group := request.Group
+	if err := s.validateGroup(group); err != nil {
+		return nil, err
+	}
+	if err := s.deviceStore.CreateGroup(ctx, group); err == ErrAlreadyExists {
+		return nil, status.Errorf(codes.AlreadyExists, "group %s already exists", group.Id)
+	} else if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Added device group", OperationField("add-group"), Field{Key: "group", Value: group.Id}, VersionField(group.Version))
+	return &AddGroupResponse{
+		Group: group,
+	}, nil
+}
+
+// UpdateGroup replaces the name, selector and members of a device group
+func (s *Server) UpdateGroup(ctx context.Context, request *UpdateGroupRequest) (*UpdateGroupResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	group := request.Group
+	if err := s.validateGroup(group); err != nil {
+		return nil, err
+	}
+
+	// Groups are updated with the stored version if the client did not request a specific version, so
+	// groups that are concurrently removed are not recreated
+	stored, err := s.deviceStore.LoadGroup(ctx, group.Id)
+	if err != nil {
+		return nil, err
+	} else if stored == nil {
+		return nil, groupNotFound(group.Id)
+	}
+	if group.Version == 0 {
+		group.Version = stored.Version
+	}
+	if err := s.deviceStore.StoreGroup(ctx, group); err == ErrConflict {
+		return nil, status.Error(codes.Aborted, "group version has changed")
+	} else if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Updated device group", OperationField("update-group"), Field{Key: "group", Value: group.Id}, VersionField(group.Version))
+	return &UpdateGroupResponse{
+		Group: group,
+	}, nil
+}
+
+// GetGroup gets a device group
+func (s *Server) GetGroup(ctx context.Context, request *GetGroupRequest) (*GetGroupResponse, error) {
+	group, err := s.deviceStore.LoadGroup(ctx, request.GroupId)
+	if err != nil {
+		return nil, err
+	} else if group == nil {
+		return nil, groupNotFound(request.GroupId)
+	}
+	return &GetGroupResponse{
+		Group: group,
+	}, nil
+}
+
+// ListGroups lists the device groups in order of their IDs
+func (s *Server) ListGroups(ctx context.Context, request *ListGroupsRequest) (*ListGroupsResponse, error) {
+	groups, err := s.deviceStore.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Id < groups[j].Id
+	})
+	return &ListGroupsResponse{
+		Groups: groups,
+	}, nil
+}
+
+// RemoveGroup removes a device group
+func (s *Server) RemoveGroup(ctx context.Context, request *RemoveGroupRequest) (*RemoveGroupResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.GroupId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no group specified")
+	}
+	err := s.deviceStore.DeleteGroup(ctx, &DeviceGroup{Id: request.GroupId, Version: request.Version})
+	if err == ErrNotFound {
+		return nil, groupNotFound(request.GroupId)
+	} else if err == ErrConflict {
+		return nil, status.Error(codes.Aborted, "group version has changed")
+	} else if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Removed device group", OperationField("remove-group"), Field{Key: "group", Value: request.GroupId})
+	return &RemoveGroupResponse{}, nil
+}
+
+// ListGroupMembers lists the devices that are members of a device group
+// The explicit members are loaded individually, and the group's selector is resolved against the current
+// devices.
+func (s *Server) ListGroupMembers(ctx context.Context, request *ListGroupMembersRequest) (*ListGroupMembersResponse, error) {
+	group, err := s.deviceStore.LoadGroup(ctx, request.GroupId)
+	if err != nil {
+		return nil, err
+	} else if group == nil {
+		return nil, groupNotFound(request.GroupId)
+	}
+	selector, err := ParseSelector(group.Selector)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	members := make(map[string]*Device)
+	if !selector.Empty() {
+		selected, err := s.selectDevices(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range selected {
+			members[device.Id] = device
+		}
+	}
+	for _, id := range group.MemberIds {
+		if _, ok := members[id]; ok {
+			continue
+		}
+		device, err := s.deviceStore.Load(ctx, id)
+		if err != nil {
+			return nil, err
+		} else if device != nil {
+			members[id] = device
+		}
+	}
+
+	response := &ListGroupMembersResponse{
+		Devices: make([]*Device, 0, len(members)),
+	}
+	for _, device := range members {
+		response.Devices = append(response.Devices, presentDevice(device, request.View, false))
+	}
+	sortDevices(response.Devices)
+	for _, id := range group.MemberIds {
+		if _, ok := members[id]; !ok {
+			response.MissingIds = append(response.MissingIds, id)
+		}
+	}
+	return response, nil
+}
+
+// validateGroup returns an error if the given group has no ID, an invalid ID, or an invalid selector
+func (s *Server) validateGroup(group *DeviceGroup) error {
+	if group == nil || group.Id == "" {
+		return status.Error(codes.InvalidArgument, "no group specified")
+	}
+	if err := s.validateID(group.Id); err != nil {
+		return err
+	}
+	if _, err := ParseSelector(group.Selector); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// groupNotFound returns a NotFound error for the group with the given ID
+func groupNotFound(id string) error {
+	return status.Errorf(codes.NotFound, "group %s not found", id)
+}
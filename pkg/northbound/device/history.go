@@ -0,0 +1,265 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"github.com/atomix/atomix-go-client/pkg/client/session"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/onosproject/onos-topo/pkg/util"
+	"time"
+)
+
+// historyRetries is the number of times an event is appended to a device's history if the history is
+// concurrently modified
+const historyRetries = 3
+
+// newHistoryLog returns a new historyLog backed by an Atomix map
+// The log is shared by all replicas of the service. Each replica records the events it observes, and events
+// already recorded by another replica are ignored.
+func newHistoryLog(maxEvents int, maxAge time.Duration, logger Logger) (*historyLog, error) {
+	client, err := util.GetSharedAtomixClient()
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := client.GetGroup(context.Background(), util.GetAtomixRaftGroup())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := group.GetMap(context.Background(), "device-history", session.WithTimeout(30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return &historyLog{
+		events:    events,
+		maxEvents: maxEvents,
+		maxAge:    maxAge,
+		logger:    logger,
+	}, nil
+}
+
+// historyLog records the most recent events for each device
+// The history of each device is stored as a GetDeviceHistoryResponse keyed by the device ID.
+type historyLog struct {
+	events    map_.Map
+	maxEvents int
+	maxAge    time.Duration
+	logger    Logger
+}
+
+// historyWatchOptions are the options with which the history log watches the store
+// Each event is recorded with round trips to the history map, so the log is evicted rather than blocking the
+// delivery of events to other watchers if it falls behind, and watches the store again.
+var historyWatchOptions = []WatchOption{WithReplay(false), WithOverflowPolicy(OverflowEvict)}
+
+// record records the events of the given store until the store's watch is closed
+// If the log falls behind and its watch is evicted, the events dropped until the store is watched again are
+// not recorded.
+func (h *historyLog) record(store Store) error {
+	ch := make(chan *Event)
+	if err := store.Watch(ch, historyWatchOptions...); err != nil {
+		return err
+	}
+	go h.process(store, ch)
+	return nil
+}
+
+// process records the events received from the given channel
+func (h *historyLog) process(store Store, ch <-chan *Event) {
+	for ch != nil {
+		evicted := false
+		for event := range ch {
+			if event.Type == EventEvicted {
+				evicted = true
+				continue
+			}
+			// Devices replayed when the watch is opened do not represent changes
+			if event.Type == EventNone || event.Type == EventAnnotated {
+				continue
+			}
+			if err := h.append(context.Background(), event); err != nil {
+				h.logger.Warn("Failed to record device event", DeviceIDField(event.Device.Id), OperationField("history"), VersionField(event.Device.GetMetadata().GetVersion()), ErrorField(err))
+			}
+		}
+		ch = nil
+		if evicted {
+			h.logger.Warn("Device history watch evicted, events were not recorded", OperationField("history"))
+			next := make(chan *Event)
+			if err := store.Watch(next, historyWatchOptions...); err != nil {
+				h.logger.Error("Failed to watch devices", OperationField("history"), ErrorField(err))
+			} else {
+				ch = next
+			}
+		}
+	}
+}
+
+// append appends the given event to the history of the event's device
+func (h *historyLog) append(ctx context.Context, event *Event) error {
+	timestamp, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	recorded := &DeviceHistoryEvent{
+		Type:      eventResponseType(event.Type),
+		Device:    event.Device,
+		Timestamp: timestamp,
+	}
+
+	for i := 0; ; i++ {
+		kv, err := h.events.Get(ctx, event.Device.Id)
+		if err != nil {
+			return err
+		}
+
+		history := &GetDeviceHistoryResponse{}
+		if kv != nil {
+			if err := proto.Unmarshal(kv.Value, history); err != nil {
+				return err
+			}
+		}
+		if containsEvent(history.Events, recorded) {
+			return nil
+		}
+		history.Events = h.trim(append(history.Events, recorded))
+
+		bytes, err := proto.Marshal(history)
+		if err != nil {
+			return err
+		}
+		if kv == nil {
+			_, err = h.events.Put(ctx, event.Device.Id, bytes)
+		} else {
+			_, err = h.events.Put(ctx, event.Device.Id, bytes, map_.WithVersion(kv.Version))
+		}
+		if err == nil || i == historyRetries-1 {
+			return err
+		}
+	}
+}
+
+// load loads the unexpired history of the given device
+func (h *historyLog) load(ctx context.Context, deviceID string) ([]*DeviceHistoryEvent, error) {
+	kv, err := h.events.Get(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	} else if kv == nil {
+		return []*DeviceHistoryEvent{}, nil
+	}
+
+	history := &GetDeviceHistoryResponse{}
+	if err := proto.Unmarshal(kv.Value, history); err != nil {
+		return nil, err
+	}
+	return h.trim(history.Events), nil
+}
+
+// compact removes the events that were recorded more than the given duration ago
+// Events are never removed before they have expired, and no events are removed if the age of events is
+// not bounded. Returns the number of events removed.
+func (h *historyLog) compact(ctx context.Context, olderThan time.Duration) (uint64, error) {
+	if h.maxAge == 0 {
+		return 0, nil
+	} else if olderThan < h.maxAge {
+		olderThan = h.maxAge
+	}
+
+	ch := make(chan *map_.KeyValue)
+	if err := h.events.Entries(ctx, ch); err != nil {
+		return 0, err
+	}
+
+	var removed uint64
+	for kv := range ch {
+		history := &GetDeviceHistoryResponse{}
+		if err := proto.Unmarshal(kv.Value, history); err != nil {
+			continue
+		}
+		events := expireEvents(history.Events, olderThan)
+		if len(events) == len(history.Events) {
+			continue
+		}
+
+		// Update the history only if it has not been modified since it was listed
+		if len(events) == 0 {
+			_, err := h.events.Remove(ctx, kv.Key, map_.WithVersion(kv.Version))
+			if err == nil {
+				removed += uint64(len(history.Events))
+			}
+			continue
+		}
+		bytes, err := proto.Marshal(&GetDeviceHistoryResponse{Events: events})
+		if err != nil {
+			return removed, err
+		}
+		if _, err := h.events.Put(ctx, kv.Key, bytes, map_.WithVersion(kv.Version)); err == nil {
+			removed += uint64(len(history.Events) - len(events))
+		}
+	}
+	return removed, nil
+}
+
+// trim removes expired events and the oldest events exceeding the maximum number of events
+func (h *historyLog) trim(events []*DeviceHistoryEvent) []*DeviceHistoryEvent {
+	if h.maxAge > 0 {
+		events = expireEvents(events, h.maxAge)
+	}
+	if len(events) > h.maxEvents {
+		events = events[len(events)-h.maxEvents:]
+	}
+	return events
+}
+
+// expireEvents returns the events that were recorded within the given duration
+func expireEvents(events []*DeviceHistoryEvent, maxAge time.Duration) []*DeviceHistoryEvent {
+	for i, event := range events {
+		timestamp, err := ptypes.Timestamp(event.Timestamp)
+		if err == nil && time.Since(timestamp) <= maxAge {
+			return events[i:]
+		}
+	}
+	return []*DeviceHistoryEvent{}
+}
+
+// containsEvent returns whether the given events contain an event of the same type for the same device version
+func containsEvent(events []*DeviceHistoryEvent, event *DeviceHistoryEvent) bool {
+	for _, e := range events {
+		if e.Type == event.Type && e.Device.GetMetadata().GetVersion() == event.Device.GetMetadata().GetVersion() {
+			return true
+		}
+	}
+	return false
+}
+
+// eventResponseType returns the ListResponse type for the given store event type
+func eventResponseType(eventType EventType) ListResponse_Type {
+	switch eventType {
+	case EventInserted:
+		return ListResponse_ADDED
+	case EventUpdated:
+		return ListResponse_UPDATED
+	case EventRemoved:
+		return ListResponse_REMOVED
+	case EventAnnotated:
+		return ListResponse_ANNOTATED
+	default:
+		return ListResponse_NONE
+	}
+}
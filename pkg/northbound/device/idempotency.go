@@ -0,0 +1,214 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/atomix/atomix-go-client/pkg/client/lock"
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"github.com/atomix/atomix-go-client/pkg/client/session"
+	"github.com/gogo/protobuf/proto"
+	"github.com/onosproject/onos-topo/pkg/util"
+	"time"
+)
+
+// defaultIdempotencyTTL is the default duration for which responses to idempotent requests are cached
+const defaultIdempotencyTTL = 10 * time.Minute
+
+// idempotencyPendingTimeout is the duration after which a reserved key whose request hasn't completed is
+// assumed to have been reserved by a replica that failed, and may be reserved again
+const idempotencyPendingTimeout = time.Minute
+
+// idempotencyPollInterval is the interval at which a request waits for the response to a request with the
+// same idempotency key
+const idempotencyPollInterval = 50 * time.Millisecond
+
+const (
+	// entryPending marks a cache entry reserved by a request that's being applied
+	entryPending byte = iota
+
+	// entryResponse marks a cache entry holding the response to an applied request
+	entryResponse
+)
+
+// newIdempotencyCache returns a new idempotencyCache backed by an Atomix map
+// The cache is shared by all replicas of the service, so retries are deduplicated regardless of the
+// replica to which they're sent.
+func newIdempotencyCache(ttl time.Duration) (*idempotencyCache, error) {
+	client, err := util.GetSharedAtomixClient()
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := client.GetGroup(context.Background(), util.GetAtomixRaftGroup())
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := group.GetMap(context.Background(), "device-requests", session.WithTimeout(30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	reserveLock, err := group.GetLock(context.Background(), "device-requests", session.WithTimeout(30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return &idempotencyCache{
+		requests:    requests,
+		reserveLock: reserveLock,
+		ttl:         ttl,
+	}, nil
+}
+
+// idempotencyCache caches the responses to mutating requests by idempotency key
+// Each entry is stored as the time at which it was written, followed by a byte marking the entry as pending
+// or as a response, followed by the encoded response. A key is reserved with a pending entry before its
+// request is applied, so concurrent requests with the same key wait for the first request's response rather
+// than applying the request again.
+type idempotencyCache struct {
+	requests map_.Map
+
+	// reserveLock serializes the reservations of absent keys, since the Atomix map doesn't support
+	// conditional puts of absent keys
+	reserveLock lock.Lock
+	ttl         time.Duration
+}
+
+// reserve reserves the given key for a request, or loads the cached response for the key into the given
+// response if a request with the key has been applied
+// If a request with the key is being applied, reserve waits for its response. It returns whether a cached
+// response was loaded and, if not, the version of the reservation, with which the response must be stored
+// or the reservation released.
+func (c *idempotencyCache) reserve(ctx context.Context, method string, key string, response proto.Message) (bool, int64, error) {
+	for {
+		kv, err := c.requests.Get(ctx, method+"/"+key)
+		if err != nil {
+			return false, 0, err
+		}
+
+		if kv != nil {
+			if len(kv.Value) >= 9 {
+				written := time.Unix(0, int64(binary.BigEndian.Uint64(kv.Value[:8])))
+				switch {
+				case kv.Value[8] == entryResponse && time.Since(written) <= c.ttl:
+					if err := proto.Unmarshal(kv.Value[9:], response); err != nil {
+						return false, 0, err
+					}
+					return true, 0, nil
+				case kv.Value[8] == entryPending && time.Since(written) <= idempotencyPendingTimeout:
+					select {
+					case <-time.After(idempotencyPollInterval):
+					case <-ctx.Done():
+						return false, 0, ctx.Err()
+					}
+					continue
+				}
+			}
+
+			// The entry has expired, so it's replaced by a new reservation unless it's been replaced already
+			kv, err = c.requests.Put(ctx, kv.Key, encodeEntry(entryPending, nil), map_.WithVersion(kv.Version))
+			if err == nil {
+				return false, kv.Version, nil
+			} else if conflictError(err) != ErrConflict {
+				return false, 0, err
+			}
+			continue
+		}
+
+		version, err := c.reserveAbsent(ctx, method+"/"+key)
+		if err != nil || version != 0 {
+			return false, version, err
+		}
+	}
+}
+
+// reserveAbsent reserves the given key if it's absent while holding the reservation lock
+// If the key was reserved or cached concurrently, 0 is returned.
+func (c *idempotencyCache) reserveAbsent(ctx context.Context, key string) (int64, error) {
+	if _, err := c.reserveLock.Lock(ctx); err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = c.reserveLock.Unlock(context.Background())
+	}()
+
+	if kv, err := c.requests.Get(ctx, key); err != nil {
+		return 0, err
+	} else if kv != nil {
+		return 0, nil
+	}
+	kv, err := c.requests.Put(ctx, key, encodeEntry(entryPending, nil))
+	if err != nil {
+		return 0, err
+	}
+	return kv.Version, nil
+}
+
+// release removes the given reservation of the given key, allowing the request to be retried
+func (c *idempotencyCache) release(ctx context.Context, method string, key string, version int64) error {
+	_, err := c.requests.Remove(ctx, method+"/"+key, map_.WithVersion(version))
+	return err
+}
+
+// compact removes the cached responses that were cached more than the given duration ago
+// Responses are never removed before the TTL has expired. Returns the number of responses removed.
+func (c *idempotencyCache) compact(ctx context.Context, olderThan time.Duration) (uint64, error) {
+	if olderThan < c.ttl {
+		olderThan = c.ttl
+	}
+
+	ch := make(chan *map_.KeyValue)
+	if err := c.requests.Entries(ctx, ch); err != nil {
+		return 0, err
+	}
+
+	var expired []*map_.KeyValue
+	for kv := range ch {
+		if len(kv.Value) < 8 || time.Since(time.Unix(0, int64(binary.BigEndian.Uint64(kv.Value[:8])))) > olderThan {
+			expired = append(expired, kv)
+		}
+	}
+
+	var removed uint64
+	for _, kv := range expired {
+		// Remove the entry only if it has not been replaced since it was listed
+		if _, err := c.requests.Remove(ctx, kv.Key, map_.WithVersion(kv.Version)); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// store caches the given response for the given method and key in place of the given reservation
+// The response isn't stored if the reservation has expired and the key has been reserved again.
+func (c *idempotencyCache) store(ctx context.Context, method string, key string, version int64, response proto.Message) error {
+	bytes, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = c.requests.Put(ctx, method+"/"+key, encodeEntry(entryResponse, bytes), map_.WithVersion(version))
+	return err
+}
+
+// encodeEntry encodes a cache entry of the given type holding the given encoded response
+func encodeEntry(entryType byte, response []byte) []byte {
+	value := make([]byte, 9, 9+len(response))
+	binary.BigEndian.PutUint64(value, uint64(time.Now().UnixNano()))
+	value[8] = entryType
+	return append(value, response...)
+}
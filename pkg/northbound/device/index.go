@@ -0,0 +1,405 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"sort"
+	"sync"
+)
+
+// indexWatchOptions are the options with which indexes watch the store
+// An index that falls behind the store's events is evicted rather than blocking the delivery of events to
+// other watchers, and is rebuilt from a new watch.
+var indexWatchOptions = []WatchOption{WithReplayDone(true), WithOverflowPolicy(OverflowEvict)}
+
+// rewatchIndex watches the given store again once the watch of an index is evicted
+// nil is returned if the store can't be watched.
+func rewatchIndex(store Store, logger Logger, fields ...Field) <-chan *Event {
+	logger.Warn("Device index watch evicted, rebuilding the index", append(fields, OperationField("index"))...)
+	ch := make(chan *Event)
+	if err := store.Watch(ch, indexWatchOptions...); err != nil {
+		logger.Error("Failed to watch devices", append(fields, OperationField("index"), ErrorField(err))...)
+		return nil
+	}
+	return ch
+}
+
+// newLabelIndex returns a label index that is maintained from the events of the given store
+// The index is built from the devices replayed when the store is watched and is updated as devices are
+// added, updated and removed by any replica of the service. It's not used until the replay is complete.
+func newLabelIndex(store Store, logger Logger) (*labelIndex, error) {
+	index := &labelIndex{
+		values:  make(map[string]map[string]map[string]bool),
+		devices: make(map[string]map[string]string),
+		logger:  logger,
+	}
+	ch := make(chan *Event)
+	if err := store.Watch(ch, indexWatchOptions...); err != nil {
+		return nil, err
+	}
+	go index.process(store, ch)
+	return index, nil
+}
+
+// labelIndex is an inverted index of device labels
+// Because the index is maintained from store events, it may briefly lag behind writes. Lookups return
+// candidate devices that must be loaded and matched against the selector, so a device whose labels no longer
+// match is never selected, but a device whose labels only just started to match may be missed.
+type labelIndex struct {
+	mu sync.RWMutex
+
+	// values is the set of IDs of the devices with each label value, keyed by label key and value
+	values map[string]map[string]map[string]bool
+
+	// devices is the labels of each indexed device
+	devices map[string]map[string]string
+
+	// ready indicates whether the index has been built and is being maintained
+	ready  bool
+	logger Logger
+}
+
+// process indexes the devices of the given events until the store's watch is closed
+// If the watch is evicted, the index is rebuilt from a new watch and isn't used until the devices are replayed.
+// If the watch is otherwise closed, the index stops being used and selectors are evaluated by listing all
+// devices.
+func (i *labelIndex) process(store Store, ch <-chan *Event) {
+	for ch != nil {
+		evicted := false
+		for event := range ch {
+			i.mu.Lock()
+			switch event.Type {
+			case EventNone, EventInserted, EventUpdated:
+				i.remove(event.Device.Id)
+				i.put(event.Device)
+			case EventRemoved:
+				i.remove(event.Device.Id)
+			case EventReplayDone:
+				i.ready = true
+				i.logger.Info("Built device label index", OperationField("index"), Field{Key: "devices", Value: len(i.devices)})
+			case EventEvicted:
+				i.ready = false
+				i.values = make(map[string]map[string]map[string]bool)
+				i.devices = make(map[string]map[string]string)
+				evicted = true
+			}
+			i.mu.Unlock()
+		}
+		ch = nil
+		if evicted {
+			ch = rewatchIndex(store, i.logger)
+		}
+	}
+
+	i.mu.Lock()
+	i.ready = false
+	i.mu.Unlock()
+	i.logger.Warn("Device label index watch closed", OperationField("index"))
+}
+
+// put adds the labels of the given device to the index
+// The caller must hold the index's write lock.
+func (i *labelIndex) put(device *Device) {
+	labels := device.GetLabels()
+	if len(labels) == 0 {
+		return
+	}
+	indexed := make(map[string]string, len(labels))
+	i.devices[device.Id] = indexed
+	for key, value := range labels {
+		indexed[key] = value
+		values, ok := i.values[key]
+		if !ok {
+			values = make(map[string]map[string]bool)
+			i.values[key] = values
+		}
+		ids, ok := values[value]
+		if !ok {
+			ids = make(map[string]bool)
+			values[value] = ids
+		}
+		ids[device.Id] = true
+	}
+}
+
+// remove removes the labels of the device with the given ID from the index
+// The caller must hold the index's write lock.
+func (i *labelIndex) remove(id string) {
+	for key, value := range i.devices[id] {
+		ids := i.values[key][value]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(i.values[key], value)
+		}
+		if len(i.values[key]) == 0 {
+			delete(i.values, key)
+		}
+	}
+	delete(i.devices, id)
+}
+
+// lookup returns the IDs of the candidate devices for the given selector in ID order
+// Only requirements that a label equals a value or exists narrow the candidates, so lookup returns false if
+// the index is not ready or the selector has no such requirement, in which case all devices must be scanned.
+func (i *labelIndex) lookup(selector Selector) ([]string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if !i.ready {
+		return nil, false
+	}
+
+	var candidates map[string]bool
+	narrowed := false
+	for _, requirement := range selector {
+		var matches map[string]bool
+		switch requirement.operator {
+		case selectorEquals:
+			matches = i.values[requirement.key][requirement.value]
+		case selectorExists:
+			matches = make(map[string]bool)
+			for _, ids := range i.values[requirement.key] {
+				for id := range ids {
+					matches[id] = true
+				}
+			}
+		default:
+			continue
+		}
+		if !narrowed {
+			candidates = make(map[string]bool, len(matches))
+			for id := range matches {
+				candidates[id] = true
+			}
+			narrowed = true
+			continue
+		}
+		for id := range candidates {
+			if !matches[id] {
+				delete(candidates, id)
+			}
+		}
+	}
+	if !narrowed {
+		return nil, false
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, true
+}
+
+// newValueIndex returns an index of the values of a device field that is maintained from the events of the
+// given store
+// The indexed value of each device is returned by the given function, and devices for which it returns an
+// empty value are not indexed.
+func newValueIndex(store Store, field string, value func(*Device) string, logger Logger) (*valueIndex, error) {
+	index := &valueIndex{
+		field:   field,
+		value:   value,
+		ids:     make(map[string]map[string]bool),
+		devices: make(map[string]string),
+		pending: make(map[string]map[string]int),
+		logger:  logger,
+	}
+	ch := make(chan *Event)
+	if err := store.Watch(ch, indexWatchOptions...); err != nil {
+		return nil, err
+	}
+	go index.process(store, ch)
+	return index, nil
+}
+
+// valueIndex is an index of the values of a device field, e.g. the serial numbers of devices
+// Like the label index, it's maintained from store events, so a write isn't indexed until its event is
+// received. To enforce uniqueness on a replica, writers reserve the value before writing the device, and the
+// reservation is held until the write's event is indexed. Two replicas writing the same value concurrently
+// may still both succeed.
+type valueIndex struct {
+	mu sync.RWMutex
+
+	// reserveMu serializes the checks and reservations of values
+	reserveMu sync.Mutex
+
+	// field is the name of the indexed field
+	field string
+
+	// value returns the indexed value of a device
+	value func(*Device) string
+
+	// ids is the set of IDs of the devices with each value
+	ids map[string]map[string]bool
+
+	// devices is the indexed value of each device
+	devices map[string]string
+
+	// pending is the number of reservations of each value by each device whose writes have not been indexed
+	pending map[string]map[string]int
+
+	// ready indicates whether the index has been built and is being maintained
+	ready  bool
+	logger Logger
+}
+
+// process indexes the devices of the given events until the store's watch is closed
+// Like the label index, the index is rebuilt if its watch is evicted. Reservations are kept while it's rebuilt.
+func (i *valueIndex) process(store Store, ch <-chan *Event) {
+	for ch != nil {
+		evicted := false
+		for event := range ch {
+			i.mu.Lock()
+			switch event.Type {
+			case EventNone, EventInserted, EventUpdated:
+				i.remove(event.Device.Id)
+				i.put(event.Device)
+			case EventRemoved:
+				i.remove(event.Device.Id)
+			case EventReplayDone:
+				i.ready = true
+				i.logger.Info("Built device field index", OperationField("index"), Field{Key: "field", Value: i.field}, Field{Key: "devices", Value: len(i.devices)})
+			case EventEvicted:
+				i.ready = false
+				i.ids = make(map[string]map[string]bool)
+				i.devices = make(map[string]string)
+				evicted = true
+			}
+			i.mu.Unlock()
+		}
+		ch = nil
+		if evicted {
+			ch = rewatchIndex(store, i.logger, Field{Key: "field", Value: i.field})
+		}
+	}
+
+	i.mu.Lock()
+	i.ready = false
+	i.mu.Unlock()
+	i.logger.Warn("Device field index watch closed", OperationField("index"), Field{Key: "field", Value: i.field})
+}
+
+// put adds the value of the given device to the index
+// The device's write has been indexed, so its reservations of the value are released. The caller must hold
+// the index's write lock.
+func (i *valueIndex) put(device *Device) {
+	value := i.value(device)
+	if value == "" {
+		return
+	}
+	ids, ok := i.ids[value]
+	if !ok {
+		ids = make(map[string]bool)
+		i.ids[value] = ids
+	}
+	ids[device.Id] = true
+	i.devices[device.Id] = value
+	if reservations, ok := i.pending[value]; ok {
+		delete(reservations, device.Id)
+		if len(reservations) == 0 {
+			delete(i.pending, value)
+		}
+	}
+}
+
+// remove removes the value of the device with the given ID from the index
+// The caller must hold the index's write lock.
+func (i *valueIndex) remove(id string) {
+	value, ok := i.devices[id]
+	if !ok {
+		return
+	}
+	delete(i.ids[value], id)
+	if len(i.ids[value]) == 0 {
+		delete(i.ids, value)
+	}
+	delete(i.devices, id)
+}
+
+// reserve reserves the given value for the device with the given ID
+// If another device has the value or has reserved it, its ID is returned and the value is not reserved. If the
+// index is not ready, the values of the devices returned by the given scan function are checked instead. The
+// reservation must be released if the device is not written.
+func (i *valueIndex) reserve(id string, value string, scan func() ([]*Device, error)) (string, error) {
+	i.reserveMu.Lock()
+	defer i.reserveMu.Unlock()
+	ids, ok := i.lookup(value)
+	if !ok {
+		devices, err := scan()
+		if err != nil {
+			return "", err
+		}
+		for _, device := range devices {
+			if i.value(device) == value {
+				ids = append(ids, device.Id)
+			}
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	reservations, ok := i.pending[value]
+	if !ok {
+		reservations = make(map[string]int)
+		i.pending[value] = reservations
+	}
+	for other := range reservations {
+		ids = append(ids, other)
+	}
+	for _, other := range ids {
+		if other != id {
+			if len(reservations) == 0 {
+				delete(i.pending, value)
+			}
+			return other, nil
+		}
+	}
+	reservations[id]++
+	return "", nil
+}
+
+// release releases a reservation of the given value by the device with the given ID
+// Reservations that were released when the device's write was indexed are ignored.
+func (i *valueIndex) release(id string, value string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	reservations, ok := i.pending[value]
+	if !ok || reservations[id] == 0 {
+		return
+	}
+	reservations[id]--
+	if reservations[id] == 0 {
+		delete(reservations, id)
+	}
+	if len(reservations) == 0 {
+		delete(i.pending, value)
+	}
+}
+
+// lookup returns the IDs of the devices with the given value in ID order
+// lookup returns false if the index is not ready, in which case all devices must be scanned.
+func (i *valueIndex) lookup(value string) ([]string, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if !i.ready {
+		return nil, false
+	}
+	ids := make([]string, 0, len(i.ids[value]))
+	for id := range i.ids[value] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, true
+}
@@ -0,0 +1,116 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// ClaimDevice claims a lease on a device for a controller, or renews the controller's existing lease
+func (s *Server) ClaimDevice(ctx context.Context, request *ClaimDeviceRequest) (*ClaimDeviceResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if request.OwnerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no owner specified")
+	} else if request.LeaseDuration == nil {
+		return nil, status.Error(codes.InvalidArgument, "no lease duration specified")
+	}
+	lease, err := ptypes.Duration(request.LeaseDuration)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if lease <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "lease duration must be positive")
+	}
+
+	var device *Device
+	err = s.modifyDevice(ctx, request.DeviceId, "claim", func(stored *Device) error {
+		now := time.Now()
+		if isLeased(stored.Owner, now) && stored.Owner.Id != request.OwnerId {
+			return ownedError(stored)
+		}
+		expiry, err := ptypes.TimestampProto(now.Add(lease))
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		stored.Owner = &Owner{
+			Id:     request.OwnerId,
+			Expiry: expiry,
+		}
+		device = stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimDeviceResponse{
+		Owner:    device.Owner,
+		Metadata: device.Metadata,
+	}, nil
+}
+
+// ReleaseDevice releases a controller's lease on a device
+func (s *Server) ReleaseDevice(ctx context.Context, request *ReleaseDeviceRequest) (*ReleaseDeviceResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if request.OwnerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no owner specified")
+	}
+
+	var device *Device
+	err := s.modifyDevice(ctx, request.DeviceId, "release", func(stored *Device) error {
+		// An expired lease held by another controller may be released, since it could be claimed anyway
+		if isLeased(stored.Owner, time.Now()) && stored.Owner.Id != request.OwnerId {
+			return ownedError(stored)
+		}
+		stored.Owner = nil
+		device = stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReleaseDeviceResponse{
+		Metadata: device.Metadata,
+	}, nil
+}
+
+// isLeased returns whether the given owner holds a lease that has not expired at the given time
+func isLeased(owner *Owner, now time.Time) bool {
+	if owner == nil || owner.Id == "" {
+		return false
+	}
+	expiry, err := ptypes.Timestamp(owner.Expiry)
+	if err != nil {
+		return false
+	}
+	return now.Before(expiry)
+}
+
+// ownedError returns a FailedPrecondition error for a device leased by another controller
+func ownedError(device *Device) error {
+	expiry, _ := ptypes.Timestamp(device.Owner.Expiry)
+	return status.Error(codes.FailedPrecondition, fmt.Sprintf("device %s is owned by %s until %s", device.Id, device.Owner.Id, expiry.Format(time.RFC3339)))
+}
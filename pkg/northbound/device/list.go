@@ -0,0 +1,50 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"sync"
+)
+
+// decodeEntries decodes the map entries received from the given channel and writes the devices to the given
+// device channel using the given number of concurrent workers, closing the device channel once all entries
+// have been decoded
+// Entries are decoded in order by a single worker if workers is less than 2. With multiple workers, every
+// entry is still delivered but devices may be written out of order. Entries that can't be decoded are passed
+// to the given error handler and skipped.
+func decodeEntries(entries <-chan *map_.KeyValue, ch chan<- *Device, workers int, onError func(*map_.KeyValue, error)) {
+	if workers < 2 {
+		workers = 1
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for kv := range entries {
+				if device, err := decodeDevice(kv.Key, kv.Value, kv.Version); err == nil {
+					ch <- device
+				} else {
+					onError(kv, err)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+}
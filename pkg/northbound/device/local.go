@@ -0,0 +1,813 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/gogo/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// NewLocalStore returns a new in-memory Store
+// The local store is not shared between service instances and does not persist devices across restarts;
+// use NewPersistentLocalStore to persist devices to a file.
+func NewLocalStore(opts ...LocalStoreOption) Store {
+	store := &localStore{
+		devices:     make(map[string]*localEntry),
+		annotations: make(map[string]*localEntry),
+		groups:      make(map[string]*localEntry),
+		logger:      NewNopLogger(),
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// LocalStoreOption is an option for configuring the local store
+type LocalStoreOption func(*localStore)
+
+// WithLatency sets a simulated latency for each store operation
+// The latency can be used to simulate a remote store, e.g. to verify that operations are canceled when
+// their context is canceled.
+func WithLatency(latency time.Duration) LocalStoreOption {
+	return func(store *localStore) {
+		store.latency = latency
+	}
+}
+
+// WithLocalLogger sets the logger to which the local store logs errors and watch lifecycle events
+func WithLocalLogger(logger Logger) LocalStoreOption {
+	return func(store *localStore) {
+		store.logger = logger
+	}
+}
+
+// WithLocalCompression sets the size in bytes above which encoded devices are compressed with gzip
+// Devices are not compressed if the threshold is 0.
+func WithLocalCompression(threshold int) LocalStoreOption {
+	return func(store *localStore) {
+		store.compressionThreshold = threshold
+	}
+}
+
+// WithLocalMaxValueSize sets the maximum size in bytes of an encoded device
+// Writes of devices that exceed the limit fail with ErrValueTooLarge. The local store has no limit of its
+// own, but setting the limit of the persistent store it replaces catches oversized devices in testing. The
+// size of devices is not limited by default.
+func WithLocalMaxValueSize(limit int) LocalStoreOption {
+	return func(store *localStore) {
+		store.maxValueSize = limit
+	}
+}
+
+// localEntry is a device, device annotations or device group stored in the local store
+type localEntry struct {
+	value   []byte
+	version uint64
+}
+
+// localStore is an in-memory implementation of the Store
+// Versions are assigned from a single counter that is incremented on each write, mirroring the
+// versioning of the Atomix map.
+type localStore struct {
+	devices     map[string]*localEntry
+	annotations map[string]*localEntry
+	groups      map[string]*localEntry
+	mu          sync.RWMutex
+	version     uint64
+	watchers    []*watcher
+	dispatcher  dispatcher
+	seq         uint64
+	latency     time.Duration
+	logger      Logger
+
+	// compressionThreshold is the size above which encoded devices are compressed
+	compressionThreshold int
+
+	// maxValueSize is the maximum size of an encoded device
+	maxValueSize int
+
+	// path is the file to which a persistent store is written, or empty if the store is in-memory
+	path string
+
+	// changed indicates whether the store has changed since it was last written to its file
+	changed bool
+}
+
+// wait waits for the simulated latency, returning an error if the given context is canceled
+func (s *localStore) wait(ctx context.Context) error {
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-ctx.Done():
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *localStore) Load(ctx context.Context, deviceID string) (*Device, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.devices[deviceID]
+	if !ok {
+		return nil, nil
+	}
+	return decodeDevice(deviceID, entry.value, int64(entry.version))
+}
+
+func (s *localStore) Exists(ctx context.Context, deviceID string) (bool, error) {
+	if err := s.wait(ctx); err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.devices[deviceID]
+	return ok, nil
+}
+
+// encode encodes the given device to be stored, returning ErrValueTooLarge if the device is too large
+func (s *localStore) encode(device *Device) ([]byte, error) {
+	bytes, err := encodeDevice(device, s.compressionThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return bytes, checkValueSize(device.Id, bytes, s.maxValueSize)
+}
+
+func (s *localStore) Count(ctx context.Context) (int, error) {
+	if err := s.wait(ctx); err != nil {
+		return 0, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.devices), nil
+}
+
+func (s *localStore) Create(ctx context.Context, device *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	bytes, err := s.encode(device)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+	if _, ok := s.devices[device.Id]; ok {
+		return ErrAlreadyExists
+	}
+	s.write(device, bytes, nil)
+	return nil
+}
+
+func (s *localStore) EnsureDevice(ctx context.Context, device *Device) (*Device, bool, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, false, err
+	}
+	bytes, err := s.encode(device)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+	if entry, ok := s.devices[device.Id]; ok {
+		stored, err := decodeDevice(device.Id, entry.value, int64(entry.version))
+		return stored, false, err
+	}
+	s.write(device, bytes, nil)
+	return device, true, nil
+}
+
+func (s *localStore) Store(ctx context.Context, device *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	return s.put(device, true)
+}
+
+func (s *localStore) ForcePut(ctx context.Context, device *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	return s.put(device, false)
+}
+
+// put stores the given device, checking the version of the stored device if checkVersion is true
+func (s *localStore) put(device *Device, checkVersion bool) error {
+	bytes, err := s.encode(device)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	// Check the version of the stored device using an optimistic lock if this is an update
+	entry, ok := s.devices[device.Id]
+	if checkVersion && device.Metadata != nil && device.Metadata.Version != 0 && (!ok || entry.version != device.Metadata.Version) {
+		return ErrConflict
+	}
+
+	s.write(device, bytes, entry)
+	return nil
+}
+
+// write stores the given encoded device, replacing the given previous entry, and publishes an event
+// The caller must hold the store's write lock.
+func (s *localStore) write(device *Device, bytes []byte, entry *localEntry) {
+	s.changed = true
+	s.version++
+	s.devices[device.Id] = &localEntry{
+		value:   bytes,
+		version: s.version,
+	}
+
+	// Update the device metadata
+	device.Metadata = &ObjectMetadata{
+		Id:      device.Id,
+		Version: s.version,
+	}
+
+	event := &Event{
+		Type:   EventInserted,
+		Device: proto.Clone(device).(*Device),
+	}
+	if entry != nil {
+		event.Type = EventUpdated
+		if prev, err := decodeDevice(device.Id, entry.value, int64(entry.version)); err == nil {
+			event.PrevDevice = prev
+		}
+	}
+	s.publish(event)
+}
+
+func (s *localStore) Delete(ctx context.Context, device *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	id := device.Id
+	if device.Metadata != nil && device.Metadata.Version > 0 {
+		id = device.Metadata.Id
+	}
+
+	entry, ok := s.devices[id]
+	if !ok {
+		return ErrNotFound
+	} else if device.Metadata != nil && device.Metadata.Version > 0 && entry.version != device.Metadata.Version {
+		return ErrConflict
+	}
+	return s.remove(id, entry)
+}
+
+// remove removes the device with the given ID and its annotations and publishes an event
+// The caller must hold the store's write lock.
+func (s *localStore) remove(id string, entry *localEntry) error {
+	s.changed = true
+	delete(s.devices, id)
+	delete(s.annotations, id)
+
+	removed, err := decodeDevice(id, entry.value, int64(entry.version))
+	if err != nil {
+		return err
+	}
+	s.publish(&Event{
+		Type:       EventRemoved,
+		Device:     removed,
+		PrevDevice: removed,
+	})
+	return nil
+}
+
+func (s *localStore) Rename(ctx context.Context, device *Device, newID string) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	oldID := device.Id
+	entry, ok := s.devices[oldID]
+	if !ok {
+		return ErrNotFound
+	} else if device.Metadata != nil && device.Metadata.Version > 0 && entry.version != device.Metadata.Version {
+		return ErrConflict
+	} else if _, ok := s.devices[newID]; ok {
+		return ErrAlreadyExists
+	}
+
+	removed, err := decodeDevice(oldID, entry.value, int64(entry.version))
+	if err != nil {
+		return err
+	}
+	renamed := proto.Clone(removed).(*Device)
+	renamed.Id = newID
+	renamed.Metadata = nil
+	bytes, err := s.encode(renamed)
+	if err != nil {
+		return err
+	}
+	// The children are encoded before any write is applied, so the rename is atomic
+	children, values, err := s.reparent(oldID, newID)
+	if err != nil {
+		return err
+	}
+
+	s.changed = true
+	delete(s.devices, oldID)
+	s.version++
+	s.devices[newID] = &localEntry{
+		value:   bytes,
+		version: s.version,
+	}
+	if annotations, ok := s.annotations[oldID]; ok {
+		delete(s.annotations, oldID)
+		s.annotations[newID] = annotations
+	}
+	renamed.Metadata = &ObjectMetadata{
+		Id:      newID,
+		Version: s.version,
+	}
+
+	s.publish(&Event{
+		Type:       EventRemoved,
+		Device:     removed,
+		PrevDevice: removed,
+	})
+	s.publish(&Event{
+		Type:   EventInserted,
+		Device: proto.Clone(renamed).(*Device),
+	})
+	for i, child := range children {
+		s.write(child, values[i], s.devices[child.Id])
+	}
+
+	device.Id = newID
+	device.Metadata = renamed.Metadata
+	return nil
+}
+
+// reparent returns the children of the device with the old ID updated to reference the new ID, along with
+// their encoded values
+// The caller must hold the store's write lock.
+func (s *localStore) reparent(oldID string, newID string) ([]*Device, [][]byte, error) {
+	var children []*Device
+	var values [][]byte
+	for id, entry := range s.devices {
+		child, err := decodeDevice(id, entry.value, int64(entry.version))
+		if err != nil {
+			return nil, nil, err
+		} else if child.ParentId != oldID {
+			continue
+		}
+		child.ParentId = newID
+		bytes, err := s.encode(child)
+		if err != nil {
+			return nil, nil, err
+		}
+		children = append(children, child)
+		values = append(values, bytes)
+	}
+	return children, values, nil
+}
+
+func (s *localStore) SwapAddresses(ctx context.Context, firstID string, secondID string) (*Device, *Device, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	firstEntry, ok := s.devices[firstID]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	secondEntry, ok := s.devices[secondID]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	firstPrev, err := decodeDevice(firstID, firstEntry.value, int64(firstEntry.version))
+	if err != nil {
+		return nil, nil, err
+	}
+	secondPrev, err := decodeDevice(secondID, secondEntry.value, int64(secondEntry.version))
+	if err != nil {
+		return nil, nil, err
+	}
+	first := proto.Clone(firstPrev).(*Device)
+	second := proto.Clone(secondPrev).(*Device)
+	swapAddresses(first, second)
+
+	// Both devices are updated under the store's lock, so the swap is atomic
+	first.Metadata = nil
+	firstBytes, err := s.encode(first)
+	if err != nil {
+		return nil, nil, err
+	}
+	second.Metadata = nil
+	secondBytes, err := s.encode(second)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.changed = true
+	s.version++
+	s.devices[firstID] = &localEntry{
+		value:   firstBytes,
+		version: s.version,
+	}
+	first.Metadata = &ObjectMetadata{
+		Id:      firstID,
+		Version: s.version,
+	}
+	s.version++
+	s.devices[secondID] = &localEntry{
+		value:   secondBytes,
+		version: s.version,
+	}
+	second.Metadata = &ObjectMetadata{
+		Id:      secondID,
+		Version: s.version,
+	}
+
+	s.publish(&Event{
+		Type:       EventUpdated,
+		Device:     proto.Clone(first).(*Device),
+		PrevDevice: firstPrev,
+	})
+	s.publish(&Event{
+		Type:       EventUpdated,
+		Device:     proto.Clone(second).(*Device),
+		PrevDevice: secondPrev,
+	})
+	return first, second, nil
+}
+
+func (s *localStore) Clear(ctx context.Context) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	s.changed = true
+	for id, entry := range s.devices {
+		delete(s.devices, id)
+		removed, err := decodeDevice(id, entry.value, int64(entry.version))
+		if err != nil {
+			s.logger.Error("Failed to decode device", DeviceIDField(id), OperationField("clear"), VersionField(entry.version), ErrorField(err))
+			continue
+		}
+		s.publish(&Event{
+			Type:       EventRemoved,
+			Device:     removed,
+			PrevDevice: removed,
+		})
+	}
+	s.annotations = make(map[string]*localEntry)
+	return nil
+}
+
+func (s *localStore) Tx(ctx context.Context, fn func(Txn) error) error {
+	t := &txn{}
+	if err := fn(t); err != nil {
+		return err
+	}
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+
+	values := make([][]byte, len(t.ops))
+	for i, op := range t.ops {
+		if !op.remove {
+			bytes, err := s.encode(op.device)
+			if err != nil {
+				return err
+			}
+			values[i] = bytes
+		}
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	// All versions are checked before any write is applied, so the transaction is atomic
+	for _, op := range t.ops {
+		entry, ok := s.devices[op.device.Id]
+		if version := op.device.GetMetadata().GetVersion(); version != 0 && (!ok || entry.version != version) {
+			return ErrConflict
+		}
+	}
+
+	for i, op := range t.ops {
+		entry := s.devices[op.device.Id]
+		if !op.remove {
+			s.write(op.device, values[i], entry)
+		} else if entry != nil {
+			if err := s.remove(op.device.Id, entry); err != nil {
+				s.logger.Error("Failed to decode device", DeviceIDField(op.device.Id), OperationField("tx"), VersionField(entry.version), ErrorField(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (s *localStore) LoadAnnotations(ctx context.Context, deviceID string) (*Annotations, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.annotations[deviceID]
+	if !ok {
+		return nil, nil
+	}
+	return decodeAnnotations(deviceID, entry.value, int64(entry.version))
+}
+
+func (s *localStore) StoreAnnotations(ctx context.Context, annotations *Annotations) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	bytes, err := proto.Marshal(annotations)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	// Check the version of the stored annotations using an optimistic lock if the version is set
+	entry, ok := s.annotations[annotations.DeviceId]
+	if annotations.Version != 0 && (!ok || entry.version != annotations.Version) {
+		return ErrConflict
+	}
+
+	s.changed = true
+	s.version++
+	s.annotations[annotations.DeviceId] = &localEntry{
+		value:   bytes,
+		version: s.version,
+	}
+	annotations.Version = s.version
+
+	s.publish(&Event{
+		Type:        EventAnnotated,
+		Annotations: proto.Clone(annotations).(*Annotations),
+	})
+	return nil
+}
+
+func (s *localStore) CreateGroup(ctx context.Context, group *DeviceGroup) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+	if _, ok := s.groups[group.Id]; ok {
+		return ErrAlreadyExists
+	}
+	group.Version = 0
+	return s.putGroup(group)
+}
+
+func (s *localStore) StoreGroup(ctx context.Context, group *DeviceGroup) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	// Check the version of the stored group using an optimistic lock if the version is set
+	entry, ok := s.groups[group.Id]
+	if group.Version != 0 && (!ok || entry.version != group.Version) {
+		return ErrConflict
+	}
+	return s.putGroup(group)
+}
+
+// putGroup stores the given group and updates its version
+// The caller must hold the store's write lock.
+func (s *localStore) putGroup(group *DeviceGroup) error {
+	bytes, err := proto.Marshal(group)
+	if err != nil {
+		return err
+	}
+	s.changed = true
+	s.version++
+	s.groups[group.Id] = &localEntry{
+		value:   bytes,
+		version: s.version,
+	}
+	group.Version = s.version
+	return nil
+}
+
+func (s *localStore) LoadGroup(ctx context.Context, groupID string) (*DeviceGroup, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.groups[groupID]
+	if !ok {
+		return nil, nil
+	}
+	return decodeGroup(groupID, entry.value, int64(entry.version))
+}
+
+func (s *localStore) DeleteGroup(ctx context.Context, group *DeviceGroup) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.unlock()
+
+	entry, ok := s.groups[group.Id]
+	if !ok {
+		return ErrNotFound
+	} else if group.Version > 0 && entry.version != group.Version {
+		return ErrConflict
+	}
+	s.changed = true
+	delete(s.groups, group.Id)
+	return nil
+}
+
+func (s *localStore) ListGroups(ctx context.Context) ([]*DeviceGroup, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	groups := make([]*DeviceGroup, 0, len(s.groups))
+	for id, entry := range s.groups {
+		group, err := decodeGroup(id, entry.value, int64(entry.version))
+		if err != nil {
+			s.logger.Error("Failed to decode device group", Field{Key: "group", Value: id}, OperationField("list-groups"), ErrorField(err))
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (s *localStore) List(ctx context.Context, ch chan<- *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	devices := s.snapshot()
+	go func() {
+		defer close(ch)
+		for _, device := range devices {
+			ch <- device
+		}
+	}()
+	return nil
+}
+
+// ListModifiedSince streams the devices modified since the given time
+// The local store isn't indexed by update time, so every device is decoded.
+func (s *localStore) ListModifiedSince(ctx context.Context, since time.Time, ch chan<- *Device) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	devices := s.snapshot()
+	go func() {
+		defer close(ch)
+		for _, device := range devices {
+			if modifiedSince(device, since) {
+				ch <- device
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *localStore) ListChildren(ctx context.Context, parentID string) ([]*Device, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+	children := make([]*Device, 0)
+	for _, device := range s.snapshot() {
+		if device.ParentId == parentID {
+			children = append(children, device)
+		}
+	}
+	return children, nil
+}
+
+// snapshot returns a copy of all the devices in the store
+func (s *localStore) snapshot() []*Device {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	devices := make([]*Device, 0, len(s.devices))
+	for id, entry := range s.devices {
+		if device, err := decodeDevice(id, entry.value, int64(entry.version)); err == nil {
+			devices = append(devices, device)
+		} else {
+			s.logger.Error("Failed to decode device", DeviceIDField(id), OperationField("list"), VersionField(entry.version), ErrorField(err))
+		}
+	}
+	return devices
+}
+
+func (s *localStore) Watch(ch chan<- *Event, opts ...WatchOption) error {
+	return s.WatchFrom(context.Background(), 0, ch, opts...)
+}
+
+func (s *localStore) VersionsOrdered() bool {
+	return true
+}
+
+func (s *localStore) WatchFrom(ctx context.Context, version uint64, ch chan<- *Event, opts ...WatchOption) error {
+	options := &watchOptions{
+		bufferSize: defaultWatchBufferSize,
+		policy:     OverflowBlock,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	w := newWatcher(options)
+
+	// Register the watcher and take a snapshot of the devices under the same lock to ensure the replay
+	// is consistent with the live events
+	s.mu.Lock()
+	s.watchers = append(s.watchers, w)
+	seq := s.seq
+	devices := make([]*Device, 0, len(s.devices))
+	for id, entry := range s.devices {
+		if options.noReplay || entry.version <= version || !w.accepts(EventNone) || (w.deviceIDs != nil && !w.deviceIDs[id]) {
+			continue
+		}
+		if device, err := decodeDevice(id, entry.value, int64(entry.version)); err == nil {
+			devices = append(devices, device)
+		} else {
+			s.logger.Error("Failed to decode device", DeviceIDField(id), OperationField("watch"), VersionField(entry.version), ErrorField(err))
+		}
+	}
+	s.logger.Debug("Added device watcher", OperationField("watch"), Field{Key: "watchers", Value: len(s.watchers)})
+	s.mu.Unlock()
+
+	deviceCh := make(chan *Device, len(devices))
+	for _, device := range devices {
+		deviceCh <- device
+	}
+	close(deviceCh)
+	forwardEvents(ctx, w, seq, deviceCh, ch, s.removeWatcher)
+	return nil
+}
+
+func (s *localStore) WatchDevices(ctx context.Context, ids []string, ch chan<- *Event, opts ...WatchOption) error {
+	return s.WatchFrom(ctx, 0, ch, append(opts, withDeviceIDs(ids))...)
+}
+
+// removeWatcher unregisters the given watcher and stops forwarding its events
+func (s *localStore) removeWatcher(w *watcher) {
+	s.unregisterWatcher(w)
+	w.stop()
+}
+
+// unregisterWatcher unregisters the given watcher so that no further events are published to it
+func (s *localStore) unregisterWatcher(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, watcher := range s.watchers {
+		if watcher == w {
+			s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+			s.logger.Debug("Removed device watcher", OperationField("watch"), Field{Key: "watchers", Value: len(s.watchers)})
+			return
+		}
+	}
+}
+
+// publish assigns a sequence number to the given event and dispatches it to all registered watchers
+// The event is delivered asynchronously by the store's dispatcher. The caller must hold the store's write lock.
+func (s *localStore) publish(event *Event) {
+	s.seq++
+	event.Seq = s.seq
+	s.dispatcher.dispatch(s.watchers, event, s.unregisterWatcher)
+}
@@ -0,0 +1,114 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+	log "k8s.io/klog"
+	"strings"
+)
+
+// Logger is a structured logger for the device store and server
+// Implementations can be provided to integrate the device package with an existing logging stack.
+type Logger interface {
+	// Debug logs a debug message with the given fields
+	Debug(msg string, fields ...Field)
+
+	// Info logs an informational message with the given fields
+	Info(msg string, fields ...Field)
+
+	// Warn logs a warning message with the given fields
+	Warn(msg string, fields ...Field)
+
+	// Error logs an error message with the given fields
+	Error(msg string, fields ...Field)
+}
+
+// Field is a key/value pair attached to a log message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// DeviceIDField returns a field for the given device ID
+func DeviceIDField(deviceID string) Field {
+	return Field{Key: "device", Value: deviceID}
+}
+
+// OperationField returns a field for the given store or server operation
+func OperationField(operation string) Field {
+	return Field{Key: "operation", Value: operation}
+}
+
+// VersionField returns a field for the given device version
+func VersionField(version uint64) Field {
+	return Field{Key: "version", Value: version}
+}
+
+// ErrorField returns a field for the given error
+func ErrorField(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// NewNopLogger returns a Logger that discards all messages
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+// nopLogger is a Logger that discards all messages
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...Field) {}
+func (nopLogger) Info(string, ...Field)  {}
+func (nopLogger) Warn(string, ...Field)  {}
+func (nopLogger) Error(string, ...Field) {}
+
+// NewKlogLogger returns a Logger that writes messages to klog
+// Debug messages are logged at verbosity level 4.
+func NewKlogLogger() Logger {
+	return klogLogger{}
+}
+
+// klogLogger is a Logger that writes messages to klog
+type klogLogger struct{}
+
+func (klogLogger) Debug(msg string, fields ...Field) {
+	log.V(4).Info(formatMessage(msg, fields))
+}
+
+func (klogLogger) Info(msg string, fields ...Field) {
+	log.Info(formatMessage(msg, fields))
+}
+
+func (klogLogger) Warn(msg string, fields ...Field) {
+	log.Warning(formatMessage(msg, fields))
+}
+
+func (klogLogger) Error(msg string, fields ...Field) {
+	log.Error(formatMessage(msg, fields))
+}
+
+// formatMessage appends the given fields to the message as key=value pairs
+func formatMessage(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	var sb strings.Builder
+	sb.WriteString(msg)
+	for _, field := range fields {
+		fmt.Fprintf(&sb, " %s=%v", field.Key, field.Value)
+	}
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lagBuckets are the upper bounds in seconds of the send lag histogram buckets
+var lagBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+// newSubscriberMetrics returns new subscriber metrics published in the given registry
+func newSubscriberMetrics(registry *expvar.Map) *subscriberMetrics {
+	metrics := &subscriberMetrics{
+		subscribers: new(expvar.Int),
+		lag: &lagHistogram{
+			counts: make([]uint64, len(lagBuckets)+1),
+		},
+	}
+	registry.Set("subscribers", metrics.subscribers)
+	registry.Set("send_lag_seconds", metrics.lag)
+	return metrics
+}
+
+// subscriberMetrics is the metrics of the List subscribers
+type subscriberMetrics struct {
+	// subscribers is the number of active subscribers
+	subscribers *expvar.Int
+
+	// lag is the distribution of the time between the store publishing an event and the event being sent
+	lag *lagHistogram
+}
+
+// lagHistogram is a histogram of send lags exported as an expvar.Var
+type lagHistogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// observe records the given lag in the histogram
+func (h *lagHistogram) observe(lag time.Duration) {
+	seconds := lag.Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for i < len(lagBuckets) && seconds > lagBuckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += seconds
+}
+
+// String returns the histogram as a JSON object with cumulative bucket counts keyed by upper bound
+func (h *lagHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := make([]string, 0, len(h.counts))
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		bound := "+Inf"
+		if i < len(lagBuckets) {
+			bound = fmt.Sprintf("%g", lagBuckets[i])
+		}
+		buckets = append(buckets, fmt.Sprintf("%q: %d", bound, cumulative))
+	}
+	return fmt.Sprintf(`{"count": %d, "sum": %g, "buckets": {%s}}`, h.count, h.sum, strings.Join(buckets, ", "))
+}
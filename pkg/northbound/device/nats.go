@@ -0,0 +1,333 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// natsDialTimeout is the timeout for connecting to a NATS server
+	natsDialTimeout = 5 * time.Second
+
+	// defaultNATSWriteTimeout is the default timeout for a message to be written and acknowledged
+	defaultNATSWriteTimeout = 5 * time.Second
+)
+
+// NATSSinkOption is an option for configuring a NATS event sink
+type NATSSinkOption func(*natsSink)
+
+// WithNATSTLS secures the connection to the NATS server with the given TLS configuration
+func WithNATSTLS(config *tls.Config) NATSSinkOption {
+	return func(sink *natsSink) {
+		sink.tlsConfig = config
+	}
+}
+
+// WithNATSUserInfo authenticates to the NATS server with the given user and password
+func WithNATSUserInfo(user string, password string) NATSSinkOption {
+	return func(sink *natsSink) {
+		sink.user = user
+		sink.password = password
+	}
+}
+
+// WithNATSToken authenticates to the NATS server with the given token
+func WithNATSToken(token string) NATSSinkOption {
+	return func(sink *natsSink) {
+		sink.token = token
+	}
+}
+
+// WithNATSWriteTimeout sets the timeout for an event to be written to and acknowledged by the NATS server
+func WithNATSWriteTimeout(timeout time.Duration) NATSSinkOption {
+	return func(sink *natsSink) {
+		sink.writeTimeout = timeout
+	}
+}
+
+// NewNATSSink returns an EventSink that publishes events to the given subject of the NATS server at the
+// given address
+// The sink speaks the NATS client protocol directly in verbose mode, so each event is acknowledged by the
+// server and an -ERR response fails the publish. The sink reconnects to the server on the next event if
+// the connection is lost. Events published while the server is unreachable are lost.
+func NewNATSSink(address string, subject string, format SinkFormat, opts ...NATSSinkOption) (EventSink, error) {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return nil, fmt.Errorf("invalid NATS subject %q", subject)
+	}
+	if format != SinkFormatJSON && format != SinkFormatProto {
+		return nil, fmt.Errorf("unknown sink format %s", format)
+	}
+	sink := &natsSink{
+		address:      address,
+		subject:      subject,
+		format:       format,
+		writeTimeout: defaultNATSWriteTimeout,
+	}
+	for _, opt := range opts {
+		opt(sink)
+	}
+	if err := sink.connect(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// natsSink is an EventSink that publishes events to a NATS subject
+type natsSink struct {
+	address      string
+	subject      string
+	format       SinkFormat
+	tlsConfig    *tls.Config
+	user         string
+	password     string
+	token        string
+	writeTimeout time.Duration
+	conn         net.Conn
+	acks         chan error
+	mu           sync.Mutex
+	// writeMu serializes writes to the connection, which are made both by Publish and by the reader
+	// responding to the server's PINGs while Publish waits for an acknowledgement
+	writeMu sync.Mutex
+}
+
+// natsInfo is the subset of the server's INFO message used by the sink
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+// natsConnect is the client's CONNECT message
+type natsConnect struct {
+	Verbose     bool   `json:"verbose"`
+	Pedantic    bool   `json:"pedantic"`
+	TLSRequired bool   `json:"tls_required"`
+	Name        string `json:"name"`
+	User        string `json:"user,omitempty"`
+	Password    string `json:"pass,omitempty"`
+	AuthToken   string `json:"auth_token,omitempty"`
+}
+
+// natsError returns the error for the given -ERR message
+func natsError(line string) error {
+	return fmt.Errorf("NATS error: %s", strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+}
+
+// connect connects to the NATS server
+// The caller must hold the sink's lock unless the sink has not yet been returned to the caller.
+func (s *natsSink) connect() error {
+	conn, err := net.DialTimeout("tcp", s.address, natsDialTimeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(natsDialTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	// The server sends an INFO message when the client connects, and the client must respond with CONNECT
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	} else if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return errors.New("unexpected NATS server greeting")
+	}
+	info := &natsInfo{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "INFO "))), info); err != nil {
+		conn.Close()
+		return err
+	}
+
+	// The connection is upgraded to TLS after the INFO message
+	if s.tlsConfig != nil {
+		config := s.tlsConfig.Clone()
+		if config.ServerName == "" {
+			if host, _, err := net.SplitHostPort(s.address); err == nil {
+				config.ServerName = host
+			}
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return err
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	} else if info.TLSRequired {
+		conn.Close()
+		return errors.New("NATS server requires TLS")
+	}
+
+	connect, err := json.Marshal(&natsConnect{
+		Verbose:     true,
+		TLSRequired: s.tlsConfig != nil,
+		Name:        "onos-topo",
+		User:        s.user,
+		Password:    s.password,
+		AuthToken:   s.token,
+	})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	// A PING is sent after the CONNECT so that authentication failures are reported before the sink is used
+	if _, err := conn.Write([]byte(fmt.Sprintf("CONNECT %s\r\nPING\r\n", connect))); err != nil {
+		conn.Close()
+		return err
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return err
+		} else if strings.HasPrefix(line, "-ERR") {
+			conn.Close()
+			return natsError(line)
+		} else if strings.HasPrefix(line, "PONG") {
+			break
+		}
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return err
+	}
+
+	acks := make(chan error, 1)
+	s.conn = conn
+	s.acks = acks
+	go s.read(conn, reader, acks)
+	return nil
+}
+
+// read delivers the server's acknowledgements and responds to its PING messages until the given
+// connection is closed
+func (s *natsSink) read(conn net.Conn, reader *bufio.Reader, acks chan<- error) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			s.disconnect(conn)
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			s.writeMu.Lock()
+			_, err = conn.Write([]byte("PONG\r\n"))
+			s.writeMu.Unlock()
+			if err != nil {
+				s.disconnect(conn)
+				return
+			}
+		case strings.HasPrefix(line, "+OK"):
+			select {
+			case acks <- nil:
+			default:
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			select {
+			case acks <- natsError(line):
+			default:
+			}
+		}
+	}
+}
+
+// disconnect closes the given connection if it's still the sink's current connection
+func (s *natsSink) disconnect(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close(conn)
+}
+
+// close closes the given connection, clearing it if it's the sink's current connection
+// The caller must hold the sink's lock.
+func (s *natsSink) close(conn net.Conn) {
+	if s.conn == conn {
+		s.conn = nil
+		s.acks = nil
+	}
+	conn.Close()
+}
+
+func (s *natsSink) Publish(event *Event) error {
+	payload, err := encodeEvent(event, s.format)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	conn, acks := s.conn, s.acks
+
+	// Drop any stale acknowledgement left by a publish that timed out
+	select {
+	case <-acks:
+	default:
+	}
+
+	message := append([]byte(fmt.Sprintf("PUB %s %d\r\n", s.subject, len(payload))), payload...)
+	message = append(message, '\r', '\n')
+	s.writeMu.Lock()
+	err = conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	if err == nil {
+		_, err = conn.Write(message)
+	}
+	s.writeMu.Unlock()
+	if err != nil {
+		s.close(conn)
+		return err
+	}
+
+	timer := time.NewTimer(s.writeTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-acks:
+		if err != nil {
+			// The server closes the connection after most errors, so the sink reconnects on the next event
+			s.close(conn)
+		}
+		return err
+	case <-timer.C:
+		s.close(conn)
+		return errors.New("timed out waiting for NATS acknowledgement")
+	}
+}
+
+func (s *natsSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.acks = nil
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	// defaultPageSize is the number of devices returned in a page if the request doesn't set a page size
+	defaultPageSize = 100
+
+	// maxPageSize is the maximum number of devices returned in a page
+	maxPageSize = 1000
+)
+
+// newPageTokenKey returns a random key for signing page tokens
+func newPageTokenKey() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// pageCursor encodes and decodes page tokens
+// A page token encodes the ID of the last device on a page, which is the position after which the next page
+// begins. Tokens are signed so that clients can't construct tokens and must treat them as opaque.
+type pageCursor struct {
+	key []byte
+}
+
+// encode returns a page token for the page ending with the given device ID
+func (c pageCursor) encode(lastID string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(lastID))
+	return base64.RawURLEncoding.EncodeToString(append(mac.Sum(nil), lastID...))
+}
+
+// decode returns the ID of the last device on the page for the given page token
+func (c pageCursor) decode(token string) (string, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(bytes) < sha256.Size {
+		return "", errors.New("invalid page token")
+	}
+
+	lastID := bytes[sha256.Size:]
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(lastID)
+	if !hmac.Equal(bytes[:sha256.Size], mac.Sum(nil)) {
+		return "", errors.New("invalid page token")
+	}
+	return string(lastID), nil
+}
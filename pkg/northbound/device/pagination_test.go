@@ -0,0 +1,155 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"encoding/base64"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	cursor := pageCursor{key: newPageTokenKey()}
+	for _, id := range []string{"", "device-1", "rack-1/device-1", "dévice"} {
+		decoded, err := cursor.decode(cursor.encode(id))
+		if err != nil {
+			t.Errorf("failed to decode token for %q: %v", id, err)
+		} else if decoded != id {
+			t.Errorf("expected %q, got %q", id, decoded)
+		}
+	}
+}
+
+func TestPageTokenTampered(t *testing.T) {
+	cursor := pageCursor{key: newPageTokenKey()}
+	token := cursor.encode("device-1")
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := append([]byte{}, bytes...)
+	modified[len(modified)-1] = '2'
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "modified ID", token: base64.RawURLEncoding.EncodeToString(modified)},
+		{name: "truncated", token: token[:len(token)/2]},
+		{name: "other key", token: pageCursor{key: newPageTokenKey()}.encode("device-1")},
+		{name: "not base64", token: "not a token!"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := cursor.decode(test.token); err == nil {
+				t.Error("expected the token to be rejected")
+			}
+		})
+	}
+}
+
+func TestListPageConcurrentInserts(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalStore()
+	server := newTestServer(t, store)
+	for _, id := range []string{"device-b", "device-c", "device-d", "device-e", "device-f"} {
+		if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	response, err := server.ListPage(ctx, &ListPageRequest{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := pageIDs(response)
+	if len(ids) != 2 || ids[0] != "device-b" || ids[1] != "device-c" {
+		t.Fatalf("unexpected first page %v", ids)
+	}
+
+	// Devices added before the cursor are not returned, and devices added after it appear on later pages
+	for _, id := range []string{"device-a", "device-g"} {
+		if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var rest []string
+	for response.NextPageToken != "" {
+		response, err = server.ListPage(ctx, &ListPageRequest{PageSize: 2, PageToken: response.NextPageToken})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rest = append(rest, pageIDs(response)...)
+	}
+	expected := []string{"device-d", "device-e", "device-f", "device-g"}
+	if len(rest) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, rest)
+	}
+	for i := range expected {
+		if rest[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, rest)
+		}
+	}
+}
+
+func TestListPageLastPage(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalStore()
+	server := newTestServer(t, store)
+	for _, id := range []string{"device-1", "device-2", "device-3", "device-4"} {
+		if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A page that ends with the last device has no next page token
+	response, err := server.ListPage(ctx, &ListPageRequest{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	} else if response.NextPageToken == "" {
+		t.Fatal("expected a next page token")
+	}
+	response, err = server.ListPage(ctx, &ListPageRequest{PageSize: 2, PageToken: response.NextPageToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := pageIDs(response); len(ids) != 2 || ids[1] != "device-4" {
+		t.Errorf("unexpected last page %v", ids)
+	}
+	if response.NextPageToken != "" {
+		t.Error("expected no next page token on the last page")
+	}
+}
+
+func TestListPageInvalidToken(t *testing.T) {
+	server := newTestServer(t, NewLocalStore())
+	token := pageCursor{key: newPageTokenKey()}.encode("device-1")
+	_, err := server.ListPage(context.Background(), &ListPageRequest{PageToken: token})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("expected %s, got %s", codes.InvalidArgument, code)
+	}
+}
+
+// pageIDs returns the IDs of the devices on the given page
+func pageIDs(response *ListPageResponse) []string {
+	ids := make([]string, len(response.Devices))
+	for i, device := range response.Devices {
+		ids[i] = device.Id
+	}
+	return ids
+}
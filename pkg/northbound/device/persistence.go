@@ -0,0 +1,138 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// NewPersistentLocalStore returns a new local Store that persists devices to the file with the given path
+// The devices, annotations and device groups stored in the file, if it exists, are restored when the store is created, and
+// the file is rewritten after each change. The store is intended for standalone deployments, e.g. for
+// development and demos; like the in-memory local store, it's not shared between service instances.
+func NewPersistentLocalStore(path string, opts ...LocalStoreOption) (Store, error) {
+	store := NewLocalStore(opts...).(*localStore)
+	store.path = path
+	if err := store.restore(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// localSnapshot is the format of the file to which a persistent local store is written
+type localSnapshot struct {
+	Version     uint64                         `json:"version"`
+	Devices     map[string]*localSnapshotEntry `json:"devices"`
+	Annotations map[string]*localSnapshotEntry `json:"annotations"`
+	Groups      map[string]*localSnapshotEntry `json:"groups"`
+}
+
+// localSnapshotEntry is an encoded device, device annotations or device group in a snapshot
+// Devices are stored in the encoding used by the stores, so snapshots written by older versions of the
+// service are migrated when the devices are decoded.
+type localSnapshotEntry struct {
+	Value   []byte `json:"value"`
+	Version uint64 `json:"version"`
+}
+
+// restore restores the store from its file if the file exists
+func (s *localStore) restore() error {
+	bytes, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	snapshot := &localSnapshot{}
+	if err := json.Unmarshal(bytes, snapshot); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = snapshot.Version
+	for id, entry := range snapshot.Devices {
+		s.devices[id] = &localEntry{value: entry.Value, version: entry.Version}
+	}
+	for id, entry := range snapshot.Annotations {
+		s.annotations[id] = &localEntry{value: entry.Value, version: entry.Version}
+	}
+	for id, entry := range snapshot.Groups {
+		s.groups[id] = &localEntry{value: entry.Value, version: entry.Version}
+	}
+	return nil
+}
+
+// unlock writes the store to its file if the store is persistent and has changed, and releases the
+// store's write lock
+// Writing the store is best effort: the change has already been applied, so failures are logged and the
+// store is written again after the next change.
+func (s *localStore) unlock() {
+	defer s.mu.Unlock()
+	if s.path == "" || !s.changed {
+		return
+	}
+	if err := s.persist(); err != nil {
+		s.logger.Error("Failed to persist devices", OperationField("persist"), Field{Key: "path", Value: s.path}, ErrorField(err))
+		return
+	}
+	s.changed = false
+}
+
+// persist writes the store to its file
+// The store is written to a temporary file that replaces the file, so a failed write doesn't corrupt the
+// file. The caller must hold the store's write lock.
+func (s *localStore) persist() error {
+	snapshot := &localSnapshot{
+		Version:     s.version,
+		Devices:     make(map[string]*localSnapshotEntry, len(s.devices)),
+		Annotations: make(map[string]*localSnapshotEntry, len(s.annotations)),
+		Groups:      make(map[string]*localSnapshotEntry, len(s.groups)),
+	}
+	for id, entry := range s.devices {
+		snapshot.Devices[id] = &localSnapshotEntry{Value: entry.value, Version: entry.version}
+	}
+	for id, entry := range s.annotations {
+		snapshot.Annotations[id] = &localSnapshotEntry{Value: entry.value, Version: entry.version}
+	}
+	for id, entry := range s.groups {
+		snapshot.Groups[id] = &localSnapshotEntry{Value: entry.value, Version: entry.version}
+	}
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(bytes); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	} else if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	} else if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), s.path)
+}
@@ -0,0 +1,58 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+// RequestDeviceID returns the ID of the device targeted by the given request, or an empty string if the
+// request doesn't target a device
+// Requests that target two devices, e.g. SwapAddressesRequest, return the ID of the first device, and
+// CloneDeviceRequest returns the ID of the source device.
+func RequestDeviceID(req interface{}) string {
+	switch r := req.(type) {
+	case *AddRequest:
+		return r.GetDevice().GetId()
+	case *EnsureDeviceRequest:
+		return r.GetDevice().GetId()
+	case *UpdateRequest:
+		return r.GetDevice().GetId()
+	case *RemoveRequest:
+		return r.GetDevice().GetId()
+	case *GetRequest:
+		return r.DeviceId
+	case *ClaimDeviceRequest:
+		return r.DeviceId
+	case *ReleaseDeviceRequest:
+		return r.DeviceId
+	case *ReportStateRequest:
+		return r.DeviceId
+	case *CompareAndSwapFieldRequest:
+		return r.DeviceId
+	case *RotateCredentialsRequest:
+		return r.DeviceId
+	case *GetDeviceHistoryRequest:
+		return r.DeviceId
+	case *RenameRequest:
+		return r.DeviceId
+	case *SetAnnotationsRequest:
+		return r.GetAnnotations().GetDeviceId()
+	case *ListChildrenRequest:
+		return r.ParentId
+	case *SwapAddressesRequest:
+		return r.FirstDeviceId
+	case *CloneDeviceRequest:
+		return r.SourceId
+	default:
+		return ""
+	}
+}
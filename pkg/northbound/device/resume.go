@@ -0,0 +1,112 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// snapshotPosition is the position in a snapshot of devices streamed in ID order up to which a client has
+// received the snapshot
+type snapshotPosition struct {
+	// lastID is the ID of the last device received
+	lastID string
+
+	// version is the highest version of the devices received
+	version uint64
+}
+
+// received returns whether the client has received the given version of the given device
+// Devices are received in ID order, so a device that sorts before the last received device was received
+// unless it has been updated since, in which case its version is higher than any version received.
+func (p *snapshotPosition) received(device *Device) bool {
+	return p != nil && device.Id <= p.lastID && device.GetMetadata().GetVersion() <= p.version
+}
+
+// advance moves the position past the given device
+// Devices that are streamed again because they were updated since they were received sort before the
+// position and don't move it.
+func (p *snapshotPosition) advance(device *Device) {
+	if device.Id <= p.lastID {
+		return
+	}
+	p.lastID = device.Id
+	if version := device.GetMetadata().GetVersion(); version > p.version {
+		p.version = version
+	}
+}
+
+// encodeResume returns a resume token for the given snapshot position
+// Resume tokens are signed with the page token key, so replicas that share the key accept each other's tokens.
+func (c pageCursor) encodeResume(position *snapshotPosition) string {
+	return c.encode(strconv.FormatUint(position.version, 10) + "/" + position.lastID)
+}
+
+// decodeResume returns the snapshot position for the given resume token
+func (c pageCursor) decodeResume(token string) (*snapshotPosition, error) {
+	value, err := c.decode(token)
+	if err != nil {
+		return nil, errors.New("invalid resume token")
+	}
+	i := strings.Index(value, "/")
+	if i < 0 {
+		return nil, errors.New("invalid resume token")
+	}
+	version, err := strconv.ParseUint(value[:i], 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid resume token")
+	}
+	return &snapshotPosition{
+		lastID:  value[i+1:],
+		version: version,
+	}, nil
+}
+
+// resumePosition returns the snapshot position from which the given request resumes a snapshot, or nil if the
+// request does not resume a snapshot
+func (s *Server) resumePosition(request *ListRequest) (*snapshotPosition, error) {
+	if request.ResumeToken == "" {
+		return nil, nil
+	} else if !s.deviceStore.VersionsOrdered() {
+		// Positions record the highest version received, which only orders devices within a shard
+		return nil, status.Error(codes.FailedPrecondition, "resume tokens are not supported when devices are sharded")
+	}
+	position, err := s.pageCursor.decodeResume(request.ResumeToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return position, nil
+}
+
+// nextPosition returns the position from which the snapshot streamed from the given position advances
+func nextPosition(position *snapshotPosition) *snapshotPosition {
+	next := &snapshotPosition{}
+	if position != nil {
+		*next = *position
+	}
+	return next
+}
+
+// sortDevices sorts the given devices by ID
+func sortDevices(devices []*Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Id < devices[j].Id
+	})
+}
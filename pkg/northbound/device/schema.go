@@ -0,0 +1,80 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+)
+
+// schemaHeader is the first byte of device values that are prefixed with their schema version
+// Like compressedValueHeader, a protobuf-encoded device never starts with this byte because it's not a
+// valid field tag, so values stored before schema versions were introduced are decoded as schema version 1.
+const schemaHeader byte = 1
+
+// legacySchemaVersion is the schema version of device values stored without a schema version prefix
+const legacySchemaVersion byte = 1
+
+// deviceMigration upgrades a device decoded from a value of one schema version to the next schema version
+type deviceMigration func(device *Device) error
+
+// deviceMigrations is the registered device migrations, in order of schema version
+// The migration at index i upgrades devices of schema version i+1 to schema version i+2, so the current
+// schema version is one greater than the number of migrations. To change how devices are stored, append a
+// migration that upgrades devices decoded from the previous schema version.
+var deviceMigrations = []deviceMigration{
+	// Version 2 stores credentials as a BasicAuth, SshKey or Token rather than a user and password
+	func(device *Device) error {
+		return device.NormalizeCredentials()
+	},
+}
+
+// currentSchemaVersion returns the schema version with which devices are stored
+func currentSchemaVersion() byte {
+	return byte(len(deviceMigrations)) + legacySchemaVersion
+}
+
+// addSchemaVersion prefixes the given encoded device with the current schema version
+func addSchemaVersion(value []byte) []byte {
+	versioned := make([]byte, 0, len(value)+2)
+	versioned = append(versioned, schemaHeader, currentSchemaVersion())
+	return append(versioned, value...)
+}
+
+// splitSchemaVersion returns the schema version of the given device value and the encoded device
+func splitSchemaVersion(value []byte) (byte, []byte, error) {
+	if len(value) == 0 || value[0] != schemaHeader {
+		return legacySchemaVersion, value, nil
+	} else if len(value) < 2 {
+		return 0, nil, fmt.Errorf("missing device schema version")
+	}
+	version := value[1]
+	if version < legacySchemaVersion || version > currentSchemaVersion() {
+		return 0, nil, fmt.Errorf("unsupported device schema version %d", version)
+	}
+	return version, value[2:], nil
+}
+
+// migrateDevice upgrades the given device decoded from a value of the given schema version to the current
+// schema version
+// Migrated devices are not written back to the store; they're stored with the current schema version the
+// next time they're updated.
+func migrateDevice(device *Device, version byte) error {
+	for _, migration := range deviceMigrations[version-legacySchemaVersion:] {
+		if err := migration(device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
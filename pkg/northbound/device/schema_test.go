@@ -0,0 +1,131 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"testing"
+)
+
+// newLegacyDevice returns a device with credentials in the deprecated schema version 1 user and password
+func newLegacyDevice() *Device {
+	return &Device{
+		Id:      "device-1",
+		Address: "device-1:5150",
+		Credentials: &Credentials{
+			User:     "admin",
+			Password: "secret",
+		},
+	}
+}
+
+func TestDecodeSchemaVersions(t *testing.T) {
+	legacy, err := proto.Marshal(newLegacyDevice())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The device is only compressed if compression makes it smaller
+	large := newLegacyDevice()
+	large.Protocols = []*Protocol{{Type: Protocol_GNMI, Config: make([]byte, 4096)}}
+	compressed, err := compressDevice(large, 1)
+	if err != nil {
+		t.Fatal(err)
+	} else if compressed[0] != compressedValueHeader {
+		t.Fatal("expected the legacy device to be compressed")
+	}
+	// Devices are stored with the current schema version after their credentials are normalized
+	normalized := newLegacyDevice()
+	if err := normalized.NormalizeCredentials(); err != nil {
+		t.Fatal(err)
+	}
+	current, err := encodeDevice(normalized, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		// Values stored before schema versions were introduced have no header
+		{name: "v1 unprefixed", value: legacy},
+		{name: "v1 unprefixed compressed", value: compressed},
+		{name: "v1 prefixed", value: append([]byte{schemaHeader, legacySchemaVersion}, legacy...)},
+		{name: "current", value: current},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			device, err := decodeDevice("device-1", test.value, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Version 1 credentials are migrated through NormalizeCredentials to a BasicAuth
+			credentials := device.GetCredentials()
+			if credentials.User != "" || credentials.Password != "" {
+				t.Errorf("expected the deprecated credentials to be cleared, got %q/%q", credentials.User, credentials.Password)
+			}
+			basicAuth := credentials.GetBasicAuth()
+			if basicAuth == nil || basicAuth.User != "admin" || basicAuth.Password != "secret" {
+				t.Errorf("expected basic auth admin/secret, got %v", basicAuth)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidSchemaVersions(t *testing.T) {
+	legacy, err := proto.Marshal(newLegacyDevice())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Version 1 user and password can't be combined with an SSH key, so the migration fails
+	conflicting := newLegacyDevice()
+	conflicting.Credentials.Credential = &Credentials_SshKey{SshKey: &SshKey{User: "admin", PrivateKeyRef: "key"}}
+	unmigratable, err := proto.Marshal(conflicting)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value []byte
+	}{
+		{name: "missing version", value: []byte{schemaHeader}},
+		{name: "version 0", value: append([]byte{schemaHeader, 0}, legacy...)},
+		{name: "unknown version", value: append([]byte{schemaHeader, currentSchemaVersion() + 1}, legacy...)},
+		{name: "failed migration", value: unmigratable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := decodeDevice("device-1", test.value, 1); err == nil {
+				t.Error("expected the value to be rejected")
+			}
+		})
+	}
+}
+
+func TestEncodeSchemaVersion(t *testing.T) {
+	value, err := encodeDevice(&Device{Id: "device-1"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, _, err := splitSchemaVersion(value)
+	if err != nil {
+		t.Fatal(err)
+	} else if version != currentSchemaVersion() {
+		t.Errorf("expected schema version %d, got %d", currentSchemaVersion(), version)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectorOperator is the operator of a label selector requirement
+type selectorOperator string
+
+const (
+	selectorEquals       selectorOperator = "="
+	selectorNotEquals    selectorOperator = "!="
+	selectorExists       selectorOperator = "exists"
+	selectorDoesNotExist selectorOperator = "!"
+)
+
+// selectorRequirement is a single requirement of a label selector
+type selectorRequirement struct {
+	key      string
+	operator selectorOperator
+	value    string
+}
+
+// matches returns whether the given labels satisfy the requirement
+func (r selectorRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.operator {
+	case selectorEquals:
+		return ok && value == r.value
+	case selectorNotEquals:
+		return !ok || value != r.value
+	case selectorExists:
+		return ok
+	case selectorDoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// Selector is a label selector
+// A device matches the selector if its labels satisfy all of the selector's requirements. An empty
+// selector matches all devices.
+type Selector []selectorRequirement
+
+// ParseSelector parses a comma-separated list of label requirements
+// Each requirement is one of key=value (or key==value), key!=value, key (the label is set), or !key (the
+// label is not set).
+func ParseSelector(selector string) (Selector, error) {
+	var requirements Selector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var requirement selectorRequirement
+		if i := strings.Index(term, "!="); i >= 0 {
+			requirement = selectorRequirement{key: term[:i], operator: selectorNotEquals, value: term[i+2:]}
+		} else if i := strings.Index(term, "=="); i >= 0 {
+			requirement = selectorRequirement{key: term[:i], operator: selectorEquals, value: term[i+2:]}
+		} else if i := strings.Index(term, "="); i >= 0 {
+			requirement = selectorRequirement{key: term[:i], operator: selectorEquals, value: term[i+1:]}
+		} else if strings.HasPrefix(term, "!") {
+			requirement = selectorRequirement{key: term[1:], operator: selectorDoesNotExist}
+		} else {
+			requirement = selectorRequirement{key: term, operator: selectorExists}
+		}
+
+		requirement.key = strings.TrimSpace(requirement.key)
+		requirement.value = strings.TrimSpace(requirement.value)
+		if requirement.key == "" || strings.ContainsAny(requirement.key, "=!") {
+			return nil, fmt.Errorf("invalid selector requirement %q", term)
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
+
+// Matches returns whether the labels of the given device satisfy the selector
+func (s Selector) Matches(device *Device) bool {
+	for _, requirement := range s {
+		if !requirement.matches(device.GetLabels()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns whether the selector has no requirements
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
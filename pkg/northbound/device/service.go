@@ -17,135 +17,1869 @@ package device
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/onosproject/onos-topo/pkg/northbound"
+	"github.com/onosproject/onos-topo/pkg/northbound/admin"
+	"github.com/onosproject/onos-topo/pkg/trace"
+	"google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
+// listBufferSize is the number of devices or events buffered for each List stream
+const listBufferSize = 1000
+
+// defaultConflictRetries is the default number of times a field mask update is retried on a version conflict
+const defaultConflictRetries = 3
+
 // NewService returns a new device Service
-func NewService() (northbound.Service, error) {
-	deviceStore, err := NewAtomixStore()
+func NewService(opts ...ServiceOption) (northbound.Service, error) {
+	service := &Service{
+		idempotencyTTL:   defaultIdempotencyTTL,
+		pageTokenKey:     newPageTokenKey(),
+		logger:           NewKlogLogger(),
+		idPattern:        DefaultIDPattern,
+		cache:            newReadCache(),
+		conflictRetries:  defaultConflictRetries,
+		secretAuthorizer: admin.AuthorizeVerifiedClient,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	if service.store == nil {
+		deviceStore, err := NewAtomixStore(WithAtomixLogger(service.logger))
+		if err != nil {
+			return nil, err
+		}
+		service.store = deviceStore
+	}
+	deviceStore := service.store
+	if service.tracer != nil {
+		service.store = NewTracingStore(deviceStore, service.tracer)
+	}
+	if service.statsSize > 0 {
+		service.stats = newDeviceStats(service.statsSize)
+		service.store = newStatsStore(service.store, service.stats)
+	}
+	if service.indexLabels {
+		labels, err := newLabelIndex(deviceStore, service.logger)
+		if err != nil {
+			return nil, err
+		}
+		service.labels = labels
+	}
+	if service.uniqueSerials {
+		serials, err := newValueIndex(deviceStore, "serial number", func(device *Device) string {
+			return device.SerialNumber
+		}, service.logger)
+		if err != nil {
+			return nil, err
+		}
+		service.serials = serials
+	}
+	if service.uniqueAddresses {
+		addresses, err := newValueIndex(deviceStore, "address", func(device *Device) string {
+			return device.Address
+		}, service.logger)
+		if err != nil {
+			return nil, err
+		}
+		service.addresses = addresses
+	}
+	if service.metricsRegistry != nil {
+		service.metrics = newSubscriberMetrics(service.metricsRegistry)
+	}
+	if service.eventSink != nil {
+		if err := publishEvents(deviceStore, service.eventSink, defaultWatchBufferSize, service.logger); err != nil {
+			return nil, err
+		}
+	}
+
+	// The idempotency cache and history log are stored in Atomix, so they're only available if the devices
+	// are also stored in Atomix
+	if _, ok := deviceStore.(*atomixStore); !ok {
+		if service.historySize > 0 {
+			return nil, errors.New("the device history log requires the Atomix store")
+		}
+		return service, nil
+	}
+	requests, err := newIdempotencyCache(service.idempotencyTTL)
 	if err != nil {
 		return nil, err
 	}
-	return &Service{
-		store: deviceStore,
-	}, nil
+	service.requests = requests
+	if service.historySize > 0 {
+		history, err := newHistoryLog(service.historySize, service.historyAge, service.logger)
+		if err != nil {
+			return nil, err
+		} else if err := history.record(deviceStore); err != nil {
+			return nil, err
+		}
+		service.history = history
+	}
+	return service, nil
+}
+
+// ServiceOption is an option for configuring the device Service
+type ServiceOption func(*Service)
+
+// WithStore sets the store in which the service stores devices
+// By default, devices are stored in Atomix. Idempotency keys are ignored and the device history log can't
+// be enabled if devices are stored in any store other than an Atomix store.
+func WithStore(store Store) ServiceOption {
+	return func(service *Service) {
+		service.store = store
+	}
+}
+
+// WithReadOnly sets whether the service runs as a read-only replica
+// A read-only replica serves Get and List requests (including subscribe requests) against the shared
+// store but rejects Add, Update, and Remove requests with FailedPrecondition. Because watch events are
+// produced by the store rather than by the service, subscribers to a read-only replica still receive
+// events for changes made through any writable instance of the service.
+func WithReadOnly(readOnly bool) ServiceOption {
+	return func(service *Service) {
+		service.readOnly = readOnly
+	}
+}
+
+// WithIdempotencyTTL sets the duration for which responses to requests with an idempotency key are cached
+// A retry of an Add, Update, or Remove request with the same idempotency key within the TTL returns the
+// original response rather than applying the request again.
+func WithIdempotencyTTL(ttl time.Duration) ServiceOption {
+	return func(service *Service) {
+		service.idempotencyTTL = ttl
+	}
+}
+
+// WithPageTokenKey sets the key with which page tokens are signed
+// By default, a random key is generated for each service. Replicas of the service must share the same key
+// for clients to be able to request pages from different replicas.
+func WithPageTokenKey(key []byte) ServiceOption {
+	return func(service *Service) {
+		service.pageTokenKey = key
+	}
+}
+
+// WithIDPattern sets the pattern to which the IDs of added and renamed devices must conform
+// By default, IDs must be RFC 1123 DNS labels. If the pattern is nil, any non-empty ID is accepted.
+// The IDs of existing devices are not validated against the pattern.
+func WithIDPattern(pattern *regexp.Regexp) ServiceOption {
+	return func(service *Service) {
+		service.idPattern = pattern
+	}
+}
+
+// WithHistory enables the device history log
+// The most recent maxEvents events of each device are recorded for up to maxAge, or indefinitely if maxAge
+// is 0, and can be retrieved with GetDeviceHistory requests. The history log is disabled if maxEvents is 0.
+func WithHistory(maxEvents int, maxAge time.Duration) ServiceOption {
+	return func(service *Service) {
+		service.historySize = maxEvents
+		service.historyAge = maxAge
+	}
+}
+
+// WithLogger sets the logger to which the service and its store log
+// By default, the service logs to klog.
+func WithLogger(logger Logger) ServiceOption {
+	return func(service *Service) {
+		service.logger = logger
+	}
+}
+
+// WithWriteDeduplication sets whether updates that don't change a device are skipped
+// If enabled, an update that doesn't change any field of the stored device is not written to the store,
+// so the device version is not incremented and no event is sent to subscribers. The response carries the
+// metadata of the stored device.
+func WithWriteDeduplication(dedupeWrites bool) ServiceOption {
+	return func(service *Service) {
+		service.dedupeWrites = dedupeWrites
+	}
+}
+
+// WithSecretAccess sets whether clients may request device secrets
+// Secrets, i.e. passwords, SSH private key and token references, and TLS keys, are omitted from the devices
+// returned by the service unless requested with GetRequest.include_secrets or ListRequest.include_secrets.
+// Such requests are rejected with PermissionDenied unless secret access is allowed and the caller is authorized
+// by the secret authorizer.
+func WithSecretAccess(allowSecretAccess bool) ServiceOption {
+	return func(service *Service) {
+		service.allowSecretAccess = allowSecretAccess
+	}
+}
+
+// WithSecretAuthorizer sets the Authorizer for requests that include device secrets
+// By default, only clients that present a client certificate verified by the server are authorized, so secrets
+// are never returned by servers that don't verify client certificates.
+func WithSecretAuthorizer(authorizer admin.Authorizer) ServiceOption {
+	return func(service *Service) {
+		service.secretAuthorizer = authorizer
+	}
+}
+
+// WithForceUpdates sets whether the service accepts forced updates
+// Forced updates store the device regardless of the stored device's version and should only be enabled
+// for data migrations.
+func WithForceUpdates(allowForceUpdates bool) ServiceOption {
+	return func(service *Service) {
+		service.allowForceUpdates = allowForceUpdates
+	}
+}
+
+// WithMaxDevices sets the maximum number of devices in the store
+// Add requests are rejected with ResourceExhausted once the store holds maxDevices devices. Removed
+// devices are not counted. Because the limit is checked before a device is added, concurrent Add
+// requests may exceed the limit slightly. The number of devices is not limited if maxDevices is 0.
+func WithMaxDevices(maxDevices int) ServiceOption {
+	return func(service *Service) {
+		service.maxDevices = maxDevices
+	}
+}
+
+// WithEventSink sets a sink to which the events of the service's store are published
+// Events are published asynchronously and dropped if the sink can't keep up, so publishing never blocks
+// writes or subscribers. Devices replayed when the service starts are not published.
+func WithEventSink(sink EventSink) ServiceOption {
+	return func(service *Service) {
+		service.eventSink = sink
+	}
+}
+
+// WithValidators adds functions that validate devices before they're added or updated
+// Validators enforce deployment-specific policy in addition to the rules applied by Device.Validate. All
+// validators are run, and the request is rejected with InvalidArgument if any validator returns a violation.
+func WithValidators(validators ...ValidatorFunc) ServiceOption {
+	return func(service *Service) {
+		service.validators = append(service.validators, validators...)
+	}
+}
+
+// WithConflictRetries sets the number of times a field mask update is retried if the device is concurrently
+// modified
+// Updates that don't specify a device version are applied to the latest version of the device, so when a
+// concurrent write changes the device, the device is reloaded and the update is reapplied. The update is
+// rejected with Aborted once the retries are exhausted.
+func WithConflictRetries(retries int) ServiceOption {
+	return func(service *Service) {
+		service.conflictRetries = retries
+	}
+}
+
+// WithTracer sets the tracer used to trace the service's store operations
+// Store operations are traced as children of the span in the request context, so RPC spans created by
+// northbound.TracingUnaryInterceptor include the store operations performed by each request. Store
+// operations are not traced by default.
+func WithTracer(tracer trace.Tracer) ServiceOption {
+	return func(service *Service) {
+		service.tracer = tracer
+	}
+}
+
+// WithDeviceStats enables the collection of device access statistics
+// The reads and writes of up to maxDevices of the most accessed devices are counted in memory and can be
+// retrieved with the admin GetDeviceStats request. Statistics are not collected if maxDevices is 0.
+func WithDeviceStats(maxDevices int) ServiceOption {
+	return func(service *Service) {
+		service.statsSize = maxDevices
+	}
+}
+
+// WithSubscriberEviction enables the eviction of subscribers that can't keep up with device events
+// Up to threshold events are buffered for each subscriber, and subscribers whose buffer overflows are
+// disconnected with ResourceExhausted rather than having their oldest events dropped. Subscribers are not
+// evicted if the threshold is 0.
+func WithSubscriberEviction(threshold int) ServiceOption {
+	return func(service *Service) {
+		service.evictionThreshold = threshold
+	}
+}
+
+// WithSubscriberMetrics enables subscriber metrics, which are published in the given registry
+// The registry is typically an expvar map published at /debug/vars. The number of active subscribers is
+// published as "subscribers", and a histogram of the time between the store publishing an event and the
+// event being sent to a subscriber is published as "send_lag_seconds".
+func WithSubscriberMetrics(registry *expvar.Map) ServiceOption {
+	return func(service *Service) {
+		service.metricsRegistry = registry
+	}
+}
+
+// WithLabelIndex enables the device label index
+// The index maps label values to the devices that have them, so requests that select devices by labels load
+// only the candidate devices rather than listing all devices. The index is held in memory and is rebuilt
+// from the store when the service starts.
+func WithLabelIndex(labelIndex bool) ServiceOption {
+	return func(service *Service) {
+		service.indexLabels = labelIndex
+	}
+}
+
+// WithUniqueSerialNumbers enables the enforcement of unique device serial numbers
+// Devices can't be added or updated with the serial number of another device. Serial numbers are checked
+// against an in-memory index of the serial numbers of all devices, which is rebuilt from the store when the
+// service starts, and are reserved until the write is indexed, so concurrent writes of a serial number to the
+// same replica can't both succeed.
+func WithUniqueSerialNumbers(unique bool) ServiceOption {
+	return func(service *Service) {
+		service.uniqueSerials = unique
+	}
+}
+
+// WithUniqueAddresses enables the rejection of devices that share a management address
+// Devices can't be added, updated, cloned or compare-and-swapped to the address of another device unless the
+// Add or Update request sets allow_duplicate_address. Like serial numbers, addresses are checked against an
+// in-memory index of the addresses of all devices.
+func WithUniqueAddresses(unique bool) ServiceOption {
+	return func(service *Service) {
+		service.uniqueAddresses = unique
+	}
 }
 
 // Service is a Service implementation for administration.
 type Service struct {
 	northbound.Service
-	store Store
+	store             Store
+	requests          *idempotencyCache
+	idempotencyTTL    time.Duration
+	readOnly          bool
+	allowForceUpdates bool
+	allowSecretAccess bool
+	secretAuthorizer  admin.Authorizer
+	dedupeWrites      bool
+	pageTokenKey      []byte
+	logger            Logger
+	history           *historyLog
+	historySize       int
+	historyAge        time.Duration
+	idPattern         *regexp.Regexp
+	cache             *readCache
+	maxDevices        int
+	eventSink         EventSink
+	validators        []ValidatorFunc
+	conflictRetries   int
+	tracer            trace.Tracer
+	stats             *deviceStats
+	statsSize         int
+	evictionThreshold int
+	metricsRegistry   *expvar.Map
+	metrics           *subscriberMetrics
+	indexLabels       bool
+	labels            *labelIndex
+	uniqueSerials     bool
+	serials           *valueIndex
+	uniqueAddresses   bool
+	addresses         *valueIndex
+}
+
+// Compact removes expired idempotency keys from the service's request cache
+func (s Service) Compact(ctx context.Context, olderThan time.Duration) (map[string]uint64, error) {
+	removed := make(map[string]uint64)
+	if s.requests != nil {
+		count, err := s.requests.compact(ctx, olderThan)
+		if err != nil {
+			return nil, err
+		}
+		removed["idempotency_keys"] = count
+	}
+	if s.history != nil {
+		count, err := s.history.compact(ctx, olderThan)
+		if err != nil {
+			return nil, err
+		}
+		removed["device_history_events"] = count
+	}
+	return removed, nil
+}
+
+// Clear removes all devices and their annotations from the service's store
+func (s Service) Clear(ctx context.Context) error {
+	if s.readOnly {
+		return status.Error(codes.FailedPrecondition, "server is a read-only replica")
+	}
+	return s.store.Clear(ctx)
+}
+
+// DeviceStats returns the access statistics of the most accessed devices
+func (s Service) DeviceStats(ctx context.Context, limit int) ([]*admin.DeviceStats, time.Time, error) {
+	if s.stats == nil {
+		return nil, time.Time{}, status.Error(codes.FailedPrecondition, "device statistics are not enabled")
+	}
+	return s.stats.top(limit), s.stats.since, nil
+}
+
+// CheckHealth returns an error if the service's store is unreachable
+func (s Service) CheckHealth(ctx context.Context) error {
+	_, err := s.store.Count(ctx)
+	return err
 }
 
 // Register registers the Service with the gRPC server.
 func (s Service) Register(r *grpc.Server) {
-	server := &Server{
-		deviceStore: s.store,
+	RegisterDeviceServiceServer(r, s.newServer())
+}
+
+// newServer returns a new device Server for the Service
+func (s Service) newServer() *Server {
+	return &Server{
+		deviceStore:       s.store,
+		requests:          s.requests,
+		readOnly:          s.readOnly,
+		allowForceUpdates: s.allowForceUpdates,
+		allowSecretAccess: s.allowSecretAccess,
+		secretAuthorizer:  s.secretAuthorizer,
+		dedupeWrites:      s.dedupeWrites,
+		pageCursor:        pageCursor{key: s.pageTokenKey},
+		logger:            s.logger,
+		history:           s.history,
+		idPattern:         s.idPattern,
+		cache:             s.cache,
+		maxDevices:        s.maxDevices,
+		validators:        s.validators,
+		conflictRetries:   s.conflictRetries,
+		evictionThreshold: s.evictionThreshold,
+		metrics:           s.metrics,
+		labels:            s.labels,
+		serials:           s.serials,
+		addresses:         s.addresses,
 	}
-	RegisterDeviceServiceServer(r, server)
 }
 
 // Server implements the gRPC service for administrative facilities.
 type Server struct {
-	deviceStore Store
+	deviceStore       Store
+	requests          *idempotencyCache
+	readOnly          bool
+	allowForceUpdates bool
+	allowSecretAccess bool
+	secretAuthorizer  admin.Authorizer
+	dedupeWrites      bool
+	pageCursor        pageCursor
+	logger            Logger
+	history           *historyLog
+	idPattern         *regexp.Regexp
+	cache             *readCache
+	maxDevices        int
+	validators        []ValidatorFunc
+	conflictRetries   int
+	evictionThreshold int
+	metrics           *subscriberMetrics
+	labels            *labelIndex
+	serials           *valueIndex
+	addresses         *valueIndex
+}
+
+// checkSecretAccess returns an error if secrets are requested but secret access is not allowed or the caller
+// is not authorized to access secrets
+func (s *Server) checkSecretAccess(ctx context.Context, includeSecrets bool) error {
+	if !includeSecrets {
+		return nil
+	} else if !s.allowSecretAccess {
+		return status.Error(codes.PermissionDenied, "secret access is not enabled")
+	} else if s.secretAuthorizer == nil {
+		return status.Error(codes.PermissionDenied, "no secret authorizer configured")
+	}
+	return s.secretAuthorizer(ctx)
+}
+
+// checkWritable returns an error if the server is a read-only replica
+func (s *Server) checkWritable() error {
+	if s.readOnly {
+		return status.Error(codes.FailedPrecondition, "server is a read-only replica")
+	}
+	return nil
+}
+
+// validateID returns an error if the given ID of a new device does not match the server's ID pattern
+func (s *Server) validateID(id string) error {
+	if s.idPattern == nil {
+		return nil
+	}
+	if err := ValidateID(id, s.idPattern); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// checkCapacity returns an error if the store already holds the maximum number of devices
+func (s *Server) checkCapacity(ctx context.Context) error {
+	if s.maxDevices <= 0 {
+		return nil
+	}
+	count, err := s.deviceStore.Count(ctx)
+	if err != nil {
+		return err
+	} else if count >= s.maxDevices {
+		return status.Errorf(codes.ResourceExhausted, "maximum number of devices (%d) reached", s.maxDevices)
+	}
+	return nil
+}
+
+// notFound returns a NotFound error for the device with the given ID
+func notFound(id string) error {
+	return status.Errorf(codes.NotFound, "device %s not found", id)
+}
+
+// storeError returns an InvalidArgument error if the given store error indicates the device is too large
+// to be stored, otherwise the error is returned unchanged
+func storeError(err error) error {
+	if tooLarge, ok := err.(*ErrValueTooLarge); ok {
+		return status.Error(codes.InvalidArgument, tooLarge.Error())
+	}
+	return err
+}
+
+// loadResponse reserves the given idempotency key for a request, or loads the cached response for the key
+// If a request with the same key is being applied, loadResponse waits for its response. If the key is empty
+// or no response is cached for the key, loadResponse returns false along with the version of the reservation,
+// which must be passed to storeResponse or releaseResponse once the request is applied or fails.
+func (s *Server) loadResponse(ctx context.Context, method string, key string, response proto.Message) (bool, int64, error) {
+	if key == "" || s.requests == nil {
+		return false, 0, nil
+	}
+	return s.requests.reserve(ctx, method, key, response)
+}
+
+// storeResponse caches the response for a request with the given idempotency key
+// The request has already been applied, so failures to cache the response are logged rather than returned.
+// The response is cached under a new context, since the request's context may be done.
+func (s *Server) storeResponse(method string, key string, reservation int64, response proto.Message) {
+	if key == "" || s.requests == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := s.requests.store(ctx, method, key, reservation, response); err != nil {
+		s.logger.Warn("Failed to cache response for idempotency key", OperationField(method), Field{Key: "key", Value: key}, ErrorField(err))
+	}
+}
+
+// releaseResponse releases the reservation of the given idempotency key by a request that failed, so that the
+// request can be retried
+// If the reservation can't be released, retries wait until it expires.
+func (s *Server) releaseResponse(method string, key string, reservation int64) {
+	if key == "" || s.requests == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := s.requests.release(ctx, method, key, reservation); err != nil {
+		s.logger.Warn("Failed to release idempotency key", OperationField(method), Field{Key: "key", Value: key}, ErrorField(err))
+	}
 }
 
 func (s *Server) Add(ctx context.Context, request *AddRequest) (*AddResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	response := &AddResponse{}
+	ok, reservation, err := s.loadResponse(ctx, "add", request.IdempotencyKey, response)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return response, nil
+	}
+	response, err = s.add(ctx, request)
+	if err != nil {
+		s.releaseResponse("add", request.IdempotencyKey, reservation)
+		return nil, err
+	}
+	s.storeResponse("add", request.IdempotencyKey, reservation, response)
+	return response, nil
+}
+
+// add adds the device in the given request
+func (s *Server) add(ctx context.Context, request *AddRequest) (*AddResponse, error) {
 	device := request.Device
 	if device == nil {
 		return nil, status.Error(codes.InvalidArgument, "no device specified")
 	} else if device.Metadata != nil && device.Metadata.Version != 0 {
 		return nil, status.Error(codes.InvalidArgument, "device version is already set")
+	} else if device.Owner != nil {
+		return nil, status.Error(codes.InvalidArgument, "device owner cannot be set; use ClaimDevice")
+	} else if err := device.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := device.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(device); err != nil {
+		return nil, err
+	} else if err := s.validateID(device.Id); err != nil {
+		return nil, err
+	} else if err := s.validateParent(ctx, device); err != nil {
+		return nil, err
+	}
+	if exists, err := s.deviceStore.Exists(ctx, device.Id); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, status.Error(codes.AlreadyExists, "device already exists")
+	}
+	if err := s.checkCapacity(ctx); err != nil {
+		return nil, err
 	}
-	if err := s.deviceStore.Store(device); err != nil {
+
+	release, err := s.reserveUnique(device, nil, request.AllowDuplicateAddress)
+	if err != nil {
 		return nil, err
 	}
+
+	// The device may have been added concurrently since its existence was checked, in which case the
+	// create fails rather than overwriting the concurrently added device
+	if err := s.deviceStore.Create(ctx, device); err == ErrAlreadyExists {
+		release()
+		return nil, status.Error(codes.AlreadyExists, "device already exists")
+	} else if err != nil {
+		release()
+		return nil, storeError(err)
+	}
 	return &AddResponse{
 		Metadata: device.Metadata,
 	}, nil
 }
 
+// EnsureDevice adds the device in the given request unless it already exists and returns the stored device
+func (s *Server) EnsureDevice(ctx context.Context, request *EnsureDeviceRequest) (*EnsureDeviceResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	device := request.Device
+	if device == nil {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if device.Metadata != nil && device.Metadata.Version != 0 {
+		return nil, status.Error(codes.InvalidArgument, "device version is already set")
+	} else if device.Owner != nil {
+		return nil, status.Error(codes.InvalidArgument, "device owner cannot be set; use ClaimDevice")
+	} else if err := device.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := device.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(device); err != nil {
+		return nil, err
+	} else if err := s.validateID(device.Id); err != nil {
+		return nil, err
+	} else if err := s.validateParent(ctx, device); err != nil {
+		return nil, err
+	}
+
+	// Capacity and unique values are only checked if the device doesn't exist, since returning an existing
+	// device doesn't add to the number of stored devices
+	release := func() {}
+	if exists, err := s.deviceStore.Exists(ctx, device.Id); err != nil {
+		return nil, err
+	} else if !exists {
+		if err := s.checkCapacity(ctx); err != nil {
+			return nil, err
+		}
+		release, err = s.reserveUnique(device, nil, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stored, created, err := s.deviceStore.EnsureDevice(ctx, device)
+	if err != nil {
+		release()
+		return nil, storeError(err)
+	} else if !created {
+		release()
+	}
+	return &EnsureDeviceResponse{
+		Device:  presentDevice(stored, ListRequest_FULL, false),
+		Created: created,
+	}, nil
+}
+
 func (s *Server) Update(ctx context.Context, request *UpdateRequest) (*UpdateResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	response := &UpdateResponse{}
+	ok, reservation, err := s.loadResponse(ctx, "update", request.IdempotencyKey, response)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return response, nil
+	}
+	response, err = s.update(ctx, request)
+	if err != nil {
+		s.releaseResponse("update", request.IdempotencyKey, reservation)
+		return nil, err
+	}
+	s.storeResponse("update", request.IdempotencyKey, reservation, response)
+	return response, nil
+}
+
+// update updates the device in the given request
+func (s *Server) update(ctx context.Context, request *UpdateRequest) (*UpdateResponse, error) {
 	device := request.Device
 	if device == nil {
 		return nil, status.Error(codes.InvalidArgument, "no device specified")
-	} else if device.Metadata == nil || device.Metadata.Version == 0 {
+	} else if request.Force && !s.allowForceUpdates {
+		return nil, status.Error(codes.PermissionDenied, "forced updates are not enabled")
+	}
+	if request.UpdateMask != nil {
+		return s.updateMasked(ctx, device, request.UpdateMask, request.Force, request.AllowDuplicateAddress)
+	}
+	if request.Force {
+		return s.forceUpdate(ctx, device, request.AllowDuplicateAddress)
+	}
+	if device.Metadata == nil || device.Metadata.Version == 0 {
 		return nil, status.Error(codes.InvalidArgument, "device version not set")
+	} else if err := device.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	stored, err := s.deviceStore.Load(ctx, device.Id)
+	if err != nil {
+		return nil, err
+	} else if stored == nil {
+		return nil, notFound(device.Id)
 	}
-	if err := s.deviceStore.Store(device); err != nil {
+	retainSecrets(device, stored)
+	if err := device.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(device); err != nil {
+		return nil, err
+	} else if err := s.validateParent(ctx, device); err != nil {
+		return nil, err
+	} else if err := device.ValidateTransition(stored); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	// The owner is managed with ClaimDevice and ReleaseDevice and is retained across updates
+	device.Owner = stored.Owner
+	if s.isUnchanged(device, stored) {
+		return &UpdateResponse{
+			Metadata: stored.Metadata,
+		}, nil
+	}
+	release, err := s.reserveUnique(device, stored, request.AllowDuplicateAddress)
+	if err != nil {
 		return nil, err
 	}
+	if err := s.deviceStore.Store(ctx, device); err != nil {
+		release()
+		return nil, storeError(err)
+	}
 	return &UpdateResponse{
 		Metadata: device.Metadata,
 	}, nil
 }
 
-func (s *Server) Get(ctx context.Context, request *GetRequest) (*GetResponse, error) {
-	device, err := s.deviceStore.Load(request.DeviceId)
+// forceUpdate stores the given device regardless of the stored device's version
+func (s *Server) forceUpdate(ctx context.Context, device *Device, allowDuplicateAddress bool) (*UpdateResponse, error) {
+	if err := device.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	stored, err := s.deviceStore.Load(ctx, device.Id)
 	if err != nil {
 		return nil, err
-	} else if device == nil {
-		return nil, status.Error(codes.NotFound, "device not found")
 	}
-	return &GetResponse{
-		Device: device,
+
+	// As with other updates, the owner is managed with ClaimDevice and ReleaseDevice, so a forced update
+	// retains the owner of the stored device and a device added by a forced update has no owner
+	device.Owner = nil
+	if stored != nil {
+		retainSecrets(device, stored)
+		device.Owner = stored.Owner
+	}
+	if err := device.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(device); err != nil {
+		return nil, err
+	} else if err := s.validateParent(ctx, device); err != nil {
+		return nil, err
+	}
+	release, err := s.reserveUnique(device, stored, allowDuplicateAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deviceStore.ForcePut(ctx, device); err != nil {
+		release()
+		return nil, storeError(err)
+	}
+	return &UpdateResponse{
+		Metadata: device.Metadata,
 	}, nil
 }
 
-func (s *Server) List(request *ListRequest, server DeviceService_ListServer) error {
-	if request.Subscribe {
-		ch := make(chan *Event)
-		if err := s.deviceStore.Watch(ch); err != nil {
-			return err
-		}
-
-		for event := range ch {
-			var t ListResponse_Type
-			switch event.Type {
-			case EventNone:
-				t = ListResponse_NONE
-			case EventInserted:
-				t = ListResponse_ADDED
-			case EventUpdated:
-				t = ListResponse_UPDATED
-			case EventRemoved:
-				t = ListResponse_REMOVED
-			}
-			err := server.Send(&ListResponse{
-				Type:   t,
-				Device: event.Device,
-			})
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		ch := make(chan *Device)
-		if err := s.deviceStore.List(ch); err != nil {
-			return err
-		}
+// updateMasked applies the masked fields of the given device to the stored device
+// If the given device's version is set, the update is applied only if the stored device has the same version.
+// Otherwise, the update is applied against the version of the device that was loaded. If force is true,
+// the stored device is overwritten regardless of its version.
+func (s *Server) updateMasked(ctx context.Context, device *Device, mask *field_mask.FieldMask, force bool, allowDuplicateAddress bool) (*UpdateResponse, error) {
+	if err := validateFieldMask(mask); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-		for device := range ch {
-			err := server.Send(&ListResponse{
-				Type:   ListResponse_NONE,
-				Device: device,
-			})
-			if err != nil {
-				return err
-			}
+	// If the client did not request a specific version, the device is reloaded and the mask reapplied when
+	// the device is concurrently modified
+	for attempt := 0; ; attempt++ {
+		response, err := s.applyMasked(ctx, device, mask, force, allowDuplicateAddress)
+		if err != ErrConflict {
+			return response, err
+		} else if device.GetMetadata().GetVersion() != 0 || attempt >= s.conflictRetries {
+			return nil, status.Error(codes.Aborted, "device version has changed")
 		}
+		s.logger.Debug("Retrying update of concurrently modified device", DeviceIDField(device.Id), OperationField("update"), Field{Key: "attempt", Value: attempt + 1})
 	}
-	return nil
 }
 
-func (s *Server) Remove(ctx context.Context, request *RemoveRequest) (*RemoveResponse, error) {
-	device := request.Device
-	err := s.deviceStore.Delete(device)
+// applyMasked loads the stored device and applies the masked fields of the given device to it
+// ErrConflict is returned if the stored device is modified before the update is stored.
+func (s *Server) applyMasked(ctx context.Context, device *Device, mask *field_mask.FieldMask, force bool, allowDuplicateAddress bool) (*UpdateResponse, error) {
+	stored, err := s.deviceStore.Load(ctx, device.Id)
+	if err != nil {
+		return nil, err
+	} else if stored == nil {
+		return nil, notFound(device.Id)
+	}
+
+	if !force && device.Metadata != nil && device.Metadata.Version != 0 && device.Metadata.Version != stored.Metadata.Version {
+		return nil, status.Error(codes.Aborted, "device version has changed")
+	}
+
+	previous := proto.Clone(stored).(*Device)
+	applyFieldMask(stored, device, mask)
+	if !force {
+		if err := stored.ValidateTransition(previous); err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if err := stored.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := stored.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(stored); err != nil {
+		return nil, err
+	} else if err := s.validateParent(ctx, stored); err != nil {
+		return nil, err
+	} else if s.isUnchanged(stored, previous) {
+		return &UpdateResponse{
+			Metadata: previous.Metadata,
+		}, nil
+	}
+	release, err := s.reserveUnique(stored, previous, allowDuplicateAddress)
 	if err != nil {
 		return nil, err
 	}
-	return &RemoveResponse{}, nil
+	if force {
+		err = s.deviceStore.ForcePut(ctx, stored)
+	} else {
+		err = s.deviceStore.Store(ctx, stored)
+	}
+	if err != nil {
+		release()
+		return nil, storeError(err)
+	}
+	return &UpdateResponse{
+		Metadata: stored.Metadata,
+	}, nil
+}
+
+// modifyDevice loads the device with the given ID, applies the given change to it, and stores it
+// The change is reapplied to the reloaded device if the device is concurrently modified, so the change is
+// always applied to the version of the device that is stored. Serial numbers and addresses that the change
+// sets are reserved before the device is stored if they must be unique.
+func (s *Server) modifyDevice(ctx context.Context, id string, operation string, change func(*Device) error) error {
+	for attempt := 0; ; attempt++ {
+		stored, err := s.deviceStore.Load(ctx, id)
+		if err != nil {
+			return err
+		} else if stored == nil {
+			return notFound(id)
+		}
+		previous := &Device{SerialNumber: stored.SerialNumber, Address: stored.Address}
+		if err := change(stored); err != nil {
+			return err
+		}
+		release, err := s.reserveUnique(stored, previous, false)
+		if err != nil {
+			return err
+		}
+		err = s.deviceStore.Store(ctx, stored)
+		if err != nil {
+			release()
+		}
+		if err != ErrConflict {
+			return storeError(err)
+		} else if attempt >= s.conflictRetries {
+			return status.Error(codes.Aborted, "device version has changed")
+		}
+		s.logger.Debug("Retrying change of concurrently modified device", DeviceIDField(id), OperationField(operation), Field{Key: "attempt", Value: attempt + 1})
+	}
+}
+
+// isUnchanged returns whether write deduplication is enabled and the given device is unchanged from the
+// given stored version of the device
+// The metadata and update time are set by the store, so they're excluded from the compared hashes.
+func (s *Server) isUnchanged(device *Device, stored *Device) bool {
+	if !s.dedupeWrites || device.GetMetadata().GetVersion() != stored.GetMetadata().GetVersion() {
+		return false
+	}
+	return device.Hash() == stored.Hash()
+}
+
+// validateParent verifies that the device's parent exists and that the parent does not create a cycle
+func (s *Server) validateParent(ctx context.Context, device *Device) error {
+	visited := map[string]bool{device.Id: true}
+	parentID := device.ParentId
+	for parentID != "" {
+		if visited[parentID] {
+			return status.Error(codes.InvalidArgument, "device parent creates a cycle")
+		}
+		visited[parentID] = true
+		parent, err := s.deviceStore.Load(ctx, parentID)
+		if err != nil {
+			return err
+		} else if parent == nil {
+			return status.Errorf(codes.InvalidArgument, "parent device %s not found", parentID)
+		}
+		parentID = parent.ParentId
+	}
+	return nil
+}
+
+func (s *Server) UpdateMany(ctx context.Context, request *UpdateManyRequest) (*UpdateManyResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	} else if request.DeviceTemplate == nil {
+		return nil, status.Error(codes.InvalidArgument, "no device template specified")
+	} else if len(request.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no update mask specified")
+	} else if err := validateFieldMask(request.UpdateMask); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	selector, err := ParseSelector(request.Selector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	devices, err := s.selectDevices(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(devices))
+	for _, device := range devices {
+		ids = append(ids, device.Id)
+	}
+
+	// Each device is updated from a copy of the template without a version, so concurrent modifications
+	// are retried rather than failing the update
+	response := &UpdateManyResponse{
+		Results: make([]*UpdateManyResult, 0, len(ids)),
+	}
+	for _, id := range ids {
+		device := proto.Clone(request.DeviceTemplate).(*Device)
+		device.Id = id
+		device.Metadata = nil
+		result := &UpdateManyResult{
+			DeviceId: id,
+		}
+		if updated, err := s.updateMasked(ctx, device, request.UpdateMask, false, false); err != nil {
+			st := status.Convert(err)
+			result.Code = int32(st.Code())
+			result.Message = st.Message()
+		} else {
+			result.Metadata = updated.Metadata
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+func (s *Server) Get(ctx context.Context, request *GetRequest) (*GetResponse, error) {
+	if err := s.checkSecretAccess(ctx, request.IncludeSecrets); err != nil {
+		return nil, err
+	}
+	device, err := s.deviceStore.Load(ctx, request.DeviceId)
+	if err != nil {
+		return s.getStale(request, err)
+	} else if device == nil {
+		if s.cache != nil {
+			s.cache.removeDevice(request.DeviceId)
+		}
+		return nil, notFound(request.DeviceId)
+	}
+	annotations, err := s.deviceStore.LoadAnnotations(ctx, request.DeviceId)
+	if err != nil {
+		return s.getStale(request, err)
+	}
+	if s.cache != nil {
+		s.cache.storeDevice(device, annotations)
+	}
+	if isNotModified(request, device) {
+		return &GetResponse{
+			NotModified: true,
+		}, nil
+	}
+	return &GetResponse{
+		Device:      presentDevice(device, ListRequest_FULL, request.IncludeSecrets),
+		Annotations: annotations,
+	}, nil
+}
+
+// isNotModified returns whether the given device's version matches the version known to the client
+func isNotModified(request *GetRequest, device *Device) bool {
+	return request.KnownVersion != 0 && request.KnownVersion == device.GetMetadata().GetVersion()
+}
+
+// getStale returns the cached device for the given request if the request accepts stale reads
+// If the device is not cached or stale reads are not accepted, the given store error is returned.
+func (s *Server) getStale(request *GetRequest, err error) (*GetResponse, error) {
+	if !request.StaleOk || s.cache == nil {
+		return nil, err
+	}
+	cached, ok := s.cache.loadDevice(request.DeviceId)
+	if !ok {
+		return nil, err
+	}
+	cachedAt, tsErr := ptypes.TimestampProto(cached.cachedAt)
+	if tsErr != nil {
+		return nil, err
+	}
+	s.logger.Warn("Serving stale device", DeviceIDField(request.DeviceId), OperationField("get"), ErrorField(err))
+	if isNotModified(request, cached.device) {
+		return &GetResponse{
+			Stale:       true,
+			CachedAt:    cachedAt,
+			NotModified: true,
+		}, nil
+	}
+	return &GetResponse{
+		Device:      presentDevice(cached.device, ListRequest_FULL, request.IncludeSecrets),
+		Annotations: cached.annotations,
+		Stale:       true,
+		CachedAt:    cachedAt,
+	}, nil
+}
+
+func (s *Server) SetAnnotations(ctx context.Context, request *SetAnnotationsRequest) (*SetAnnotationsResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	annotations := request.Annotations
+	if annotations == nil || annotations.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	}
+	device, err := s.deviceStore.Load(ctx, annotations.DeviceId)
+	if err != nil {
+		return nil, err
+	} else if device == nil {
+		return nil, notFound(annotations.DeviceId)
+	}
+	if err := s.deviceStore.StoreAnnotations(ctx, annotations); err != nil {
+		return nil, err
+	}
+	return &SetAnnotationsResponse{
+		Version: annotations.Version,
+	}, nil
+}
+
+func (s *Server) List(request *ListRequest, server DeviceService_ListServer) error {
+	if err := s.checkSecretAccess(server.Context(), request.IncludeSecrets); err != nil {
+		return err
+	}
+	if request.Subscribe {
+		return s.subscribe(server.Context(), request, server.Send, nil)
+	}
+	position, err := s.resumePosition(request)
+	if err != nil {
+		return err
+	}
+	selector, err := ParseSelector(request.Selector)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The devices are sorted by ID so that an interrupted snapshot can be resumed after the last device
+	// the client received
+	var devices []*Device
+	if !selector.Empty() {
+		if devices, err = s.selectDevices(server.Context(), selector); err != nil {
+			return s.listStale(request, server, err)
+		}
+	} else {
+		ch := make(chan *Device, listBufferSize)
+		if err := s.deviceStore.List(server.Context(), ch); err != nil {
+			return s.listStale(request, server, err)
+		}
+		for device := range ch {
+			devices = append(devices, device)
+		}
+		if s.cache != nil && server.Context().Err() == nil {
+			s.cache.storeList(devices)
+		}
+		sortDevices(devices)
+	}
+
+	next := nextPosition(position)
+	for _, device := range devices {
+		if position.received(device) || !matchesFilters(device, request) {
+			continue
+		}
+		next.advance(device)
+		err := server.Send(&ListResponse{
+			Type:        ListResponse_NONE,
+			Device:      presentDevice(device, request.View, request.IncludeSecrets),
+			ResumeToken: s.pageCursor.encodeResume(next),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listStale streams the cached devices to the client if the request accepts stale reads
+// If no devices are cached or stale reads are not accepted, the given store error is returned.
+func (s *Server) listStale(request *ListRequest, server DeviceService_ListServer, err error) error {
+	if !request.StaleOk || s.cache == nil {
+		return err
+	}
+	devices, listedAt, ok := s.cache.loadList()
+	if !ok {
+		return err
+	}
+	cachedAt, tsErr := ptypes.TimestampProto(listedAt)
+	if tsErr != nil {
+		return err
+	}
+	s.logger.Warn("Serving stale devices", OperationField("list"), ErrorField(err))
+	for _, device := range devices {
+		if !matchesFilters(device, request) {
+			continue
+		}
+		err := server.Send(&ListResponse{
+			Type:     ListResponse_NONE,
+			Device:   presentDevice(device, request.View, request.IncludeSecrets),
+			Stale:    true,
+			CachedAt: cachedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) WatchAll(request *WatchAllRequest, server DeviceService_WatchAllServer) error {
+	// Devices are currently the only topology resource, so all events are device events
+	listRequest := &ListRequest{
+		Subscribe:   true,
+		FromVersion: request.FromVersion,
+	}
+	return s.subscribe(server.Context(), listRequest, func(response *ListResponse) error {
+		return server.Send(&WatchAllResponse{
+			Type: WatchAllResponse_DEVICE,
+			Event: &WatchAllResponse_Device{
+				Device: response,
+			},
+		})
+	}, nil)
+}
+
+// Subscribe streams device events for the list request that opens the stream, resending the current devices
+// when the client requests a resync
+func (s *Server) Subscribe(server DeviceService_SubscribeServer) error {
+	first, err := server.Recv()
+	if err != nil {
+		return err
+	}
+	request := first.GetList()
+	if request == nil {
+		return status.Error(codes.InvalidArgument, "the first request must be a list request")
+	}
+	if err := s.checkSecretAccess(server.Context(), request.IncludeSecrets); err != nil {
+		return err
+	}
+	request.Subscribe = true
+
+	// Resync requests are coalesced while a resync is pending. Once the client closes its side of the stream,
+	// events continue to be streamed until the server's side of the stream is closed.
+	resyncs := make(chan struct{}, 1)
+	go func() {
+		defer close(resyncs)
+		for {
+			message, err := server.Recv()
+			if err != nil {
+				return
+			}
+			if message.GetResync() == nil {
+				s.logger.Warn("Ignoring request on subscribe stream", OperationField("subscribe"))
+				continue
+			}
+			select {
+			case resyncs <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return s.subscribe(server.Context(), request, server.Send, resyncs)
+}
+
+// minHeartbeatInterval is the shortest heartbeat interval a subscriber may request
+const minHeartbeatInterval = time.Second
+
+// subscribe streams device events for the given subscribe request to the given send function
+// The current devices are resent whenever a value is received from the given resyncs channel, which may be nil.
+func (s *Server) subscribe(ctx context.Context, request *ListRequest, send func(*ListResponse) error, resyncs <-chan struct{}) error {
+	var heartbeatInterval time.Duration
+	if request.HeartbeatInterval != nil {
+		interval, err := ptypes.Duration(request.HeartbeatInterval)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		} else if interval < minHeartbeatInterval {
+			return status.Errorf(codes.InvalidArgument, "heartbeat interval must be at least %s", minHeartbeatInterval)
+		}
+		heartbeatInterval = interval
+	}
+	if request.FromVersion > 0 && !s.deviceStore.VersionsOrdered() {
+		return status.Error(codes.FailedPrecondition, "from_version is not supported when devices are sharded")
+	}
+	position, err := s.resumePosition(request)
+	if err != nil {
+		return err
+	}
+	if _, err := ParseSelector(request.Selector); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Drop the oldest events if the client can't keep up rather than blocking the store's event pipeline.
+	// Clients can detect dropped events from gaps in the event sequence numbers. If subscriber eviction is
+	// enabled, clients that can't keep up are disconnected instead.
+	// If the client is resuming a subscription, only devices changed since the given version are replayed.
+	// The devices are replayed in ID order so that an interrupted replay can be resumed with a resume token.
+	ch := make(chan *Event)
+	opts := []WatchOption{
+		WithBufferSize(listBufferSize),
+		WithOverflowPolicy(OverflowDropOldest),
+		WithAnnotations(request.Annotations),
+		WithReplayDone(request.ReplayDone),
+		WithOrderedReplay(true),
+		WithReplay(!request.SkipReplay),
+	}
+	if s.evictionThreshold > 0 {
+		opts = append(opts, WithBufferSize(s.evictionThreshold), WithOverflowPolicy(OverflowEvict))
+	}
+	if err := s.watch(ctx, request, ch, opts...); err != nil {
+		s.logger.Error("Failed to subscribe to devices", OperationField("subscribe"), VersionField(request.FromVersion), ErrorField(err))
+		return err
+	}
+	s.logger.Debug("Subscribed to devices", OperationField("subscribe"), VersionField(request.FromVersion))
+	defer s.logger.Debug("Unsubscribed from devices", OperationField("subscribe"))
+	if s.metrics != nil {
+		s.metrics.subscribers.Add(1)
+		defer s.metrics.subscribers.Add(-1)
+	}
+
+	// Heartbeats are only sent once no response has been sent for the heartbeat interval
+	var heartbeats <-chan time.Time
+	var lastSent time.Time
+	var lastSeq uint64
+	if heartbeatInterval > 0 {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
+		sendResponse := send
+		send = func(response *ListResponse) error {
+			lastSent = time.Now()
+			lastSeq = response.Seq
+			return sendResponse(response)
+		}
+	}
+
+	watermarks := make(versionWatermarks)
+	changes := make(changeDetector)
+	next := nextPosition(position)
+	var seq uint64
+	for {
+		var event *Event
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			event = e
+			seq = e.Seq
+		case _, ok := <-resyncs:
+			if !ok {
+				resyncs = nil
+				continue
+			}
+			if err := s.resync(ctx, request, send, watermarks, changes, seq); err != nil {
+				return err
+			}
+			continue
+		case <-heartbeats:
+			if time.Since(lastSent) < heartbeatInterval {
+				continue
+			}
+			if err := send(&ListResponse{Type: ListResponse_HEARTBEAT, Seq: lastSeq}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if event.Type == EventEvicted {
+			s.logger.Warn("Evicted slow subscriber", OperationField("subscribe"), Field{Key: "threshold", Value: s.evictionThreshold})
+			return status.Errorf(codes.ResourceExhausted, "subscriber fell more than %d events behind", s.evictionThreshold)
+		}
+		if event.Type == EventReplayDone {
+			if err := send(&ListResponse{Type: ListResponse_REPLAY_DONE, Seq: event.Seq}); err != nil {
+				return err
+			}
+			continue
+		}
+		if !watermarks.forward(event) {
+			continue
+		}
+
+		if event.Device != nil && !matchesFilters(event.Device, request) && (event.PrevDevice == nil || !matchesFilters(event.PrevDevice, request)) {
+			continue
+		}
+
+		// Replayed devices the client received before the replay was interrupted are recorded but not sent
+		subtype := changes.subtype(event)
+		if subtype == ListResponse_CREDENTIALS && request.ExcludeCredentialRotations {
+			continue
+		}
+		var resumeToken string
+		if event.Type == EventNone {
+			if position.received(event.Device) {
+				continue
+			}
+			next.advance(event.Device)
+			resumeToken = s.pageCursor.encodeResume(next)
+		}
+
+		var prevDevice *Device
+		if request.PrevDevice {
+			prevDevice = presentDevice(event.PrevDevice, request.View, request.IncludeSecrets)
+		}
+
+		err := send(&ListResponse{
+			Type:        eventResponseType(event.Type),
+			Device:      presentDevice(event.Device, request.View, request.IncludeSecrets),
+			Annotations: event.Annotations,
+			Seq:         event.Seq,
+			Subtype:     subtype,
+			PrevDevice:  prevDevice,
+			ResumeToken: resumeToken,
+			Version:     event.Version,
+			PrevVersion: event.PrevVersion,
+		})
+		if err != nil {
+			return err
+		}
+		if s.metrics != nil && !event.Time.IsZero() {
+			s.metrics.lag.observe(time.Since(event.Time))
+		}
+	}
+}
+
+// selectDevices returns the devices matching the given selector in ID order
+// If the label index can narrow the selector, only the candidate devices are loaded; otherwise all devices
+// are listed.
+func (s *Server) selectDevices(ctx context.Context, selector Selector) ([]*Device, error) {
+	var devices []*Device
+	if s.labels != nil {
+		if ids, ok := s.labels.lookup(selector); ok {
+			for _, id := range ids {
+				device, err := s.deviceStore.Load(ctx, id)
+				if err != nil {
+					return nil, err
+				} else if device != nil && selector.Matches(device) {
+					devices = append(devices, device)
+				}
+			}
+			return devices, nil
+		}
+	}
+
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.List(ctx, ch); err != nil {
+		return nil, err
+	}
+	for device := range ch {
+		if selector.Matches(device) {
+			devices = append(devices, device)
+		}
+	}
+	sortDevices(devices)
+	return devices, nil
+}
+
+// resync sends the current devices matching the given request to a subscriber followed by a REPLAY_DONE response
+// The resent devices are recorded in the subscriber's watermarks, so events that are already queued for the
+// subscriber and are reflected in the resent devices are not sent after the resync. The responses carry the
+// given sequence number of the last event sent to the subscriber.
+func (s *Server) resync(ctx context.Context, request *ListRequest, send func(*ListResponse) error, watermarks versionWatermarks, changes changeDetector, seq uint64) error {
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.List(ctx, ch); err != nil {
+		s.logger.Error("Failed to resync subscriber", OperationField("resync"), ErrorField(err))
+		return err
+	}
+	var devices []*Device
+	for device := range ch {
+		if matchesFilters(device, request) {
+			devices = append(devices, device)
+		}
+	}
+	sortDevices(devices)
+	s.logger.Debug("Resyncing subscriber", OperationField("resync"), Field{Key: "devices", Value: len(devices)})
+
+	for _, device := range devices {
+		event := &Event{
+			Type:    EventNone,
+			Device:  device,
+			Seq:     seq,
+			Version: device.GetMetadata().GetVersion(),
+		}
+		watermarks.forward(event)
+		err := send(&ListResponse{
+			Type:    ListResponse_NONE,
+			Device:  presentDevice(device, request.View, request.IncludeSecrets),
+			Seq:     seq,
+			Subtype: changes.subtype(event),
+			Version: event.Version,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return send(&ListResponse{Type: ListResponse_REPLAY_DONE, Seq: seq})
+}
+
+// watch starts the store watch for the given subscription
+// Subscriptions to specific devices watch only those devices. The store replays the current state of each
+// watched device, so devices that haven't changed since the subscription's from_version are skipped here.
+func (s *Server) watch(ctx context.Context, request *ListRequest, ch chan<- *Event, opts ...WatchOption) error {
+	if len(request.DeviceIds) == 0 {
+		return s.deviceStore.WatchFrom(ctx, request.FromVersion, ch, opts...)
+	}
+	if request.FromVersion == 0 {
+		return s.deviceStore.WatchDevices(ctx, request.DeviceIds, ch, opts...)
+	}
+	eventCh := make(chan *Event)
+	if err := s.deviceStore.WatchDevices(ctx, request.DeviceIds, eventCh, opts...); err != nil {
+		return err
+	}
+	go func() {
+		defer close(ch)
+		for event := range eventCh {
+			if event.Type == EventNone && event.Device.Metadata.Version <= request.FromVersion {
+				continue
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return nil
+}
+
+// matchesFilters returns whether the given device matches the ID, label, asset, connection state and
+// maintenance filters of the given request
+// The request's selector must have been validated.
+func matchesFilters(device *Device, request *ListRequest) bool {
+	if request.ExcludeQuiesced && device.IsQuiesced() {
+		return false
+	}
+	if request.Selector != "" {
+		if selector, err := ParseSelector(request.Selector); err != nil || !selector.Matches(device) {
+			return false
+		}
+	}
+	if (request.Vendor != "" && device.Vendor != request.Vendor) || (request.Model != "" && device.Model != request.Model) {
+		return false
+	}
+	if len(request.DeviceIds) > 0 && !matchesIDs(device, request.DeviceIds) {
+		return false
+	}
+	return matchesStates(device, request.States)
+}
+
+// matchesIDs returns whether the given device has any of the given IDs
+func matchesIDs(device *Device, ids []string) bool {
+	for _, id := range ids {
+		if id == device.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesStates returns whether the given device is in any of the given connection states
+// If no states are given, all devices match.
+func matchesStates(device *Device, states []ConnectionState) bool {
+	if len(states) == 0 {
+		return true
+	}
+	state := device.GetConnectionStatus().GetState()
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// versionWatermarks tracks the versions of devices replayed to a subscriber
+// The store registers a watcher before taking the snapshot of devices to replay, so a change that occurs
+// while the snapshot is being taken may be both reflected in the snapshot and delivered as a live event.
+// The watermark of each replayed device is used to filter out live events for versions of the device the
+// subscriber has already received, ensuring each version is sent exactly once.
+type versionWatermarks map[string]uint64
+
+// forward returns whether the given event should be forwarded to the subscriber
+func (w versionWatermarks) forward(event *Event) bool {
+	if event.Device == nil || event.Device.Metadata == nil {
+		return true
+	}
+	id := event.Device.Id
+	version := event.Device.Metadata.Version
+	if event.Type == EventNone {
+		w[id] = version
+		return true
+	}
+
+	watermark, ok := w[id]
+	if !ok {
+		return true
+	}
+
+	// A remove event carries the version of the removed device, so it's forwarded if the replayed
+	// version was removed. Any other event is forwarded only if it's newer than the replayed version.
+	if version < watermark || (version == watermark && event.Type != EventRemoved) {
+		return false
+	}
+
+	// Once a newer event has been forwarded, all subsequent events for the device are newer as well
+	delete(w, id)
+	return true
+}
+
+func (s *Server) Remove(ctx context.Context, request *RemoveRequest) (*RemoveResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.Device == nil {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	}
+	response := &RemoveResponse{}
+	ok, reservation, err := s.loadResponse(ctx, "remove", request.IdempotencyKey, response)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return response, nil
+	}
+	if err := s.deviceStore.Delete(ctx, request.Device); err != nil {
+		s.releaseResponse("remove", request.IdempotencyKey, reservation)
+		if err == ErrNotFound {
+			return nil, notFound(request.Device.Id)
+		}
+		return nil, err
+	}
+	s.storeResponse("remove", request.IdempotencyKey, reservation, response)
+	return response, nil
+}
+
+// RotateCredentials replaces the credentials of a device
+// Neither the old nor the new credentials are logged or returned in the response.
+func (s *Server) RotateCredentials(ctx context.Context, request *RotateCredentialsRequest) (*RotateCredentialsResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	} else if request.Credentials == nil {
+		return nil, status.Error(codes.InvalidArgument, "no credentials specified")
+	}
+
+	device, err := s.deviceStore.Load(ctx, request.DeviceId)
+	if err != nil {
+		return nil, err
+	} else if device == nil {
+		return nil, notFound(request.DeviceId)
+	} else if request.Version != 0 && request.Version != device.Metadata.Version {
+		return nil, status.Error(codes.Aborted, "device version has changed")
+	}
+
+	device.Credentials = request.Credentials
+	if err := device.NormalizeCredentials(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := device.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err := s.runValidators(device); err != nil {
+		return nil, err
+	}
+	if err := s.deviceStore.Store(ctx, device); err != nil {
+		return nil, storeError(err)
+	}
+	return &RotateCredentialsResponse{
+		Metadata: device.Metadata,
+	}, nil
+}
+
+func (s *Server) ListPage(ctx context.Context, request *ListPageRequest) (*ListPageResponse, error) {
+	pageSize := int(request.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	} else if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var lastID string
+	if request.PageToken != "" {
+		id, err := s.pageCursor.decode(request.PageToken)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		lastID = id
+	}
+
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.List(ctx, ch); err != nil {
+		return nil, err
+	}
+
+	// Devices are paged in ID order starting after the last device of the previous page, so devices
+	// that sort before the cursor are never returned again and devices that sort after it are never skipped
+	devices := make([]*Device, 0, pageSize)
+	for device := range ch {
+		if device.Id > lastID {
+			devices = append(devices, redactSecrets(device))
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Id < devices[j].Id
+	})
+
+	response := &ListPageResponse{}
+	if len(devices) > pageSize {
+		devices = devices[:pageSize]
+		response.NextPageToken = s.pageCursor.encode(devices[pageSize-1].Id)
+	}
+	response.Devices = devices
+	return response, nil
+}
+
+func (s *Server) Rename(ctx context.Context, request *RenameRequest) (*RenameResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.DeviceId == "" || request.NewId == "" {
+		return nil, status.Error(codes.InvalidArgument, "device ID and new ID are required")
+	} else if request.DeviceId == request.NewId {
+		return nil, status.Error(codes.InvalidArgument, "new ID is the same as the device ID")
+	} else if err := s.validateID(request.NewId); err != nil {
+		return nil, err
+	}
+
+	device, err := s.deviceStore.Load(ctx, request.DeviceId)
+	if err != nil {
+		return nil, err
+	} else if device == nil {
+		return nil, notFound(request.DeviceId)
+	} else if request.Version != 0 && request.Version != device.Metadata.Version {
+		return nil, status.Error(codes.Aborted, "device version has changed")
+	}
+
+	if exists, err := s.deviceStore.Exists(ctx, request.NewId); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, status.Error(codes.AlreadyExists, "device already exists")
+	}
+
+	if err := s.deviceStore.Rename(ctx, device, request.NewId); err == ErrNotFound {
+		return nil, notFound(request.DeviceId)
+	} else if err == ErrAlreadyExists {
+		return nil, status.Error(codes.AlreadyExists, "device already exists")
+	} else if err != nil {
+		return nil, storeError(err)
+	}
+	return &RenameResponse{
+		Device: presentDevice(device, ListRequest_FULL, false),
+	}, nil
+}
+
+// CloneDevice adds a copy of the source device with the new ID and the masked override fields
+func (s *Server) CloneDevice(ctx context.Context, request *CloneDeviceRequest) (*CloneDeviceResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.SourceId == "" || request.NewId == "" {
+		return nil, status.Error(codes.InvalidArgument, "source ID and new ID are required")
+	} else if request.Overrides != nil && len(request.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "overrides require an update mask")
+	} else if request.UpdateMask != nil {
+		if err := validateFieldMask(request.UpdateMask); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	source, err := s.deviceStore.Load(ctx, request.SourceId)
+	if err != nil {
+		return nil, err
+	} else if source == nil {
+		return nil, notFound(request.SourceId)
+	}
+
+	// The stored source device is decoded for this request, so it's modified in place to become the copy
+	device := source
+	device.Id = request.NewId
+	device.Metadata = nil
+	device.Owner = nil
+	device.LifecycleStatus = nil
+	device.ConnectionStatus = nil
+	device.Maintenance = nil
+	device.SerialNumber = ""
+	if request.UpdateMask != nil {
+		applyFieldMask(device, request.Overrides, request.UpdateMask)
+	}
+
+	if _, err := s.add(ctx, &AddRequest{Device: device}); err != nil {
+		return nil, err
+	}
+	return &CloneDeviceResponse{
+		Device: presentDevice(device, ListRequest_FULL, false),
+	}, nil
+}
+
+func (s *Server) SwapAddresses(ctx context.Context, request *SwapAddressesRequest) (*SwapAddressesResponse, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if request.FirstDeviceId == "" || request.SecondDeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "both device IDs are required")
+	} else if request.FirstDeviceId == request.SecondDeviceId {
+		return nil, status.Error(codes.InvalidArgument, "device IDs must be different")
+	}
+
+	for _, id := range []string{request.FirstDeviceId, request.SecondDeviceId} {
+		if exists, err := s.deviceStore.Exists(ctx, id); err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, notFound(id)
+		}
+	}
+
+	first, second, err := s.deviceStore.SwapAddresses(ctx, request.FirstDeviceId, request.SecondDeviceId)
+	if err == ErrNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, storeError(err)
+	}
+	return &SwapAddressesResponse{
+		FirstDevice:  presentDevice(first, ListRequest_FULL, false),
+		SecondDevice: presentDevice(second, ListRequest_FULL, false),
+	}, nil
+}
+
+func (s *Server) ListChildren(ctx context.Context, request *ListChildrenRequest) (*ListChildrenResponse, error) {
+	devices, err := s.deviceStore.ListChildren(ctx, request.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	for i, device := range devices {
+		devices[i] = redactSecrets(device)
+	}
+	return &ListChildrenResponse{
+		Devices: devices,
+	}, nil
+}
+
+// ListModifiedSince streams the devices modified since the requested time
+// If the time is not set, all devices are streamed.
+func (s *Server) ListModifiedSince(request *ListModifiedSinceRequest, server DeviceService_ListModifiedSinceServer) error {
+	if err := s.checkSecretAccess(server.Context(), request.IncludeSecrets); err != nil {
+		return err
+	}
+	var since time.Time
+	if request.Since != nil {
+		var err error
+		if since, err = ptypes.Timestamp(request.Since); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.ListModifiedSince(server.Context(), since, ch); err != nil {
+		return err
+	}
+	for device := range ch {
+		err := server.Send(&ListResponse{
+			Type:   ListResponse_NONE,
+			Device: presentDevice(device, request.View, request.IncludeSecrets),
+		})
+		if err != nil {
+			// Drain the remaining devices so the store's listing goroutine isn't blocked
+			go func() {
+				for range ch {
+				}
+			}()
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) GetDeviceHistory(ctx context.Context, request *GetDeviceHistoryRequest) (*GetDeviceHistoryResponse, error) {
+	if s.history == nil {
+		return nil, status.Error(codes.FailedPrecondition, "device history is not enabled")
+	} else if request.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device specified")
+	}
+	events, err := s.history.load(ctx, request.DeviceId)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		event.Device = redactSecrets(event.Device)
+	}
+	return &GetDeviceHistoryResponse{
+		Events: events,
+	}, nil
+}
+
+func (s *Server) SearchDevices(ctx context.Context, request *SearchDevicesRequest) (*SearchDevicesResponse, error) {
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.List(ctx, ch); err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(request.Query)
+	devices := make([]*Device, 0)
+	for device := range ch {
+		if matchesQuery(device, query) {
+			devices = append(devices, redactSecrets(device))
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Id < devices[j].Id
+	})
+	return &SearchDevicesResponse{
+		Devices: devices,
+	}, nil
+}
+
+// matchesQuery returns whether any of the searchable fields of the given device contains the given
+// lower case query
+func matchesQuery(device *Device, query string) bool {
+	fields := append([]string{device.Id, device.Address, device.Target, device.SoftwareVersion}, device.Addresses...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
 }
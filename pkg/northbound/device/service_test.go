@@ -0,0 +1,935 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestServer returns a device Server backed by the given store
+func newTestServer(t *testing.T, store Store, opts ...ServiceOption) *Server {
+	t.Helper()
+	service, err := NewService(append([]ServiceOption{WithStore(store), WithLogger(NewNopLogger())}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service.(*Service).newServer()
+}
+
+// newTestAtomixServer returns a device Server backed by an Atomix store with in-memory maps
+// The service creates its idempotency cache in Atomix if its store is an Atomix store, so the server is
+// created with a local store that's then replaced.
+func newTestAtomixServer(t *testing.T) (*Server, *atomixStore) {
+	t.Helper()
+	store, _ := newTestAtomixStore()
+	server := newTestServer(t, NewLocalStore())
+	server.deviceStore = store
+	return server, store
+}
+
+// newTestDevice returns a valid device with the given ID
+func newTestDevice(id string) *Device {
+	return &Device{
+		Id:              id,
+		Address:         id + ":5150",
+		Target:          id,
+		SoftwareVersion: "1.0.0",
+	}
+}
+
+func TestHandlerDeadlines(t *testing.T) {
+	const latency = 500 * time.Millisecond
+	store := NewLocalStore(WithLatency(latency))
+	server := newTestServer(t, store)
+	stored := newTestDevice("device-1")
+	if err := store.Store(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{
+			name: "get",
+			call: func(ctx context.Context) error {
+				_, err := server.Get(ctx, &GetRequest{DeviceId: stored.Id})
+				return err
+			},
+		},
+		{
+			name: "add",
+			call: func(ctx context.Context) error {
+				_, err := server.Add(ctx, &AddRequest{Device: newTestDevice("device-2")})
+				return err
+			},
+		},
+		{
+			name: "update",
+			call: func(ctx context.Context) error {
+				device := newTestDevice(stored.Id)
+				device.Metadata = stored.Metadata
+				device.SoftwareVersion = "1.0.1"
+				_, err := server.Update(ctx, &UpdateRequest{Device: device})
+				return err
+			},
+		},
+		{
+			name: "remove",
+			call: func(ctx context.Context) error {
+				_, err := server.Remove(ctx, &RemoveRequest{Device: stored})
+				return err
+			},
+		},
+		{
+			name: "list page",
+			call: func(ctx context.Context) error {
+				_, err := server.ListPage(ctx, &ListPageRequest{})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name+"/deadline", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			assertCanceled(ctx, t, test.call, latency, codes.DeadlineExceeded)
+		})
+		t.Run(test.name+"/cancel", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			assertCanceled(ctx, t, test.call, latency, codes.Canceled)
+		})
+	}
+}
+
+// assertCanceled asserts that the given call returns the given code once the given context is done, well
+// before the store's latency has elapsed
+func assertCanceled(ctx context.Context, t *testing.T, call func(context.Context) error, latency time.Duration, code codes.Code) {
+	t.Helper()
+	start := time.Now()
+	err := call(ctx)
+	if elapsed := time.Since(start); elapsed >= latency/2 {
+		t.Errorf("call returned after %s; the store call was not canceled", elapsed)
+	}
+	if err == nil {
+		t.Fatal("expected the call to fail")
+	}
+	if actual := status.Code(err); actual != code && err != ctx.Err() {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	servers := map[string]func(t *testing.T) *Server{
+		"local": func(t *testing.T) *Server {
+			return newTestServer(t, NewLocalStore())
+		},
+		"atomix": func(t *testing.T) *Server {
+			server, _ := newTestAtomixServer(t)
+			return server
+		},
+	}
+	tests := []struct {
+		name string
+		call func(server *Server) error
+	}{
+		{
+			name: "get",
+			call: func(server *Server) error {
+				_, err := server.Get(context.Background(), &GetRequest{DeviceId: "missing"})
+				return err
+			},
+		},
+		{
+			name: "update",
+			call: func(server *Server) error {
+				device := newTestDevice("missing")
+				device.Metadata = &ObjectMetadata{Id: device.Id, Version: 1}
+				_, err := server.Update(context.Background(), &UpdateRequest{Device: device})
+				return err
+			},
+		},
+		{
+			name: "remove",
+			call: func(server *Server) error {
+				_, err := server.Remove(context.Background(), &RemoveRequest{Device: newTestDevice("missing")})
+				return err
+			},
+		},
+	}
+	for storeName, newServer := range servers {
+		for _, test := range tests {
+			t.Run(storeName+"/"+test.name, func(t *testing.T) {
+				err := test.call(newServer(t))
+				if code := status.Code(err); code != codes.NotFound {
+					t.Fatalf("expected %s, got %v", codes.NotFound, err)
+				}
+				if !strings.Contains(status.Convert(err).Message(), "missing") {
+					t.Errorf("expected the device ID in the message, got %q", status.Convert(err).Message())
+				}
+			})
+		}
+	}
+}
+
+func TestConcurrentAdd(t *testing.T) {
+	servers := map[string]func(t *testing.T) *Server{
+		"local": func(t *testing.T) *Server {
+			return newTestServer(t, NewLocalStore())
+		},
+		"atomix": func(t *testing.T) *Server {
+			server, store := newTestAtomixServer(t)
+			store.devices.(*testMap).latency = time.Millisecond
+			return server
+		},
+	}
+	const adds = 50
+	for name, newServer := range servers {
+		t.Run(name, func(t *testing.T) {
+			server := newServer(t)
+
+			// Every add of the same ID starts at once, so exactly one must win
+			start := make(chan struct{})
+			errs := make(chan error, adds)
+			var wg sync.WaitGroup
+			for i := 0; i < adds; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					_, err := server.Add(context.Background(), &AddRequest{Device: newTestDevice("device-1")})
+					errs <- err
+				}()
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+
+			added := 0
+			for err := range errs {
+				if err == nil {
+					added++
+				} else if code := status.Code(err); code != codes.AlreadyExists {
+					t.Errorf("expected %s, got %v", codes.AlreadyExists, err)
+				}
+			}
+			if added != 1 {
+				t.Errorf("expected exactly one add to succeed, got %d", added)
+			}
+		})
+	}
+}
+
+// newSecretDevice returns a valid device with the given ID, credential and TLS key
+func newSecretDevice(id string, credentials *Credentials) *Device {
+	device := newTestDevice(id)
+	device.Credentials = credentials
+	device.Tls = &TlsConfig{Cert: "cert", Key: "key"}
+	return device
+}
+
+func TestUpdateRetainsSecrets(t *testing.T) {
+	basicAuth := func(password string) *Credentials {
+		return &Credentials{Credential: &Credentials_BasicAuth{BasicAuth: &BasicAuth{User: "user", Password: password}}}
+	}
+	sshKey := func(ref string) *Credentials {
+		return &Credentials{Credential: &Credentials_SshKey{SshKey: &SshKey{User: "user", PrivateKeyRef: ref}}}
+	}
+	token := func(ref string) *Credentials {
+		return &Credentials{Credential: &Credentials_Token{Token: &Token{ValueRef: ref}}}
+	}
+	tests := []struct {
+		name     string
+		stored   *Credentials
+		update   func(device *Device)
+		mask     *field_mask.FieldMask
+		force    bool
+		expected *Device
+	}{
+		{
+			name:     "basic auth",
+			stored:   basicAuth("secret"),
+			expected: newSecretDevice("device-1", basicAuth("secret")),
+		},
+		{
+			name:     "ssh key",
+			stored:   sshKey("secret"),
+			expected: newSecretDevice("device-1", sshKey("secret")),
+		},
+		{
+			name:     "token",
+			stored:   token("secret"),
+			expected: newSecretDevice("device-1", token("secret")),
+		},
+		{
+			name:     "forced",
+			stored:   basicAuth("secret"),
+			force:    true,
+			expected: newSecretDevice("device-1", basicAuth("secret")),
+		},
+		{
+			name:   "new secret",
+			stored: basicAuth("secret"),
+			update: func(device *Device) {
+				device.Credentials.GetBasicAuth().Password = "new"
+				device.Tls.Key = "new"
+			},
+			expected: func() *Device {
+				device := newSecretDevice("device-1", basicAuth("new"))
+				device.Tls.Key = "new"
+				return device
+			}(),
+		},
+		{
+			name:   "changed credential",
+			stored: basicAuth("secret"),
+			update: func(device *Device) {
+				device.Credentials = token("token")
+			},
+			expected: newSecretDevice("device-1", token("token")),
+		},
+		{
+			name:   "removed by mask",
+			stored: basicAuth("secret"),
+			mask:   &field_mask.FieldMask{Paths: []string{"credentials", "tls"}},
+			expected: func() *Device {
+				device := newSecretDevice("device-1", basicAuth(""))
+				device.Tls.Key = ""
+				return device
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, NewLocalStore(), WithForceUpdates(true))
+			ctx := context.Background()
+			if _, err := server.Add(ctx, &AddRequest{Device: newSecretDevice("device-1", test.stored)}); err != nil {
+				t.Fatal(err)
+			}
+
+			// The device is updated as it's returned to a client that didn't request secrets
+			response, err := server.Get(ctx, &GetRequest{DeviceId: "device-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			device := response.Device
+			if test.update != nil {
+				test.update(device)
+			}
+			if test.force {
+				device.Metadata = nil
+			}
+			if _, err := server.Update(ctx, &UpdateRequest{Device: device, UpdateMask: test.mask, Force: test.force}); err != nil {
+				t.Fatal(err)
+			}
+
+			stored, err := server.deviceStore.Load(ctx, "device-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Credentials.String() != test.expected.Credentials.String() {
+				t.Errorf("expected credentials %v, got %v", test.expected.Credentials, stored.Credentials)
+			}
+			if stored.Tls.String() != test.expected.Tls.String() {
+				t.Errorf("expected TLS %v, got %v", test.expected.Tls, stored.Tls)
+			}
+		})
+	}
+}
+
+func TestUpdateRetainsOwner(t *testing.T) {
+	tests := []struct {
+		name  string
+		force bool
+	}{
+		{
+			name: "update",
+		},
+		{
+			name:  "forced",
+			force: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t, NewLocalStore(), WithForceUpdates(true))
+			if _, err := server.Add(ctx, &AddRequest{Device: newTestDevice("device-1")}); err != nil {
+				t.Fatal(err)
+			}
+			claimed, err := server.ClaimDevice(ctx, &ClaimDeviceRequest{
+				DeviceId:      "device-1",
+				OwnerId:       "controller-1",
+				LeaseDuration: ptypes.DurationProto(time.Minute),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			device := newTestDevice("device-1")
+			device.Target = "updated"
+			device.Metadata = claimed.Metadata
+			if _, err := server.Update(ctx, &UpdateRequest{Device: device, Force: test.force}); err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := server.Get(ctx, &GetRequest{DeviceId: "device-1"})
+			if err != nil {
+				t.Fatal(err)
+			} else if response.Device.Target != "updated" {
+				t.Errorf("expected target updated, got %s", response.Device.Target)
+			} else if !proto.Equal(response.Device.Owner, claimed.Owner) {
+				t.Errorf("expected owner %v, got %v", claimed.Owner, response.Device.Owner)
+			}
+		})
+	}
+}
+
+func TestRotateCredentialsValidators(t *testing.T) {
+	// The validator rejects devices that are accessed as root
+	validator := func(device *Device) []FieldViolation {
+		if device.GetCredentials().GetBasicAuth().GetUser() == "root" {
+			return []FieldViolation{{Field: "credentials.basic_auth.user", Description: "root access is not allowed"}}
+		}
+		return nil
+	}
+	tests := []struct {
+		name string
+		user string
+		code codes.Code
+	}{
+		{
+			name: "valid",
+			user: "admin",
+			code: codes.OK,
+		},
+		{
+			name: "invalid",
+			user: "root",
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t, NewLocalStore(), WithValidators(validator))
+			if _, err := server.Add(ctx, &AddRequest{Device: newTestDevice("device-1")}); err != nil {
+				t.Fatal(err)
+			}
+			_, err := server.RotateCredentials(ctx, &RotateCredentialsRequest{
+				DeviceId: "device-1",
+				Credentials: &Credentials{
+					Credential: &Credentials_BasicAuth{BasicAuth: &BasicAuth{User: test.user, Password: "secret"}},
+				},
+			})
+			if code := status.Code(err); code != test.code {
+				t.Errorf("expected %s, got %s", test.code, code)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup is called before the requests are sent
+		setup func(ctx context.Context, server *Server) error
+		// requests are the requests sent concurrently with the same idempotency key
+		requests []func(ctx context.Context, server *Server) error
+		// codes are the expected codes of the requests in code order
+		codes []codes.Code
+		// after is a request sent with the same key once the other requests are complete
+		after func(ctx context.Context, server *Server) error
+		code  codes.Code
+	}{
+		{
+			name: "concurrent adds",
+			requests: []func(ctx context.Context, server *Server) error{
+				addWithKey("device-1", "key"),
+				addWithKey("device-1", "key"),
+			},
+			codes: []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name: "retried add",
+			requests: []func(ctx context.Context, server *Server) error{
+				addWithKey("device-1", "key"),
+			},
+			codes: []codes.Code{codes.OK},
+			after: addWithKey("device-1", "key"),
+			code:  codes.OK,
+		},
+		{
+			name: "failed remove",
+			requests: []func(ctx context.Context, server *Server) error{
+				removeWithKey("device-1", "key"),
+			},
+			codes: []codes.Code{codes.NotFound},
+			// The failed request releases the key, so the retry is applied once the device is added
+			after: func(ctx context.Context, server *Server) error {
+				if err := addWithKey("device-1", "")(ctx, server); err != nil {
+					return err
+				}
+				return removeWithKey("device-1", "key")(ctx, server)
+			},
+			code: codes.OK,
+		},
+		{
+			name: "different keys",
+			requests: []func(ctx context.Context, server *Server) error{
+				addWithKey("device-1", "key-1"),
+				addWithKey("device-1", "key-2"),
+			},
+			codes: []codes.Code{codes.OK, codes.AlreadyExists},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			// The latency of the store widens the window in which the requests are applied concurrently
+			server := newTestServer(t, NewLocalStore(WithLatency(20*time.Millisecond)))
+			server.requests = &idempotencyCache{
+				requests:    newTestMap("device-requests"),
+				reserveLock: newTestLock(),
+				ttl:         defaultIdempotencyTTL,
+			}
+
+			results := make(chan codes.Code, len(test.requests))
+			for _, request := range test.requests {
+				go func(request func(ctx context.Context, server *Server) error) {
+					results <- status.Code(request(ctx, server))
+				}(request)
+			}
+			var got []codes.Code
+			for range test.requests {
+				got = append(got, <-results)
+			}
+			sort.Slice(got, func(i, j int) bool {
+				return got[i] < got[j]
+			})
+			if !reflect.DeepEqual(got, test.codes) {
+				t.Errorf("expected %v, got %v", test.codes, got)
+			}
+
+			if test.after != nil {
+				if code := status.Code(test.after(ctx, server)); code != test.code {
+					t.Errorf("expected %s, got %s", test.code, code)
+				}
+			}
+		})
+	}
+}
+
+// addWithKey returns a request adding a test device with the given ID and idempotency key
+func addWithKey(id string, key string) func(ctx context.Context, server *Server) error {
+	return func(ctx context.Context, server *Server) error {
+		_, err := server.Add(ctx, &AddRequest{Device: newTestDevice(id), IdempotencyKey: key})
+		return err
+	}
+}
+
+// removeWithKey returns a request removing the device with the given ID with the given idempotency key
+func removeWithKey(id string, key string) func(ctx context.Context, server *Server) error {
+	return func(ctx context.Context, server *Server) error {
+		_, err := server.Remove(ctx, &RemoveRequest{Device: &Device{Id: id}, IdempotencyKey: key})
+		return err
+	}
+}
+
+func TestSecretAccess(t *testing.T) {
+	verified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}},
+	})
+	unverified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{},
+	})
+	tests := []struct {
+		name    string
+		opts    []ServiceOption
+		ctx     context.Context
+		secrets bool
+		code    codes.Code
+	}{
+		{name: "disabled", ctx: verified, secrets: true, code: codes.PermissionDenied},
+		{name: "no peer", opts: []ServiceOption{WithSecretAccess(true)}, ctx: context.Background(), secrets: true, code: codes.Unauthenticated},
+		{name: "unverified client", opts: []ServiceOption{WithSecretAccess(true)}, ctx: unverified, secrets: true, code: codes.PermissionDenied},
+		{name: "verified client", opts: []ServiceOption{WithSecretAccess(true)}, ctx: verified, secrets: true, code: codes.OK},
+		{name: "redacted", opts: []ServiceOption{WithSecretAccess(true)}, ctx: verified, code: codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, NewLocalStore(), test.opts...)
+			device := newSecretDevice("device-1", &Credentials{Credential: &Credentials_BasicAuth{BasicAuth: &BasicAuth{User: "user", Password: "secret"}}})
+			if _, err := server.Add(context.Background(), &AddRequest{Device: device}); err != nil {
+				t.Fatal(err)
+			}
+			response, err := server.Get(test.ctx, &GetRequest{DeviceId: device.Id, IncludeSecrets: test.secrets})
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			} else if err != nil {
+				return
+			}
+			password := response.Device.Credentials.GetBasicAuth().GetPassword()
+			if test.secrets && password != "secret" {
+				t.Errorf("expected the password to be included")
+			} else if !test.secrets && password != "" {
+				t.Errorf("expected the password to be redacted")
+			}
+		})
+	}
+}
+
+func TestUniqueValues(t *testing.T) {
+	withSerial := func(id string, serial string) *Device {
+		device := newTestDevice(id)
+		device.SerialNumber = serial
+		return device
+	}
+	withAddress := func(id string, address string) *Device {
+		device := newTestDevice(id)
+		device.Address = address
+		return device
+	}
+	tests := []struct {
+		name  string
+		opts  []ServiceOption
+		first *Device
+		add   func(i int) *AddRequest
+		// added is the number of the concurrent adds that must succeed
+		added int
+	}{
+		{
+			name:  "serial number",
+			opts:  []ServiceOption{WithUniqueSerialNumbers(true)},
+			first: withSerial("device-0", "serial-1"),
+			add: func(i int) *AddRequest {
+				return &AddRequest{Device: withSerial(fmt.Sprintf("device-%d", i+1), "serial-1")}
+			},
+		},
+		{
+			name:  "address",
+			opts:  []ServiceOption{WithUniqueAddresses(true)},
+			first: withAddress("device-0", "address:5150"),
+			add: func(i int) *AddRequest {
+				return &AddRequest{Device: withAddress(fmt.Sprintf("device-%d", i+1), "address:5150")}
+			},
+		},
+		{
+			name:  "allowed duplicate address",
+			opts:  []ServiceOption{WithUniqueAddresses(true)},
+			first: withAddress("device-0", "address:5150"),
+			add: func(i int) *AddRequest {
+				return &AddRequest{Device: withAddress(fmt.Sprintf("device-%d", i+1), "address:5150"), AllowDuplicateAddress: true}
+			},
+			added: 10,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name+"/sequential", func(t *testing.T) {
+			server := newTestServer(t, NewLocalStore(), test.opts...)
+			ctx := context.Background()
+			if _, err := server.Add(ctx, &AddRequest{Device: test.first}); err != nil {
+				t.Fatal(err)
+			}
+			_, err := server.Add(ctx, test.add(0))
+			if test.added > 0 {
+				if err != nil {
+					t.Fatalf("expected the duplicate to be allowed, got %v", err)
+				}
+			} else if code := status.Code(err); code != codes.AlreadyExists {
+				t.Fatalf("expected %s, got %v", codes.AlreadyExists, err)
+			}
+		})
+		t.Run(test.name+"/concurrent", func(t *testing.T) {
+			// The latency of the store widens the window between each add's check of the value and its write
+			server := newTestServer(t, NewLocalStore(WithLatency(10*time.Millisecond)), test.opts...)
+			const adds = 10
+			start := make(chan struct{})
+			errs := make(chan error, adds)
+			var wg sync.WaitGroup
+			for i := 0; i < adds; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					_, err := server.Add(context.Background(), test.add(i))
+					errs <- err
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+
+			expected := test.added
+			if expected == 0 {
+				expected = 1
+			}
+			added := 0
+			for err := range errs {
+				if err == nil {
+					added++
+				} else if code := status.Code(err); code != codes.AlreadyExists {
+					t.Errorf("expected %s, got %v", codes.AlreadyExists, err)
+				}
+			}
+			if added != expected {
+				t.Errorf("expected %d adds to succeed, got %d", expected, added)
+			}
+		})
+	}
+}
+
+func TestUniqueValueUpdates(t *testing.T) {
+	server := newTestServer(t, NewLocalStore(), WithUniqueSerialNumbers(true))
+	ctx := context.Background()
+	first := newTestDevice("device-1")
+	first.SerialNumber = "serial-1"
+	second := newTestDevice("device-2")
+	second.SerialNumber = "serial-2"
+	for _, device := range []*Device{first, second} {
+		if _, err := server.Add(ctx, &AddRequest{Device: device}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Updating the second device to the serial number of the first must fail, and the failed update must not
+	// leave the second device's serial number reserved
+	update := proto.Clone(second).(*Device)
+	update.SerialNumber = "serial-1"
+	if _, err := server.Update(ctx, &UpdateRequest{Device: update}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected %s, got %v", codes.AlreadyExists, err)
+	}
+	update.SerialNumber = "serial-3"
+	update.Metadata = &ObjectMetadata{Version: 1}
+	if _, err := server.Update(ctx, &UpdateRequest{Device: update}); err != ErrConflict {
+		t.Fatalf("expected %v, got %v", ErrConflict, err)
+	}
+	third := newTestDevice("device-3")
+	third.SerialNumber = "serial-3"
+	if _, err := server.Add(ctx, &AddRequest{Device: third}); err != nil {
+		t.Fatalf("expected the serial number of a failed update to be released, got %v", err)
+	}
+
+	// Updates that don't change the serial number are allowed
+	if _, err := server.Update(ctx, &UpdateRequest{Device: &Device{Id: second.Id, Target: "target"}, UpdateMask: &field_mask.FieldMask{Paths: []string{"target"}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLeases(t *testing.T) {
+	// leaseStep is a claim or release of the lease on device-1 and the status code it's expected to return
+	type leaseStep struct {
+		release bool
+		owner   string
+		lease   time.Duration
+		code    codes.Code
+	}
+	tests := []struct {
+		name  string
+		steps []leaseStep
+		// owner is the expected owner once the steps are applied
+		owner string
+	}{
+		{
+			name: "claim",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+			},
+			owner: "controller-1",
+		},
+		{
+			name: "renew",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+			},
+			owner: "controller-1",
+		},
+		{
+			name: "claim owned",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+				{owner: "controller-2", lease: time.Minute, code: codes.FailedPrecondition},
+			},
+			owner: "controller-1",
+		},
+		{
+			name: "claim expired",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Nanosecond, code: codes.OK},
+				{owner: "controller-2", lease: time.Minute, code: codes.OK},
+			},
+			owner: "controller-2",
+		},
+		{
+			name: "release",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+				{release: true, owner: "controller-1", code: codes.OK},
+				{owner: "controller-2", lease: time.Minute, code: codes.OK},
+			},
+			owner: "controller-2",
+		},
+		{
+			name: "release owned",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Minute, code: codes.OK},
+				{release: true, owner: "controller-2", code: codes.FailedPrecondition},
+			},
+			owner: "controller-1",
+		},
+		{
+			name: "release expired",
+			steps: []leaseStep{
+				{owner: "controller-1", lease: time.Nanosecond, code: codes.OK},
+				{release: true, owner: "controller-2", code: codes.OK},
+			},
+		},
+		{
+			name: "no lease",
+			steps: []leaseStep{
+				{owner: "controller-1", code: codes.InvalidArgument},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t, NewLocalStore())
+			if _, err := server.Add(ctx, &AddRequest{Device: newTestDevice("device-1")}); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, step := range test.steps {
+				var err error
+				if step.release {
+					_, err = server.ReleaseDevice(ctx, &ReleaseDeviceRequest{
+						DeviceId: "device-1",
+						OwnerId:  step.owner,
+					})
+				} else {
+					request := &ClaimDeviceRequest{
+						DeviceId: "device-1",
+						OwnerId:  step.owner,
+					}
+					if step.lease != 0 {
+						request.LeaseDuration = ptypes.DurationProto(step.lease)
+					}
+					_, err = server.ClaimDevice(ctx, request)
+				}
+				if code := status.Code(err); code != step.code {
+					t.Fatalf("step %d: expected %s, got %v", i, step.code, err)
+				}
+			}
+
+			response, err := server.Get(ctx, &GetRequest{DeviceId: "device-1"})
+			if err != nil {
+				t.Fatal(err)
+			} else if owner := response.Device.GetOwner().GetId(); owner != test.owner {
+				t.Errorf("expected owner %q, got %q", test.owner, owner)
+			}
+		})
+	}
+}
+
+func TestLifecycleTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  LifecyclePhase
+		to    LifecyclePhase
+		force bool
+		code  codes.Code
+	}{
+		{
+			name: "forward",
+			from: LifecyclePhase_CONFIGURING,
+			to:   LifecyclePhase_CONFIGURED,
+			code: codes.OK,
+		},
+		{
+			name: "failed",
+			from: LifecyclePhase_CONFIGURING,
+			to:   LifecyclePhase_FAILED,
+			code: codes.OK,
+		},
+		{
+			name: "same rank",
+			from: LifecyclePhase_FAILED,
+			to:   LifecyclePhase_CONFIGURED,
+			code: codes.OK,
+		},
+		{
+			name: "backward",
+			from: LifecyclePhase_CONFIGURED,
+			to:   LifecyclePhase_CONFIGURING,
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:  "forced backward",
+			from:  LifecyclePhase_CONFIGURED,
+			to:    LifecyclePhase_CONFIGURING,
+			force: true,
+			code:  codes.OK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newTestServer(t, NewLocalStore(), WithForceUpdates(true))
+			device := newTestDevice("device-1")
+			device.LifecycleStatus = &LifecycleStatus{Phase: test.from}
+			added, err := server.Add(ctx, &AddRequest{Device: device})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			device = newTestDevice("device-1")
+			device.LifecycleStatus = &LifecycleStatus{Phase: test.to}
+			device.Metadata = added.Metadata
+			_, err = server.Update(ctx, &UpdateRequest{Device: device, Force: test.force})
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("expected %s, got %v", test.code, err)
+			}
+
+			phase := test.to
+			if err != nil {
+				phase = test.from
+			}
+			response, err := server.Get(ctx, &GetRequest{DeviceId: "device-1"})
+			if err != nil {
+				t.Fatal(err)
+			} else if actual := response.Device.GetLifecycleStatus().GetPhase(); actual != phase {
+				t.Errorf("expected phase %s, got %s", phase, actual)
+			}
+		})
+	}
+}
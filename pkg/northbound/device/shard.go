@@ -0,0 +1,203 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"github.com/atomix/atomix-go-client/pkg/client/primitive"
+	"hash/fnv"
+	"sync"
+)
+
+// newShardedMap returns a map that shards keys across the given maps by the hash of the key
+// Keys are always routed to the same shard for a given number of shards, so changing the number of shards
+// requires the stored entries to be migrated. If only one map is given, the map is returned as is.
+func newShardedMap(shards []map_.Map) map_.Map {
+	if len(shards) == 1 {
+		return shards[0]
+	}
+	return &shardedMap{
+		shards: shards,
+	}
+}
+
+// shardIndex returns the index of the shard to which the given key is routed
+func shardIndex(key string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// shardedMap is a map_.Map that routes each key to one of a set of maps
+// Keyed operations are forwarded to the key's shard, while Entries and Watch fan out across all shards
+// and merge the shards' streams. Versions are only ordered within a shard.
+type shardedMap struct {
+	shards []map_.Map
+}
+
+func (m *shardedMap) shard(key string) map_.Map {
+	return m.shards[shardIndex(key, len(m.shards))]
+}
+
+func (m *shardedMap) Name() primitive.Name {
+	return m.shards[0].Name()
+}
+
+func (m *shardedMap) Put(ctx context.Context, key string, value []byte, opts ...map_.PutOption) (*map_.KeyValue, error) {
+	return m.shard(key).Put(ctx, key, value, opts...)
+}
+
+func (m *shardedMap) Get(ctx context.Context, key string, opts ...map_.GetOption) (*map_.KeyValue, error) {
+	return m.shard(key).Get(ctx, key, opts...)
+}
+
+func (m *shardedMap) Remove(ctx context.Context, key string, opts ...map_.RemoveOption) (*map_.KeyValue, error) {
+	return m.shard(key).Remove(ctx, key, opts...)
+}
+
+func (m *shardedMap) Size(ctx context.Context) (int, error) {
+	size := 0
+	for _, shard := range m.shards {
+		n, err := shard.Size(ctx)
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return size, nil
+}
+
+func (m *shardedMap) Clear(ctx context.Context) error {
+	for _, shard := range m.shards {
+		if err := shard.Clear(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entries lists the entries of all shards concurrently
+// The channel is closed once every shard's entries have been listed.
+func (m *shardedMap) Entries(ctx context.Context, ch chan<- *map_.KeyValue) error {
+	ctx, cancel := context.WithCancel(ctx)
+	shardChs := make([]chan *map_.KeyValue, len(m.shards))
+	for i, shard := range m.shards {
+		shardChs[i] = make(chan *map_.KeyValue)
+		if err := shard.Entries(ctx, shardChs[i]); err != nil {
+			cancel()
+			drainShards(shardChs[:i])
+			return err
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(shardChs))
+	for _, shardCh := range shardChs {
+		go func(shardCh <-chan *map_.KeyValue) {
+			defer wg.Done()
+			for kv := range shardCh {
+				select {
+				case ch <- kv:
+				case <-ctx.Done():
+				}
+			}
+		}(shardCh)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+	}()
+	return nil
+}
+
+// Watch watches all shards concurrently
+// The watch of every shard depends on the session with its group, so if the watch of any shard is closed,
+// the watches of the other shards are cancelled and the channel is closed, allowing the caller to
+// re-establish a watch of the whole map.
+func (m *shardedMap) Watch(ctx context.Context, ch chan<- *map_.MapEvent, opts ...map_.WatchOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	shardChs := make([]chan *map_.MapEvent, len(m.shards))
+	for i, shard := range m.shards {
+		shardChs[i] = make(chan *map_.MapEvent)
+		if err := shard.Watch(ctx, shardChs[i], opts...); err != nil {
+			cancel()
+			drainShardEvents(shardChs[:i])
+			return err
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(shardChs))
+	for _, shardCh := range shardChs {
+		go func(shardCh <-chan *map_.MapEvent) {
+			defer wg.Done()
+			defer cancel()
+			for event := range shardCh {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+				}
+			}
+		}(shardCh)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+	}()
+	return nil
+}
+
+func (m *shardedMap) Close() error {
+	var err error
+	for _, shard := range m.shards {
+		if closeErr := shard.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (m *shardedMap) Delete() error {
+	var err error
+	for _, shard := range m.shards {
+		if deleteErr := shard.Delete(); deleteErr != nil && err == nil {
+			err = deleteErr
+		}
+	}
+	return err
+}
+
+// drainShards discards the entries of shards whose listing was cancelled until their channels are closed
+func drainShards(shardChs []chan *map_.KeyValue) {
+	for _, shardCh := range shardChs {
+		go func(shardCh <-chan *map_.KeyValue) {
+			for range shardCh {
+			}
+		}(shardCh)
+	}
+}
+
+// drainShardEvents discards the events of shards whose watch was cancelled until their channels are closed
+func drainShardEvents(shardChs []chan *map_.MapEvent) {
+	for _, shardCh := range shardChs {
+		go func(shardCh <-chan *map_.MapEvent) {
+			for range shardCh {
+			}
+		}(shardCh)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"expvar"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+)
+
+// EventSink is a destination to which device events are published, such as a message bus
+type EventSink interface {
+	// Publish publishes the given event
+	Publish(event *Event) error
+
+	// Close closes the sink
+	Close() error
+}
+
+// SinkFormat is the format in which events are serialized by an EventSink
+type SinkFormat string
+
+const (
+	// SinkFormatJSON serializes events as ListResponses encoded as JSON
+	SinkFormatJSON SinkFormat = "json"
+
+	// SinkFormatProto serializes events as ListResponses encoded as protobuf
+	SinkFormatProto SinkFormat = "proto"
+)
+
+// encodeEvent serializes the given event as a ListResponse in the given format
+func encodeEvent(event *Event, format SinkFormat) ([]byte, error) {
+	response := &ListResponse{
+		Type:        eventResponseType(event.Type),
+		Device:      event.Device,
+		Annotations: event.Annotations,
+		Seq:         event.Seq,
+		PrevDevice:  event.PrevDevice,
+		Version:     event.Version,
+		PrevVersion: event.PrevVersion,
+	}
+	switch format {
+	case SinkFormatJSON:
+		json, err := marshaler.MarshalToString(response)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(json), nil
+	case SinkFormatProto:
+		return proto.Marshal(response)
+	default:
+		return nil, fmt.Errorf("unknown sink format %s", format)
+	}
+}
+
+// sinkDroppedEvents counts the number of events dropped because an event sink could not keep up
+var sinkDroppedEvents = expvar.NewInt("topo_device_sink_dropped_events")
+
+// publishEvents publishes the events of the given store to the given sink until the store's watch is closed
+// Events are buffered so that a slow sink never blocks the store's watch pipeline. If the buffer is full,
+// the event is dropped and counted in the topo_device_sink_dropped_events metric. Secrets are redacted from
+// the events' devices before they are published, since sinks are not subject to the service's access controls.
+func publishEvents(store Store, sink EventSink, bufferSize int, logger Logger) error {
+	ch := make(chan *Event)
+	// Devices replayed when the watch is opened do not represent changes
+	if err := store.Watch(ch, WithReplay(false)); err != nil {
+		return err
+	}
+
+	queue := make(chan *Event, bufferSize)
+	go func() {
+		defer close(queue)
+		for event := range ch {
+			redacted := *event
+			redacted.Device = redactSecrets(event.Device)
+			redacted.PrevDevice = redactSecrets(event.PrevDevice)
+			select {
+			case queue <- &redacted:
+			default:
+				sinkDroppedEvents.Add(1)
+			}
+		}
+	}()
+
+	go func() {
+		defer sink.Close()
+		for event := range queue {
+			if err := sink.Publish(event); err != nil {
+				logger.Warn("Failed to publish device event", DeviceIDField(event.Device.Id), OperationField("sink"), VersionField(event.Device.GetMetadata().GetVersion()), ErrorField(err))
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+)
+
+// maxSnapshotEntrySize is the maximum size in bytes of an entry of a restored snapshot
+const maxSnapshotEntrySize = 64 * 1024 * 1024
+
+// Snapshot writes the devices, their annotations and the device groups in the service's store to the given
+// writer as a sequence of length-delimited SnapshotEntry messages
+func (s Service) Snapshot(ctx context.Context, writer io.Writer) error {
+	ch := make(chan *Device, listBufferSize)
+	if err := s.store.List(ctx, ch); err != nil {
+		return err
+	}
+	var devices []*Device
+	for device := range ch {
+		devices = append(devices, device)
+	}
+	sortDevices(devices)
+
+	buffered := bufio.NewWriter(writer)
+	for _, device := range devices {
+		if err := writeSnapshotEntry(buffered, &SnapshotEntry{Entry: &SnapshotEntry_Device{Device: device}}); err != nil {
+			return err
+		}
+		annotations, err := s.store.LoadAnnotations(ctx, device.Id)
+		if err != nil {
+			return err
+		} else if annotations != nil {
+			if err := writeSnapshotEntry(buffered, &SnapshotEntry{Entry: &SnapshotEntry_Annotations{Annotations: annotations}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	groups, err := s.store.ListGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := writeSnapshotEntry(buffered, &SnapshotEntry{Entry: &SnapshotEntry_Group{Group: group}}); err != nil {
+			return err
+		}
+	}
+	s.logger.Info("Wrote device store snapshot", OperationField("snapshot"), Field{Key: "devices", Value: len(devices)}, Field{Key: "groups", Value: len(groups)})
+	return buffered.Flush()
+}
+
+// Restore stores the entries of the snapshot read from the given reader in the service's store
+// The whole snapshot is read and validated before the store is modified, so an invalid snapshot leaves the
+// store unchanged. Restored devices, annotations and groups replace any stored with the same IDs. If replace
+// is true, all devices and groups are removed from the store first.
+func (s Service) Restore(ctx context.Context, reader io.Reader, replace bool) error {
+	if s.readOnly {
+		return status.Error(codes.FailedPrecondition, "server is a read-only replica")
+	}
+	entries, err := readSnapshot(reader)
+	if err != nil {
+		return err
+	}
+
+	if replace {
+		if err := s.store.Clear(ctx); err != nil {
+			return err
+		}
+		groups, err := s.store.ListGroups(ctx)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			group.Version = 0
+			if err := s.store.DeleteGroup(ctx, group); err != nil && err != ErrNotFound {
+				return err
+			}
+		}
+	}
+
+	devices, groups := 0, 0
+	for _, entry := range entries {
+		switch e := entry.Entry.(type) {
+		case *SnapshotEntry_Device:
+			e.Device.Metadata = nil
+			if err := s.store.ForcePut(ctx, e.Device); err != nil {
+				return storeError(err)
+			}
+			devices++
+		case *SnapshotEntry_Annotations:
+			e.Annotations.Version = 0
+			if err := s.store.StoreAnnotations(ctx, e.Annotations); err != nil {
+				return err
+			}
+		case *SnapshotEntry_Group:
+			e.Group.Version = 0
+			if err := s.store.StoreGroup(ctx, e.Group); err != nil {
+				return err
+			}
+			groups++
+		}
+	}
+	s.logger.Info("Restored device store snapshot", OperationField("restore"), Field{Key: "replace", Value: replace}, Field{Key: "devices", Value: devices}, Field{Key: "groups", Value: groups})
+	return nil
+}
+
+// writeSnapshotEntry writes the given entry preceded by its length to the given writer
+func writeSnapshotEntry(writer io.Writer, entry *SnapshotEntry) error {
+	bytes, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	length := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(length, uint64(len(bytes)))
+	if _, err := writer.Write(length[:n]); err != nil {
+		return err
+	}
+	_, err = writer.Write(bytes)
+	return err
+}
+
+// readSnapshot reads and validates the entries of the snapshot read from the given reader
+// An InvalidArgument error is returned if the snapshot is malformed or contains an invalid entry.
+func readSnapshot(reader io.Reader) ([]*SnapshotEntry, error) {
+	buffered := bufio.NewReader(reader)
+	var entries []*SnapshotEntry
+	for {
+		length, err := binary.ReadUvarint(buffered)
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, snapshotError(len(entries), err)
+		} else if length > maxSnapshotEntrySize {
+			return nil, snapshotError(len(entries), fmt.Errorf("entry of %d bytes exceeds the maximum of %d bytes", length, maxSnapshotEntrySize))
+		}
+		bytes := make([]byte, length)
+		if _, err := io.ReadFull(buffered, bytes); err != nil {
+			return nil, snapshotError(len(entries), err)
+		}
+		entry := &SnapshotEntry{}
+		if err := proto.Unmarshal(bytes, entry); err != nil {
+			return nil, snapshotError(len(entries), err)
+		}
+		switch e := entry.Entry.(type) {
+		case *SnapshotEntry_Device:
+			if err := e.Device.Validate(); err != nil {
+				return nil, snapshotError(len(entries), err)
+			}
+		case *SnapshotEntry_Annotations:
+			if e.Annotations.DeviceId == "" {
+				return nil, snapshotError(len(entries), fmt.Errorf("annotations have no device ID"))
+			}
+		case *SnapshotEntry_Group:
+			if e.Group.Id == "" {
+				return nil, snapshotError(len(entries), fmt.Errorf("group has no ID"))
+			}
+		default:
+			return nil, snapshotError(len(entries), fmt.Errorf("unknown entry"))
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// snapshotError returns an InvalidArgument error for the snapshot entry with the given index
+func snapshotError(index int, err error) error {
+	return status.Errorf(codes.InvalidArgument, "invalid snapshot entry %d: %s", index, err)
+}
@@ -0,0 +1,264 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/onosproject/onos-topo/pkg/northbound/admin"
+	"sort"
+	"sync"
+	"time"
+)
+
+// newDeviceStats returns a new deviceStats that tracks at most the given number of devices
+func newDeviceStats(maxDevices int) *deviceStats {
+	return &deviceStats{
+		maxDevices: maxDevices,
+		counters:   make(map[string]*accessCounter),
+		since:      time.Now(),
+	}
+}
+
+// deviceStats counts the store reads and writes of the most accessed devices
+// Memory is bounded with the space-saving algorithm: once maxDevices devices are tracked, an access to an
+// untracked device replaces the least accessed tracked device, and the new device inherits the replaced
+// device's estimated count as its overcount. A device's reads and writes count only the accesses since it was
+// last tracked, and its true number of accesses is at most its reads and writes plus its overcount, so the
+// most accessed devices are retained while rarely accessed devices are forgotten.
+type deviceStats struct {
+	mu         sync.Mutex
+	maxDevices int
+	counters   map[string]*accessCounter
+	since      time.Time
+}
+
+// accessCounter is the access counts of a single device
+type accessCounter struct {
+	reads     uint64
+	writes    uint64
+	overcount uint64
+}
+
+// estimate returns the estimated number of accesses of the device, which is an upper bound
+func (c *accessCounter) estimate() uint64 {
+	return c.reads + c.writes + c.overcount
+}
+
+// record records a read or write of the given device
+func (s *deviceStats) record(deviceID string, write bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.counters[deviceID]
+	if !ok {
+		counter = &accessCounter{}
+		if len(s.counters) >= s.maxDevices {
+			var minID string
+			var min *accessCounter
+			for id, c := range s.counters {
+				if min == nil || c.estimate() < min.estimate() {
+					minID, min = id, c
+				}
+			}
+			delete(s.counters, minID)
+			counter.overcount = min.estimate()
+		}
+		s.counters[deviceID] = counter
+	}
+	if write {
+		counter.writes++
+	} else {
+		counter.reads++
+	}
+}
+
+// top returns the statistics of the given number of most accessed devices, or of all tracked devices if
+// limit is 0
+func (s *deviceStats) top(limit int) []*admin.DeviceStats {
+	s.mu.Lock()
+	stats := make([]*admin.DeviceStats, 0, len(s.counters))
+	for id, counter := range s.counters {
+		stats = append(stats, &admin.DeviceStats{
+			DeviceId:  id,
+			Reads:     counter.reads,
+			Writes:    counter.writes,
+			Overcount: counter.overcount,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		ti := stats[i].Reads + stats[i].Writes + stats[i].Overcount
+		tj := stats[j].Reads + stats[j].Writes + stats[j].Overcount
+		if ti != tj {
+			return ti > tj
+		}
+		return stats[i].DeviceId < stats[j].DeviceId
+	})
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
+
+// newStatsStore returns a Store that records the device reads and writes of the given store
+func newStatsStore(store Store, stats *deviceStats) Store {
+	return &statsStore{
+		store: store,
+		stats: stats,
+	}
+}
+
+// statsStore is a Store that counts the reads and writes of each device
+// Reads and writes are counted when they're attempted, whether or not they succeed. Listing devices does not
+// count as a read of each listed device.
+type statsStore struct {
+	store Store
+	stats *deviceStats
+}
+
+func (s *statsStore) Load(ctx context.Context, deviceID string) (*Device, error) {
+	s.stats.record(deviceID, false)
+	return s.store.Load(ctx, deviceID)
+}
+
+func (s *statsStore) Exists(ctx context.Context, deviceID string) (bool, error) {
+	return s.store.Exists(ctx, deviceID)
+}
+
+func (s *statsStore) Count(ctx context.Context) (int, error) {
+	return s.store.Count(ctx)
+}
+
+func (s *statsStore) Create(ctx context.Context, device *Device) error {
+	s.stats.record(device.Id, true)
+	return s.store.Create(ctx, device)
+}
+
+func (s *statsStore) EnsureDevice(ctx context.Context, device *Device) (*Device, bool, error) {
+	stored, created, err := s.store.EnsureDevice(ctx, device)
+	s.stats.record(device.Id, created)
+	return stored, created, err
+}
+
+func (s *statsStore) Store(ctx context.Context, device *Device) error {
+	s.stats.record(device.Id, true)
+	return s.store.Store(ctx, device)
+}
+
+func (s *statsStore) ForcePut(ctx context.Context, device *Device) error {
+	s.stats.record(device.Id, true)
+	return s.store.ForcePut(ctx, device)
+}
+
+func (s *statsStore) Delete(ctx context.Context, device *Device) error {
+	s.stats.record(device.Id, true)
+	return s.store.Delete(ctx, device)
+}
+
+func (s *statsStore) Rename(ctx context.Context, device *Device, newID string) error {
+	s.stats.record(device.Id, true)
+	s.stats.record(newID, true)
+	return s.store.Rename(ctx, device, newID)
+}
+
+func (s *statsStore) SwapAddresses(ctx context.Context, firstID string, secondID string) (*Device, *Device, error) {
+	s.stats.record(firstID, true)
+	s.stats.record(secondID, true)
+	return s.store.SwapAddresses(ctx, firstID, secondID)
+}
+
+func (s *statsStore) Clear(ctx context.Context) error {
+	return s.store.Clear(ctx)
+}
+
+func (s *statsStore) Tx(ctx context.Context, fn func(Txn) error) error {
+	return s.store.Tx(ctx, func(t Txn) error {
+		return fn(&statsTxn{txn: t, stats: s.stats})
+	})
+}
+
+func (s *statsStore) LoadAnnotations(ctx context.Context, deviceID string) (*Annotations, error) {
+	s.stats.record(deviceID, false)
+	return s.store.LoadAnnotations(ctx, deviceID)
+}
+
+func (s *statsStore) StoreAnnotations(ctx context.Context, annotations *Annotations) error {
+	s.stats.record(annotations.DeviceId, true)
+	return s.store.StoreAnnotations(ctx, annotations)
+}
+
+func (s *statsStore) List(ctx context.Context, ch chan<- *Device) error {
+	return s.store.List(ctx, ch)
+}
+
+func (s *statsStore) ListModifiedSince(ctx context.Context, since time.Time, ch chan<- *Device) error {
+	return s.store.ListModifiedSince(ctx, since, ch)
+}
+
+func (s *statsStore) ListChildren(ctx context.Context, parentID string) ([]*Device, error) {
+	return s.store.ListChildren(ctx, parentID)
+}
+
+func (s *statsStore) Watch(ch chan<- *Event, opts ...WatchOption) error {
+	return s.store.Watch(ch, opts...)
+}
+
+func (s *statsStore) VersionsOrdered() bool {
+	return s.store.VersionsOrdered()
+}
+
+func (s *statsStore) WatchFrom(ctx context.Context, version uint64, ch chan<- *Event, opts ...WatchOption) error {
+	return s.store.WatchFrom(ctx, version, ch, opts...)
+}
+
+func (s *statsStore) WatchDevices(ctx context.Context, ids []string, ch chan<- *Event, opts ...WatchOption) error {
+	return s.store.WatchDevices(ctx, ids, ch, opts...)
+}
+
+func (s *statsStore) CreateGroup(ctx context.Context, group *DeviceGroup) error {
+	return s.store.CreateGroup(ctx, group)
+}
+
+func (s *statsStore) StoreGroup(ctx context.Context, group *DeviceGroup) error {
+	return s.store.StoreGroup(ctx, group)
+}
+
+func (s *statsStore) LoadGroup(ctx context.Context, groupID string) (*DeviceGroup, error) {
+	return s.store.LoadGroup(ctx, groupID)
+}
+
+func (s *statsStore) DeleteGroup(ctx context.Context, group *DeviceGroup) error {
+	return s.store.DeleteGroup(ctx, group)
+}
+
+func (s *statsStore) ListGroups(ctx context.Context) ([]*DeviceGroup, error) {
+	return s.store.ListGroups(ctx)
+}
+
+// statsTxn is a Txn that counts the writes buffered in another Txn
+type statsTxn struct {
+	txn   Txn
+	stats *deviceStats
+}
+
+func (t *statsTxn) Put(device *Device) {
+	t.stats.record(device.Id, true)
+	t.txn.Put(device)
+}
+
+func (t *statsTxn) Remove(device *Device) {
+	t.stats.record(device.Id, true)
+	t.txn.Remove(device)
+}
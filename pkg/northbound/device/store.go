@@ -16,151 +16,1897 @@ package device
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/atomix/atomix-go-client/pkg/client/lock"
 	"github.com/atomix/atomix-go-client/pkg/client/map_"
 	"github.com/atomix/atomix-go-client/pkg/client/session"
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/onosproject/onos-topo/pkg/util"
+	"sort"
+	"sync"
 	"time"
 )
 
+// ErrNotFound is returned by a Store when a device to be deleted or otherwise modified does not exist
+var ErrNotFound = errors.New("device not found")
+
+// ErrAlreadyExists is returned by a Store when a device to be created already exists
+var ErrAlreadyExists = errors.New("device already exists")
+
+// ErrConflict is returned by a Store when a versioned write fails because the stored device has a
+// different version
+var ErrConflict = errors.New("write condition failed")
+
+// ErrUnorderedVersions is returned by a Store when a watch is started from a version, but device versions
+// are only ordered within each shard of the store
+var ErrUnorderedVersions = errors.New("device versions are not ordered across shards")
+
+// ErrValueTooLarge is returned by a Store when an encoded device exceeds the store's maximum value size
+// The size is the size of the device as it would be stored, i.e. after compression, if enabled.
+type ErrValueTooLarge struct {
+	// DeviceID is the ID of the device that is too large
+	DeviceID string
+
+	// Size is the size in bytes of the encoded device
+	Size int
+
+	// Limit is the maximum size in bytes of an encoded device
+	Limit int
+}
+
+func (e *ErrValueTooLarge) Error() string {
+	return fmt.Sprintf("device %s is too large to store: encoded size %d bytes exceeds the limit of %d bytes", e.DeviceID, e.Size, e.Limit)
+}
+
+// checkValueSize returns ErrValueTooLarge if the given encoded device is larger than the given limit
+// The size isn't limited if the limit is 0.
+func checkValueSize(deviceID string, value []byte, limit int) error {
+	if limit > 0 && len(value) > limit {
+		return &ErrValueTooLarge{
+			DeviceID: deviceID,
+			Size:     len(value),
+			Limit:    limit,
+		}
+	}
+	return nil
+}
+
+// conflictError returns ErrConflict if the given map error indicates a version conflict
+// The Atomix map reports failed versioned writes as a generic error, so the error is identified by its message.
+func conflictError(err error) error {
+	if err != nil && err.Error() == ErrConflict.Error() {
+		return ErrConflict
+	}
+	return err
+}
+
+// StoreType is the type of a device Store
+type StoreType string
+
+const (
+	// StoreTypeAtomix is the persistent Store shared by all replicas of the service through Atomix
+	StoreTypeAtomix StoreType = "atomix"
+
+	// StoreTypeLocal is the in-memory Store, which does not require Atomix
+	StoreTypeLocal StoreType = "local"
+)
+
+// StoreConfig is the configuration of a Store created by NewStore
+type StoreConfig struct {
+	// CompressionThreshold is the size in bytes above which encoded devices are compressed, or 0 to disable
+	// compression
+	CompressionThreshold int
+
+	// ListWorkers is the number of workers that concurrently decode devices listed from the Atomix store
+	ListWorkers int
+
+	// ListBufferSize is the number of listed Atomix map entries buffered ahead of the decoding workers
+	ListBufferSize int
+
+	// LocalPath is the file to which the local store persists devices, or empty to keep devices in memory
+	LocalPath string
+
+	// AtomixGroups is the names of the Atomix groups across which devices are sharded, or empty to store
+	// devices in the group configured by the environment
+	AtomixGroups []string
+
+	// MaxValueSize is the maximum size in bytes of an encoded device, or 0 to not limit the size of devices
+	MaxValueSize int
+}
+
+// NewStore returns a new Store of the given type with the given configuration
+// Stores of any type other than StoreTypeAtomix and StoreTypeLocal are rejected with an error.
+func NewStore(storeType StoreType, logger Logger, config StoreConfig) (Store, error) {
+	switch storeType {
+	case StoreTypeAtomix:
+		return NewAtomixStore(
+			WithAtomixLogger(logger),
+			WithAtomixCompression(config.CompressionThreshold),
+			WithAtomixListConcurrency(config.ListWorkers, config.ListBufferSize),
+			WithAtomixGroups(config.AtomixGroups...),
+			WithAtomixMaxValueSize(config.MaxValueSize))
+	case StoreTypeLocal:
+		opts := []LocalStoreOption{WithLocalLogger(logger), WithLocalCompression(config.CompressionThreshold), WithLocalMaxValueSize(config.MaxValueSize)}
+		if config.LocalPath != "" {
+			return NewPersistentLocalStore(config.LocalPath, opts...)
+		}
+		return NewLocalStore(opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown store type %q: must be %q or %q", storeType, StoreTypeAtomix, StoreTypeLocal)
+	}
+}
+
 // NewAtomixStore returns a new persistent Store
-func NewAtomixStore() (Store, error) {
-	client, err := util.GetAtomixClient()
+func NewAtomixStore(opts ...AtomixStoreOption) (Store, error) {
+	client, err := util.GetSharedAtomixClient()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &atomixStore{
+		logger:       NewNopLogger(),
+		atomixGroups: []string{util.GetAtomixRaftGroup()},
+		watchBackoff: watchBackoff{
+			initial: defaultWatchBackoffInitial,
+			max:     defaultWatchBackoffMax,
+		},
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	// Devices and their annotations are sharded across all groups, while device groups and the idempotency
+	// and history maps are stored in the first group
+	deviceShards := make([]map_.Map, len(store.atomixGroups))
+	annotationShards := make([]map_.Map, len(store.atomixGroups))
+	store.createLocks = make([]lock.Lock, len(store.atomixGroups))
+	for i, name := range store.atomixGroups {
+		group, err := client.GetGroup(context.Background(), name)
+		if err != nil {
+			return nil, err
+		}
+
+		deviceShards[i], err = group.GetMap(context.Background(), "devices", session.WithTimeout(30*time.Second))
+		if err != nil {
+			return nil, err
+		}
+
+		annotationShards[i], err = group.GetMap(context.Background(), "device-annotations", session.WithTimeout(30*time.Second))
+		if err != nil {
+			return nil, err
+		}
+
+		store.createLocks[i], err = group.GetLock(context.Background(), "device-create", session.WithTimeout(30*time.Second))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			store.groups, err = group.GetMap(context.Background(), "device-groups", session.WithTimeout(30*time.Second))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	store.devices = newShardedMap(deviceShards)
+	store.annotations = newShardedMap(annotationShards)
+	return store, nil
+}
+
+// defaultWatchBackoffInitial is the default delay before the device map watch is first re-established
+const defaultWatchBackoffInitial = 100 * time.Millisecond
+
+// defaultWatchBackoffMax is the default maximum delay between attempts to re-establish the device map watch
+const defaultWatchBackoffMax = 30 * time.Second
+
+// AtomixStoreOption is an option for configuring the Atomix store
+type AtomixStoreOption func(*atomixStore)
+
+// WithAtomixLogger sets the logger to which the Atomix store logs errors and watch lifecycle events
+func WithAtomixLogger(logger Logger) AtomixStoreOption {
+	return func(store *atomixStore) {
+		store.logger = logger
+	}
+}
+
+// WithAtomixCompression sets the size in bytes above which encoded devices are compressed with gzip
+// Compression reduces the memory used by devices with large protocol configurations at the cost of CPU.
+// Devices are not compressed if the threshold is 0. Compressed and uncompressed devices can be read
+// regardless of the threshold, so compression can be enabled or disabled for an existing store.
+func WithAtomixCompression(threshold int) AtomixStoreOption {
+	return func(store *atomixStore) {
+		store.compressionThreshold = threshold
+	}
+}
+
+// WithAtomixMaxValueSize sets the maximum size in bytes of an encoded device
+// Atomix limits the size of map entries, and writes of larger entries fail with an opaque error, so the
+// limit should be set to the limit of the Atomix deployment. Writes of devices that exceed the limit fail
+// with ErrValueTooLarge before they're sent to Atomix. The size of devices is not limited by default.
+func WithAtomixMaxValueSize(limit int) AtomixStoreOption {
+	return func(store *atomixStore) {
+		store.maxValueSize = limit
+	}
+}
+
+// WithAtomixListConcurrency sets the number of workers that decode devices when listing the Atomix store
+// and the number of map entries buffered ahead of the workers
+// Decoding large topologies with a single worker serializes the initial dump of List and Watch. With
+// multiple workers, every device is still listed but devices may be listed in any order. Devices are
+// decoded by a single worker with no buffer by default.
+func WithAtomixListConcurrency(workers int, bufferSize int) AtomixStoreOption {
+	return func(store *atomixStore) {
+		store.listWorkers = workers
+		store.listBufferSize = bufferSize
+	}
+}
+
+// WithAtomixGroups sets the Atomix groups across which devices are sharded
+// Each device and its annotations are stored in the group selected by the hash of the device ID, and List
+// and Watch merge the devices of all groups. Device groups are stored in the first group. Devices are
+// stored in the group configured by the environment by default. Changing the set of groups changes the
+// group of most devices, so devices must be migrated, e.g. with a snapshot and restore, when the set of
+// groups changes.
+func WithAtomixGroups(names ...string) AtomixStoreOption {
+	return func(store *atomixStore) {
+		if len(names) > 0 {
+			store.atomixGroups = names
+		}
+	}
+}
+
+// WithAtomixWatchBackoff sets the backoff with which the device map watch is re-established if it's closed
+// The watch is closed if the Atomix session is lost. The delay between attempts to watch the map starts at
+// the initial delay and doubles after each failed attempt up to the maximum delay. Watches of the store
+// remain open while the map watch is re-established.
+func WithAtomixWatchBackoff(initial time.Duration, max time.Duration) AtomixStoreOption {
+	return func(store *atomixStore) {
+		store.watchBackoff = watchBackoff{
+			initial: initial,
+			max:     max,
+		}
+	}
+}
+
+// watchBackoff is the exponential backoff with which a map watch is re-established
+type watchBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+// delay returns the delay before the given attempt, numbered from 1
+func (b watchBackoff) delay(attempt int) time.Duration {
+	delay := b.initial
+	for i := 1; i < attempt && delay < b.max; i++ {
+		delay *= 2
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	return delay
+}
+
+// Store stores topology information
+type Store interface {
+	// Load loads a device from the store
+	Load(ctx context.Context, deviceID string) (*Device, error)
+
+	// Exists returns whether a device exists in the store without decoding the device
+	Exists(ctx context.Context, deviceID string) (bool, error)
+
+	// Count returns the number of devices in the store without listing the devices
+	Count(ctx context.Context) (int, error)
+
+	// Create stores a new device in the store
+	// If a device with the same ID is already stored, ErrAlreadyExists is returned, so exactly one of any
+	// concurrent creates of the same device succeeds.
+	Create(ctx context.Context, device *Device) error
+
+	// EnsureDevice stores a new device in the store unless a device with the same ID is already stored
+	// The stored device is returned along with whether it was created by the call. The device is created or
+	// loaded atomically with respect to other creates, so exactly one of any concurrent calls creates it.
+	EnsureDevice(ctx context.Context, device *Device) (*Device, bool, error)
+
+	// Store stores a device in the store
+	// If the device's version is set, ErrConflict is returned unless the stored device has the same version.
+	Store(ctx context.Context, device *Device) error
+
+	// ForcePut stores a device in the store regardless of the version of the stored device
+	ForcePut(ctx context.Context, device *Device) error
+
+	// Delete deletes a device and its annotations from the store
+	// ErrNotFound is returned if the device does not exist.
+	Delete(ctx context.Context, device *Device) error
+
+	// Rename moves a device and its annotations to the given ID, updating the device's children to reference
+	// the new ID
+	// If the device's version is set, the device is renamed only if the stored device has the same version.
+	// On success, the given device is updated with its new ID and metadata. ErrNotFound is returned if the
+	// device does not exist.
+	Rename(ctx context.Context, device *Device, newID string) error
+
+	// SwapAddresses swaps the address and failover addresses of the given devices
+	// On success, the updated devices are returned. ErrNotFound is returned if either device does not exist.
+	SwapAddresses(ctx context.Context, firstID string, secondID string) (*Device, *Device, error)
+
+	// Clear removes all devices and their annotations from the store
+	// A REMOVED event is sent to watchers for each removed device.
+	Clear(ctx context.Context) error
+
+	// Tx calls the given function to buffer writes and then commits the writes together
+	// If the function returns an error, no writes are committed. If any write fails, the writes that have
+	// already been committed are rolled back and the error is returned. On success, the devices that were
+	// put are updated with their new metadata.
+	Tx(ctx context.Context, fn func(Txn) error) error
+
+	// LoadAnnotations loads the annotations for a device from the store
+	LoadAnnotations(ctx context.Context, deviceID string) (*Annotations, error)
+
+	// StoreAnnotations stores the annotations for a device in the store
+	// Annotations are versioned independently of the device, so storing annotations does not change
+	// the device version.
+	StoreAnnotations(ctx context.Context, annotations *Annotations) error
+
+	// List streams devices to the given channel
+	List(ctx context.Context, ch chan<- *Device) error
+
+	// ListModifiedSince streams the devices updated at or after the given time to the given channel
+	// Devices without an update time, i.e. not written since update times were recorded, are always streamed.
+	ListModifiedSince(ctx context.Context, since time.Time, ch chan<- *Device) error
+
+	// ListChildren returns the devices whose parent is the given device
+	ListChildren(ctx context.Context, parentID string) ([]*Device, error)
+
+	// Watch streams device events to the given channel
+	// If the store's own watch of the underlying storage is interrupted, it's re-established and the missed
+	// changes are delivered as events, so the channel remains open.
+	Watch(chan<- *Event, ...WatchOption) error
+
+	// WatchFrom streams device events to the given channel, replaying only devices newer than the given version
+	// Devices removed since the given version are not replayed. If the version is 0, all devices are replayed.
+	// The watch is closed when the given context is canceled. ErrUnorderedVersions is returned if the version
+	// is set but the store's versions are not ordered.
+	WatchFrom(ctx context.Context, version uint64, ch chan<- *Event, opts ...WatchOption) error
+
+	// WatchDevices streams events for the given devices to the given channel
+	// The current state of each listed device that exists is replayed, and then only events for the listed
+	// devices are delivered. Devices that don't exist yet are delivered once they're added. The watch is
+	// closed when the given context is canceled.
+	WatchDevices(ctx context.Context, ids []string, ch chan<- *Event, opts ...WatchOption) error
+
+	// CreateGroup stores a new device group
+	// If a group with the same ID is already stored, ErrAlreadyExists is returned.
+	CreateGroup(ctx context.Context, group *DeviceGroup) error
+
+	// StoreGroup stores a device group
+	// If the group's version is set, ErrConflict is returned unless the stored group has the same version.
+	// On success, the group's version is updated.
+	StoreGroup(ctx context.Context, group *DeviceGroup) error
+
+	// LoadGroup loads a device group from the store
+	// If the group does not exist, nil is returned.
+	LoadGroup(ctx context.Context, groupID string) (*DeviceGroup, error)
+
+	// DeleteGroup removes a device group from the store
+	// ErrNotFound is returned if the group does not exist. If the group's version is set, ErrConflict is
+	// returned unless the stored group has the same version.
+	DeleteGroup(ctx context.Context, group *DeviceGroup) error
+
+	// ListGroups returns all device groups
+	ListGroups(ctx context.Context) ([]*DeviceGroup, error)
+
+	// VersionsOrdered returns whether device versions are ordered across all devices in the store
+	// Versions are only ordered within each shard of a sharded store, so devices can't be streamed from a
+	// version or a position in the version order.
+	VersionsOrdered() bool
+}
+
+// Txn buffers device writes to be committed together by Store.Tx
+// Versions are checked against the devices stored when the transaction is committed, so each device
+// should be written at most once in a transaction.
+type Txn interface {
+	// Put stores a device
+	// If the device's version is set, the device is stored only if the stored device has the same version.
+	Put(device *Device)
+
+	// Remove removes a device and its annotations
+	// If the device's version is set, the device is removed only if the stored device has the same version.
+	Remove(device *Device)
+}
+
+// txnOp is a write buffered in a transaction
+type txnOp struct {
+	device *Device
+	remove bool
+	// create indicates whether the write fails with ErrAlreadyExists if the device exists
+	create bool
+	// annotations, if set, are the encoded annotations to store for the device's ID in place of the device
+	annotations []byte
+}
+
+// txn is the Txn implementation shared by the stores
+type txn struct {
+	ops []txnOp
+}
+
+func (t *txn) Put(device *Device) {
+	t.ops = append(t.ops, txnOp{device: device})
+}
+
+func (t *txn) Remove(device *Device) {
+	t.ops = append(t.ops, txnOp{device: device, remove: true})
+}
+
+// OverflowPolicy is a policy for handling events when a watcher's buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the delivery of events to all watchers until the watcher consumes buffered events
+	// Events are delivered outside the store's lock, so a blocked watcher delays other watchers but not writes.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest buffered event to make room for the newest event
+	// Dropped events can be detected by watchers as gaps in event sequence numbers.
+	OverflowDropOldest
+
+	// OverflowEvict closes the watch once the watcher's buffer is full
+	// The buffered events are delivered followed by an EventEvicted event, after which the channel is closed.
+	// Evicting slow watchers keeps them from blocking the store's event pipeline without silently dropping
+	// their events.
+	OverflowEvict
+)
+
+// WatchOption is an option for a store Watch
+type WatchOption func(*watchOptions)
+
+// watchOptions are the options for a store Watch
+type watchOptions struct {
+	bufferSize  int
+	policy      OverflowPolicy
+	annotations bool
+	replayDone  bool
+	eventTypes  map[EventType]bool
+	ordered     bool
+	deviceIDs   map[string]bool
+	noReplay    bool
+}
+
+// WithBufferSize sets the number of events buffered for the watcher
+func WithBufferSize(size int) WatchOption {
+	return func(options *watchOptions) {
+		options.bufferSize = size
+	}
+}
+
+// WithOverflowPolicy sets the policy for handling events when the watcher's buffer is full
+func WithOverflowPolicy(policy OverflowPolicy) WatchOption {
+	return func(options *watchOptions) {
+		options.policy = policy
+	}
+}
+
+// WithAnnotations sets whether the watcher receives EventAnnotated events for changes to device annotations
+func WithAnnotations(annotations bool) WatchOption {
+	return func(options *watchOptions) {
+		options.annotations = annotations
+	}
+}
+
+// WithReplayDone sets whether the watcher receives an EventReplayDone event once the current devices
+// have been replayed
+func WithReplayDone(replayDone bool) WatchOption {
+	return func(options *watchOptions) {
+		options.replayDone = replayDone
+	}
+}
+
+// WithReplay sets whether the current devices are replayed to the watcher before events are streamed
+// Devices are replayed by default. Watchers that only need changes can disable the replay to avoid listing
+// the store, in which case only events for changes made after the watcher is registered are streamed.
+func WithReplay(replay bool) WatchOption {
+	return func(options *watchOptions) {
+		options.noReplay = !replay
+	}
+}
+
+// WithOrderedReplay sets whether the current devices are replayed in ID order
+// Ordering the replay requires the store to hold all the replayed devices in memory until they're sorted, so
+// the first device is delivered only once the store has listed all devices.
+func WithOrderedReplay(ordered bool) WatchOption {
+	return func(options *watchOptions) {
+		options.ordered = ordered
+	}
+}
+
+// WithEventTypes restricts the events delivered to the watcher to the given event types
+// Events of other types are discarded by the store before they're queued for the watcher, including devices
+// replayed from the current state of the store, which are delivered only if EventNone is included. The
+// EventReplayDone event is controlled by WithReplayDone and is not filtered. Because filtered events still
+// consume sequence numbers, watchers that filter events can't detect dropped events from sequence gaps.
+// By default, events of all types are delivered.
+func WithEventTypes(types ...EventType) WatchOption {
+	return func(options *watchOptions) {
+		options.eventTypes = make(map[EventType]bool)
+		for _, eventType := range types {
+			options.eventTypes[eventType] = true
+		}
+	}
+}
+
+// withDeviceIDs restricts the events delivered to the watcher to events for the given devices
+// The option is applied by WatchDevices.
+func withDeviceIDs(ids []string) WatchOption {
+	return func(options *watchOptions) {
+		options.deviceIDs = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			options.deviceIDs[id] = true
+		}
+	}
+}
+
+// droppedEvents counts the number of events dropped by watchers using the OverflowDropOldest policy
+var droppedEvents = expvar.NewInt("topo_device_watch_dropped_events")
+
+// evictedWatchers counts the number of watchers evicted by the OverflowEvict policy
+var evictedWatchers = expvar.NewInt("topo_device_watch_evicted_watchers")
+
+// abandonedWatchers counts the number of watchers removed because their reader stopped reading
+var abandonedWatchers = expvar.NewInt("topo_device_watch_abandoned_watchers")
+
+// watchStallTimeout is the time after which the reader of a watch without a cancelable context that accepts
+// no event is considered to have exited
+// Such watches can't be canceled by their reader, so the watcher is removed and its channel closed to keep
+// it from blocking the delivery of events to other watchers.
+var watchStallTimeout = time.Minute
+
+// defaultWatchBufferSize is the default number of events buffered for each watcher
+const defaultWatchBufferSize = 1000
+
+// atomixStore is the device implementation of the Store
+type atomixStore struct {
+	devices             map_.Map
+	annotations         map_.Map
+	groups              map_.Map
+	createLocks         []lock.Lock
+	mu                  sync.Mutex
+	watchers            []*watcher
+	dispatcher          dispatcher
+	watching            bool
+	watchingAnnotations bool
+	seq                 uint64
+	logger              Logger
+
+	// compressionThreshold is the size above which encoded devices are compressed
+	compressionThreshold int
+
+	// maxValueSize is the maximum size of an encoded device
+	maxValueSize int
+
+	// listWorkers is the number of workers that decode listed devices
+	listWorkers int
+
+	// listBufferSize is the number of listed map entries buffered ahead of the workers
+	listBufferSize int
+
+	// lastKnown is the last known value of each device, used to populate the previous device of events
+	lastKnown map[string]*Device
+
+	// watchBackoff is the backoff with which the device map watch is re-established
+	watchBackoff watchBackoff
+
+	// atomixGroups is the names of the Atomix groups across which devices are sharded
+	atomixGroups []string
+}
+
+// encode encodes the given device to be stored, returning ErrValueTooLarge if the device is too large
+func (s *atomixStore) encode(device *Device) ([]byte, error) {
+	bytes, err := encodeDevice(device, s.compressionThreshold)
+	if err != nil {
+		return nil, err
+	}
+	return bytes, checkValueSize(device.Id, bytes, s.maxValueSize)
+}
+
+// createLock returns the create lock of the group in which the given device is stored
+// Creates are only serialized with creates of devices in the same group.
+func (s *atomixStore) createLock(deviceID string) lock.Lock {
+	return s.createLocks[shardIndex(deviceID, len(s.createLocks))]
+}
+
+// lockCreate acquires the create lock of the given device, returning a function that releases it
+// The Atomix map doesn't support conditional puts of absent keys, so every write that may add a device to the
+// map is made while holding the lock, making the existence check of a create atomic with the other writes.
+func (s *atomixStore) lockCreate(ctx context.Context, deviceID string, operation string) (func(), error) {
+	if _, err := s.createLock(deviceID).Lock(ctx); err != nil {
+		return nil, err
+	}
+	return func() {
+		if _, err := s.createLock(deviceID).Unlock(context.Background()); err != nil {
+			s.logger.Error("Failed to release the create lock", DeviceIDField(deviceID), OperationField(operation), ErrorField(err))
+		}
+	}, nil
+}
+
+func (s *atomixStore) Load(ctx context.Context, deviceID string) (*Device, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	kv, err := s.devices.Get(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	} else if kv == nil {
+		return nil, nil
+	}
+	return decodeDevice(kv.Key, kv.Value, kv.Version)
+}
+
+func (s *atomixStore) Exists(ctx context.Context, deviceID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	kv, err := s.devices.Get(ctx, deviceID)
+	if err != nil {
+		return false, err
+	}
+	return kv != nil, nil
+}
+
+func (s *atomixStore) Count(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	return s.devices.Size(ctx)
+}
+
+// Create stores a new device while holding the create lock of the device's group
+// The Atomix map doesn't support conditional puts of absent keys, so creates are serialized across all
+// replicas of the service with a distributed lock to make the existence check and put atomic. Other writes
+// that may add the device, i.e. unversioned stores, forced puts, renames and transactions, hold the same lock.
+func (s *atomixStore) Create(ctx context.Context, device *Device) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	unlock, err := s.lockCreate(ctx, device.Id, "create")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	kv, err := s.devices.Get(ctx, device.Id)
+	if err != nil {
+		return err
+	} else if kv != nil {
+		return ErrAlreadyExists
+	}
+	device.Metadata = nil
+	return s.put(ctx, device)
+}
+
+func (s *atomixStore) EnsureDevice(ctx context.Context, device *Device) (*Device, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	unlock, err := s.lockCreate(ctx, device.Id, "ensure")
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+
+	kv, err := s.devices.Get(ctx, device.Id)
+	if err != nil {
+		return nil, false, err
+	} else if kv != nil {
+		stored, err := decodeDevice(kv.Key, kv.Value, kv.Version)
+		return stored, false, err
+	}
+	device.Metadata = nil
+	if err := s.put(ctx, device); err != nil {
+		return nil, false, err
+	}
+	return device, true, nil
+}
+
+func (s *atomixStore) Store(ctx context.Context, device *Device) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// A store without a version may add the device, so it's serialized with creates
+	if device.Metadata == nil || device.Metadata.Version == 0 {
+		unlock, err := s.lockCreate(ctx, device.Id, "store")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	return s.put(ctx, device)
+}
+
+// put puts the given device in the map, conditioned on the device's version if it's set
+func (s *atomixStore) put(ctx context.Context, device *Device) error {
+	bytes, err := s.encode(device)
+	if err != nil {
+		return err
+	}
+
+	// Put the device in the map using an optimistic lock if this is an update
+	var kv *map_.KeyValue
+	if device.Metadata == nil || device.Metadata.Version == 0 {
+		kv, err = s.devices.Put(ctx, device.Id, bytes)
+	} else {
+		kv, err = s.devices.Put(ctx, device.Id, bytes, map_.WithVersion(int64(device.Metadata.Version)))
+	}
+
+	if err != nil {
+		s.logger.Warn("Failed to store device", DeviceIDField(device.Id), OperationField("store"), VersionField(device.GetMetadata().GetVersion()), ErrorField(err))
+		return conflictError(err)
+	}
+
+	// Update the device metadata
+	device.Metadata = &ObjectMetadata{
+		Id:      device.Id,
+		Version: uint64(kv.Version),
+	}
+	return err
+}
+
+func (s *atomixStore) ForcePut(ctx context.Context, device *Device) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	bytes, err := s.encode(device)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := s.lockCreate(ctx, device.Id, "force-put")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	kv, err := s.devices.Put(ctx, device.Id, bytes)
+	if err != nil {
+		s.logger.Warn("Failed to store device", DeviceIDField(device.Id), OperationField("force-put"), ErrorField(err))
+		return err
+	}
+
+	// Update the device metadata
+	device.Metadata = &ObjectMetadata{
+		Id:      device.Id,
+		Version: uint64(kv.Version),
+	}
+	return nil
+}
+
+func (s *atomixStore) Delete(ctx context.Context, device *Device) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	id := device.Id
+	if device.Metadata != nil && device.Metadata.Version > 0 {
+		id = device.Metadata.Id
+	}
+	if kv, err := s.devices.Get(ctx, id); err != nil {
+		return err
+	} else if kv == nil {
+		return ErrNotFound
+	}
+
+	var err error
+	if device.Metadata != nil && device.Metadata.Version > 0 {
+		_, err = s.devices.Remove(ctx, id, map_.WithVersion(int64(device.Metadata.Version)))
+	} else {
+		_, err = s.devices.Remove(ctx, id)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to delete device", DeviceIDField(id), OperationField("delete"), VersionField(device.GetMetadata().GetVersion()), ErrorField(err))
+		return conflictError(err)
+	}
+	// The device is already removed, so as in commit, a failure to remove its annotations is only logged
+	if _, err := s.annotations.Remove(ctx, id); err != nil {
+		s.logger.Warn("Failed to delete device annotations", DeviceIDField(id), OperationField("delete"), ErrorField(err))
+	}
+	return nil
+}
+
+func (s *atomixStore) Rename(ctx context.Context, device *Device, newID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	if kv, err := s.devices.Get(ctx, newID); err != nil {
+		return err
+	} else if kv != nil {
+		return ErrAlreadyExists
+	}
+
+	oldID := device.Id
+	if kv, err := s.devices.Get(ctx, oldID); err != nil {
+		return err
+	} else if kv == nil {
+		return ErrNotFound
+	}
+
+	// The device's annotations are read before the old device is removed so they can be moved to the new ID
+	annotations, err := s.annotations.Get(ctx, oldID)
+	if err != nil {
+		return err
+	}
+	children, err := s.ListChildren(ctx, oldID)
+	if err != nil {
+		return err
+	}
+
+	// The children are stored with the versions they were listed with, so the rename fails if a child
+	// changes before the transaction is committed
+	renamed := proto.Clone(device).(*Device)
+	renamed.Id = newID
+	renamed.Metadata = nil
+	t := &txn{}
+	t.ops = append(t.ops, txnOp{device: renamed, create: true})
+	for _, child := range children {
+		child.ParentId = newID
+		t.Put(child)
+	}
+	if annotations != nil {
+		t.ops = append(t.ops, txnOp{device: &Device{Id: newID}, annotations: annotations.Value})
+	}
+	t.Remove(device)
+	if err := s.commit(ctx, t); err != nil {
+		s.logger.Warn("Failed to rename device", DeviceIDField(oldID), OperationField("rename"), VersionField(device.GetMetadata().GetVersion()), ErrorField(err))
+		return err
+	}
+
+	device.Id = newID
+	device.Metadata = renamed.Metadata
+	return nil
+}
+
+func (s *atomixStore) SwapAddresses(ctx context.Context, firstID string, secondID string) (*Device, *Device, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	firstKV, err := s.devices.Get(ctx, firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+	secondKV, err := s.devices.Get(ctx, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if firstKV == nil || secondKV == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	first, err := decodeDevice(firstKV.Key, firstKV.Value, firstKV.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := decodeDevice(secondKV.Key, secondKV.Value, secondKV.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	swapAddresses(first, second)
+
+	// The devices' versions are set by decodeDevice, so the swap fails if either device has changed
+	err = s.Tx(ctx, func(t Txn) error {
+		t.Put(first)
+		t.Put(second)
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("Failed to swap device addresses", DeviceIDField(firstID), Field{Key: "peer", Value: secondID}, OperationField("swap-addresses"), ErrorField(err))
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+// swapAddresses swaps the address and failover addresses of the given devices
+func swapAddresses(first *Device, second *Device) {
+	first.Address, second.Address = second.Address, first.Address
+	first.Addresses, second.Addresses = second.Addresses, first.Addresses
+}
+
+func (s *atomixStore) Clear(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// Devices are removed individually rather than clearing the map so that watchers receive a
+	// REMOVED event for each device
+	mapCh := make(chan *map_.KeyValue)
+	if err := s.devices.Entries(ctx, mapCh); err != nil {
+		return err
+	}
+	var keys []string
+	for kv := range mapCh {
+		keys = append(keys, kv.Key)
+	}
+	for _, key := range keys {
+		if _, err := s.devices.Remove(ctx, key); err != nil {
+			s.logger.Warn("Failed to delete device", DeviceIDField(key), OperationField("clear"), ErrorField(err))
+			return err
+		}
+	}
+	if err := s.annotations.Clear(ctx); err != nil {
+		s.logger.Warn("Failed to clear device annotations", OperationField("clear"), ErrorField(err))
+		return err
+	}
+	s.logger.Info("Cleared devices", OperationField("clear"), Field{Key: "devices", Value: len(keys)})
+	return nil
+}
+
+// txnUndo records how to undo a write committed by an Atomix transaction
+type txnUndo struct {
+	id          string
+	prev        *map_.KeyValue
+	version     int64
+	annotations bool
+}
+
+func (s *atomixStore) Tx(ctx context.Context, fn func(Txn) error) error {
+	t := &txn{}
+	if err := fn(t); err != nil {
+		return err
+	}
+	return s.commit(ctx, t)
+}
+
+// commit applies the writes buffered in the given transaction
+func (s *atomixStore) commit(ctx context.Context, t *txn) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// The map does not support transactions, so the writes are applied one at a time using optimistic
+	// locks, and the applied writes are undone in reverse order if a write fails
+	var undos []txnUndo
+	for _, op := range t.ops {
+		undo, err := s.commitOp(ctx, op)
+		if err != nil {
+			s.logger.Warn("Failed to commit transaction", DeviceIDField(op.device.Id), OperationField("tx"), VersionField(op.device.GetMetadata().GetVersion()), ErrorField(err))
+			s.rollback(undos)
+			return err
+		}
+		undos = append(undos, undo)
+	}
+
+	for i, op := range t.ops {
+		if op.remove {
+			if _, err := s.annotations.Remove(ctx, op.device.Id); err != nil {
+				s.logger.Warn("Failed to delete device annotations", DeviceIDField(op.device.Id), OperationField("tx"), ErrorField(err))
+			}
+		} else if op.annotations == nil {
+			op.device.Metadata = &ObjectMetadata{
+				Id:      op.device.Id,
+				Version: uint64(undos[i].version),
+			}
+		}
+	}
+	return nil
+}
+
+// commitOp applies a single transaction write, returning how to undo the write
+// If the device's version is not set, the write is still conditioned on the version of the stored device
+// so that the stored device can be restored on rollback, and it's made while holding the create lock since
+// it may add the device.
+func (s *atomixStore) commitOp(ctx context.Context, op txnOp) (txnUndo, error) {
+	id := op.device.Id
+	if op.annotations != nil {
+		kv, err := s.annotations.Put(ctx, id, op.annotations)
+		if err != nil {
+			return txnUndo{}, err
+		}
+		return txnUndo{id: id, version: kv.Version, annotations: true}, nil
+	}
+
+	version := int64(op.device.GetMetadata().GetVersion())
+	if version == 0 && !op.remove {
+		unlock, err := s.lockCreate(ctx, id, "tx")
+		if err != nil {
+			return txnUndo{}, err
+		}
+		defer unlock()
+	}
+
+	prev, err := s.devices.Get(ctx, id)
+	if err != nil {
+		return txnUndo{}, err
+	}
+
+	if op.create && prev != nil {
+		return txnUndo{}, ErrAlreadyExists
+	} else if version == 0 && prev != nil {
+		version = prev.Version
+	} else if version != 0 && (prev == nil || prev.Version != version) {
+		return txnUndo{}, ErrConflict
+	}
+
+	if op.remove {
+		if prev == nil {
+			return txnUndo{id: id}, nil
+		}
+		if _, err := s.devices.Remove(ctx, id, map_.WithVersion(version)); err != nil {
+			return txnUndo{}, conflictError(err)
+		}
+		return txnUndo{id: id, prev: prev}, nil
+	}
+
+	bytes, err := s.encode(op.device)
+	if err != nil {
+		return txnUndo{}, err
+	}
+	var kv *map_.KeyValue
+	if version == 0 {
+		kv, err = s.devices.Put(ctx, id, bytes)
+	} else {
+		kv, err = s.devices.Put(ctx, id, bytes, map_.WithVersion(version))
+	}
+	if err != nil {
+		return txnUndo{}, conflictError(err)
+	}
+	return txnUndo{id: id, prev: prev, version: kv.Version}, nil
+}
+
+// rollback undoes the given transaction writes in reverse order
+// The writes are undone under a new context, since the transaction may have failed because its context was
+// canceled or timed out.
+func (s *atomixStore) rollback(undos []txnUndo) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	for i := len(undos) - 1; i >= 0; i-- {
+		undo := undos[i]
+		var err error
+		if undo.annotations {
+			_, err = s.annotations.Remove(ctx, undo.id)
+		} else if undo.prev == nil && undo.version != 0 {
+			_, err = s.devices.Remove(ctx, undo.id, map_.WithVersion(undo.version))
+		} else if undo.prev != nil && undo.version != 0 {
+			_, err = s.devices.Put(ctx, undo.id, undo.prev.Value, map_.WithVersion(undo.version))
+		} else if undo.prev != nil {
+			_, err = s.devices.Put(ctx, undo.id, undo.prev.Value)
+		}
+		if err != nil {
+			s.logger.Error("Failed to roll back transaction", DeviceIDField(undo.id), OperationField("tx"), VersionField(uint64(undo.version)), ErrorField(err))
+		}
+	}
+}
+
+func (s *atomixStore) LoadAnnotations(ctx context.Context, deviceID string) (*Annotations, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	kv, err := s.annotations.Get(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	} else if kv == nil {
+		return nil, nil
+	}
+	return decodeAnnotations(kv.Key, kv.Value, kv.Version)
+}
+
+func (s *atomixStore) StoreAnnotations(ctx context.Context, annotations *Annotations) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	bytes, err := proto.Marshal(annotations)
+	if err != nil {
+		return err
+	}
+
+	// Put the annotations in the map using an optimistic lock if the version is set
+	var kv *map_.KeyValue
+	if annotations.Version == 0 {
+		kv, err = s.annotations.Put(ctx, annotations.DeviceId, bytes)
+	} else {
+		kv, err = s.annotations.Put(ctx, annotations.DeviceId, bytes, map_.WithVersion(int64(annotations.Version)))
+	}
+	if err != nil {
+		return err
+	}
+	annotations.Version = uint64(kv.Version)
+	return nil
+}
+
+// CreateGroup stores a new device group while holding the store's create lock
+func (s *atomixStore) CreateGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	if _, err := s.createLocks[0].Lock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := s.createLocks[0].Unlock(context.Background()); err != nil {
+			s.logger.Error("Failed to release the create lock", Field{Key: "group", Value: group.Id}, OperationField("create-group"), ErrorField(err))
+		}
+	}()
+
+	kv, err := s.groups.Get(ctx, group.Id)
+	if err != nil {
+		return err
+	} else if kv != nil {
+		return ErrAlreadyExists
+	}
+	group.Version = 0
+	return s.StoreGroup(ctx, group)
+}
+
+func (s *atomixStore) StoreGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	bytes, err := proto.Marshal(group)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	group, err := client.GetGroup(context.Background(), util.GetAtomixRaftGroup())
+	// Put the group in the map using an optimistic lock if the version is set
+	var kv *map_.KeyValue
+	if group.Version == 0 {
+		kv, err = s.groups.Put(ctx, group.Id, bytes)
+	} else {
+		kv, err = s.groups.Put(ctx, group.Id, bytes, map_.WithVersion(int64(group.Version)))
+	}
 	if err != nil {
-		return nil, err
+		return conflictError(err)
 	}
+	group.Version = uint64(kv.Version)
+	return nil
+}
+
+func (s *atomixStore) LoadGroup(ctx context.Context, groupID string) (*DeviceGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
 
-	devices, err := group.GetMap(context.Background(), "devices", session.WithTimeout(30*time.Second))
+	kv, err := s.groups.Get(ctx, groupID)
 	if err != nil {
 		return nil, err
+	} else if kv == nil {
+		return nil, nil
 	}
-
-	return &atomixStore{
-		devices: devices,
-	}, nil
+	return decodeGroup(kv.Key, kv.Value, kv.Version)
 }
 
-// Store stores topology information
-type Store interface {
-	// Load loads a device from the store
-	Load(deviceID string) (*Device, error)
-
-	// Store stores a device in the store
-	Store(*Device) error
+func (s *atomixStore) DeleteGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
 
-	// Delete deletes a device from the store
-	Delete(*Device) error
+	if kv, err := s.groups.Get(ctx, group.Id); err != nil {
+		return err
+	} else if kv == nil {
+		return ErrNotFound
+	}
 
-	// List streams devices to the given channel
-	List(chan<- *Device) error
+	var err error
+	if group.Version > 0 {
+		_, err = s.groups.Remove(ctx, group.Id, map_.WithVersion(int64(group.Version)))
+	} else {
+		_, err = s.groups.Remove(ctx, group.Id)
+	}
+	return conflictError(err)
+}
 
-	// Watch streams device events to the given channel
-	Watch(chan<- *Event) error
+func (s *atomixStore) ListGroups(ctx context.Context) ([]*DeviceGroup, error) {
+	mapCh := make(chan *map_.KeyValue)
+	if err := s.groups.Entries(ctx, mapCh); err != nil {
+		return nil, err
+	}
+	var groups []*DeviceGroup
+	for kv := range mapCh {
+		group, err := decodeGroup(kv.Key, kv.Value, kv.Version)
+		if err != nil {
+			s.logger.Error("Failed to decode device group", Field{Key: "group", Value: kv.Key}, OperationField("list-groups"), ErrorField(err))
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
 }
 
-// atomixStore is the device implementation of the Store
-type atomixStore struct {
-	devices map_.Map
+func (s *atomixStore) List(ctx context.Context, ch chan<- *Device) error {
+	mapCh := make(chan *map_.KeyValue, s.listBufferSize)
+	if err := s.devices.Entries(ctx, mapCh); err != nil {
+		return err
+	}
+
+	decodeEntries(mapCh, ch, s.listWorkers, func(kv *map_.KeyValue, err error) {
+		s.logger.Error("Failed to decode device", DeviceIDField(kv.Key), OperationField("list"), VersionField(uint64(kv.Version)), ErrorField(err))
+	})
+	return nil
 }
 
-func (s *atomixStore) Load(deviceID string) (*Device, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+// ListModifiedSince lists all devices and streams those modified since the given time
+// The Atomix map isn't indexed by update time, so every device is read and decoded, and the cost is the same
+// as listing the whole store regardless of how few devices were modified.
+func (s *atomixStore) ListModifiedSince(ctx context.Context, since time.Time, ch chan<- *Device) error {
+	deviceCh := make(chan *Device)
+	if err := s.List(ctx, deviceCh); err != nil {
+		return err
+	}
+	go filterModifiedSince(since, deviceCh, ch)
+	return nil
+}
 
-	kv, err := s.devices.Get(ctx, deviceID)
-	if err != nil {
+func (s *atomixStore) ListChildren(ctx context.Context, parentID string) ([]*Device, error) {
+	mapCh := make(chan *map_.KeyValue)
+	if err := s.devices.Entries(ctx, mapCh); err != nil {
 		return nil, err
 	}
-	return decodeDevice(kv.Key, kv.Value, kv.Version)
+
+	children := make([]*Device, 0)
+	for kv := range mapCh {
+		device, err := decodeDevice(kv.Key, kv.Value, kv.Version)
+		if err != nil {
+			s.logger.Error("Failed to decode device", DeviceIDField(kv.Key), OperationField("list-children"), VersionField(uint64(kv.Version)), ErrorField(err))
+		} else if device.ParentId == parentID {
+			children = append(children, device)
+		}
+	}
+	return children, nil
 }
 
-func (s *atomixStore) Store(device *Device) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+func (s *atomixStore) Watch(ch chan<- *Event, opts ...WatchOption) error {
+	return s.WatchFrom(context.Background(), 0, ch, opts...)
+}
 
-	bytes, err := proto.Marshal(device)
-	if err != nil {
-		return err
+func (s *atomixStore) VersionsOrdered() bool {
+	return len(s.atomixGroups) <= 1
+}
+
+func (s *atomixStore) WatchFrom(ctx context.Context, version uint64, ch chan<- *Event, opts ...WatchOption) error {
+	if version > 0 && !s.VersionsOrdered() {
+		return ErrUnorderedVersions
 	}
 
-	// Put the device in the map using an optimistic lock if this is an update
-	var kv *map_.KeyValue
-	if device.Metadata == nil || device.Metadata.Version == 0 {
-		kv, err = s.devices.Put(ctx, device.Id, bytes)
-	} else {
-		kv, err = s.devices.Put(ctx, device.Id, bytes, map_.WithVersion(int64(device.Metadata.Version)))
+	options := &watchOptions{
+		bufferSize: defaultWatchBufferSize,
+		policy:     OverflowBlock,
+	}
+	for _, opt := range opts {
+		opt(options)
 	}
 
+	w := newWatcher(options)
+
+	// Register the watcher before listing the current devices to ensure no events are missed
+	// between the replay and the live events
+	seq, err := s.addWatcher(w)
 	if err != nil {
 		return err
 	}
 
-	// Update the device metadata
-	device.Metadata = &ObjectMetadata{
-		Id:      device.Id,
-		Version: uint64(kv.Version),
+	deviceCh := make(chan *Device)
+	if options.noReplay {
+		close(deviceCh)
+	} else if err := s.List(ctx, deviceCh); err != nil {
+		s.removeWatcher(w)
+		return err
 	}
-	return err
+
+	// Map versions increase monotonically, so only devices updated since the given version are replayed
+	replayCh := make(chan *Device)
+	go func() {
+		defer close(replayCh)
+		for device := range deviceCh {
+			if device.Metadata.Version > version {
+				replayCh <- device
+			}
+		}
+	}()
+	forwardEvents(ctx, w, seq, replayCh, ch, s.removeWatcher)
+	return nil
 }
 
-func (s *atomixStore) Delete(device *Device) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+func (s *atomixStore) WatchDevices(ctx context.Context, ids []string, ch chan<- *Event, opts ...WatchOption) error {
+	options := &watchOptions{
+		bufferSize: defaultWatchBufferSize,
+		policy:     OverflowBlock,
+	}
+	for _, opt := range append(opts, withDeviceIDs(ids)) {
+		opt(options)
+	}
 
-	if device.Metadata != nil && device.Metadata.Version > 0 {
-		_, err := s.devices.Remove(ctx, device.Metadata.Id, map_.WithVersion(int64(device.Metadata.Version)))
+	w := newWatcher(options)
+
+	// Register the watcher before loading the listed devices to ensure no events are missed
+	// between the replay and the live events
+	seq, err := s.addWatcher(w)
+	if err != nil {
 		return err
 	}
-	_, err := s.devices.Remove(ctx, device.Id)
-	return err
+
+	// Only the listed devices are loaded rather than listing the whole map
+	var devices []*Device
+	if !options.noReplay && w.accepts(EventNone) {
+		for id := range w.deviceIDs {
+			device, err := s.Load(ctx, id)
+			if err != nil {
+				s.removeWatcher(w)
+				return err
+			} else if device != nil {
+				devices = append(devices, device)
+			}
+		}
+	}
+
+	deviceCh := make(chan *Device, len(devices))
+	for _, device := range devices {
+		deviceCh <- device
+	}
+	close(deviceCh)
+	forwardEvents(ctx, w, seq, deviceCh, ch, s.removeWatcher)
+	return nil
 }
 
-func (s *atomixStore) List(ch chan<- *Device) error {
-	mapCh := make(chan *map_.KeyValue)
-	if err := s.devices.Entries(context.Background(), mapCh); err != nil {
-		return err
+// forwardEvents replays the given devices and then forwards the watcher's events to the given channel
+// If the context is canceled, the watcher is removed and the channel is closed. A watch without a cancelable
+// context can only be ended by the store, so if its reader accepts no event for watchStallTimeout, the reader
+// is assumed to have exited and the watcher is removed.
+func forwardEvents(ctx context.Context, w *watcher, seq uint64, devices <-chan *Device, ch chan<- *Event, remove func(*watcher)) {
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				remove(w)
+			case <-w.done:
+			}
+		}()
+	}
+
+	stopped := false
+	send := func(event *Event) {
+		if stopped {
+			return
+		}
+		select {
+		case ch <- event:
+			return
+		default:
+		}
+		var stall <-chan time.Time
+		if ctx.Done() == nil {
+			timer := time.NewTimer(w.stallTimeout)
+			defer timer.Stop()
+			stall = timer.C
+		}
+		select {
+		case ch <- event:
+			return
+		case <-ctx.Done():
+		case <-w.done:
+		case <-stall:
+			abandonedWatchers.Add(1)
+			remove(w)
+		}
+		stopped = true
 	}
 
 	go func() {
 		defer close(ch)
-		for kv := range mapCh {
-			if device, err := decodeDevice(kv.Key, kv.Value, kv.Version); err == nil {
-				ch <- device
+		if w.ordered {
+			devices = orderDevices(devices)
+		}
+		// The replayed devices are consumed even once the watch is stopped so that the listing completes
+		for device := range devices {
+			if !w.accepts(EventNone) {
+				continue
+			}
+			send(&Event{
+				Type:    EventNone,
+				Device:  device,
+				Seq:     seq,
+				Version: device.GetMetadata().GetVersion(),
+			})
+		}
+		if w.replayDone {
+			send(&Event{
+				Type: EventReplayDone,
+				Seq:  seq,
+			})
+		}
+		for !stopped {
+			select {
+			case event, ok := <-w.queue:
+				// The queue is closed only when the watcher is evicted
+				if !ok {
+					send(&Event{Type: EventEvicted})
+					return
+				}
+				send(event)
+			case <-ctx.Done():
+				return
+			case <-w.done:
+				return
 			}
 		}
 	}()
-	return nil
 }
 
-func (s *atomixStore) Watch(ch chan<- *Event) error {
-	mapCh := make(chan *map_.MapEvent)
-	if err := s.devices.Watch(context.Background(), mapCh, map_.WithReplay()); err != nil {
+// orderDevices returns a channel to which the devices received from the given channel are sent in ID order
+// All devices are received before the first device is sent.
+func orderDevices(devices <-chan *Device) <-chan *Device {
+	var sorted []*Device
+	for device := range devices {
+		sorted = append(sorted, device)
+	}
+	sortDevices(sorted)
+	ch := make(chan *Device, len(sorted))
+	for _, device := range sorted {
+		ch <- device
+	}
+	close(ch)
+	return ch
+}
+
+// addWatcher registers a watcher to receive store events, returning the current event sequence number
+// The underlying map is watched when the first watcher is registered, and all watchers share the same
+// map watch so that each event is assigned a single sequence number.
+func (s *atomixStore) addWatcher(w *watcher) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.watching {
+		mapCh := make(chan *map_.MapEvent)
+		if err := s.devices.Watch(context.Background(), mapCh); err != nil {
+			s.logger.Error("Failed to watch devices", OperationField("watch"), ErrorField(err))
+			return 0, err
+		}
+		s.logger.Info("Started watching devices", OperationField("watch"))
+		s.watching = true
+		s.lastKnown = s.loadLastKnown()
+		go s.processEvents(mapCh)
+	}
+	if w.annotations && !s.watchingAnnotations {
+		mapCh := make(chan *map_.MapEvent)
+		if err := s.annotations.Watch(context.Background(), mapCh); err != nil {
+			s.logger.Error("Failed to watch device annotations", OperationField("watch"), ErrorField(err))
+			return 0, err
+		}
+		s.logger.Info("Started watching device annotations", OperationField("watch"))
+		s.watchingAnnotations = true
+		go s.processAnnotationEvents(mapCh)
+	}
+	s.watchers = append(s.watchers, w)
+	s.logger.Debug("Added device watcher", OperationField("watch"), Field{Key: "watchers", Value: len(s.watchers)})
+	return s.seq, nil
+}
+
+// removeWatcher unregisters the given watcher and stops forwarding its events
+func (s *atomixStore) removeWatcher(w *watcher) {
+	s.unregisterWatcher(w)
+	w.stop()
+}
+
+// unregisterWatcher unregisters the given watcher so that no further events are published to it
+func (s *atomixStore) unregisterWatcher(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, watcher := range s.watchers {
+		if watcher == w {
+			s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+			s.logger.Debug("Removed device watcher", OperationField("watch"), Field{Key: "watchers", Value: len(s.watchers)})
+			return
+		}
+	}
+}
+
+// processEvents assigns a sequence number to each map event and publishes it to all registered watchers
+// If the map watch is closed, it's re-established and watchers continue to receive events.
+func (s *atomixStore) processEvents(mapCh <-chan *map_.MapEvent) {
+	for mapCh != nil {
+		for mapEvent := range mapCh {
+			event, err := decodeEvent(mapEvent)
+			if err != nil {
+				s.logger.Error("Failed to decode device event", DeviceIDField(mapEvent.Key), OperationField("watch"), VersionField(uint64(mapEvent.Version)), ErrorField(err))
+				continue
+			}
+
+			s.mu.Lock()
+			s.trackLastKnown(event)
+			s.seq++
+			event.Seq = s.seq
+			s.dispatcher.dispatch(s.watchers, event, s.unregisterWatcher)
+			s.mu.Unlock()
+		}
+		mapCh = s.rewatch()
+	}
+}
+
+// rewatch re-establishes the device map watch after it's closed, retrying with the store's backoff
+// Once the map is watched again, the changes missed while the map wasn't watched are published to the
+// watchers. If all the watchers are removed before the map is watched again, the map is no longer watched
+// and nil is returned.
+func (s *atomixStore) rewatch() <-chan *map_.MapEvent {
+	s.logger.Warn("Device watch closed; re-establishing the watch", OperationField("watch"))
+	var mapCh chan *map_.MapEvent
+	for attempt := 1; ; attempt++ {
+		time.Sleep(s.watchBackoff.delay(attempt))
+
+		// Once the map is watched again, only the resync is retried until it succeeds
+		if mapCh == nil {
+			s.mu.Lock()
+			if len(s.watchers) == 0 {
+				s.watching = false
+				s.lastKnown = nil
+				s.mu.Unlock()
+				s.logger.Info("Stopped watching devices", OperationField("watch"))
+				return nil
+			}
+			s.mu.Unlock()
+
+			mapCh = make(chan *map_.MapEvent)
+			if err := s.devices.Watch(context.Background(), mapCh); err != nil {
+				mapCh = nil
+				s.logger.Warn("Failed to re-establish the device watch", OperationField("watch"), Field{Key: "attempt", Value: attempt}, ErrorField(err))
+				continue
+			}
+		}
+		if err := s.resync(); err != nil {
+			s.logger.Warn("Failed to resynchronize devices", OperationField("watch"), Field{Key: "attempt", Value: attempt}, ErrorField(err))
+			continue
+		}
+		s.logger.Info("Re-established the device watch", OperationField("watch"), Field{Key: "attempt", Value: attempt})
+		return mapCh
+	}
+}
+
+// resync publishes an event for each device change missed while the device map wasn't watched
+// The stored devices are compared with their last known values: new devices are published as inserted,
+// devices with a different version as updated, and last known devices that are no longer stored as removed.
+// The map is watched before the devices are listed, so a missed change may be published again when its map
+// event is received.
+func (s *atomixStore) resync() error {
+	mapCh := make(chan *map_.KeyValue)
+	if err := s.devices.Entries(context.Background(), mapCh); err != nil {
 		return err
 	}
+	current := make(map[string]*Device)
+	for kv := range mapCh {
+		device, err := decodeDevice(kv.Key, kv.Value, kv.Version)
+		if err != nil {
+			s.logger.Error("Failed to decode device", DeviceIDField(kv.Key), OperationField("watch"), ErrorField(err))
+			continue
+		}
+		current[kv.Key] = device
+	}
 
-	go func() {
-		defer close(ch)
-		for event := range mapCh {
-			if device, err := decodeDevice(event.Key, event.Value, event.Version); err == nil {
-				ch <- &Event{
-					Type:   EventType(event.Type),
-					Device: device,
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []*Event
+	for id, device := range current {
+		if prev, ok := s.lastKnown[id]; !ok {
+			events = append(events, &Event{Type: EventInserted, Device: device})
+		} else if prev.Metadata.Version != device.Metadata.Version {
+			events = append(events, &Event{Type: EventUpdated, Device: device, PrevDevice: prev})
+		}
+	}
+	for id, prev := range s.lastKnown {
+		if _, ok := current[id]; !ok {
+			events = append(events, &Event{Type: EventRemoved, Device: prev, PrevDevice: prev})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Device.Id < events[j].Device.Id
+	})
+	for _, event := range events {
+		s.seq++
+		event.Seq = s.seq
+		s.dispatcher.dispatch(s.watchers, event, s.unregisterWatcher)
+	}
+	s.lastKnown = current
+	s.logger.Info("Resynchronized devices", OperationField("watch"), Field{Key: "changes", Value: len(events)})
+	return nil
+}
+
+// loadLastKnown returns the current value of each device in the store
+// The devices are listed after the map watch is registered, so changes made while devices are listed may
+// be both reflected in the listed devices and delivered as events.
+func (s *atomixStore) loadLastKnown() map[string]*Device {
+	lastKnown := make(map[string]*Device)
+	mapCh := make(chan *map_.KeyValue)
+	if err := s.devices.Entries(context.Background(), mapCh); err != nil {
+		s.logger.Warn("Failed to load devices for previous device values", OperationField("watch"), ErrorField(err))
+		return lastKnown
+	}
+	for kv := range mapCh {
+		if device, err := decodeDevice(kv.Key, kv.Value, kv.Version); err == nil {
+			lastKnown[kv.Key] = device
+		}
+	}
+	return lastKnown
+}
+
+// trackLastKnown sets the previous device of the given event and records the event's device as the
+// last known value of the device
+// The caller must hold the store's lock.
+func (s *atomixStore) trackLastKnown(event *Event) {
+	id := event.Device.Id
+	prev, ok := s.lastKnown[id]
+	if ok && prev.Metadata.Version >= event.Device.Metadata.Version && event.Type != EventRemoved {
+		// The last known value was loaded after the event occurred, so the previous value is unknown
+		prev = nil
+	}
+	switch event.Type {
+	case EventUpdated:
+		event.PrevDevice = prev
+		s.lastKnown[id] = event.Device
+	case EventInserted:
+		s.lastKnown[id] = event.Device
+	case EventRemoved:
+		// Remove events that carry the removed value are decoded with the previous device already set
+		if event.PrevDevice == nil {
+			event.PrevDevice = prev
+		}
+		delete(s.lastKnown, id)
+	}
+}
+
+// processAnnotationEvents publishes annotation events to all registered watchers that watch annotations
+// Annotation events share the sequence of device events.
+func (s *atomixStore) processAnnotationEvents(mapCh <-chan *map_.MapEvent) {
+	for mapEvent := range mapCh {
+		// Annotations are only removed along with their device, which produces a device event
+		if mapEvent.Type == map_.EventRemoved {
+			continue
+		}
+
+		annotations, err := decodeAnnotations(mapEvent.Key, mapEvent.Value, mapEvent.Version)
+		if err != nil {
+			s.logger.Error("Failed to decode device annotations event", DeviceIDField(mapEvent.Key), OperationField("watch"), VersionField(uint64(mapEvent.Version)), ErrorField(err))
+			continue
+		}
+
+		s.mu.Lock()
+		s.seq++
+		event := &Event{
+			Type:        EventAnnotated,
+			Annotations: annotations,
+			Seq:         s.seq,
+		}
+		s.dispatcher.dispatch(s.watchers, event, s.unregisterWatcher)
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	s.logger.Info("Device annotations watch closed", OperationField("watch"))
+	s.watchingAnnotations = false
+	s.mu.Unlock()
+}
+
+// watcher is a store watch subscriber
+type watcher struct {
+	queue       chan *Event
+	policy      OverflowPolicy
+	annotations bool
+	replayDone  bool
+	eventTypes  map[EventType]bool
+	ordered     bool
+	deviceIDs   map[string]bool
+
+	// done is closed once the watcher is removed, which stops the forwarding and blocking publication of events
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// stallTimeout is the time after which the reader of a watch without a cancelable context is considered
+	// to have exited
+	stallTimeout time.Duration
+
+	// evicted indicates whether the watcher's queue was closed by the OverflowEvict policy
+	evicted bool
+}
+
+// newWatcher returns a watcher with the given watch options
+func newWatcher(options *watchOptions) *watcher {
+	return &watcher{
+		queue:        make(chan *Event, options.bufferSize),
+		policy:       options.policy,
+		annotations:  options.annotations,
+		replayDone:   options.replayDone,
+		eventTypes:   options.eventTypes,
+		ordered:      options.ordered,
+		deviceIDs:    options.deviceIDs,
+		done:         make(chan struct{}),
+		stallTimeout: watchStallTimeout,
+	}
+}
+
+// stop closes the watcher's done channel
+func (w *watcher) stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+// accepts returns whether the watcher receives events of the given type
+func (w *watcher) accepts(eventType EventType) bool {
+	return w.eventTypes == nil || w.eventTypes[eventType]
+}
+
+// watches returns whether the watcher receives events for the device of the given event
+func (w *watcher) watches(event *Event) bool {
+	if w.deviceIDs == nil {
+		return true
+	}
+	if event.Device != nil {
+		return w.deviceIDs[event.Device.Id]
+	}
+	return event.Annotations != nil && w.deviceIDs[event.Annotations.DeviceId]
+}
+
+// dispatcher delivers the events published by a store to its watchers
+// Events are dispatched under the store's lock, which orders them and determines the watchers to which they're
+// delivered, and are delivered by a separate goroutine, so a watcher whose buffer is full delays the delivery
+// of events to other watchers but never blocks the store's writers. The goroutine runs while events are
+// pending delivery.
+type dispatcher struct {
+	mu      sync.Mutex
+	pending []*dispatch
+	running bool
+}
+
+// dispatch is an event pending delivery
+type dispatch struct {
+	event    *Event
+	watchers []*watcher
+
+	// unregister unregisters watchers that are evicted
+	unregister func(*watcher)
+}
+
+// dispatch queues the given event for delivery to the given watchers
+// The event's time is set to the current time. Watchers evicted by the event are unregistered with the given
+// function. The caller must hold the store's lock.
+func (d *dispatcher) dispatch(watchers []*watcher, event *Event, unregister func(*watcher)) {
+	event.Time = time.Now()
+	event.Version = event.Device.GetMetadata().GetVersion()
+	event.PrevVersion = event.PrevDevice.GetMetadata().GetVersion()
+	pending := &dispatch{
+		event:      event,
+		watchers:   append([]*watcher(nil), watchers...),
+		unregister: unregister,
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = append(d.pending, pending)
+	if !d.running {
+		d.running = true
+		go d.run()
+	}
+}
+
+// run delivers pending events in the order in which they were dispatched until no events are pending
+func (d *dispatcher) run() {
+	for {
+		d.mu.Lock()
+		if len(d.pending) == 0 {
+			d.running = false
+			d.mu.Unlock()
+			return
+		}
+		next := d.pending[0]
+		d.pending[0] = nil
+		d.pending = d.pending[1:]
+		d.mu.Unlock()
+
+		for _, w := range next.watchers {
+			if w.evicted {
+				continue
+			}
+			w.publish(next.event)
+			if w.evicted {
+				next.unregister(w)
+			}
+		}
+	}
+}
+
+// publish adds the given event to the watcher's queue according to the watcher's overflow policy
+// Events of types the watcher does not accept, annotation events if the watcher does not watch annotations,
+// and events for devices the watcher does not watch are discarded, as are events published once the watcher
+// is removed.
+func (w *watcher) publish(event *Event) {
+	if !w.accepts(event.Type) || !w.watches(event) || (event.Type == EventAnnotated && !w.annotations) {
+		return
+	}
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	if w.policy == OverflowEvict {
+		select {
+		case w.queue <- event:
+		default:
+			w.evicted = true
+			close(w.queue)
+			evictedWatchers.Add(1)
+		}
+		return
+	}
+	if w.policy == OverflowDropOldest {
+		for {
+			select {
+			case w.queue <- event:
+				return
+			default:
+				select {
+				case <-w.queue:
+					droppedEvents.Add(1)
+				default:
 				}
 			}
 		}
-	}()
-	return nil
+	}
+	select {
+	case w.queue <- event:
+	case <-w.done:
+	}
+}
+
+// decodeEvent decodes a device event from the given map event
+func decodeEvent(mapEvent *map_.MapEvent) (*Event, error) {
+	// Remove events may not carry the removed value, in which case the event device is identified by its key only
+	if mapEvent.Type == map_.EventRemoved && mapEvent.Value == nil {
+		return &Event{
+			Type: EventRemoved,
+			Device: &Device{
+				Id: mapEvent.Key,
+				Metadata: &ObjectMetadata{
+					Id:      mapEvent.Key,
+					Version: uint64(mapEvent.Version),
+				},
+			},
+		}, nil
+	}
+
+	device, err := decodeDevice(mapEvent.Key, mapEvent.Value, mapEvent.Version)
+	if err != nil {
+		return nil, err
+	}
+	event := &Event{
+		Type:   EventType(mapEvent.Type),
+		Device: device,
+	}
+	if event.Type == EventRemoved {
+		event.PrevDevice = device
+	}
+	return event, nil
+}
+
+// filterModifiedSince forwards the devices modified since the given time from in to out, closing out once
+// in is closed
+func filterModifiedSince(since time.Time, in <-chan *Device, out chan<- *Device) {
+	defer close(out)
+	for device := range in {
+		if modifiedSince(device, since) {
+			out <- device
+		}
+	}
+}
+
+// modifiedSince returns whether the given device was updated at or after the given time
+// Devices without an update time may have been modified at any time, so they're considered modified.
+func modifiedSince(device *Device, since time.Time) bool {
+	if device.Updated == nil {
+		return true
+	}
+	updated, err := ptypes.Timestamp(device.Updated)
+	return err != nil || !updated.Before(since)
 }
 
 func decodeDevice(key string, value []byte, version int64) (*Device, error) {
+	schemaVersion, value, err := splitSchemaVersion(value)
+	if err != nil {
+		return nil, err
+	}
+	value, err = decompressValue(value)
+	if err != nil {
+		return nil, err
+	}
 	device := &Device{}
 	if err := proto.Unmarshal(value, device); err != nil {
 		return nil, err
+	} else if err := migrateDevice(device, schemaVersion); err != nil {
+		return nil, err
 	}
 	device.Metadata = &ObjectMetadata{
 		Id:      key,
@@ -169,6 +1915,27 @@ func decodeDevice(key string, value []byte, version int64) (*Device, error) {
 	return device, nil
 }
 
+func decodeAnnotations(key string, value []byte, version int64) (*Annotations, error) {
+	annotations := &Annotations{}
+	if err := proto.Unmarshal(value, annotations); err != nil {
+		return nil, err
+	}
+	annotations.DeviceId = key
+	annotations.Version = uint64(version)
+	return annotations, nil
+}
+
+// decodeGroup decodes a device group from the given map entry
+func decodeGroup(key string, value []byte, version int64) (*DeviceGroup, error) {
+	group := &DeviceGroup{}
+	if err := proto.Unmarshal(value, group); err != nil {
+		return nil, err
+	}
+	group.Id = key
+	group.Version = uint64(version)
+	return group, nil
+}
+
 // EventType provides the type for a device event
 type EventType string
 
@@ -177,10 +1944,50 @@ const (
 	EventInserted EventType = "inserted"
 	EventUpdated  EventType = "updated"
 	EventRemoved  EventType = "removed"
+
+	// EventAnnotated is the type of events for changes to device annotations
+	EventAnnotated EventType = "annotated"
+
+	// EventReplayDone is the type of the event that marks the end of the replay of the current devices
+	// The event carries no device and is only sent to watchers that request it with WithReplayDone.
+	EventReplayDone EventType = "replay-done"
+
+	// EventEvicted is the type of the last event sent to a watcher evicted by the OverflowEvict policy
+	// The event carries no device.
+	EventEvicted EventType = "evicted"
 )
 
 // Event is a store event for a device
 type Event struct {
 	Type   EventType
 	Device *Device
+
+	// PrevDevice is the previous value of the device for EventUpdated and EventRemoved events
+	// The previous value is nil if it's not known to the store, e.g. if the device was changed while
+	// the store started watching devices.
+	PrevDevice *Device
+
+	// Annotations is the device annotations for EventAnnotated events
+	Annotations *Annotations
+
+	// Seq is the sequence number of the event
+	// Sequence numbers are assigned by the store in the order in which events occur and increase
+	// monotonically by one for each event. Events replayed from the current state of the store
+	// carry the sequence number of the last event that occurred before the replay.
+	Seq uint64
+
+	// Time is the time at which the store published the event to its watchers
+	// The time is not set for events replayed from the current state of the store.
+	Time time.Time
+
+	// Version is the store version of the device after the event
+	// For EventRemoved events, it's the version of the removed device. Events replayed from the current state
+	// of the store carry the version of the replayed device.
+	Version uint64
+
+	// PrevVersion is the store version of the device before an EventUpdated or EventRemoved event
+	// The previous version is 0 if the device was inserted or its previous value is not known to the store.
+	// The previous version of each event of a device matches the version of the device's preceding event
+	// unless events were missed, so consumers can verify that they've processed every change to a device.
+	PrevVersion uint64
 }
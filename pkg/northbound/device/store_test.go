@@ -0,0 +1,1263 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/atomix/atomix-go-client/pkg/client/lock"
+	"github.com/atomix/atomix-go-client/pkg/client/map_"
+	"github.com/atomix/atomix-go-client/pkg/client/primitive"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMap is an in-memory map_.Map used to test the Atomix store without an Atomix cluster
+// Versioned writes fail with the error the Atomix map returns for failed write conditions, and each watch
+// buffers its events so that writes never block on watchers.
+type testMap struct {
+	name    string
+	mu      sync.Mutex
+	entries map[string]*map_.KeyValue
+	version int64
+	watches []chan *map_.MapEvent
+
+	// removeValues indicates whether remove events carry the removed value
+	removeValues bool
+
+	// watchErrors is the number of subsequent watches that fail
+	watchErrors int
+
+	// muted indicates whether events are withheld from watches, simulating changes that are missed while
+	// the watches are disconnected
+	muted bool
+
+	// latency is the simulated latency of read responses, which widens the window for races between
+	// operations that read and then write
+	latency time.Duration
+
+	// removeErr, if set, is the error returned by removes
+	removeErr error
+
+	// putErrs are the errors returned by puts of the keys they're mapped to
+	putErrs map[string]error
+}
+
+func newTestMap(name string) *testMap {
+	return &testMap{
+		name:         name,
+		entries:      make(map[string]*map_.KeyValue),
+		removeValues: true,
+	}
+}
+
+// testVersion returns the version condition set with map_.WithVersion, or 0 if no condition is set
+// The version isn't exported by the option, so it's read by reflection.
+func testVersion(opts ...interface{}) int64 {
+	for _, opt := range opts {
+		if option, ok := opt.(map_.VersionOption); ok {
+			return reflect.ValueOf(option).FieldByName("version").Int()
+		}
+	}
+	return 0
+}
+
+// publish sends the given event to all watches
+// The caller must hold the map's lock.
+func (m *testMap) publish(event *map_.MapEvent) {
+	if m.muted {
+		return
+	}
+	for _, watch := range m.watches {
+		watch <- event
+	}
+}
+
+// mute sets whether events are withheld from watches
+func (m *testMap) mute(muted bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.muted = muted
+}
+
+// failWatches sets the number of subsequent watches that fail
+func (m *testMap) failWatches(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchErrors = n
+}
+
+// dropWatches closes all watches, as the Atomix map does when its session is lost
+func (m *testMap) dropWatches() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, watch := range m.watches {
+		close(watch)
+	}
+	m.watches = nil
+}
+
+func (m *testMap) Name() primitive.Name {
+	return primitive.Name{Namespace: "test", Name: m.name}
+}
+
+func (m *testMap) Put(ctx context.Context, key string, value []byte, opts ...map_.PutOption) (*map_.KeyValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	options := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		options[i] = opt
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.putErrs[key]; err != nil {
+		return nil, err
+	}
+	prev, ok := m.entries[key]
+	if version := testVersion(options...); version != 0 && (!ok || prev.Version != version) {
+		return nil, errors.New(ErrConflict.Error())
+	}
+	m.version++
+	kv := &map_.KeyValue{Key: key, Value: value, Version: m.version}
+	m.entries[key] = kv
+	eventType := map_.EventInserted
+	if ok {
+		eventType = map_.EventUpdated
+	}
+	m.publish(&map_.MapEvent{Type: eventType, Key: key, Value: value, Version: kv.Version})
+	return kv, nil
+}
+
+func (m *testMap) Get(ctx context.Context, key string, opts ...map_.GetOption) (*map_.KeyValue, error) {
+	defer time.Sleep(m.latency)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if kv, ok := m.entries[key]; ok {
+		entry := *kv
+		return &entry, nil
+	}
+	return nil, nil
+}
+
+func (m *testMap) Remove(ctx context.Context, key string, opts ...map_.RemoveOption) (*map_.KeyValue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	options := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		options[i] = opt
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.removeErr != nil {
+		return nil, m.removeErr
+	}
+	prev, ok := m.entries[key]
+	if version := testVersion(options...); version != 0 && (!ok || prev.Version != version) {
+		return nil, errors.New(ErrConflict.Error())
+	} else if !ok {
+		return nil, nil
+	}
+	delete(m.entries, key)
+	m.version++
+	event := &map_.MapEvent{Type: map_.EventRemoved, Key: key, Version: m.version}
+	if m.removeValues {
+		event.Value = prev.Value
+		event.Version = prev.Version
+	}
+	m.publish(event)
+	return prev, nil
+}
+
+func (m *testMap) Size(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries), nil
+}
+
+func (m *testMap) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*map_.KeyValue)
+	return nil
+}
+
+func (m *testMap) Entries(ctx context.Context, ch chan<- *map_.KeyValue) error {
+	m.mu.Lock()
+	entries := make([]*map_.KeyValue, 0, len(m.entries))
+	for _, kv := range m.entries {
+		entry := *kv
+		entries = append(entries, &entry)
+	}
+	m.mu.Unlock()
+	go func() {
+		defer close(ch)
+		for _, kv := range entries {
+			ch <- kv
+		}
+	}()
+	return nil
+}
+
+func (m *testMap) Watch(ctx context.Context, ch chan<- *map_.MapEvent, opts ...map_.WatchOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.watchErrors > 0 {
+		m.watchErrors--
+		return errors.New("watch failed")
+	}
+	watch := make(chan *map_.MapEvent, 1000)
+	m.watches = append(m.watches, watch)
+	go func() {
+		defer close(ch)
+		for event := range watch {
+			ch <- event
+		}
+	}()
+	return nil
+}
+
+func (m *testMap) Close() error {
+	return nil
+}
+
+func (m *testMap) Delete() error {
+	return nil
+}
+
+// testLock is an in-memory lock.Lock used to test the Atomix store without an Atomix cluster
+type testLock struct {
+	ch chan struct{}
+}
+
+func newTestLock() *testLock {
+	return &testLock{
+		ch: make(chan struct{}, 1),
+	}
+}
+
+func (l *testLock) Name() primitive.Name {
+	return primitive.Name{Namespace: "test", Name: "device-create"}
+}
+
+func (l *testLock) Lock(ctx context.Context, opts ...lock.LockOption) (uint64, error) {
+	select {
+	case l.ch <- struct{}{}:
+		return 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (l *testLock) Unlock(ctx context.Context, opts ...lock.UnlockOption) (bool, error) {
+	select {
+	case <-l.ch:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *testLock) IsLocked(ctx context.Context, opts ...lock.IsLockedOption) (bool, error) {
+	return len(l.ch) > 0, nil
+}
+
+func (l *testLock) Close() error {
+	return nil
+}
+
+func (l *testLock) Delete() error {
+	return nil
+}
+
+// newTestAtomixStore returns an Atomix store backed by in-memory maps, along with its device map
+func newTestAtomixStore(opts ...AtomixStoreOption) (*atomixStore, *testMap) {
+	devices := newTestMap("devices")
+	store := &atomixStore{
+		devices:      devices,
+		annotations:  newTestMap("device-annotations"),
+		groups:       newTestMap("device-groups"),
+		createLocks:  []lock.Lock{newTestLock()},
+		logger:       NewNopLogger(),
+		atomixGroups: []string{"test"},
+		watchBackoff: watchBackoff{
+			initial: time.Millisecond,
+			max:     10 * time.Millisecond,
+		},
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store, devices
+}
+
+// nextEvent returns the next event received on the given channel, skipping replayed devices
+func nextEvent(t *testing.T, ch <-chan *Event) *Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				t.Fatal("watch closed")
+			} else if event.Type != EventNone {
+				return event
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
+
+func TestDecodeRemoveEvent(t *testing.T) {
+	value, err := encodeDevice(&Device{Id: "device-1", Address: "device-1:5150"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   []byte
+		address string
+	}{
+		{name: "value", value: value, address: "device-1:5150"},
+		{name: "nil value", value: nil, address: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event, err := decodeEvent(&map_.MapEvent{
+				Type:    map_.EventRemoved,
+				Key:     "device-1",
+				Value:   test.value,
+				Version: 2,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if event.Type != EventRemoved {
+				t.Errorf("expected %s event, got %s", EventRemoved, event.Type)
+			}
+			if event.Device.Id != "device-1" || event.Device.Metadata.Version != 2 {
+				t.Errorf("expected device-1 at version 2, got %s at version %d", event.Device.Id, event.Device.Metadata.Version)
+			}
+			if event.Device.Address != test.address {
+				t.Errorf("expected address %q, got %q", test.address, event.Device.Address)
+			}
+		})
+	}
+}
+
+func TestWatchRemoveWithoutValue(t *testing.T) {
+	store, devices := newTestAtomixStore()
+	devices.removeValues = false
+	ctx := context.Background()
+
+	device := &Device{Id: "device-1", Address: "device-1:5150"}
+	if err := store.Store(ctx, device); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan *Event)
+	if err := store.Watch(ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, device); err != nil {
+		t.Fatal(err)
+	}
+
+	// The removed device is identified by the event key, and its last known value is the previous device
+	event := nextEvent(t, ch)
+	if event.Type != EventRemoved {
+		t.Fatalf("expected %s event, got %s", EventRemoved, event.Type)
+	}
+	if event.Device.Id != device.Id {
+		t.Errorf("expected device %s, got %s", device.Id, event.Device.Id)
+	}
+	if event.PrevDevice == nil || event.PrevDevice.Address != device.Address {
+		t.Errorf("expected the last known device as the previous device, got %v", event.PrevDevice)
+	}
+}
+
+func TestWatchBackoffDelay(t *testing.T) {
+	backoff := watchBackoff{
+		initial: 100 * time.Millisecond,
+		max:     time.Second,
+	}
+	tests := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{attempt: 1, delay: 100 * time.Millisecond},
+		{attempt: 2, delay: 200 * time.Millisecond},
+		{attempt: 3, delay: 400 * time.Millisecond},
+		{attempt: 4, delay: 800 * time.Millisecond},
+		{attempt: 5, delay: time.Second},
+		{attempt: 50, delay: time.Second},
+	}
+	for _, test := range tests {
+		if delay := backoff.delay(test.attempt); delay != test.delay {
+			t.Errorf("expected attempt %d to be delayed %s, got %s", test.attempt, test.delay, delay)
+		}
+	}
+}
+
+func TestWatchReconnect(t *testing.T) {
+	tests := []struct {
+		name string
+		// watchErrors is the number of attempts to re-establish the watch that fail
+		watchErrors int
+		// missed are the changes made while the watch is disconnected
+		missed func(ctx context.Context, store *atomixStore) error
+		// after are the changes made after the watch is dropped, once the missed changes are received
+		after  func(ctx context.Context, store *atomixStore) error
+		events map[string]EventType
+	}{
+		{
+			name: "no missed changes",
+			after: func(ctx context.Context, store *atomixStore) error {
+				return store.Store(ctx, &Device{Id: "device-4", Address: "device-4:5150"})
+			},
+			events: map[string]EventType{
+				"device-4": EventInserted,
+			},
+		},
+		{
+			name: "missed changes",
+			missed: func(ctx context.Context, store *atomixStore) error {
+				device, err := store.Load(ctx, "device-1")
+				if err != nil {
+					return err
+				} else if err := store.Delete(ctx, device); err != nil {
+					return err
+				}
+				device, err = store.Load(ctx, "device-2")
+				if err != nil {
+					return err
+				}
+				device.Address = "device-2:5151"
+				if err := store.Store(ctx, device); err != nil {
+					return err
+				}
+				return store.Store(ctx, &Device{Id: "device-3", Address: "device-3:5150"})
+			},
+			events: map[string]EventType{
+				"device-1": EventRemoved,
+				"device-2": EventUpdated,
+				"device-3": EventInserted,
+			},
+		},
+		{
+			name:        "failed watch attempts",
+			watchErrors: 3,
+			missed: func(ctx context.Context, store *atomixStore) error {
+				return store.Store(ctx, &Device{Id: "device-3", Address: "device-3:5150"})
+			},
+			after: func(ctx context.Context, store *atomixStore) error {
+				return store.Store(ctx, &Device{Id: "device-4", Address: "device-4:5150"})
+			},
+			events: map[string]EventType{
+				"device-3": EventInserted,
+				"device-4": EventInserted,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, devices := newTestAtomixStore()
+			for _, id := range []string{"device-1", "device-2"} {
+				if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ch := make(chan *Event)
+			if err := store.Watch(ch); err != nil {
+				t.Fatal(err)
+			}
+
+			// The changes are made while the map's events are withheld, so they can only be delivered by the
+			// resync once the watch is re-established
+			devices.mute(true)
+			if test.missed != nil {
+				if err := test.missed(ctx, store); err != nil {
+					t.Fatal(err)
+				}
+			}
+			devices.failWatches(test.watchErrors)
+			devices.dropWatches()
+			devices.mute(false)
+
+			// Changes made after the watch is dropped are delivered whether or not the watch has been
+			// re-established yet, since they're either received from the new watch or found by the resync
+			if test.after != nil {
+				if err := test.after(ctx, store); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events := make(map[string]EventType)
+			for len(events) < len(test.events) {
+				event := nextEvent(t, ch)
+				events[event.Device.Id] = event.Type
+			}
+			for id, eventType := range test.events {
+				if events[id] != eventType {
+					t.Errorf("expected %s event for %s, got %s", eventType, id, events[id])
+				}
+			}
+		})
+	}
+}
+
+func TestWatchAbandoned(t *testing.T) {
+	stallTimeout := watchStallTimeout
+	watchStallTimeout = 500 * time.Millisecond
+	defer func() {
+		watchStallTimeout = stallTimeout
+	}()
+
+	tests := []struct {
+		name     string
+		store    func() Store
+		watchers func(Store) int
+	}{
+		{
+			name: "local",
+			store: func() Store {
+				return NewLocalStore()
+			},
+			watchers: func(store Store) int {
+				local := store.(*localStore)
+				local.mu.RLock()
+				defer local.mu.RUnlock()
+				return len(local.watchers)
+			},
+		},
+		{
+			name: "atomix",
+			store: func() Store {
+				store, _ := newTestAtomixStore()
+				return store
+			},
+			watchers: func(store Store) int {
+				atomix := store.(*atomixStore)
+				atomix.mu.Lock()
+				defer atomix.mu.Unlock()
+				return len(atomix.watchers)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := test.store()
+
+			// The reader of the abandoned watch never reads, so its buffer fills after the first event
+			abandoned := make(chan *Event)
+			if err := store.Watch(abandoned, WithBufferSize(1)); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan *Event, 10)
+			if err := store.Watch(ch); err != nil {
+				t.Fatal(err)
+			}
+
+			// Events are delivered outside the store's lock, so writes complete while the watcher is blocked
+			ctx := context.Background()
+			start := time.Now()
+			for i := 1; i <= 5; i++ {
+				id := fmt.Sprintf("device-%d", i)
+				if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if elapsed := time.Since(start); elapsed >= watchStallTimeout/2 {
+				t.Errorf("writes blocked for %s by the abandoned watcher", elapsed)
+			}
+
+			// Once the abandoned watcher is removed, the remaining events are delivered to the other watcher
+			for i := 1; i <= 5; i++ {
+				event := nextEvent(t, ch)
+				if id := fmt.Sprintf("device-%d", i); event.Type != EventInserted || event.Device.Id != id {
+					t.Fatalf("expected %s event for %s, got %s event for %s", EventInserted, id, event.Type, event.Device.Id)
+				}
+			}
+			if watchers := test.watchers(store); watchers != 1 {
+				t.Errorf("expected 1 watcher, got %d", watchers)
+			}
+
+			timeout := time.After(5 * time.Second)
+			for open := true; open; {
+				select {
+				case _, open = <-abandoned:
+				case <-timeout:
+					t.Fatal("timed out waiting for the abandoned watch to be closed")
+				}
+			}
+		})
+	}
+}
+
+func TestIndexEviction(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalStore()
+	for i := 1; i <= 2; i++ {
+		id := fmt.Sprintf("device-%d", i)
+		device := &Device{Id: id, Address: id + ":5150", Labels: map[string]string{"site": fmt.Sprintf("site-%d", i)}}
+		if err := store.Store(ctx, device); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The index holds a device that was removed while its watch was falling behind
+	index := &labelIndex{
+		values:  map[string]map[string]map[string]bool{"site": {"site-3": {"device-3": true}}},
+		devices: map[string]map[string]string{"device-3": {"site": "site-3"}},
+		ready:   true,
+		logger:  NewNopLogger(),
+	}
+	ch := make(chan *Event, 1)
+	ch <- &Event{Type: EventEvicted}
+	close(ch)
+	go index.process(store, ch)
+
+	tests := []struct {
+		selector string
+		ids      []string
+	}{
+		{selector: "site=site-1", ids: []string{"device-1"}},
+		{selector: "site=site-3", ids: []string{}},
+		{selector: "site", ids: []string{"device-1", "device-2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			selector, err := ParseSelector(test.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			timeout := time.After(5 * time.Second)
+			for {
+				ids, ok := index.lookup(selector)
+				if ok && reflect.DeepEqual(ids, test.ids) {
+					return
+				}
+				select {
+				case <-time.After(10 * time.Millisecond):
+				case <-timeout:
+					t.Fatalf("expected %v for %s, got %v (ready: %t)", test.ids, test.selector, ids, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRace(t *testing.T) {
+	tests := []struct {
+		name string
+		// write adds device-2 concurrently with its create
+		write func(ctx context.Context, store *atomixStore) error
+		// overwrites indicates whether the write may replace the created device
+		overwrites bool
+	}{
+		{
+			name: "rename",
+			write: func(ctx context.Context, store *atomixStore) error {
+				return store.Rename(ctx, &Device{Id: "device-1", Address: "write:5150"}, "device-2")
+			},
+		},
+		{
+			name: "tx",
+			write: func(ctx context.Context, store *atomixStore) error {
+				return store.Tx(ctx, func(t Txn) error {
+					t.Put(&Device{Id: "device-2", Address: "write:5150"})
+					return nil
+				})
+			},
+			overwrites: true,
+		},
+		{
+			name: "store",
+			write: func(ctx context.Context, store *atomixStore) error {
+				return store.Store(ctx, &Device{Id: "device-2", Address: "write:5150"})
+			},
+			overwrites: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, devices := newTestAtomixStore()
+			if err := store.Store(ctx, &Device{Id: "device-1", Address: "write:5150"}); err != nil {
+				t.Fatal(err)
+			}
+			// The latency of reads widens the window between the existence checks and the puts
+			devices.latency = 10 * time.Millisecond
+
+			var createErr, writeErr error
+			wg := &sync.WaitGroup{}
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				createErr = store.Create(ctx, &Device{Id: "device-2", Address: "create:5150"})
+			}()
+			go func() {
+				defer wg.Done()
+				writeErr = test.write(ctx, store)
+			}()
+			wg.Wait()
+
+			for _, err := range []error{createErr, writeErr} {
+				if err != nil && err != ErrAlreadyExists {
+					t.Fatal(err)
+				}
+			}
+			device, err := store.Load(ctx, "device-2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case createErr == nil && writeErr == nil && !test.overwrites:
+				t.Error("expected either the create or the write to fail")
+			case createErr == nil && writeErr == nil && device.Address != "write:5150":
+				// The write succeeded after the create, so it must have replaced the created device
+				t.Errorf("expected the written device, got %s", device.Address)
+			case createErr == nil && writeErr != nil && device.Address != "create:5150":
+				t.Errorf("expected the created device, got %s", device.Address)
+			case createErr != nil && device.Address != "write:5150":
+				t.Errorf("expected the written device, got %s", device.Address)
+			}
+		})
+	}
+}
+
+func TestDeleteAnnotationsError(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestAtomixStore()
+	device := &Device{Id: "device-1", Address: "device-1:5150"}
+	if err := store.Store(ctx, device); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.StoreAnnotations(ctx, &Annotations{DeviceId: "device-1", Values: map[string]string{"key": "value"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The device is removed before its annotations, so the delete succeeds even if they can't be removed
+	store.annotations.(*testMap).removeErr = errors.New("remove failed")
+	if err := store.Delete(ctx, device); err != nil {
+		t.Fatalf("expected the delete to succeed, got %v", err)
+	}
+	if stored, err := store.Load(ctx, "device-1"); err != nil {
+		t.Fatal(err)
+	} else if stored != nil {
+		t.Error("expected the device to be removed")
+	}
+}
+
+func TestTxRollbackTimeout(t *testing.T) {
+	store, devices := newTestAtomixStore()
+
+	// The first put is committed within the transaction's timeout, but the second put is made after the
+	// timeout since each read takes longer than half the timeout
+	devices.latency = 50 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 75*time.Millisecond)
+	defer cancel()
+	err := store.Tx(ctx, func(t Txn) error {
+		t.Put(&Device{Id: "device-1", Address: "device-1:5150"})
+		t.Put(&Device{Id: "device-2", Address: "device-2:5150"})
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	// The rollback isn't bound by the transaction's context, so the first put is undone
+	devices.latency = 0
+	for _, id := range []string{"device-1", "device-2"} {
+		if device, err := store.Load(context.Background(), id); err != nil {
+			t.Fatal(err)
+		} else if device != nil {
+			t.Errorf("expected %s to be rolled back", id)
+		}
+	}
+}
+
+// testSink is an EventSink that hands each published event to the test and blocks until it's released
+type testSink struct {
+	published chan *Event
+	release   chan struct{}
+}
+
+func (s *testSink) Publish(event *Event) error {
+	s.published <- event
+	<-s.release
+	return nil
+}
+
+func (s *testSink) Close() error {
+	return nil
+}
+
+func TestPublishEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		bufferSize int
+		devices    int
+	}{
+		{
+			name:       "no drops",
+			bufferSize: 4,
+			devices:    4,
+		},
+		{
+			name:       "drops",
+			bufferSize: 2,
+			devices:    6,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewLocalStore()
+			sink := &testSink{
+				published: make(chan *Event),
+				release:   make(chan struct{}),
+			}
+			if err := publishEvents(store, sink, test.bufferSize, NewNopLogger()); err != nil {
+				t.Fatal(err)
+			}
+			newDevice := func(id string) *Device {
+				return &Device{
+					Id:          id,
+					Address:     id + ":5150",
+					Credentials: &Credentials{Credential: &Credentials_BasicAuth{BasicAuth: &BasicAuth{User: "user", Password: "secret"}}},
+				}
+			}
+
+			// The sink blocks publishing the first event, so the following events fill the buffer
+			dropped := sinkDroppedEvents.Value()
+			if err := store.Store(ctx, newDevice("device-0")); err != nil {
+				t.Fatal(err)
+			}
+			event := nextEvent(t, sink.published)
+			if event.Device.Id != "device-0" {
+				t.Fatalf("expected event for device-0, got event for %s", event.Device.Id)
+			}
+			if password := event.Device.GetCredentials().GetBasicAuth().GetPassword(); password != "" {
+				t.Errorf("expected the published device's password to be redacted, got %q", password)
+			}
+			if device, err := store.Load(ctx, "device-0"); err != nil {
+				t.Fatal(err)
+			} else if password := device.GetCredentials().GetBasicAuth().GetPassword(); password != "secret" {
+				t.Errorf("expected the stored device's password to be retained, got %q", password)
+			}
+
+			for i := 1; i <= test.devices; i++ {
+				if err := store.Store(ctx, newDevice(fmt.Sprintf("device-%d", i))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			drops := int64(test.devices - test.bufferSize)
+			for deadline := time.Now().Add(5 * time.Second); sinkDroppedEvents.Value()-dropped < drops; {
+				if time.Now().After(deadline) {
+					t.Fatalf("expected %d dropped events, got %d", drops, sinkDroppedEvents.Value()-dropped)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			// Once the sink is released, the buffered events are published in order
+			close(sink.release)
+			for i := 1; i <= test.bufferSize; i++ {
+				event := nextEvent(t, sink.published)
+				if id := fmt.Sprintf("device-%d", i); event.Device.Id != id {
+					t.Fatalf("expected event for %s, got event for %s", id, event.Device.Id)
+				}
+			}
+			select {
+			case event := <-sink.published:
+				t.Errorf("unexpected event for %s", event.Device.Id)
+			case <-time.After(100 * time.Millisecond):
+			}
+			if got := sinkDroppedEvents.Value() - dropped; got != drops {
+				t.Errorf("expected %d dropped events, got %d", drops, got)
+			}
+		})
+	}
+}
+
+// newTestStores returns the stores against which store behavior is tested
+func newTestStores() []struct {
+	name  string
+	store Store
+} {
+	atomix, _ := newTestAtomixStore()
+	return []struct {
+		name  string
+		store Store
+	}{
+		{
+			name:  "local",
+			store: NewLocalStore(),
+		},
+		{
+			name:  "atomix",
+			store: atomix,
+		},
+	}
+}
+
+// readEvents reads events from the given channel until the given function returns true for an event or the
+// channel is closed, returning the events read and whether the channel was closed
+func readEvents(t *testing.T, ch <-chan *Event, until func(*Event) bool) ([]*Event, bool) {
+	t.Helper()
+	var events []*Event
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return events, true
+			}
+			events = append(events, event)
+			if until(event) {
+				return events, false
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for events after %d events", len(events))
+		}
+	}
+}
+
+func TestEventSeq(t *testing.T) {
+	for _, test := range newTestStores() {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := test.store
+			ch := make(chan *Event, 10)
+			if err := store.Watch(ch, WithReplay(false)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.Store(ctx, &Device{Id: "device-1", Address: "device-1:5150"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Store(ctx, &Device{Id: "device-2", Address: "device-2:5150"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Store(ctx, &Device{Id: "device-1", Address: "device-1:5151"}); err != nil {
+				t.Fatal(err)
+			}
+			device, err := store.Load(ctx, "device-2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Delete(ctx, device); err != nil {
+				t.Fatal(err)
+			}
+
+			// Each event's sequence number is one greater than that of the previous event
+			types := []EventType{EventInserted, EventInserted, EventUpdated, EventRemoved}
+			var last *Event
+			for i, eventType := range types {
+				event := nextEvent(t, ch)
+				if event.Type != eventType {
+					t.Fatalf("expected %s event, got %s event", eventType, event.Type)
+				} else if i > 0 && event.Seq != last.Seq+1 {
+					t.Errorf("expected %s event seq %d, got %d", event.Type, last.Seq+1, event.Seq)
+				}
+				last = event
+			}
+
+			// Replayed devices carry the sequence number of the last event, and the next event follows it
+			replay := make(chan *Event, 10)
+			if err := store.Watch(replay, WithReplayDone(true)); err != nil {
+				t.Fatal(err)
+			}
+			events, _ := readEvents(t, replay, func(event *Event) bool {
+				return event.Type == EventReplayDone
+			})
+			if len(events) != 2 {
+				t.Fatalf("expected 1 replayed device, got %d events", len(events)-1)
+			}
+			for _, event := range events {
+				if event.Seq != last.Seq {
+					t.Errorf("expected %s event seq %d, got %d", event.Type, last.Seq, event.Seq)
+				}
+			}
+			if err := store.Store(ctx, &Device{Id: "device-3", Address: "device-3:5150"}); err != nil {
+				t.Fatal(err)
+			}
+			if event := nextEvent(t, replay); event.Seq != last.Seq+1 {
+				t.Errorf("expected %s event seq %d, got %d", event.Type, last.Seq+1, event.Seq)
+			}
+		})
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	const devices = 5
+	tests := []struct {
+		name   string
+		policy OverflowPolicy
+		// check checks the events read by the slow watcher given the sequence number of the first event, the
+		// number of events the watcher dropped, and the number of watchers evicted
+		check func(t *testing.T, events []*Event, first uint64, dropped int64, evicted int64)
+	}{
+		{
+			name:   "block",
+			policy: OverflowBlock,
+			check: func(t *testing.T, events []*Event, first uint64, dropped int64, evicted int64) {
+				if len(events) != devices {
+					t.Errorf("expected %d events, got %d", devices, len(events))
+				}
+				for i, event := range events {
+					if event.Seq != first+uint64(i) {
+						t.Errorf("expected seq %d, got %d", first+uint64(i), event.Seq)
+					}
+				}
+			},
+		},
+		{
+			name:   "drop oldest",
+			policy: OverflowDropOldest,
+			check: func(t *testing.T, events []*Event, first uint64, dropped int64, evicted int64) {
+				// The newest events are retained, and the dropped events show up as a gap in sequence numbers
+				if len(events) >= devices {
+					t.Fatalf("expected fewer than %d events, got %d", devices, len(events))
+				} else if dropped != int64(devices-len(events)) {
+					t.Errorf("expected %d dropped events, got %d", devices-len(events), dropped)
+				}
+				if id := events[len(events)-2].Device.Id; id != fmt.Sprintf("device-%d", devices-1) {
+					t.Errorf("expected the next to last event to be for device-%d, got %s", devices-1, id)
+				}
+				gap := false
+				seq := first - 1
+				for _, event := range events {
+					if event.Seq <= seq {
+						t.Errorf("expected seq greater than %d, got %d", seq, event.Seq)
+					} else if event.Seq > seq+1 {
+						gap = true
+					}
+					seq = event.Seq
+				}
+				if !gap {
+					t.Error("expected a gap in event sequence numbers")
+				}
+			},
+		},
+		{
+			name:   "evict",
+			policy: OverflowEvict,
+			check: func(t *testing.T, events []*Event, first uint64, dropped int64, evicted int64) {
+				// The buffered events are delivered without gaps, followed by the eviction
+				if evicted != 1 {
+					t.Errorf("expected 1 evicted watcher, got %d", evicted)
+				}
+				if event := events[len(events)-1]; event.Type != EventEvicted {
+					t.Fatalf("expected %s event, got %s event", EventEvicted, event.Type)
+				}
+				inserted := events[:len(events)-1]
+				if len(inserted) >= devices {
+					t.Errorf("expected fewer than %d events before the eviction, got %d", devices, len(inserted))
+				}
+				for i, event := range inserted {
+					if event.Seq != first+uint64(i) {
+						t.Errorf("expected seq %d, got %d", first+uint64(i), event.Seq)
+					}
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewLocalStore().(*localStore)
+			dropped := droppedEvents.Value()
+			evicted := evictedWatchers.Value()
+
+			// Events are delivered to watchers in the order in which they're registered, so once the other
+			// watcher receives the last event, the last event has been published to the slow watcher
+			slow := make(chan *Event)
+			if err := store.Watch(slow, WithReplay(false), WithBufferSize(2), WithOverflowPolicy(test.policy)); err != nil {
+				t.Fatal(err)
+			}
+			ch := make(chan *Event, devices)
+			if err := store.Watch(ch, WithReplay(false)); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 1; i <= devices; i++ {
+				id := fmt.Sprintf("device-%d", i)
+				if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// A blocked watcher delays the delivery of events to the other watcher until it reads its events
+			var first uint64
+			if test.policy != OverflowBlock {
+				first = nextEvent(t, ch).Seq
+				for i := 2; i <= devices; i++ {
+					nextEvent(t, ch)
+				}
+			}
+
+			last := fmt.Sprintf("device-%d", devices)
+			events, closed := readEvents(t, slow, func(event *Event) bool {
+				return event.Device.GetId() == last
+			})
+			if test.policy == OverflowBlock {
+				first = nextEvent(t, ch).Seq
+			}
+			if test.policy == OverflowEvict && !closed {
+				t.Error("expected the evicted watch to be closed")
+			} else if test.policy != OverflowEvict && closed {
+				t.Error("expected the watch to remain open")
+			}
+			test.check(t, events, first, droppedEvents.Value()-dropped, evictedWatchers.Value()-evicted)
+
+			store.mu.RLock()
+			watchers := len(store.watchers)
+			store.mu.RUnlock()
+			if expected := 2; test.policy == OverflowEvict && watchers != expected-1 || test.policy != OverflowEvict && watchers != expected {
+				t.Errorf("unexpected number of watchers %d", watchers)
+			}
+		})
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(Txn)
+	}{
+		{
+			name: "insert",
+			write: func(t Txn) {
+				t.Put(&Device{Id: "device-3", Address: "device-3:5150"})
+			},
+		},
+		{
+			name: "update",
+			write: func(t Txn) {
+				t.Put(&Device{Id: "device-1", Address: "device-1:5151"})
+			},
+		},
+		{
+			name: "remove",
+			write: func(t Txn) {
+				t.Remove(&Device{Id: "device-1"})
+			},
+		},
+	}
+	for _, test := range tests {
+		for _, store := range newTestStores() {
+			t.Run(fmt.Sprintf("%s/%s", test.name, store.name), func(t *testing.T) {
+				ctx := context.Background()
+				for _, id := range []string{"device-1", "device-2"} {
+					if err := store.store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				// The write is made before a write whose version condition fails
+				err := store.store.Tx(ctx, func(txn Txn) error {
+					test.write(txn)
+					txn.Put(&Device{Id: "device-2", Address: "device-2:5151", Metadata: &ObjectMetadata{Id: "device-2", Version: 1000}})
+					return nil
+				})
+				if err != ErrConflict {
+					t.Fatalf("expected %v, got %v", ErrConflict, err)
+				}
+
+				for _, id := range []string{"device-1", "device-2"} {
+					if device, err := store.store.Load(ctx, id); err != nil {
+						t.Fatal(err)
+					} else if device == nil {
+						t.Errorf("expected %s to be restored", id)
+					} else if device.Address != id+":5150" {
+						t.Errorf("expected %s address %s, got %s", id, id+":5150", device.Address)
+					}
+				}
+				if device, err := store.store.Load(ctx, "device-3"); err != nil {
+					t.Fatal(err)
+				} else if device != nil {
+					t.Error("expected device-3 to be rolled back")
+				}
+			})
+		}
+	}
+}
+
+func TestSwapAddressesRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		failKey string
+		swapped bool
+	}{
+		{
+			name:    "swapped",
+			swapped: true,
+		},
+		{
+			name:    "first put fails",
+			failKey: "device-1",
+		},
+		{
+			name:    "second put fails",
+			failKey: "device-2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, devices := newTestAtomixStore()
+			for _, id := range []string{"device-1", "device-2"} {
+				if err := store.Store(ctx, &Device{Id: id, Address: id + ":5150"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.failKey != "" {
+				devices.mu.Lock()
+				devices.putErrs = map[string]error{test.failKey: errors.New("put failed")}
+				devices.mu.Unlock()
+			}
+			_, _, err := store.SwapAddresses(ctx, "device-1", "device-2")
+			if test.swapped && err != nil {
+				t.Fatal(err)
+			} else if !test.swapped && err == nil {
+				t.Fatal("expected the swap to fail")
+			}
+
+			devices.mu.Lock()
+			devices.putErrs = nil
+			devices.mu.Unlock()
+			for _, ids := range [][]string{{"device-1", "device-2"}, {"device-2", "device-1"}} {
+				address := ids[0] + ":5150"
+				if test.swapped {
+					address = ids[1] + ":5150"
+				}
+				if device, err := store.Load(ctx, ids[0]); err != nil {
+					t.Fatal(err)
+				} else if device.Address != address {
+					t.Errorf("expected %s address %s, got %s", ids[0], address, device.Address)
+				}
+			}
+		})
+	}
+}
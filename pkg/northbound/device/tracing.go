@@ -0,0 +1,256 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"github.com/onosproject/onos-topo/pkg/trace"
+	"time"
+)
+
+// NewTracingStore returns a Store that creates a span with the given tracer around each operation of the
+// given store
+// Spans are children of the span in the operation's context, if any, so store operations performed while
+// handling a traced RPC are recorded as part of the RPC's trace.
+func NewTracingStore(store Store, tracer trace.Tracer) Store {
+	return &tracingStore{
+		store:  store,
+		tracer: tracer,
+	}
+}
+
+// tracingStore is a Store that traces the operations of another Store
+type tracingStore struct {
+	store  Store
+	tracer trace.Tracer
+}
+
+// start starts a span for the given store operation
+func (s *tracingStore) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "DeviceStore."+name)
+}
+
+// endSpan records the result of the operation and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetError(err)
+	}
+	span.End()
+}
+
+func (s *tracingStore) Load(ctx context.Context, deviceID string) (*Device, error) {
+	ctx, span := s.start(ctx, "Load")
+	span.SetAttribute("device", deviceID)
+	device, err := s.store.Load(ctx, deviceID)
+	endSpan(span, err)
+	return device, err
+}
+
+func (s *tracingStore) Exists(ctx context.Context, deviceID string) (bool, error) {
+	ctx, span := s.start(ctx, "Exists")
+	span.SetAttribute("device", deviceID)
+	exists, err := s.store.Exists(ctx, deviceID)
+	endSpan(span, err)
+	return exists, err
+}
+
+func (s *tracingStore) Count(ctx context.Context) (int, error) {
+	ctx, span := s.start(ctx, "Count")
+	count, err := s.store.Count(ctx)
+	endSpan(span, err)
+	return count, err
+}
+
+func (s *tracingStore) Create(ctx context.Context, device *Device) error {
+	ctx, span := s.start(ctx, "Create")
+	span.SetAttribute("device", device.Id)
+	err := s.store.Create(ctx, device)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) EnsureDevice(ctx context.Context, device *Device) (*Device, bool, error) {
+	ctx, span := s.start(ctx, "EnsureDevice")
+	span.SetAttribute("device", device.Id)
+	stored, created, err := s.store.EnsureDevice(ctx, device)
+	span.SetAttribute("created", created)
+	endSpan(span, err)
+	return stored, created, err
+}
+
+func (s *tracingStore) Store(ctx context.Context, device *Device) error {
+	ctx, span := s.start(ctx, "Store")
+	span.SetAttribute("device", device.Id)
+	err := s.store.Store(ctx, device)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) ForcePut(ctx context.Context, device *Device) error {
+	ctx, span := s.start(ctx, "ForcePut")
+	span.SetAttribute("device", device.Id)
+	err := s.store.ForcePut(ctx, device)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) Delete(ctx context.Context, device *Device) error {
+	ctx, span := s.start(ctx, "Delete")
+	span.SetAttribute("device", device.Id)
+	err := s.store.Delete(ctx, device)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) Rename(ctx context.Context, device *Device, newID string) error {
+	ctx, span := s.start(ctx, "Rename")
+	span.SetAttribute("device", device.Id)
+	span.SetAttribute("new_device", newID)
+	err := s.store.Rename(ctx, device, newID)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) SwapAddresses(ctx context.Context, firstID string, secondID string) (*Device, *Device, error) {
+	ctx, span := s.start(ctx, "SwapAddresses")
+	span.SetAttribute("device", firstID)
+	span.SetAttribute("peer", secondID)
+	first, second, err := s.store.SwapAddresses(ctx, firstID, secondID)
+	endSpan(span, err)
+	return first, second, err
+}
+
+func (s *tracingStore) Clear(ctx context.Context) error {
+	ctx, span := s.start(ctx, "Clear")
+	err := s.store.Clear(ctx)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) Tx(ctx context.Context, fn func(Txn) error) error {
+	ctx, span := s.start(ctx, "Tx")
+	err := s.store.Tx(ctx, fn)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) LoadAnnotations(ctx context.Context, deviceID string) (*Annotations, error) {
+	ctx, span := s.start(ctx, "LoadAnnotations")
+	span.SetAttribute("device", deviceID)
+	annotations, err := s.store.LoadAnnotations(ctx, deviceID)
+	endSpan(span, err)
+	return annotations, err
+}
+
+func (s *tracingStore) StoreAnnotations(ctx context.Context, annotations *Annotations) error {
+	ctx, span := s.start(ctx, "StoreAnnotations")
+	span.SetAttribute("device", annotations.DeviceId)
+	err := s.store.StoreAnnotations(ctx, annotations)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) List(ctx context.Context, ch chan<- *Device) error {
+	ctx, span := s.start(ctx, "List")
+	err := s.store.List(ctx, ch)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) ListModifiedSince(ctx context.Context, since time.Time, ch chan<- *Device) error {
+	ctx, span := s.start(ctx, "ListModifiedSince")
+	span.SetAttribute("since", since.Format(time.RFC3339Nano))
+	err := s.store.ListModifiedSince(ctx, since, ch)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) ListChildren(ctx context.Context, parentID string) ([]*Device, error) {
+	ctx, span := s.start(ctx, "ListChildren")
+	span.SetAttribute("parent", parentID)
+	children, err := s.store.ListChildren(ctx, parentID)
+	endSpan(span, err)
+	return children, err
+}
+
+// Watch traces starting the watch; events delivered to the channel are not traced
+func (s *tracingStore) Watch(ch chan<- *Event, opts ...WatchOption) error {
+	_, span := s.start(context.Background(), "Watch")
+	err := s.store.Watch(ch, opts...)
+	endSpan(span, err)
+	return err
+}
+
+// VersionsOrdered is not traced since it doesn't access the store
+func (s *tracingStore) VersionsOrdered() bool {
+	return s.store.VersionsOrdered()
+}
+
+// WatchFrom traces starting the watch; events delivered to the channel are not traced
+func (s *tracingStore) WatchFrom(ctx context.Context, version uint64, ch chan<- *Event, opts ...WatchOption) error {
+	ctx, span := s.start(ctx, "WatchFrom")
+	span.SetAttribute("version", version)
+	err := s.store.WatchFrom(ctx, version, ch, opts...)
+	endSpan(span, err)
+	return err
+}
+
+// WatchDevices traces starting the watch; events delivered to the channel are not traced
+func (s *tracingStore) WatchDevices(ctx context.Context, ids []string, ch chan<- *Event, opts ...WatchOption) error {
+	ctx, span := s.start(ctx, "WatchDevices")
+	span.SetAttribute("devices", len(ids))
+	err := s.store.WatchDevices(ctx, ids, ch, opts...)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) CreateGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, span := s.start(ctx, "CreateGroup")
+	span.SetAttribute("group", group.Id)
+	err := s.store.CreateGroup(ctx, group)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) StoreGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, span := s.start(ctx, "StoreGroup")
+	span.SetAttribute("group", group.Id)
+	err := s.store.StoreGroup(ctx, group)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) LoadGroup(ctx context.Context, groupID string) (*DeviceGroup, error) {
+	ctx, span := s.start(ctx, "LoadGroup")
+	span.SetAttribute("group", groupID)
+	group, err := s.store.LoadGroup(ctx, groupID)
+	endSpan(span, err)
+	return group, err
+}
+
+func (s *tracingStore) DeleteGroup(ctx context.Context, group *DeviceGroup) error {
+	ctx, span := s.start(ctx, "DeleteGroup")
+	span.SetAttribute("group", group.Id)
+	err := s.store.DeleteGroup(ctx, group)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracingStore) ListGroups(ctx context.Context) ([]*DeviceGroup, error) {
+	ctx, span := s.start(ctx, "ListGroups")
+	groups, err := s.store.ListGroups(ctx)
+	endSpan(span, err)
+	return groups, err
+}
@@ -0,0 +1,210 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"regexp"
+	"sort"
+)
+
+// reserveUnique reserves the serial number and address of the given device if they must be unique, returning
+// a function that releases the reservations
+// An AlreadyExists error is returned if another device has or is being written with either value. The values
+// are checked and reserved before the device is written and released once the write is indexed, so exactly one
+// of any concurrent writes of a value on this replica succeeds. Values that are unchanged from the previous
+// version of the device, if any, are not checked, so devices that already share a value can still be updated.
+// Only the management address is checked, so devices may share failover addresses, and addresses aren't
+// checked if allowDuplicateAddress is set. The returned function must be called if the device is not written.
+func (s *Server) reserveUnique(device *Device, previous *Device, allowDuplicateAddress bool) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	indexes := []*valueIndex{s.serials}
+	if !allowDuplicateAddress {
+		indexes = append(indexes, s.addresses)
+	}
+	for _, index := range indexes {
+		if index == nil {
+			continue
+		}
+		value := index.value(device)
+		if value == "" || (previous != nil && index.value(previous) == value) {
+			continue
+		}
+		id, err := index.reserve(device.Id, value, s.listDevices)
+		if err != nil {
+			release()
+			return nil, status.Error(codes.Unavailable, err.Error())
+		} else if id != "" {
+			release()
+			return nil, status.Errorf(codes.AlreadyExists, "%s %s is already used by device %s", index.field, value, id)
+		}
+		index, id := index, device.Id
+		releases = append(releases, func() {
+			index.release(id, value)
+		})
+	}
+	return release, nil
+}
+
+// listDevices returns all stored devices
+// It's used to check unique values while the value indexes are not ready.
+func (s *Server) listDevices() ([]*Device, error) {
+	ch := make(chan *Device, listBufferSize)
+	if err := s.deviceStore.List(context.Background(), ch); err != nil {
+		return nil, err
+	}
+	var devices []*Device
+	for device := range ch {
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// FieldViolation is a violation of a deployment-specific rule by a field of a device
+type FieldViolation struct {
+	// Field is the path to the violating field, e.g. addresses[0]
+	Field string
+
+	// Description describes why the field is invalid
+	Description string
+}
+
+// ValidatorFunc validates a device against a deployment-specific rule, returning the fields that violate it
+type ValidatorFunc func(device *Device) []FieldViolation
+
+// runValidators runs the server's validators against the given device
+// If any validator returns violations, an InvalidArgument error is returned carrying the violations as
+// BadRequest details. Unique serial numbers and addresses are checked when the device is written.
+func (s *Server) runValidators(device *Device) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, validator := range s.validators {
+		for _, violation := range validator(device) {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid device: %s: %s", violations[0].Field, violations[0].Description))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// AllowedNetworksValidator returns a validator requiring the address and failover addresses of devices to
+// be IP addresses within any of the given networks
+func AllowedNetworksValidator(networks ...*net.IPNet) ValidatorFunc {
+	return func(device *Device) []FieldViolation {
+		var violations []FieldViolation
+		check := func(field string, address string) {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				host = address
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				violations = append(violations, FieldViolation{Field: field, Description: fmt.Sprintf("host %s is not an IP address", host)})
+				return
+			}
+			for _, network := range networks {
+				if network.Contains(ip) {
+					return
+				}
+			}
+			violations = append(violations, FieldViolation{Field: field, Description: fmt.Sprintf("%s is not in an allowed network", ip)})
+		}
+
+		if device.Address != "" {
+			check("address", device.Address)
+		}
+		for i, address := range device.Addresses {
+			check(fmt.Sprintf("addresses[%d]", i), address)
+		}
+		return violations
+	}
+}
+
+// RequiredLabelsValidator returns a validator requiring devices to have a non-empty value for each of
+// the given label keys
+func RequiredLabelsValidator(keys ...string) ValidatorFunc {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	return func(device *Device) []FieldViolation {
+		var violations []FieldViolation
+		for _, key := range sorted {
+			if device.Labels[key] == "" {
+				violations = append(violations, FieldViolation{Field: fmt.Sprintf("labels[%s]", key), Description: "label is required"})
+			}
+		}
+		return violations
+	}
+}
+
+// TLSValidator returns a validator requiring the TLS configuration of devices to be coherent
+// A certificate and key must be set together, and plaintext connections can't be configured with TLS
+// certificates or as insecure TLS connections.
+func TLSValidator() ValidatorFunc {
+	return func(device *Device) []FieldViolation {
+		tls := device.GetTls()
+		if tls == nil {
+			return nil
+		}
+		var violations []FieldViolation
+		if tls.Cert != "" && tls.Key == "" {
+			violations = append(violations, FieldViolation{Field: "tls.key", Description: "key is required with a certificate"})
+		} else if tls.Key != "" && tls.Cert == "" {
+			violations = append(violations, FieldViolation{Field: "tls.cert", Description: "certificate is required with a key"})
+		}
+		if tls.Plain && (tls.Cert != "" || tls.Key != "" || tls.CaCert != "") {
+			violations = append(violations, FieldViolation{Field: "tls.plain", Description: "plaintext connections cannot use TLS certificates"})
+		}
+		if tls.Plain && tls.Insecure {
+			violations = append(violations, FieldViolation{Field: "tls.insecure", Description: "plaintext connections cannot be insecure TLS connections"})
+		}
+		return violations
+	}
+}
+
+// semanticVersionPattern is the pattern of a semantic version as defined by https://semver.org
+var semanticVersionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(-((0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(\+([0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*))?$`)
+
+// SemanticVersionValidator returns a validator requiring the software versions of devices to be semantic
+// versions
+// Devices without a software version are valid.
+func SemanticVersionValidator() ValidatorFunc {
+	return func(device *Device) []FieldViolation {
+		if device.SoftwareVersion == "" || semanticVersionPattern.MatchString(device.SoftwareVersion) {
+			return nil
+		}
+		return []FieldViolation{{Field: "software_version", Description: fmt.Sprintf("%s is not a semantic version", device.SoftwareVersion)}}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"github.com/gogo/protobuf/proto"
+)
+
+// applyView returns the given device as seen in the given view
+// Devices are shared with the store and the service's cache, so fields are omitted from a shallow copy of
+// the device rather than from the device itself.
+func applyView(device *Device, view ListRequest_View) *Device {
+	if device == nil || view != ListRequest_BASIC {
+		return device
+	}
+	basic := *device
+	basic.Credentials = nil
+	basic.Tls = nil
+	basic.Protocols = nil
+	return &basic
+}
+
+// redactSecrets returns the given device without its secrets
+// The passwords, SSH private key and token references, and TLS key are omitted from copies of the device's
+// credentials and TLS configuration, so the given device is not modified.
+func redactSecrets(device *Device) *Device {
+	if device == nil || (device.Credentials == nil && device.Tls == nil) {
+		return device
+	}
+	redacted := *device
+	if device.Credentials != nil {
+		credentials := proto.Clone(device.Credentials).(*Credentials)
+		credentials.Password = ""
+		switch credential := credentials.Credential.(type) {
+		case *Credentials_BasicAuth:
+			credential.BasicAuth.Password = ""
+		case *Credentials_SshKey:
+			credential.SshKey.PrivateKeyRef = ""
+		case *Credentials_Token:
+			credential.Token.ValueRef = ""
+		}
+		redacted.Credentials = credentials
+	}
+	if device.Tls != nil {
+		tls := *device.Tls
+		tls.Key = ""
+		redacted.Tls = &tls
+	}
+	return &redacted
+}
+
+// retainSecrets copies the secrets of the stored device to the given device where the given device's are empty
+// Devices are returned with their secrets redacted, so a client that gets a device and sends it back in a full
+// update would otherwise remove its secrets. A secret is only retained if the given device has the same kind
+// of credential as the stored device. Secrets can be removed with a field mask update of the credentials or tls.
+func retainSecrets(device, stored *Device) {
+	if credentials := device.GetCredentials(); credentials != nil {
+		switch credential := credentials.Credential.(type) {
+		case *Credentials_BasicAuth:
+			if credential.BasicAuth != nil && credential.BasicAuth.Password == "" {
+				credential.BasicAuth.Password = stored.GetCredentials().GetBasicAuth().GetPassword()
+			}
+		case *Credentials_SshKey:
+			if credential.SshKey != nil && credential.SshKey.PrivateKeyRef == "" {
+				credential.SshKey.PrivateKeyRef = stored.GetCredentials().GetSshKey().GetPrivateKeyRef()
+			}
+		case *Credentials_Token:
+			if credential.Token != nil && credential.Token.ValueRef == "" {
+				credential.Token.ValueRef = stored.GetCredentials().GetToken().GetValueRef()
+			}
+		}
+	}
+	if tls := device.GetTls(); tls != nil && tls.Key == "" {
+		tls.Key = stored.GetTls().GetKey()
+	}
+}
+
+// presentDevice returns the given device as it's sent to a client that requested the given view
+// Secrets are redacted unless includeSecrets is set.
+func presentDevice(device *Device, view ListRequest_View, includeSecrets bool) *Device {
+	device = applyView(device, view)
+	if !includeSecrets {
+		device = redactSecrets(device)
+	}
+	return device
+}
@@ -0,0 +1,61 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"crypto/tls"
+	"expvar"
+	"fmt"
+	"google.golang.org/grpc"
+	log "k8s.io/klog"
+	"net/http"
+)
+
+// HTTPService provides service-specific registration for HTTP/JSON gateway handlers.
+// Handlers must invoke the service through the given interceptors, so HTTP requests are subject to the same
+// rate limits, deadlines, logging and metrics as gRPC requests.
+type HTTPService interface {
+	RegisterHTTP(mux *http.ServeMux, interceptor grpc.UnaryServerInterceptor, streamInterceptor grpc.StreamServerInterceptor)
+}
+
+// ServeHTTP starts the HTTP/JSON gateway for all services that implement HTTPService.
+// The gateway is served over TLS using the same certificates as the gRPC server. Metrics are served
+// as JSON at /debug/vars, and the health of the server is served at /healthz.
+func (s *Server) ServeHTTP(started func(string)) error {
+	tlsCfg, err := s.getTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	lis, err := tls.Listen("tcp", fmt.Sprintf(":%d", s.cfg.HTTPPort), tlsCfg)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/healthz", s.serveHealth)
+	interceptor := chainUnaryInterceptors(s.interceptors)
+	streamInterceptor := chainStreamInterceptors(s.streamInterceptors)
+	for i := range s.services {
+		if service, ok := s.services[i].(HTTPService); ok {
+			service.RegisterHTTP(mux, interceptor, streamInterceptor)
+		}
+	}
+	started(lis.Addr().String())
+
+	log.Infof("Starting HTTP gateway on address: %s", lis.Addr().String())
+	return http.Serve(lis, mux)
+}
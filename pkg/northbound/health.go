@@ -0,0 +1,78 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	log "k8s.io/klog"
+	"net/http"
+	"time"
+)
+
+// defaultHealthCheckInterval is the default interval at which the health of services is checked
+const defaultHealthCheckInterval = 10 * time.Second
+
+// HealthChecker is implemented by services whose readiness depends on a backend such as a store
+type HealthChecker interface {
+	// CheckHealth returns an error if the service can't serve requests
+	CheckHealth(ctx context.Context) error
+}
+
+// newHealthServer returns a new gRPC health server that reports NOT_SERVING until the services are checked
+func newHealthServer() *health.Server {
+	server := health.NewServer()
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return server
+}
+
+// checkHealth periodically checks the health of all services that implement HealthChecker
+// The server reports SERVING only once every service has passed a check, so the server isn't marked ready
+// before its backends are usable.
+func (s *Server) checkHealth() {
+	s.updateHealth()
+	ticker := time.NewTicker(s.cfg.HealthCheckInterval)
+	for range ticker.C {
+		s.updateHealth()
+	}
+}
+
+// updateHealth checks the health of all services and updates the serving status of the server
+func (s *Server) updateHealth() {
+	status := healthpb.HealthCheckResponse_SERVING
+	for i := range s.services {
+		if checker, ok := s.services[i].(HealthChecker); ok {
+			ctx, cancel := context.WithTimeout(context.Background(), s.cfg.HealthCheckInterval)
+			err := checker.CheckHealth(ctx)
+			cancel()
+			if err != nil {
+				log.Warningf("Health check failed: %s", err)
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+	}
+	s.health.SetServingStatus("", status)
+}
+
+// serveHealth responds to HTTP health probes with 200 if the server is serving and 503 otherwise
+func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
+	response, err := s.health.Check(r.Context(), &healthpb.HealthCheckRequest{})
+	if err != nil || response.Status != healthpb.HealthCheckResponse_SERVING {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
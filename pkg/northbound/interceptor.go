@@ -0,0 +1,62 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"google.golang.org/grpc"
+)
+
+// AddInterceptor adds a unary interceptor to the server
+// Interceptors are invoked in the order in which they're added, with the first interceptor added being
+// the outermost interceptor.
+func (s *Server) AddInterceptor(interceptor grpc.UnaryServerInterceptor) {
+	s.interceptors = append(s.interceptors, interceptor)
+}
+
+// AddStreamInterceptor adds a stream interceptor to the server
+// Stream interceptors are invoked in the order in which they're added, with the first interceptor added
+// being the outermost interceptor.
+func (s *Server) AddStreamInterceptor(interceptor grpc.StreamServerInterceptor) {
+	s.streamInterceptors = append(s.streamInterceptors, interceptor)
+}
+
+// chainUnaryInterceptors returns a single unary interceptor that invokes the given interceptors in order
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors returns a single stream interceptor that invokes the given interceptors in order
+func chainStreamInterceptors(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return chained(srv, ss)
+	}
+}
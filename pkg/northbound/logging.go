@@ -0,0 +1,171 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	log "k8s.io/klog"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sampler decides which requests are logged by a RequestLogger
+type Sampler interface {
+	// Sample returns whether a request to the given method is logged
+	// The method is the full gRPC method name, e.g. /topo.device.DeviceService/Add.
+	Sample(method string) bool
+}
+
+// SamplerFunc is a function that implements Sampler
+type SamplerFunc func(method string) bool
+
+// Sample returns the result of calling the function with the given method
+func (f SamplerFunc) Sample(method string) bool {
+	return f(method)
+}
+
+// NewRateSampler returns a Sampler that samples the given fraction of requests at random
+// A rate of 1 or more samples every request and a rate of 0 or less samples none.
+func NewRateSampler(rate float64) Sampler {
+	return SamplerFunc(func(string) bool {
+		if rate >= 1 {
+			return true
+		} else if rate <= 0 {
+			return false
+		}
+		return rand.Float64() < rate
+	})
+}
+
+// NewRequestLogger returns a new RequestLogger configured with the given options
+// Every request is logged unless a sampler is configured.
+func NewRequestLogger(opts ...RequestLogOption) *RequestLogger {
+	logger := &RequestLogger{
+		sampler:  NewRateSampler(1),
+		deviceID: func(interface{}) string { return "" },
+	}
+	for _, opt := range opts {
+		opt(logger)
+	}
+	return logger
+}
+
+// RequestLogOption is an option for configuring a RequestLogger
+type RequestLogOption func(*RequestLogger)
+
+// WithLogSampler sets the sampler that decides which requests are logged
+func WithLogSampler(sampler Sampler) RequestLogOption {
+	return func(logger *RequestLogger) {
+		logger.sampler = sampler
+	}
+}
+
+// WithLogErrors sets whether failed requests are logged even if they're not sampled
+func WithLogErrors(logErrors bool) RequestLogOption {
+	return func(logger *RequestLogger) {
+		logger.logErrors = logErrors
+	}
+}
+
+// WithLogDeviceID sets the function that extracts the ID of the device targeted by a request
+// The function returns an empty string for requests that don't target a single device. Requests are logged
+// without a device ID by default.
+func WithLogDeviceID(deviceID func(req interface{}) string) RequestLogOption {
+	return func(logger *RequestLogger) {
+		logger.deviceID = deviceID
+	}
+}
+
+// RequestLogger logs the method, device ID, latency and status of a sample of the requests to the server
+type RequestLogger struct {
+	sampler   Sampler
+	logErrors bool
+	deviceID  func(req interface{}) string
+}
+
+// UnaryInterceptor returns a unary interceptor that logs sampled requests
+func (l *RequestLogger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		sampled := l.sampler.Sample(info.FullMethod)
+		start := time.Now()
+		response, err := handler(ctx, req)
+		if sampled || (err != nil && l.logErrors) {
+			l.log(info.FullMethod, l.deviceID(req), time.Since(start), err)
+		}
+		return response, err
+	}
+}
+
+// StreamInterceptor returns a stream interceptor that logs sampled streaming RPCs when they complete
+// The device ID of a stream is extracted from the first message received on the stream.
+func (l *RequestLogger) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		sampled := l.sampler.Sample(info.FullMethod)
+		start := time.Now()
+		stream := &loggingStream{ServerStream: ss, deviceID: l.deviceID}
+		err := handler(srv, stream)
+		if sampled || (err != nil && l.logErrors) {
+			l.log(info.FullMethod, stream.firstDeviceID(), time.Since(start), err)
+		}
+		return err
+	}
+}
+
+// log logs a completed request
+func (l *RequestLogger) log(method string, deviceID string, latency time.Duration, err error) {
+	msg := fmt.Sprintf("Handled request method=%s", method)
+	if deviceID != "" {
+		msg += fmt.Sprintf(" device=%s", deviceID)
+	}
+	msg += fmt.Sprintf(" latency=%s code=%s", latency, status.Code(err))
+	if err != nil {
+		log.Warningf("%s error=%s", msg, status.Convert(err).Message())
+	} else {
+		log.Info(msg)
+	}
+}
+
+// loggingStream is a ServerStream that records the device ID of the first message it receives
+type loggingStream struct {
+	grpc.ServerStream
+	deviceID func(req interface{}) string
+	mu       sync.Mutex
+	received bool
+	id       string
+}
+
+func (s *loggingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.received {
+		s.received = true
+		s.id = s.deviceID(m)
+	}
+	return nil
+}
+
+// firstDeviceID returns the device ID of the first message received on the stream
+func (s *loggingStream) firstDeviceID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
@@ -0,0 +1,159 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"strings"
+	"sync"
+)
+
+// payloadSizeBuckets are the upper bounds in bytes of the payload size histogram buckets
+var payloadSizeBuckets = []int{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+var (
+	// requestSizes is the distribution of request message sizes keyed by method
+	requestSizes = expvar.NewMap("topo_grpc_request_bytes")
+
+	// responseSizes is the distribution of response message sizes keyed by method
+	// Each message sent on a stream is recorded individually.
+	responseSizes = expvar.NewMap("topo_grpc_response_bytes")
+
+	// streamResponseSizes is the distribution of the total size of the messages sent on each stream keyed by method
+	streamResponseSizes = expvar.NewMap("topo_grpc_stream_response_bytes")
+)
+
+// PayloadSizeUnaryInterceptor returns a unary interceptor that records the sizes of requests and responses
+func PayloadSizeUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		observeSize(requestSizes, info.FullMethod, req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			observeSize(responseSizes, info.FullMethod, resp)
+		}
+		return resp, err
+	}
+}
+
+// PayloadSizeStreamInterceptor returns a stream interceptor that records the sizes of the messages received
+// and sent on each stream, and the total size of the messages sent on each stream
+func PayloadSizeStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &payloadSizeStream{
+			ServerStream: ss,
+			method:       info.FullMethod,
+		}
+		err := handler(srv, stream)
+		histogramFor(streamResponseSizes, info.FullMethod).observe(stream.sent)
+		return err
+	}
+}
+
+// payloadSizeStream is a ServerStream that records the sizes of the messages it receives and sends
+type payloadSizeStream struct {
+	grpc.ServerStream
+	method string
+	sent   int
+}
+
+func (s *payloadSizeStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	observeSize(requestSizes, s.method, m)
+	return nil
+}
+
+func (s *payloadSizeStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.sent += observeSize(responseSizes, s.method, m)
+	return nil
+}
+
+// observeSize records the encoded size of the given message in the histogram for the given method,
+// returning the size of the message
+func observeSize(sizes *expvar.Map, method string, m interface{}) int {
+	message, ok := m.(proto.Message)
+	if !ok {
+		return 0
+	}
+	size := proto.Size(message)
+	histogramFor(sizes, method).observe(size)
+	return size
+}
+
+// histogramMu guards the creation of histograms
+var histogramMu sync.Mutex
+
+// histogramFor returns the histogram for the given method in the given map, creating it if necessary
+func histogramFor(sizes *expvar.Map, method string) *sizeHistogram {
+	if h, ok := sizes.Get(method).(*sizeHistogram); ok {
+		return h
+	}
+	histogramMu.Lock()
+	defer histogramMu.Unlock()
+	if h, ok := sizes.Get(method).(*sizeHistogram); ok {
+		return h
+	}
+	h := &sizeHistogram{
+		counts: make([]uint64, len(payloadSizeBuckets)+1),
+	}
+	sizes.Set(method, h)
+	return h
+}
+
+// sizeHistogram is a histogram of payload sizes exported as an expvar.Var
+type sizeHistogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    uint64
+}
+
+// observe records the given size in the histogram
+func (h *sizeHistogram) observe(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for i < len(payloadSizeBuckets) && size > payloadSizeBuckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += uint64(size)
+}
+
+// String returns the histogram as a JSON object with cumulative bucket counts keyed by upper bound
+func (h *sizeHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := make([]string, 0, len(h.counts))
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		bound := "+Inf"
+		if i < len(payloadSizeBuckets) {
+			bound = fmt.Sprintf("%d", payloadSizeBuckets[i])
+		}
+		buckets = append(buckets, fmt.Sprintf("%q: %d", bound, cumulative))
+	}
+	return fmt.Sprintf(`{"count": %d, "sum": %d, "buckets": {%s}}`, h.count, h.sum, strings.Join(buckets, ", "))
+}
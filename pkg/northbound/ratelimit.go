@@ -0,0 +1,124 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// NewRateLimiter returns a new RateLimiter configured with the given options
+func NewRateLimiter(opts ...RateLimitOption) *RateLimiter {
+	limiter := &RateLimiter{
+		methods: make(map[string]*tokenBucket),
+	}
+	for _, opt := range opts {
+		opt(limiter)
+	}
+	return limiter
+}
+
+// RateLimitOption is an option for configuring a RateLimiter
+type RateLimitOption func(*RateLimiter)
+
+// WithGlobalRateLimit limits the rate of all unary requests to the given number of requests per second
+// The burst is the maximum number of requests that may be handled at once after a period of inactivity.
+func WithGlobalRateLimit(rate float64, burst int) RateLimitOption {
+	return func(limiter *RateLimiter) {
+		limiter.global = newTokenBucket(rate, burst)
+	}
+}
+
+// WithMethodRateLimit limits the rate of requests to the given method to the given number of requests per second
+// The method is the full gRPC method name, e.g. /topo.device.DeviceService/Add. Method limits are applied
+// in addition to the global limit.
+func WithMethodRateLimit(method string, rate float64, burst int) RateLimitOption {
+	return func(limiter *RateLimiter) {
+		limiter.methods[method] = newTokenBucket(rate, burst)
+	}
+}
+
+// WithSharedRateLimit limits the combined rate of requests to the given methods to the given number of
+// requests per second
+// The methods share a single token bucket, so the limit applies to the total rate of requests to all the
+// methods rather than to each method. Shared limits are applied in addition to the global limit.
+func WithSharedRateLimit(methods []string, rate float64, burst int) RateLimitOption {
+	return func(limiter *RateLimiter) {
+		bucket := newTokenBucket(rate, burst)
+		for _, method := range methods {
+			limiter.methods[method] = bucket
+		}
+	}
+}
+
+// RateLimiter limits the rate of unary requests to the server using token buckets
+type RateLimiter struct {
+	global  *tokenBucket
+	methods map[string]*tokenBucket
+}
+
+// UnaryInterceptor returns a unary interceptor that rejects requests exceeding the rate limits
+// with ResourceExhausted.
+func (l *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if bucket, ok := l.methods[info.FullMethod]; ok && !bucket.allow() {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+		}
+		if l.global != nil && !l.global.allow() {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// newTokenBucket returns a new full token bucket with the given refill rate and capacity
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// tokenBucket is a token bucket that is refilled at a constant rate up to its capacity
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket, returning false if the bucket is empty
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -22,9 +22,12 @@ import (
 	"fmt"
 	"github.com/onosproject/onos-config/pkg/certs"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"io/ioutil"
 	log "k8s.io/klog"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
 )
@@ -36,8 +39,13 @@ type Service interface {
 
 // Server provides NB gNMI server for onos-topo.
 type Server struct {
-	cfg      *ServerConfig
-	services []Service
+	cfg                *ServerConfig
+	services           []Service
+	interceptors       []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	health             *health.Server
+	maxRecvMsgSize     int
+	maxSendMsgSize     int
 }
 
 // ServerConfig comprises a set of server configuration options.
@@ -46,25 +54,61 @@ type ServerConfig struct {
 	KeyPath  *string
 	CertPath *string
 	Port     int16
+	HTTPPort int16
 	Insecure bool
+
+	// HealthCheckInterval is the interval at which the health of services is checked
+	HealthCheckInterval time.Duration
 }
 
 // NewServer initializes gNMI server using the supplied configuration.
-func NewServer(cfg *ServerConfig) *Server {
-	return &Server{
+func NewServer(cfg *ServerConfig, opts ...ServerOption) *Server {
+	server := &Server{
 		services: []Service{},
 		cfg:      cfg,
+		health:   newHealthServer(),
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+// ServerOption is an option for configuring the Server
+type ServerOption func(*Server)
+
+// WithMaxRecvMsgSize sets the maximum size in bytes of a message the server can receive
+// By default, gRPC limits received messages to 4 MiB. Requests that carry many devices, e.g. the Add requests
+// of a bulk load or an UpdateMany device template, rarely approach the limit, but a device with large TLS
+// certificates or protocol configurations can be tens of KiB. If size is 0, the gRPC default is used.
+func WithMaxRecvMsgSize(size int) ServerOption {
+	return func(server *Server) {
+		server.maxRecvMsgSize = size
+	}
+}
+
+// WithMaxSendMsgSize sets the maximum size in bytes of a message the server can send
+// Responses that carry a set of devices, e.g. ListPage, ListChildren, SearchDevices and UpdateMany responses,
+// grow with the number of devices in the topology. As a rule of thumb, allow 2 KiB for each device a single
+// response may carry: 4 MiB covers about 2,000 devices, so a topology in which a search may match 20,000
+// devices needs about 40 MiB. List streams send one device per message and are not affected. Clients must
+// raise their receive limit to match, e.g. with WithMaxCallRecvMsgSize. If size is 0, the gRPC default is used.
+func WithMaxSendMsgSize(size int) ServerOption {
+	return func(server *Server) {
+		server.maxSendMsgSize = size
 	}
 }
 
 // NewServerConfig creates a server config created with the specified end-point security details.
 func NewServerConfig(caPath string, keyPath string, certPath string) *ServerConfig {
 	return &ServerConfig{
-		Port:     5150,
-		Insecure: true,
-		CaPath:   &caPath,
-		KeyPath:  &keyPath,
-		CertPath: &certPath,
+		Port:                5150,
+		HTTPPort:            5151,
+		Insecure:            true,
+		CaPath:              &caPath,
+		KeyPath:             &keyPath,
+		CertPath:            &certPath,
+		HealthCheckInterval: defaultHealthCheckInterval,
 	}
 }
 
@@ -74,12 +118,46 @@ func (s *Server) AddService(r Service) {
 }
 
 // Serve starts the NB gNMI server.
+// The standard gRPC health service is registered with the server. The server reports NOT_SERVING until all
+// services that implement HealthChecker have passed a health check.
 func (s *Server) Serve(started func(string)) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.Port))
 	if err != nil {
 		return err
 	}
 
+	tlsCfg, err := s.getTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}
+	if len(s.interceptors) > 0 {
+		opts = append(opts, grpc.UnaryInterceptor(chainUnaryInterceptors(s.interceptors)))
+	}
+	if len(s.streamInterceptors) > 0 {
+		opts = append(opts, grpc.StreamInterceptor(chainStreamInterceptors(s.streamInterceptors)))
+	}
+	if s.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(s.maxRecvMsgSize))
+	}
+	if s.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(s.maxSendMsgSize))
+	}
+	server := grpc.NewServer(opts...)
+	for i := range s.services {
+		s.services[i].Register(server)
+	}
+	healthpb.RegisterHealthServer(server, s.health)
+	go s.checkHealth()
+	started(lis.Addr().String())
+
+	log.Infof("Starting RPC server on address: %s", lis.Addr().String())
+	return server.Serve(lis)
+}
+
+// getTLSConfig returns the server TLS configuration
+func (s *Server) getTLSConfig() (*tls.Config, error) {
 	tlsCfg := &tls.Config{}
 
 	if *s.cfg.CertPath == "" && *s.cfg.KeyPath == "" {
@@ -87,7 +165,7 @@ func (s *Server) Serve(started func(string)) error {
 		clientCerts, err := tls.X509KeyPair([]byte(certs.DefaultLocalhostCrt), []byte(certs.DefaultLocalhostKey))
 		if err != nil {
 			log.Error("Error loading default certs")
-			return err
+			return nil, err
 		}
 		tlsCfg.Certificates = []tls.Certificate{clientCerts}
 	} else {
@@ -114,16 +192,7 @@ func (s *Server) Serve(started func(string)) error {
 	} else {
 		tlsCfg.ClientCAs = getCertPool(*s.cfg.CaPath)
 	}
-
-	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}
-	server := grpc.NewServer(opts...)
-	for i := range s.services {
-		s.services[i].Register(server)
-	}
-	started(lis.Addr().String())
-
-	log.Infof("Starting RPC server on address: %s", lis.Addr().String())
-	return server.Serve(lis)
+	return tlsCfg, nil
 }
 
 func getCertPoolDefault() *x509.CertPool {
@@ -0,0 +1,81 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package northbound
+
+import (
+	"context"
+	"github.com/onosproject/onos-topo/pkg/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TracingUnaryInterceptor returns a unary interceptor that creates a span for each RPC using the given tracer
+// The span is propagated to the handler through the request context, so spans created by the handler,
+// e.g. around store operations, are children of the RPC span. A W3C traceparent sent by the caller is
+// propagated so that the RPC span joins the caller's trace.
+func TracingUnaryInterceptor(tracer trace.Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := tracer.Start(extractTraceparent(ctx), info.FullMethod)
+		defer span.End()
+		response, err := handler(ctx, req)
+		if err != nil {
+			span.SetError(err)
+		}
+		return response, err
+	}
+}
+
+// TracingStreamInterceptor returns a stream interceptor that creates a span for each streaming RPC using the
+// given tracer
+func TracingStreamInterceptor(tracer trace.Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := tracer.Start(extractTraceparent(ss.Context()), info.FullMethod)
+		defer span.End()
+		err := handler(srv, &tracingStream{ServerStream: ss, ctx: ctx})
+		if err != nil {
+			span.SetError(err)
+		}
+		return err
+	}
+}
+
+// extractTraceparent returns a context carrying the remote span context from the incoming traceparent
+// metadata, if present and valid
+// Invalid traceparent values are ignored, in which case the RPC span starts a new trace.
+func extractTraceparent(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(trace.TraceparentHeader)
+	if len(values) != 1 {
+		return ctx
+	}
+	sc, err := trace.ParseTraceparent(values[0])
+	if err != nil {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// tracingStream is a server stream whose context carries the RPC span
+type tracingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracingStream) Context() context.Context {
+	return s.ctx
+}
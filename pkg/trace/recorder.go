@@ -0,0 +1,139 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// SpanData is a completed span recorded by a recording tracer
+type SpanData struct {
+	// Name is the name of the span
+	Name string
+
+	// Context identifies the span
+	Context SpanContext
+
+	// Parent identifies the parent span, if HasParent is set
+	Parent SpanContext
+
+	// HasParent indicates whether the span has a local or remote parent
+	HasParent bool
+
+	// Start is the time at which the span started
+	Start time.Time
+
+	// Duration is the duration of the span
+	Duration time.Duration
+
+	// Attributes are the attributes set on the span
+	Attributes map[string]interface{}
+
+	// Err is the error recorded on the span, if any
+	Err error
+}
+
+// Exporter receives spans when they end
+// Exporters must be safe for concurrent use.
+type Exporter func(span SpanData)
+
+// NewRecordingTracer returns a Tracer that records spans and passes them to the given exporter when they end
+// Spans whose remote parent was not sampled by the caller are not recorded, but are still propagated to
+// child spans.
+func NewRecordingTracer(exporter Exporter) Tracer {
+	return &recordingTracer{exporter: exporter}
+}
+
+// recordingTracer is a Tracer that records spans
+type recordingTracer struct {
+	exporter Exporter
+}
+
+// spanKey is the context key for the current local span context
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{
+		tracer: t,
+		data: SpanData{
+			Name:  name,
+			Start: time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(spanKey{}).(SpanContext); ok {
+		span.data.Parent, span.data.HasParent = parent, true
+	} else if parent, ok := RemoteSpanContextFromContext(ctx); ok {
+		span.data.Parent, span.data.HasParent = parent, true
+	}
+	if span.data.HasParent {
+		span.data.Context.TraceID = span.data.Parent.TraceID
+		span.data.Context.Sampled = span.data.Parent.Sampled
+	} else {
+		randomID(span.data.Context.TraceID[:])
+		span.data.Context.Sampled = true
+	}
+	randomID(span.data.Context.SpanID[:])
+	ctx = context.WithValue(ctx, spanKey{}, span.data.Context)
+	if !span.data.Context.Sampled {
+		return ctx, nopSpan{}
+	}
+	return ctx, span
+}
+
+// randomID fills the given ID with random bytes, ensuring it's not all zeros
+func randomID(id []byte) {
+	for isZero(id) {
+		_, _ = rand.Read(id)
+	}
+}
+
+// recordingSpan is a Span recorded by a recordingTracer
+type recordingSpan struct {
+	tracer *recordingTracer
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+func (s *recordingSpan) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err
+}
+
+func (s *recordingSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.Duration = time.Since(s.data.Start)
+	data := s.data
+	s.mu.Unlock()
+	s.tracer.exporter(data)
+}
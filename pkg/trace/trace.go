@@ -0,0 +1,64 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace defines the tracing interfaces used to instrument onos-topo.
+// The interfaces are intentionally small so that any tracing library, such as OpenTelemetry, can be
+// adapted to them. By default, tracing is disabled using the no-op tracer. Span contexts propagated by
+// remote callers using the W3C traceparent header are carried in the context passed to Tracer.Start.
+package trace
+
+import (
+	"context"
+)
+
+// Tracer creates spans
+type Tracer interface {
+	// Start starts a span with the given name as a child of the span in the given context, if any
+	// If the context carries no local span but a remote span context, the span is a child of the remote span.
+	// The returned context carries the new span and must be used for operations within the span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation
+type Span interface {
+	// SetAttribute sets an attribute of the span
+	SetAttribute(key string, value interface{})
+
+	// SetError records that the operation failed with the given error
+	SetError(err error)
+
+	// End ends the span
+	End()
+}
+
+// NewNopTracer returns a Tracer that creates spans that record nothing
+func NewNopTracer() Tracer {
+	return nopTracer{}
+}
+
+// nopTracer is a Tracer that creates no-op spans
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+// nopSpan is a Span that records nothing
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+
+func (nopSpan) SetError(err error) {}
+
+func (nopSpan) End() {}
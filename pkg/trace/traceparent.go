@@ -0,0 +1,114 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header carrying the caller's span
+const TraceparentHeader = "traceparent"
+
+// sampledFlag is the trace-flags bit indicating that the caller sampled the trace
+const sampledFlag = 0x01
+
+// SpanContext identifies a span in a distributed trace
+type SpanContext struct {
+	// TraceID is the 16 byte identifier of the trace
+	TraceID [16]byte
+
+	// SpanID is the 8 byte identifier of the span within the trace
+	SpanID [8]byte
+
+	// Sampled indicates whether the trace is being recorded by the caller
+	Sampled bool
+}
+
+// String formats the span context as a W3C traceparent header value
+func (c SpanContext) String() string {
+	var flags byte
+	if c.Sampled {
+		flags = sampledFlag
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(c.TraceID[:]), hex.EncodeToString(c.SpanID[:]), flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header value
+// Versions other than 00 are accepted as long as they begin with the version 00 fields, as required by
+// the specification; all-zero trace and span IDs are rejected.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, errors.New("malformed traceparent")
+	}
+	version, err := decodeHex(parts[0], 1)
+	if err != nil || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return sc, errors.New("invalid traceparent version")
+	}
+	traceID, err := decodeHex(parts[1], len(sc.TraceID))
+	if err != nil || isZero(traceID) {
+		return sc, errors.New("invalid traceparent trace-id")
+	}
+	spanID, err := decodeHex(parts[2], len(sc.SpanID))
+	if err != nil || isZero(spanID) {
+		return sc, errors.New("invalid traceparent parent-id")
+	}
+	flags, err := decodeHex(parts[3], 1)
+	if err != nil {
+		return sc, errors.New("invalid traceparent trace-flags")
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&sampledFlag != 0
+	return sc, nil
+}
+
+// decodeHex decodes a lowercase hex field of exactly n bytes
+func decodeHex(field string, n int) ([]byte, error) {
+	if len(field) != n*2 || strings.ToLower(field) != field {
+		return nil, errors.New("invalid hex field")
+	}
+	return hex.DecodeString(field)
+}
+
+// isZero returns whether all bytes are zero
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// remoteKey is the context key for the remote span context
+type remoteKey struct{}
+
+// ContextWithRemoteSpanContext returns a context carrying the span context propagated by a remote caller
+// Tracers should start spans in such a context as children of the remote span.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// RemoteSpanContextFromContext returns the span context propagated by a remote caller, if any
+func RemoteSpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, ok
+}
@@ -0,0 +1,125 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"testing"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceparent = "00-" + testTraceID + "-" + testSpanID + "-01"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		valid   bool
+		sampled bool
+	}{
+		{name: "sampled", value: testTraceparent, valid: true, sampled: true},
+		{name: "not sampled", value: "00-" + testTraceID + "-" + testSpanID + "-00", valid: true},
+		{name: "future version", value: "01-" + testTraceID + "-" + testSpanID + "-01-extra", valid: true, sampled: true},
+		{name: "version 00 with extra field", value: testTraceparent + "-extra"},
+		{name: "invalid version", value: "ff-" + testTraceID + "-" + testSpanID + "-01"},
+		{name: "zero trace-id", value: "00-00000000000000000000000000000000-" + testSpanID + "-01"},
+		{name: "zero parent-id", value: "00-" + testTraceID + "-0000000000000000-01"},
+		{name: "short trace-id", value: "00-" + testTraceID[2:] + "-" + testSpanID + "-01"},
+		{name: "uppercase", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanID + "-01"},
+		{name: "non-hex", value: "00-" + testTraceID + "-" + testSpanID + "-zz"},
+		{name: "missing fields", value: "00-" + testTraceID},
+		{name: "empty", value: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(test.value)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("expected %q to be rejected", test.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sc.Sampled != test.sampled {
+				t.Errorf("expected sampled %t, got %t", test.sampled, sc.Sampled)
+			}
+			if sc.TraceID != mustParse(t, testTraceparent).TraceID || sc.SpanID != mustParse(t, testTraceparent).SpanID {
+				t.Errorf("unexpected span context %s", sc)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	if s := mustParse(t, testTraceparent).String(); s != testTraceparent {
+		t.Errorf("expected %s, got %s", testTraceparent, s)
+	}
+}
+
+func TestRecordingTracerPropagation(t *testing.T) {
+	var spans []SpanData
+	tracer := NewRecordingTracer(func(span SpanData) {
+		spans = append(spans, span)
+	})
+	remote := mustParse(t, testTraceparent)
+
+	ctx, rpc := tracer.Start(ContextWithRemoteSpanContext(context.Background(), remote), "rpc")
+	_, store := tracer.Start(ctx, "store")
+	store.End()
+	rpc.End()
+	rpc.End()
+
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	storeSpan, rpcSpan := spans[0], spans[1]
+	if !rpcSpan.HasParent || rpcSpan.Parent != remote {
+		t.Errorf("expected rpc span parent %s, got %s", remote, rpcSpan.Parent)
+	}
+	if rpcSpan.Context.TraceID != remote.TraceID {
+		t.Errorf("expected rpc span to join trace %s, got %s", remote, rpcSpan.Context)
+	}
+	if !storeSpan.HasParent || storeSpan.Parent != rpcSpan.Context {
+		t.Errorf("expected store span parent %s, got %s", rpcSpan.Context, storeSpan.Parent)
+	}
+
+	spans = nil
+	unsampled := remote
+	unsampled.Sampled = false
+	_, span := tracer.Start(ContextWithRemoteSpanContext(context.Background(), unsampled), "rpc")
+	span.End()
+	if len(spans) != 0 {
+		t.Errorf("expected unsampled span not to be recorded")
+	}
+
+	_, root := tracer.Start(context.Background(), "root")
+	root.End()
+	if len(spans) != 1 || spans[0].HasParent || isZero(spans[0].Context.TraceID[:]) {
+		t.Errorf("expected a new root trace, got %+v", spans)
+	}
+}
+
+func mustParse(t *testing.T, value string) SpanContext {
+	sc, err := ParseTraceparent(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sc
+}
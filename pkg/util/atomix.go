@@ -17,6 +17,7 @@ package util
 import (
 	"github.com/atomix/atomix-go-client/pkg/client"
 	"os"
+	"sync"
 )
 
 const (
@@ -50,3 +51,24 @@ func GetAtomixClient() (*client.Client, error) {
 	}
 	return client.NewClient(getAtomixController(), opts...)
 }
+
+var (
+	sharedClient   *client.Client
+	sharedClientMu sync.Mutex
+)
+
+// GetSharedAtomixClient returns an Atomix client shared by all callers in the process
+// The client is created on the first call. If the client can't be created, the error is returned and
+// the client is created again on the next call.
+func GetSharedAtomixClient() (*client.Client, error) {
+	sharedClientMu.Lock()
+	defer sharedClientMu.Unlock()
+	if sharedClient == nil {
+		atomixClient, err := GetAtomixClient()
+		if err != nil {
+			return nil, err
+		}
+		sharedClient = atomixClient
+	}
+	return sharedClient, nil
+}